@@ -0,0 +1,174 @@
+package app
+
+import (
+	"context"
+	"myApp/config"
+	"myApp/model"
+	"myApp/pkg/geo"
+	"myApp/pkg/logger"
+	"myApp/pkg/metrics"
+	"myApp/pkg/redis"
+	"myApp/pkg/scheduler"
+	"myApp/pkg/sms"
+	"myApp/repository"
+	"myApp/service"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// App 应用容器，统一创建配置、数据库、Redis、短信和地理编码服务提供商以及各层依赖，
+// 路由、定时任务和测试共用同一组实例，避免各处重复创建
+type App struct {
+	Config   *config.Config
+	DB       *gorm.DB
+	Redis    *redis.Client
+	SMS      sms.SMSProvider // 短信服务提供商，未配置时为nil
+	Geocoder geo.Geocoder    // 地理编码服务提供商，创建失败时为nil，不根据地址解析房源坐标
+
+	Repos    Repositories
+	Services Services
+
+	stopWorkers []func() // 停止随应用容器运行的后台任务
+}
+
+// Repositories 数据仓库实例
+type Repositories struct {
+	Tx                   repository.TxManager // 事务管理器，使多个数据仓库的操作在同一事务中执行
+	User                 repository.UserRepository
+	SMSRecord            repository.SMSRecordRepository
+	House                repository.HouseRepository
+	HouseRevision        repository.HouseRevisionRepository
+	HousePriceHistory    repository.HousePriceHistoryRepository
+	Landlord             repository.LandlordRepository
+	LandlordVerification repository.LandlordVerificationRepository
+	Viewing              repository.ViewingRepository
+	Favorite             repository.FavoriteRepository
+	FavoriteFolder       repository.FavoriteFolderRepository
+	Review               repository.ReviewRepository
+	ReviewReport         repository.ReviewReportRepository
+	Notification         repository.NotificationRepository
+	SavedSearch          repository.SavedSearchRepository
+	Region               repository.RegionRepository
+}
+
+// Services 服务实例
+type Services struct {
+	User         service.UserService
+	SMSCode      service.SMSCodeService
+	House        service.HouseService
+	Landlord     service.LandlordService
+	Viewing      service.ViewingService
+	Favorite     service.FavoriteService
+	Review       service.ReviewService
+	Notification service.NotificationService
+	SavedSearch  service.SavedSearchService
+	Region       service.RegionService
+}
+
+// New 使用已创建的基础设施组装应用容器，rdb会设置为缓存操作使用的Redis客户端
+// 数据库和短信服务提供商会接入监控指标，统计查询耗时和短信发送结果；新房源提醒任务随容器启动，Close时停止
+func New(cfg *config.Config, db *gorm.DB, rdb *redis.Client, smsProvider sms.SMSProvider, geocoder geo.Geocoder) *App {
+	redis.SetClient(rdb)
+	if err := metrics.InstrumentDB(db); err != nil {
+		logger.WithError(err).Warn("注册数据库监控回调失败")
+	}
+	smsProvider = metrics.InstrumentSMS(smsProvider)
+
+	a := &App{Config: cfg, DB: db, Redis: rdb, SMS: smsProvider, Geocoder: geocoder}
+	a.Repos = newRepositories(db)
+	a.Services = newServices(a.Repos, smsProvider, geocoder)
+	a.startWorkers()
+	return a
+}
+
+// Init 根据全局配置初始化数据库、Redis、短信和地理编码服务提供商并组装应用容器
+// 数据库或Redis连接失败时panic，短信服务提供商创建失败只记录日志，发送短信时返回错误；
+// 地理编码服务提供商创建失败只记录日志，房源不再根据地址自动解析坐标
+func Init() *App {
+	db := model.InitDB()
+	rdb := redis.InitRedis()
+
+	smsProvider, err := sms.CreateSMSProvider()
+	if err != nil {
+		logger.WithError(err).Warn("创建短信服务提供商失败，短信功能不可用")
+		smsProvider = nil
+	}
+
+	geocoder, err := geo.CreateGeocoder()
+	if err != nil {
+		logger.WithError(err).Warn("创建地理编码服务提供商失败，不再根据地址自动解析房源坐标")
+		geocoder = nil
+	}
+
+	return New(config.Conf, db, rdb, smsProvider, geocoder)
+}
+
+// Close 停止后台任务，等待正在执行的任务结束后关闭数据库和Redis连接
+func (a *App) Close() error {
+	for _, stop := range a.stopWorkers {
+		stop()
+	}
+	if sqlDB, err := a.DB.DB(); err == nil {
+		sqlDB.Close()
+	}
+	return a.Redis.Close()
+}
+
+// startWorkers 启动随应用容器运行的后台任务：定期发送Redis队列中等待的新房源提醒，未配置间隔时不启动
+// 任务的ctx在Close时取消，正在发送的一批提醒中断后由下次启动的任务重新发送
+func (a *App) startWorkers() {
+	if a.Config.House.AlertInterval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	interval := time.Duration(a.Config.House.AlertInterval) * time.Second
+	stop := scheduler.Every("saved_search_alerts", interval, func() error {
+		if _, err := a.Services.SavedSearch.ProcessAlerts(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+		return nil
+	})
+	a.stopWorkers = append(a.stopWorkers, func() {
+		cancel()
+		stop()
+	})
+}
+
+// newRepositories 创建全部数据仓库
+func newRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Tx:                   repository.NewTxManager(db),
+		User:                 repository.NewUserRepository(db),
+		SMSRecord:            repository.NewSMSRecordRepository(db),
+		House:                repository.NewHouseRepository(db),
+		HouseRevision:        repository.NewHouseRevisionRepository(db),
+		HousePriceHistory:    repository.NewHousePriceHistoryRepository(db),
+		Landlord:             repository.NewLandlordRepository(db),
+		LandlordVerification: repository.NewLandlordVerificationRepository(db),
+		Viewing:              repository.NewViewingRepository(db),
+		Favorite:             repository.NewFavoriteRepository(db),
+		FavoriteFolder:       repository.NewFavoriteFolderRepository(db),
+		Review:               repository.NewReviewRepository(db),
+		ReviewReport:         repository.NewReviewReportRepository(db),
+		Notification:         repository.NewNotificationRepository(db),
+		SavedSearch:          repository.NewSavedSearchRepository(db),
+		Region:               repository.NewRegionRepository(db),
+	}
+}
+
+// newServices 按依赖顺序创建全部服务
+func newServices(repos Repositories, smsProvider sms.SMSProvider, geocoder geo.Geocoder) Services {
+	var s Services
+	s.User = service.NewUserService(repos.User)
+	s.SMSCode = service.NewSMSCodeService(repos.User, repos.SMSRecord, smsProvider)
+	s.Notification = service.NewNotificationService(repos.Notification)
+	s.Region = service.NewRegionService(repos.Region)
+	s.SavedSearch = service.NewSavedSearchService(repos.SavedSearch, repos.House, repos.User, repos.SMSRecord, s.Notification, smsProvider)
+	s.House = service.NewHouseService(repos.House, repos.Landlord, repos.HouseRevision, repos.HousePriceHistory, repos.Favorite, repos.Viewing, s.Notification, s.SavedSearch, s.Region, geocoder, repos.Tx)
+	s.Landlord = service.NewLandlordService(repos.Landlord, repos.User, repos.LandlordVerification, repos.House, repos.Viewing, repos.Tx)
+	s.Viewing = service.NewViewingService(repos.Viewing, repos.Tx)
+	s.Favorite = service.NewFavoriteService(repos.Favorite, repos.FavoriteFolder, repos.House, repos.Tx)
+	s.Review = service.NewReviewService(repos.Review, repos.ReviewReport, repos.Viewing, repos.House, repos.Landlord, repos.Tx)
+	return s
+}
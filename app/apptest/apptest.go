@@ -0,0 +1,243 @@
+// Package apptest 提供接口端到端测试使用的测试服务：
+// 执行迁移脚本建表的内存SQLite数据库、miniredis和记录发送内容的假短信服务提供商，通过httptest直接调用路由
+package apptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"myApp/app"
+	"myApp/config"
+	"myApp/model"
+	"myApp/model/modeltest"
+	"myApp/pkg/geo"
+	"myApp/pkg/logger"
+	"myApp/pkg/redis"
+	"myApp/pkg/redis/cache"
+	"myApp/pkg/region"
+	"myApp/router"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// SentSMS 假短信服务提供商记录的一条短信
+type SentSMS struct {
+	Phones        []string
+	TemplateCode  string
+	TemplateParam string
+}
+
+// FakeSMSProvider 假短信服务提供商，只记录发送内容，Err不为nil时发送失败
+type FakeSMSProvider struct {
+	mu   sync.Mutex
+	sent []SentSMS
+	Err  error
+}
+
+// SendSMS 记录短信内容
+func (p *FakeSMSProvider) SendSMS(phoneNumbers []string, signName, templateCode, templateParam string) (bool, string, string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Err != nil {
+		return false, "", "", p.Err
+	}
+	p.sent = append(p.sent, SentSMS{Phones: phoneNumbers, TemplateCode: templateCode, TemplateParam: templateParam})
+	id := strconv.Itoa(len(p.sent))
+	return true, "biz-" + id, "req-" + id, nil
+}
+
+// QuerySMSStatus 查询短信发送状态，始终返回已送达
+func (p *FakeSMSProvider) QuerySMSStatus(phoneNumber, bizId string) (map[string]interface{}, error) {
+	return map[string]interface{}{"status": "DELIVERED"}, nil
+}
+
+// GetName 获取短信服务提供商名称
+func (p *FakeSMSProvider) GetName() string {
+	return "fake"
+}
+
+// GetSignName 获取短信签名
+func (p *FakeSMSProvider) GetSignName() string {
+	return "测试签名"
+}
+
+// GetTemplateCode 获取验证码短信模板ID
+func (p *FakeSMSProvider) GetTemplateCode() string {
+	return "SMS_TEST_CODE"
+}
+
+// Sent 返回已发送的全部短信
+func (p *FakeSMSProvider) Sent() []SentSMS {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]SentSMS(nil), p.sent...)
+}
+
+// LastCode 返回最近一条发送给phone的验证码短信中的验证码，没有时返回空字符串
+func (p *FakeSMSProvider) LastCode(phone string) string {
+	sent := p.Sent()
+	for i := len(sent) - 1; i >= 0; i-- {
+		var param struct {
+			Code string `json:"code"`
+		}
+		if len(sent[i].Phones) == 1 && sent[i].Phones[0] == phone &&
+			json.Unmarshal([]byte(sent[i].TemplateParam), &param) == nil && param.Code != "" {
+			return param.Code
+		}
+	}
+	return ""
+}
+
+// Server 测试服务
+type Server struct {
+	App    *app.App
+	Engine *gin.Engine
+	Redis  *miniredis.Miniredis
+	SMS    *FakeSMSProvider
+}
+
+// Response 测试请求的响应，Data为统一响应结构中未解析的data字段
+type Response struct {
+	Status  int
+	Header  http.Header
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Decode 将data字段解析到v
+func (r *Response) Decode(t *testing.T, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(r.Data, v); err != nil {
+		t.Fatalf("解析响应数据失败: %v, data: %s", err, r.Data)
+	}
+}
+
+// NewConfig 返回测试使用的配置，房源等配置项与InitConfig的默认值一致
+func NewConfig() *config.Config {
+	return &config.Config{
+		Database: config.DatabaseConfig{Driver: "sqlite", DBName: ":memory:"},
+		JWT:      config.JWTConfig{Secret: "apptest-secret", Expire: 3600},
+		Server:   config.ServerConfig{Port: 8080, Mode: "test", RequestTimeout: 30},
+		SMS:      config.SMSConfig{Provider: "fake"},
+		House: config.HouseConfig{
+			ListingTTLDays:      30,
+			ExpireCheckInterval: 3600,
+			PriceOutlierRatio:   3,
+			ViewDedupeWindow:    1800,
+			ViewFlushInterval:   60,
+			TrendingHalfLife:    48,
+			AlertInterval:       5,
+		},
+		Geo: config.GeoConfig{Provider: "local", Timeout: 3000},
+	}
+}
+
+// New 创建测试服务，config.Conf会替换为NewConfig返回的配置，测试结束时自动关闭
+// 配置和Redis客户端是全局的，使用测试服务的测试不能并行执行
+func New(t *testing.T) *Server {
+	t.Helper()
+	return NewWithConfig(t, nil)
+}
+
+// NewWithConfig 创建测试服务，modify不为nil时用于在创建路由前调整NewConfig返回的配置
+func NewWithConfig(t *testing.T, modify func(cfg *config.Config)) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	logger.Logger = zap.NewNop()
+	logger.Sugar = logger.Logger.Sugar()
+
+	cfg := NewConfig()
+	if modify != nil {
+		modify(cfg)
+	}
+	mr := miniredis.RunT(t)
+	cfg.Redis = config.RedisConfig{Host: mr.Host()}
+	cfg.Redis.Port, _ = strconv.Atoi(mr.Port())
+	config.Conf = cfg
+
+	// 新的数据库中记录ID从1开始，清空一级缓存，避免读到之前测试缓存的同ID数据
+	cache.ResetLocal()
+	db := modeltest.NewDB(t)
+	rdb, err := redis.NewClient(cfg.Redis)
+	if err != nil {
+		t.Fatalf("连接miniredis失败: %v", err)
+	}
+
+	// 使用内置行政区划数据集的离线地理编码，与区划表为空时的回退数据一致
+	regions, err := region.Dataset()
+	if err != nil {
+		t.Fatalf("读取行政区划数据集失败: %v", err)
+	}
+	smsProvider := &FakeSMSProvider{}
+	a := app.New(cfg, db, rdb, smsProvider, geo.NewLocalGeocoder(regions))
+	t.Cleanup(func() { a.Close() })
+
+	r := gin.New()
+	r.Use(gin.Recovery())
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		t.Fatalf("设置信任的代理失败: %v", err)
+	}
+	router.SetupRouter(r, a)
+
+	return &Server{App: a, Engine: r, Redis: mr, SMS: smsProvider}
+}
+
+// Do 发送请求，body不为nil时编码为JSON，token不为空时携带Authorization请求头
+func (s *Server) Do(t *testing.T, method, path string, body interface{}, token string) *Response {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("编码请求数据失败: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.Engine.ServeHTTP(w, req)
+
+	resp := &Response{Status: w.Code, Header: w.Header()}
+	if err := json.Unmarshal(w.Body.Bytes(), resp); err != nil {
+		t.Fatalf("%s %s 响应不是JSON: %v, body: %s", method, path, err, w.Body.String())
+	}
+	return resp
+}
+
+// Token 为指定用户签发访问令牌
+func (s *Server) Token(t *testing.T, userID uint) string {
+	t.Helper()
+	claims := jwt.MapClaims{
+		"userID": userID,
+		"exp":    time.Now().Add(time.Duration(s.App.Config.JWT.Expire) * time.Second).Unix(),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.App.Config.JWT.Secret))
+	if err != nil {
+		t.Fatalf("签发访问令牌失败: %v", err)
+	}
+	return token
+}
+
+// CreateUser 直接在数据库中创建用户，modify用于调整modeltest.User的默认字段
+func (s *Server) CreateUser(t *testing.T, modify func(u *model.User)) *model.User {
+	t.Helper()
+	return modeltest.Create(t, s.App.DB, modeltest.User(), modify)
+}
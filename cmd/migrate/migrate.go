@@ -1,11 +1,38 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"myApp/config"
+	"myApp/migrations"
 	"myApp/model"
+	"myApp/pkg/migrate"
+	"myApp/pkg/region"
+	"myApp/repository"
+	"myApp/service"
+	"os"
+	"strconv"
+
+	"gorm.io/gorm"
 )
 
+// baselineVersion 基线迁移的版本号，对应改用版本迁移前AutoMigrate生成的表结构
+const baselineVersion = 1
+
+// backfillBatchSize 识别历史房源所在区域时每批读取的房源数量
+const backfillBatchSize = 500
+
+// usage 命令行用法
+const usage = `用法: go run cmd/migrate/migrate.go [命令]
+
+命令:
+  up          执行全部未执行的迁移（默认）
+  down        回滚最近执行的一个迁移
+  status      查看迁移执行状态
+  to N        升级或回滚到版本N，N为0时回滚全部迁移
+  baseline N  将版本N及之前的迁移标记为已执行而不实际执行，用于接管已有表结构的数据库
+  regions     导入行政区划数据集，并根据地址为尚未设置所在区域的房源识别区划；首次部署或更换数据集后执行一次`
+
 func main() {
 	// 初始化配置
 	config.InitConfig()
@@ -13,22 +40,207 @@ func main() {
 	// 获取数据库连接
 	db := model.InitDB()
 
-	// 执行数据库迁移
-	fmt.Println("开始执行数据库迁移...")
+	// mysql和sqlite使用各自语法的迁移脚本，版本号一一对应
+	scripts, err := migrations.For(config.Conf.Database.Driver)
+	if err != nil {
+		panic(fmt.Sprintf("读取迁移脚本失败: %v", err))
+	}
+	list, err := migrate.Load(scripts)
+	if err != nil {
+		panic(fmt.Sprintf("读取迁移脚本失败: %v", err))
+	}
+	migrator := migrate.NewMigrator(db, list)
+
+	command := "up"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	switch command {
+	case "up":
+		if err := baselineLegacySchema(db, migrator); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 
-	// 自动迁移所有模型
-	err := db.AutoMigrate(
-		&model.User{},
-		&model.House{},
-		&model.Favorite{},
-		&model.Viewing{},
-		&model.Landlord{},
-		&model.SMSRecord{},
-	)
+		fmt.Println("开始执行数据库迁移...")
+		done, err := migrator.Up()
+		printMigrations("已执行", done)
+		if err != nil {
+			panic(fmt.Sprintf("数据库迁移失败: %v", err))
+		}
+		fmt.Println("数据库迁移完成！")
+	case "regions":
+		// 与版本迁移互斥执行，避免与其他进程的迁移或导入同时修改数据
+		err := migrator.WithLock(func(conn *gorm.DB) error {
+			if err := importRegions(conn); err != nil {
+				return fmt.Errorf("导入行政区划失败: %w", err)
+			}
+			count, err := backfillHouseRegions(conn)
+			if err != nil {
+				return fmt.Errorf("识别房源所在区域失败: %w", err)
+			}
+			fmt.Printf("已为%d个历史房源识别所在区域\n", count)
+			return nil
+		})
+		if err != nil {
+			panic(err.Error())
+		}
+	case "down":
+		migration, err := migrator.Down()
+		if err != nil {
+			panic(fmt.Sprintf("回滚迁移失败: %v", err))
+		}
+		if migration == nil {
+			fmt.Println("没有可回滚的迁移")
+			return
+		}
+		fmt.Printf("已回滚: %04d_%s\n", migration.Version, migration.Name)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			panic(fmt.Sprintf("查询迁移状态失败: %v", err))
+		}
+		for _, status := range statuses {
+			state := "未执行"
+			if status.AppliedAt != nil {
+				state = "已执行于 " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, state)
+		}
+	case "to":
+		version := parseVersion()
+		done, err := migrator.To(version)
+		printMigrations("已执行或回滚", done)
+		if err != nil {
+			panic(fmt.Sprintf("迁移到版本%d失败: %v", version, err))
+		}
+		fmt.Printf("已迁移到版本%d\n", version)
+	case "baseline":
+		version := parseVersion()
+		marked, err := migrator.Baseline(version)
+		if err != nil {
+			panic(fmt.Sprintf("标记版本%d失败: %v", version, err))
+		}
+		if !marked {
+			fmt.Println("已有迁移记录，未做修改")
+			return
+		}
+		fmt.Printf("已将版本%d及之前的迁移标记为已执行\n", version)
+	default:
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+}
+
+// parseVersion 读取命令行中的版本号参数，缺少或格式错误时退出
+func parseVersion() int64 {
+	if len(os.Args) < 3 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+	version, err := strconv.ParseInt(os.Args[2], 10, 64)
+	if err != nil || version < 0 {
+		fmt.Println("无效的版本号: " + os.Args[2])
+		os.Exit(1)
+	}
+	return version
+}
 
+// baselineLegacySchema 接管由旧版迁移工具（AutoMigrate）创建表结构的数据库
+// 没有迁移记录且只有基线中的表时将基线标记为已执行，之后的迁移会补齐表结构和历史数据；
+// 已有基线之后的表时无法判断对应的版本，需确认后通过 baseline 命令手动标记
+func baselineLegacySchema(db *gorm.DB, migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status()
 	if err != nil {
-		panic(fmt.Sprintf("数据库迁移失败: %v", err))
+		return fmt.Errorf("查询迁移状态失败: %w", err)
 	}
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			return nil
+		}
+	}
+	if !db.Migrator().HasTable(&model.User{}) {
+		return nil
+	}
+	if db.Migrator().HasTable(&model.LandlordVerification{}) {
+		return fmt.Errorf("检测到已有表结构但没有迁移记录，无法确定对应的版本\n" +
+			"请对照 migrations 目录确认已有表结构对应的版本N后执行 baseline N，再执行 up")
+	}
+	if _, err := migrator.Baseline(baselineVersion); err != nil {
+		return fmt.Errorf("标记基线版本失败: %w", err)
+	}
+	fmt.Printf("检测到已有表结构，已将版本%d标记为已执行\n", baselineVersion)
+	return nil
+}
 
-	fmt.Println("数据库迁移完成！")
+// printMigrations 输出本次执行或回滚的迁移
+func printMigrations(action string, list []migrate.Migration) {
+	for _, migration := range list {
+		fmt.Printf("%s: %04d_%s\n", action, migration.Version, migration.Name)
+	}
+}
+
+// importRegions 将配置的行政区划数据集导入区划表，未配置时导入内置数据集，已存在的区划更新名称、层级和中心点
+func importRegions(db *gorm.DB) error {
+	regions, err := region.Load(config.Conf.Region.DatasetFile)
+	if err != nil {
+		return err
+	}
+	rows := make([]model.Region, len(regions))
+	for i, r := range regions {
+		rows[i] = model.Region{
+			Code:       r.Code,
+			ParentCode: r.ParentCode,
+			Name:       r.Name,
+			Level:      r.Level,
+			Latitude:   r.Latitude,
+			Longitude:  r.Longitude,
+			Radius:     r.Radius,
+		}
+	}
+	return repository.NewRegionRepository(db).Upsert(context.Background(), rows)
+}
+
+// backfillHouseRegions 为尚未设置所在区域的房源尽力从地址中识别区划，返回识别成功的房源数量
+// 按ID分批读取房源，每个房源只处理一次，无法识别的房源保持为空
+func backfillHouseRegions(db *gorm.DB) (int, error) {
+	regionService := service.NewRegionService(repository.NewRegionRepository(db))
+
+	count := 0
+	var afterID uint
+	for {
+		var houses []model.House
+		err := db.Select("id", "address").
+			Where("(province_code = '' OR province_code IS NULL) AND id > ?", afterID).
+			Order("id").Limit(backfillBatchSize).Find(&houses).Error
+		if err != nil {
+			return count, err
+		}
+
+		for _, house := range houses {
+			parsed, err := regionService.ParseAddress(context.Background(), house.Address)
+			if err != nil {
+				return count, err
+			}
+			if parsed.IsEmpty() {
+				continue
+			}
+			err = db.Model(&model.House{}).Where("id = ?", house.ID).UpdateColumns(map[string]interface{}{
+				"province_code":  parsed.ProvinceCode,
+				"city_code":      parsed.CityCode,
+				"district_code":  parsed.DistrictCode,
+				"community_code": parsed.CommunityCode,
+			}).Error
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+
+		if len(houses) < backfillBatchSize {
+			return count, nil
+		}
+		afterID = houses[len(houses)-1].ID
+	}
 }
@@ -0,0 +1,675 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"myApp/config"
+	"myApp/model"
+	"myApp/pkg/idcard"
+	"myApp/pkg/redis"
+	"myApp/pkg/region"
+	"myApp/service"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// seedPassword 生成的用户的统一登录密码
+const seedPassword = "123456"
+
+// batchSize 批量写入的条数
+const batchSize = 200
+
+// defaultNow 默认的参考时间，生成数据中的时间都相对于参考时间，保证相同的种子生成相同的数据
+const defaultNow = "2026-01-01T00:00:00+08:00"
+
+// sizePreset 数据规模预设
+type sizePreset struct {
+	Users         int // 普通用户数量
+	Landlords     int // 房东数量
+	Houses        int // 房源数量
+	Favorites     int // 每个普通用户最多收藏的房源数量
+	Viewings      int // 预约看房数量
+	SMSPerUser    int // 每个用户最多的短信记录数量
+	UnverifiedPct int // 未通过认证的房东比例（百分比）
+}
+
+// sizePresets 可选的数据规模
+var sizePresets = map[string]sizePreset{
+	"small":  {Users: 20, Landlords: 5, Houses: 50, Favorites: 5, Viewings: 40, SMSPerUser: 2, UnverifiedPct: 20},
+	"medium": {Users: 200, Landlords: 30, Houses: 500, Favorites: 10, Viewings: 400, SMSPerUser: 3, UnverifiedPct: 10},
+	"large":  {Users: 2000, Landlords: 200, Houses: 5000, Favorites: 20, Viewings: 4000, SMSPerUser: 3, UnverifiedPct: 10},
+}
+
+// resetTables 重置时清空的表，包括依赖生成数据的业务表
+var resetTables = []interface{}{
+	&model.User{},
+	&model.Landlord{},
+	&model.LandlordVerification{},
+	&model.House{},
+	&model.HouseRevision{},
+	&model.HousePriceHistory{},
+	&model.Favorite{},
+	&model.FavoriteFolder{},
+	&model.Viewing{},
+	&model.Review{},
+	&model.ReviewReport{},
+	&model.Notification{},
+	&model.SavedSearch{},
+	&model.SMSRecord{},
+}
+
+var (
+	surnames    = []string{"王", "李", "张", "刘", "陈", "杨", "赵", "黄", "周", "吴", "徐", "孙", "胡", "朱", "高", "林", "何", "郭", "马", "罗"}
+	givenNames  = []string{"伟", "芳", "娜", "敏", "静", "磊", "洋", "艳", "勇", "军", "杰", "涛", "明", "超", "秀英", "建华", "晓东", "丽娟", "子涵", "雨轩"}
+	streets     = []string{"人民路", "解放路", "中山路", "建设路", "和平路", "新华路", "长江路", "黄河路", "文化路", "青年路", "幸福路", "朝阳路"}
+	communities = []string{"阳光花园", "锦绣家园", "翠湖苑", "金色港湾", "绿地新城", "碧水湾", "梧桐公寓", "紫荆小区", "香榭里", "万科城"}
+	facilities  = []string{"空调", "冰箱", "洗衣机", "热水器", "宽带", "电视", "衣柜", "床", "沙发", "燃气灶", "微波炉", "暖气", "阳台", "独立卫生间"}
+	orientation = []string{"东", "南", "西", "北", "东南", "西南", "南北通透"}
+	highlights  = []string{"近地铁", "拎包入住", "采光好", "安静舒适", "交通便利", "周边配套齐全", "随时看房", "新装修"}
+	remarks     = []string{"希望周末看房", "下班后方便", "想了解一下周边交通", "带家人一起看", ""}
+	cancels     = []string{"已租到其他房源", "时间冲突", "房东临时有事", "预算调整"}
+	banks       = []string{"中国工商银行", "中国建设银行", "中国农业银行", "中国银行", "招商银行"}
+)
+
+// seeder 测试数据生成器，相同的种子生成相同的数据
+type seeder struct {
+	db        *gorm.DB
+	rand      *rand.Rand
+	size      sizePreset
+	now       time.Time
+	password  string
+	districts [][]region.Region // 有中心点的区县及其从省级开始的路径
+
+	adminID   uint
+	users     []model.User
+	landlords []model.User
+	houses    []model.House
+}
+
+func main() {
+	seed := flag.Int64("seed", 1, "随机种子，相同的种子生成相同的数据")
+	size := flag.String("size", "small", "数据规模：small、medium、large")
+	reset := flag.Bool("reset", false, "生成前清空已有的业务数据和Redis中的房源数据")
+	nowFlag := flag.String("now", defaultNow, "参考时间（RFC3339格式），生成数据中的时间都相对于该时间")
+	flag.Parse()
+
+	preset, ok := sizePresets[*size]
+	if !ok {
+		fmt.Printf("不支持的数据规模：%s，可选 small、medium、large\n", *size)
+		os.Exit(1)
+	}
+	now, err := time.Parse(time.RFC3339, *nowFlag)
+	if err != nil {
+		fmt.Printf("参考时间格式错误：%s，示例 %s\n", *nowFlag, defaultNow)
+		os.Exit(1)
+	}
+
+	// 初始化配置
+	config.InitConfig()
+
+	// 获取数据库连接
+	db := model.InitDB()
+	if !db.Migrator().HasTable(&model.User{}) {
+		fmt.Println("数据表不存在，请先执行 go run cmd/migrate/migrate.go")
+		os.Exit(1)
+	}
+
+	if *reset {
+		// 生产环境禁止清空数据
+		if config.Conf.Server.Mode == "release" {
+			fmt.Println("release模式下不允许重置数据")
+			os.Exit(1)
+		}
+		if err := resetData(db); err != nil {
+			panic(fmt.Sprintf("清空数据失败: %v", err))
+		}
+		// 房源ID会被复用，同时清空Redis中的房源缓存、热度和浏览次数
+		rdb, err := redis.NewClient(config.Conf.Redis)
+		if err != nil {
+			panic(fmt.Sprintf("清空Redis数据失败: %v", err))
+		}
+		redis.SetClient(rdb)
+		err = service.ResetHouseData(context.Background())
+		rdb.Close()
+		if err != nil {
+			panic(fmt.Sprintf("清空Redis数据失败: %v", err))
+		}
+		fmt.Println("已清空业务数据和Redis中的房源数据")
+	} else {
+		var count int64
+		if err := db.Model(&model.User{}).Count(&count).Error; err != nil {
+			panic(fmt.Sprintf("查询用户数量失败: %v", err))
+		}
+		if count > 0 {
+			fmt.Println("数据库中已有数据，如需重新生成请使用 -reset 参数")
+			os.Exit(1)
+		}
+	}
+
+	s, err := newSeeder(db, *seed, preset, now)
+	if err != nil {
+		panic(fmt.Sprintf("初始化数据生成器失败: %v", err))
+	}
+
+	fmt.Printf("开始生成测试数据（规模：%s，种子：%d）...\n", *size, *seed)
+	steps := []struct {
+		name string
+		fn   func() (int, error)
+	}{
+		{"用户", s.seedUsers},
+		{"房东", s.seedLandlords},
+		{"房源", s.seedHouses},
+		{"收藏", s.seedFavorites},
+		{"预约看房", s.seedViewings},
+		{"短信记录", s.seedSMSRecords},
+	}
+	for _, step := range steps {
+		count, err := step.fn()
+		if err != nil {
+			panic(fmt.Sprintf("生成%s失败: %v", step.name, err))
+		}
+		fmt.Printf("已生成%d条%s\n", count, step.name)
+	}
+
+	fmt.Printf("测试数据生成完成！管理员账号 admin，所有用户密码均为 %s\n", seedPassword)
+}
+
+// resetData 清空生成的业务数据，行政区划和迁移记录保留
+func resetData(db *gorm.DB) error {
+	for _, table := range resetTables {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(table); err != nil {
+			return err
+		}
+		// 清空数据的同时重置自增ID，保证相同种子生成的数据ID一致
+		if db.Dialector.Name() == "mysql" {
+			if err := db.Exec("TRUNCATE TABLE " + stmt.Quote(stmt.Schema.Table)).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err := db.Exec("DELETE FROM " + stmt.Quote(stmt.Schema.Table)).Error; err != nil {
+			return err
+		}
+		if err := db.Exec("DELETE FROM sqlite_sequence WHERE name = ?", stmt.Schema.Table).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newSeeder 创建测试数据生成器
+func newSeeder(db *gorm.DB, seed int64, size sizePreset, now time.Time) (*seeder, error) {
+	// 所有用户使用同一个密码，只计算一次哈希
+	hashed, err := bcrypt.GenerateFromPassword([]byte(seedPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	regions, err := region.Load(config.Conf.Region.DatasetFile)
+	if err != nil {
+		return nil, err
+	}
+	index := region.NewIndex(regions)
+	var districts [][]region.Region
+	for _, r := range regions {
+		if r.Level == region.LevelDistrict && r.HasLocation() {
+			districts = append(districts, index.Path(r.Code))
+		}
+	}
+	if len(districts) == 0 {
+		return nil, fmt.Errorf("行政区划数据集中没有可用的区县")
+	}
+
+	return &seeder{
+		db:        db.Session(&gorm.Session{NowFunc: func() time.Time { return now }}), // 自动填充的时间也使用参考时间
+		rand:      rand.New(rand.NewSource(seed)),
+		size:      size,
+		now:       now,
+		password:  string(hashed),
+		districts: districts,
+	}, nil
+}
+
+// seedUsers 生成管理员和普通用户
+func (s *seeder) seedUsers() (int, error) {
+	users := []model.User{{
+		Username: "admin",
+		Password: s.password,
+		Phone:    "13800000000",
+		RealName: "管理员",
+		Email:    "admin@example.com",
+		UserType: model.UserTypeAdmin,
+	}}
+	for i := 1; i <= s.size.Users; i++ {
+		users = append(users, s.newUser(fmt.Sprintf("user%04d", i), model.UserTypeNormal))
+	}
+
+	if err := s.db.CreateInBatches(&users, batchSize).Error; err != nil {
+		return 0, err
+	}
+	s.adminID = users[0].ID
+	s.users = users[1:]
+	return len(users), nil
+}
+
+// seedLandlords 生成房东用户和房东信息，少量房东未通过认证
+func (s *seeder) seedLandlords() (int, error) {
+	users := make([]model.User, 0, s.size.Landlords)
+	for i := 1; i <= s.size.Landlords; i++ {
+		users = append(users, s.newUser(fmt.Sprintf("landlord%04d", i), model.UserTypeLandlord))
+	}
+	if err := s.db.CreateInBatches(&users, batchSize).Error; err != nil {
+		return 0, err
+	}
+
+	landlords := make([]model.Landlord, 0, len(users))
+	for i, user := range users {
+		path := s.pickDistrict()
+		landlord := model.Landlord{
+			UserID:       user.ID,
+			RealName:     user.RealName,
+			IDNumber:     user.IdCard,
+			PhoneNumber:  user.Phone,
+			Address:      s.newAddress(path),
+			Verified:     s.rand.Intn(100) >= s.size.UnverifiedPct || i == 0,
+			IdCardFront:  fmt.Sprintf("https://example.com/idcards/%d_front.jpg", user.ID),
+			IdCardBack:   fmt.Sprintf("https://example.com/idcards/%d_back.jpg", user.ID),
+			BankAccount:  s.digits("6222", 19),
+			BankName:     pick(s.rand, banks),
+			AccountName:  user.RealName,
+			Introduction: fmt.Sprintf("您好，我是%s，房源都是自有房屋，欢迎随时联系看房。", user.RealName),
+			Rating:       5,
+		}
+		landlord.CreatedAt = user.CreatedAt
+		landlords = append(landlords, landlord)
+	}
+	if err := s.db.CreateInBatches(&landlords, batchSize).Error; err != nil {
+		return 0, err
+	}
+
+	// 每个房东都有认证申请，已认证的房东的申请已由管理员审核通过，未认证的房东的申请待审核
+	verifications := make([]model.LandlordVerification, 0, len(landlords))
+	for i, landlord := range landlords {
+		verification := model.LandlordVerification{
+			LandlordID:  landlord.ID,
+			UserID:      landlord.UserID,
+			RealName:    landlord.RealName,
+			IDNumber:    landlord.IDNumber,
+			IdCardFront: landlord.IdCardFront,
+			IdCardBack:  landlord.IdCardBack,
+			Documents:   "[]",
+			Status:      model.VerificationPending,
+		}
+		verification.CreatedAt = users[i].CreatedAt
+		if landlord.Verified {
+			reviewedAt := minTime(users[i].CreatedAt.Add(time.Duration(s.rand.Intn(72)+1)*time.Hour), s.now)
+			verification.Status = model.VerificationApproved
+			verification.ReviewerID = s.adminID
+			verification.ReviewedAt = &reviewedAt
+		}
+		verifications = append(verifications, verification)
+	}
+	if err := s.db.CreateInBatches(&verifications, batchSize).Error; err != nil {
+		return 0, err
+	}
+
+	// 只有已认证的房东才能发布房源
+	for i, landlord := range landlords {
+		if landlord.Verified {
+			s.landlords = append(s.landlords, users[i])
+		}
+	}
+	return len(landlords), nil
+}
+
+// seedHouses 为已认证的房东生成各种状态的房源，坐标位于所在区县范围内
+func (s *seeder) seedHouses() (int, error) {
+	houses := make([]model.House, 0, s.size.Houses)
+	for i := 0; i < s.size.Houses; i++ {
+		houses = append(houses, s.newHouse(s.landlords[s.rand.Intn(len(s.landlords))].ID))
+	}
+
+	// 房源状态字段默认值为草稿，创建时已下架（零值）会被替换为默认值，需要在创建后更新状态
+	var offline []int
+	for i, house := range houses {
+		if house.Status == model.HouseStatusOffline {
+			offline = append(offline, i)
+		}
+	}
+	if err := s.db.CreateInBatches(&houses, batchSize).Error; err != nil {
+		return 0, err
+	}
+	if len(offline) > 0 {
+		offlineIDs := make([]uint, len(offline))
+		for j, i := range offline {
+			houses[i].Status = model.HouseStatusOffline
+			offlineIDs[j] = houses[i].ID
+		}
+		err := s.db.Model(&model.House{}).Where("id IN ?", offlineIDs).Update("status", model.HouseStatusOffline).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// 记录初始租金，作为租金走势的起点
+	history := make([]model.HousePriceHistory, 0, len(houses))
+	for _, house := range houses {
+		history = append(history, model.HousePriceHistory{HouseID: house.ID, Price: house.RentPrice})
+	}
+	if err := s.db.CreateInBatches(&history, batchSize).Error; err != nil {
+		return 0, err
+	}
+
+	s.houses = houses
+	return len(houses), nil
+}
+
+// seedFavorites 普通用户收藏随机的公开房源
+func (s *seeder) seedFavorites() (int, error) {
+	var public []model.House
+	for _, house := range s.houses {
+		if house.IsPublic() {
+			public = append(public, house)
+		}
+	}
+	if len(public) == 0 {
+		return 0, nil
+	}
+
+	var favorites []model.Favorite
+	for _, user := range s.users {
+		n := s.rand.Intn(s.size.Favorites + 1)
+		for _, i := range s.rand.Perm(len(public))[:min(n, len(public))] {
+			favorites = append(favorites, model.Favorite{
+				UserID:          user.ID,
+				HouseID:         public[i].ID,
+				PriceAtFavorite: public[i].RentPrice,
+				NotifyPriceDrop: s.rand.Intn(4) == 0,
+			})
+		}
+	}
+	if len(favorites) == 0 {
+		return 0, nil
+	}
+	if err := s.db.CreateInBatches(&favorites, batchSize).Error; err != nil {
+		return 0, err
+	}
+	return len(favorites), nil
+}
+
+// seedViewings 生成覆盖全部状态的预约看房，已完成的看房时间在过去，待确认和已确认的在未来
+func (s *seeder) seedViewings() (int, error) {
+	var published []model.House
+	for _, house := range s.houses {
+		if house.Status == model.HouseStatusPublished {
+			published = append(published, house)
+		}
+	}
+	if len(published) == 0 || len(s.users) == 0 {
+		return 0, nil
+	}
+
+	statuses := []int{model.ViewingPending, model.ViewingConfirmed, model.ViewingCompleted, model.ViewingCancelled}
+	viewings := make([]model.Viewing, 0, s.size.Viewings)
+	for i := 0; i < s.size.Viewings; i++ {
+		user := s.users[s.rand.Intn(len(s.users))]
+		viewing := model.Viewing{
+			HouseID:      published[s.rand.Intn(len(published))].ID,
+			UserID:       user.ID,
+			Status:       statuses[i%len(statuses)],
+			Remark:       pick(s.rand, remarks),
+			ContactName:  user.RealName,
+			ContactPhone: user.Phone,
+		}
+
+		switch viewing.Status {
+		case model.ViewingPending:
+			viewing.ViewingTime = s.viewingTime(1, 14)
+		case model.ViewingConfirmed:
+			viewing.ViewingTime = s.viewingTime(1, 14)
+			confirmTime := s.now.Add(-time.Duration(s.rand.Intn(48)+1) * time.Hour)
+			viewing.ConfirmTime = &confirmTime
+		case model.ViewingCompleted:
+			viewing.ViewingTime = s.viewingTime(-60, -1)
+			confirmTime := viewing.ViewingTime.AddDate(0, 0, -1)
+			viewing.ConfirmTime = &confirmTime
+		case model.ViewingCancelled:
+			viewing.ViewingTime = s.viewingTime(-30, 14)
+			cancelTime := minTime(viewing.ViewingTime.Add(-2*time.Hour), s.now)
+			viewing.CancelTime = &cancelTime
+			viewing.CancelReason = pick(s.rand, cancels)
+		}
+		viewings = append(viewings, viewing)
+	}
+
+	if err := s.db.CreateInBatches(&viewings, batchSize).Error; err != nil {
+		return 0, err
+	}
+	return len(viewings), nil
+}
+
+// seedSMSRecords 为用户生成验证码短信记录，少量发送失败
+func (s *seeder) seedSMSRecords() (int, error) {
+	var records []model.SMSRecord
+	for _, user := range append(append([]model.User{}, s.users...), s.landlords...) {
+		for n := s.rand.Intn(s.size.SMSPerUser + 1); n > 0; n-- {
+			code := s.digits("", 6)
+			record := model.SMSRecord{
+				Phone:      user.Phone,
+				Code:       code,
+				TemplateID: config.Conf.SMS.Aliyun.TemplateCode,
+				Content:    fmt.Sprintf(`{"code":"%s"}`, code),
+				Status:     true,
+				Provider:   "aliyun",
+				IPAddress:  fmt.Sprintf("192.168.%d.%d", s.rand.Intn(256), s.rand.Intn(254)+1),
+				UserAgent:  "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)",
+				BizId:      s.digits("", 18),
+				RequestId:  fmt.Sprintf("%08X-%04X-%04X", s.rand.Uint32(), s.rand.Intn(1<<16), s.rand.Intn(1<<16)),
+			}
+			record.CreatedAt = s.now.Add(-time.Duration(s.rand.Intn(30*24*60)) * time.Minute)
+			if s.rand.Intn(10) == 0 {
+				record.Status = false
+				record.BizId = ""
+				record.FailReason = "触发号码天级流控"
+			}
+			records = append(records, record)
+		}
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+	if err := s.db.CreateInBatches(&records, batchSize).Error; err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// newUser 生成一个用户，注册时间在过去一年内
+func (s *seeder) newUser(username string, userType int) model.User {
+	name := pick(s.rand, surnames) + pick(s.rand, givenNames)
+	user := model.User{
+		Username: username,
+		Password: s.password,
+		Phone:    s.digits(pick(s.rand, []string{"138", "139", "150", "186", "177"}), 11),
+		Avatar:   fmt.Sprintf("https://example.com/avatars/%s.png", username),
+		RealName: name,
+		IdCard:   s.idNumber(),
+		Email:    username + "@example.com",
+		UserType: userType,
+	}
+	user.CreatedAt = s.now.AddDate(0, 0, -s.rand.Intn(365))
+	lastLogin := user.CreatedAt.Add(time.Duration(s.rand.Int63n(int64(s.now.Sub(user.CreatedAt)) + 1)))
+	user.LastLogin = &lastLogin
+	return user
+}
+
+// newHouse 生成一个房源，大部分已发布，其余分布在草稿、待审核、已出租、已下架和审核驳回状态
+func (s *seeder) newHouse(landlordID uint) model.House {
+	path := s.pickDistrict()
+	district := path[len(path)-1]
+	rooms := s.rand.Intn(4) + 1
+	area := float64(rooms*25+s.rand.Intn(40)) + float64(s.rand.Intn(10))/10
+	rent := math.Round(area*float64(s.rand.Intn(80)+40)/10) * 10
+	totalFloor := s.rand.Intn(30) + 3
+	community := pick(s.rand, communities)
+
+	// 坐标在区县中心点附近，不超过覆盖半径的一半
+	distance := district.Radius / 2 * math.Sqrt(s.rand.Float64())
+	angle := s.rand.Float64() * 2 * math.Pi
+	latitude := district.Latitude + distance/111*math.Cos(angle)
+	longitude := district.Longitude + distance/(111*math.Cos(district.Latitude*math.Pi/180))*math.Sin(angle)
+
+	house := model.House{
+		Title:       fmt.Sprintf("%s %d室%d厅 %s %s", community, rooms, s.rand.Intn(2)+1, pick(s.rand, orientation), pick(s.rand, highlights)),
+		Description: fmt.Sprintf("%s%s，%d平米，%s。小区环境优美，物业管理完善。", district.Name, community, int(area), strings.Join(s.sample(highlights, 3), "，")),
+		Address:     s.newAddress(path) + community,
+		Area:        area,
+		Floor:       s.rand.Intn(totalFloor) + 1,
+		TotalFloor:  totalFloor,
+		Rooms:       rooms,
+		Halls:       s.rand.Intn(2) + 1,
+		Bathrooms:   s.rand.Intn(rooms) + 1,
+		RentPrice:   rent,
+		Deposit:     rent,
+		PaymentType: s.rand.Intn(4) + 1,
+		HouseType:   s.rand.Intn(4) + 1,
+		Orientation: pick(s.rand, orientation),
+		Decoration:  s.rand.Intn(3) + 1,
+		Facilities:  toJSON(s.sample(facilities, s.rand.Intn(8)+3)),
+		Images:      toJSON(s.images(landlordID)),
+		Latitude:    math.Round(latitude*1e6) / 1e6,
+		Longitude:   math.Round(longitude*1e6) / 1e6,
+		IsElevator:  totalFloor > 7,
+		LandlordID:  landlordID,
+		ViewCount:   s.rand.Intn(500),
+	}
+	for _, r := range path {
+		switch r.Level {
+		case region.LevelProvince:
+			house.ProvinceCode = r.Code
+		case region.LevelCity:
+			house.CityCode = r.Code
+		case region.LevelDistrict:
+			house.DistrictCode = r.Code
+		}
+	}
+	house.CreatedAt = s.now.AddDate(0, 0, -s.rand.Intn(90))
+
+	publishedAt := house.CreatedAt.Add(time.Duration(s.rand.Intn(48)) * time.Hour)
+	expireAt := s.now.AddDate(0, 0, s.rand.Intn(config.Conf.House.ListingTTLDays)+1)
+	switch n := s.rand.Intn(100); {
+	case n < 65:
+		house.Status = model.HouseStatusPublished
+		house.PublishedAt, house.ExpireAt = &publishedAt, &expireAt
+	case n < 73:
+		house.Status = model.HouseStatusRented
+		house.PublishedAt = &publishedAt
+	case n < 81:
+		house.Status = model.HouseStatusOffline
+		house.PublishedAt = &publishedAt
+	case n < 88:
+		house.Status = model.HouseStatusPending
+	case n < 94:
+		house.Status = model.HouseStatusDraft
+	default:
+		house.Status = model.HouseStatusRejected
+		house.RejectReason = "房源图片与描述不符"
+	}
+	return house
+}
+
+// newAddress 根据区划路径生成街道门牌地址，直辖市省市同名时只写一次
+func (s *seeder) newAddress(path []region.Region) string {
+	var b strings.Builder
+	for i, r := range path {
+		if i > 0 && r.Name == path[i-1].Name {
+			continue
+		}
+		b.WriteString(r.Name)
+	}
+	fmt.Fprintf(&b, "%s%d号", pick(s.rand, streets), s.rand.Intn(300)+1)
+	return b.String()
+}
+
+// pickDistrict 随机选择一个有中心点的区县，返回从省级开始的路径
+func (s *seeder) pickDistrict() []region.Region {
+	return s.districts[s.rand.Intn(len(s.districts))]
+}
+
+// images 生成3到6张房源图片地址
+func (s *seeder) images(landlordID uint) []string {
+	n := s.rand.Intn(4) + 3
+	prefix := s.rand.Int63()
+	images := make([]string, n)
+	for i := range images {
+		images[i] = fmt.Sprintf("https://example.com/houses/%d/%x_%d.jpg", landlordID, prefix, i+1)
+	}
+	return images
+}
+
+// viewingTime 生成距今fromDays到toDays天之间、9点到20点之间的整点看房时间
+func (s *seeder) viewingTime(fromDays, toDays int) time.Time {
+	day := s.now.AddDate(0, 0, fromDays+s.rand.Intn(toDays-fromDays+1))
+	return time.Date(day.Year(), day.Month(), day.Day(), 9+s.rand.Intn(12), 0, 0, 0, day.Location())
+}
+
+// sample 从列表中不重复地随机选取n个元素，保持原有顺序
+func (s *seeder) sample(list []string, n int) []string {
+	picked := s.rand.Perm(len(list))[:min(n, len(list))]
+	result := make([]string, 0, len(picked))
+	for i, item := range list {
+		for _, p := range picked {
+			if p == i {
+				result = append(result, item)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// idNumber 生成能通过校验的身份证号，地区码为随机的区县，年龄在20到60岁之间
+func (s *seeder) idNumber() string {
+	path := s.pickDistrict()
+	birthday := s.now.AddDate(-20-s.rand.Intn(40), 0, -s.rand.Intn(365))
+	body := s.digits(path[len(path)-1].Code+birthday.Format("20060102"), 17)
+	return body + string(idcard.CheckCode(body))
+}
+
+// digits 以prefix开头生成总长度为length的数字串
+func (s *seeder) digits(prefix string, length int) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	for b.Len() < length {
+		b.WriteByte(byte('0' + s.rand.Intn(10)))
+	}
+	return b.String()
+}
+
+// pick 随机选取一个元素
+func pick(r *rand.Rand, list []string) string {
+	return list[r.Intn(len(list))]
+}
+
+// minTime 返回较早的时间
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// toJSON 将字符串列表序列化为JSON数组
+func toJSON(list []string) string {
+	data, _ := json.Marshal(list)
+	return string(data)
+}
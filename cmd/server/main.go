@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"myApp/app"
 	"myApp/config"
-	"myApp/model"
 	"myApp/pkg/logger"
-	"myApp/pkg/redis"
+	"myApp/pkg/scheduler"
 	"myApp/router"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,11 +23,12 @@ func main() {
 	// 记录应用启动日志
 	logger.WithField("mode", config.Conf.Server.Mode).Info("应用启动中")
 
-	// 初始化数据库
-	model.InitDB()
+	// 初始化应用容器：数据库、Redis、短信服务提供商及各层依赖
+	a := app.Init()
+	defer a.Close()
 
-	// 初始化Redis
-	redis.InitRedis()
+	// 启动后台定时任务
+	startScheduledTasks(a)
 
 	// 设置Gin运行模式
 	if config.Conf.Server.Mode == "release" {
@@ -38,8 +41,28 @@ func main() {
 	r := gin.New()
 	r.Use(gin.Recovery())
 
+	// 只信任配置的反向代理转发的客户端IP，未配置时使用连接的对端地址，防止伪造X-Forwarded-For绕过限流
+	if err := r.SetTrustedProxies(config.Conf.Server.TrustedProxies); err != nil {
+		logger.WithError(err).Error("信任的代理配置无效")
+		fmt.Printf("信任的代理配置无效: %v\n", err)
+		return
+	}
+
 	// 初始化路由
-	router.SetupRouter(r)
+	router.SetupRouter(r, a)
+
+	// 监控指标使用单独的端口提供，不经过业务接口的中间件
+	if addr := config.Conf.Server.MetricsAddr; addr != "" {
+		metricsRouter := gin.New()
+		metricsRouter.Use(gin.Recovery())
+		router.SetupMetricsRouter(metricsRouter)
+		go func() {
+			logger.WithField("addr", addr).Info("监控指标服务启动")
+			if err := metricsRouter.Run(addr); err != nil {
+				logger.WithError(err).Error("监控指标服务启动失败")
+			}
+		}()
+	}
 
 	// 启动HTTP服务
 	fmt.Printf("\n🚀 服务端启动成功，监听端口 %d\n", config.Conf.Server.Port)
@@ -49,3 +72,31 @@ func main() {
 		return
 	}
 }
+
+// startScheduledTasks 启动后台定时任务
+func startScheduledTasks(a *app.App) {
+	houseService := a.Services.House
+
+	// 定期下架超过上架有效期的房源
+	interval := time.Duration(config.Conf.House.ExpireCheckInterval) * time.Second
+	scheduler.Every("house_expire", interval, func() error {
+		count, err := houseService.ExpireListings(context.Background())
+		if count > 0 {
+			logger.Infof("已下架%d个到期房源", count)
+		}
+		return err
+	})
+
+	// 定期将Redis中累加的浏览次数写回数据库
+	flushInterval := time.Duration(config.Conf.House.ViewFlushInterval) * time.Second
+	scheduler.Every("house_view_flush", flushInterval, func() error {
+		_, err := houseService.FlushViewCounts(context.Background())
+		return err
+	})
+
+	// 定期清理热度过低的房源
+	scheduler.Every("house_trending_decay", time.Hour, func() error {
+		_, err := houseService.DecayTrending(context.Background())
+		return err
+	})
+}
@@ -11,21 +11,27 @@ import (
 
 // Config 用于存储所有配置项
 type Config struct {
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	Server   ServerConfig   `mapstructure:"server"`
-	SMS      SMSConfig      `mapstructure:"sms"`
-	Logger   LoggerConfig   `mapstructure:"logger"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	Redis     RedisConfig     `mapstructure:"redis"`
+	JWT       JWTConfig       `mapstructure:"jwt"`
+	Server    ServerConfig    `mapstructure:"server"`
+	SMS       SMSConfig       `mapstructure:"sms"`
+	Logger    LoggerConfig    `mapstructure:"logger"`
+	House     HouseConfig     `mapstructure:"house"`
+	Geo       GeoConfig       `mapstructure:"geo"`
+	Region    RegionConfig    `mapstructure:"region"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	CORS      CORSConfig      `mapstructure:"cors"`
 }
 
 // DatabaseConfig 数据库相关配置
 type DatabaseConfig struct {
+	Driver   string `mapstructure:"driver" env:"DATABASE_DRIVER"`     // 数据库驱动：mysql（默认）或sqlite
 	Host     string `mapstructure:"host" env:"DATABASE_HOST"`         // 数据库主机地址
 	Port     int    `mapstructure:"port" env:"DATABASE_PORT"`         // 数据库端口
 	User     string `mapstructure:"user" env:"DATABASE_USER"`         // 数据库用户名
 	Password string `mapstructure:"password" env:"DATABASE_PASSWORD"` // 数据库密码
-	DBName   string `mapstructure:"dbname" env:"DATABASE_NAME"`       // 数据库名称
+	DBName   string `mapstructure:"dbname" env:"DATABASE_NAME"`       // 数据库名称，sqlite驱动时为数据库文件路径（:memory:为内存数据库）
 }
 
 // RedisConfig Redis相关配置
@@ -41,14 +47,18 @@ type JWTConfig struct {
 }
 
 type ServerConfig struct {
-	Port int    `mapstructure:"port" env:"SERVER_PORT"`
-	Mode string `mapstructure:"mode" env:"SERVER_MODE"` // 运行模式：debug或release
+	Port           int      `mapstructure:"port" env:"SERVER_PORT"`
+	Mode           string   `mapstructure:"mode" env:"SERVER_MODE"`                       // 运行模式：debug或release
+	RequestTimeout int      `mapstructure:"request_timeout" env:"SERVER_REQUEST_TIMEOUT"` // 单个请求的处理超时时间（秒）
+	TrustedProxies []string `mapstructure:"trusted_proxies" env:"SERVER_TRUSTED_PROXIES"` // 信任的反向代理IP或CIDR网段，只有来自这些地址的请求才按X-Forwarded-For取客户端IP，为空时不信任任何代理
+	MetricsAddr    string   `mapstructure:"metrics_addr" env:"SERVER_METRICS_ADDR"`       // 监控指标服务的监听地址，如127.0.0.1:9090，为空时不提供监控指标
 }
 
 // SMSConfig 短信服务配置
 type SMSConfig struct {
-	Provider string          `mapstructure:"provider" env:"SMS_PROVIDER"` // 短信服务提供商，如aliyun
-	Aliyun   AliyunSMSConfig `mapstructure:"aliyun"`                      // 阿里云短信配置
+	Provider            string          `mapstructure:"provider" env:"SMS_PROVIDER"`                           // 短信服务提供商，如aliyun
+	Aliyun              AliyunSMSConfig `mapstructure:"aliyun"`                                                // 阿里云短信配置
+	SearchAlertTemplate string          `mapstructure:"search_alert_template" env:"SMS_SEARCH_ALERT_TEMPLATE"` // 保存的搜索有新房源时的短信模板ID，为空时不发送短信提醒
 }
 
 // AliyunSMSConfig 阿里云短信配置
@@ -71,6 +81,82 @@ type LoggerConfig struct {
 	Console    bool   `mapstructure:"console" env:"LOGGER_CONSOLE"`         // 是否同时输出到控制台
 }
 
+// HouseConfig 房源发布与审核配置
+type HouseConfig struct {
+	ListingTTLDays      int      `mapstructure:"listing_ttl_days" env:"HOUSE_LISTING_TTL_DAYS"`           // 房源上架有效期（天），到期后需房东刷新
+	ExpireCheckInterval int      `mapstructure:"expire_check_interval" env:"HOUSE_EXPIRE_CHECK_INTERVAL"` // 过期房源检查间隔（秒）
+	PriceOutlierRatio   float64  `mapstructure:"price_outlier_ratio"`                                     // 单价偏离同类房源均值的倍数阈值，超过则标记为价格异常
+	BannedWords         []string `mapstructure:"banned_words"`                                            // 房源标题、描述中禁止出现的词语
+	ViewDedupeWindow    int      `mapstructure:"view_dedupe_window" env:"HOUSE_VIEW_DEDUPE_WINDOW"`       // 同一访客重复浏览不计数的时间窗口（秒）
+	ViewFlushInterval   int      `mapstructure:"view_flush_interval" env:"HOUSE_VIEW_FLUSH_INTERVAL"`     // 浏览次数写回数据库的间隔（秒）
+	TrendingHalfLife    int      `mapstructure:"trending_half_life" env:"HOUSE_TRENDING_HALF_LIFE"`       // 热度半衰期（小时），每经过一个半衰期热度减半
+	AlertInterval       int      `mapstructure:"alert_interval" env:"HOUSE_ALERT_INTERVAL"`               // 处理新房源提醒队列的间隔（秒）
+}
+
+// RegionConfig 行政区划配置
+type RegionConfig struct {
+	DatasetFile string `mapstructure:"dataset_file" env:"REGION_DATASET_FILE"` // 完整行政区划数据集文件路径，为空时使用内置的示例数据集
+}
+
+// GeoConfig 地理编码配置
+type GeoConfig struct {
+	Provider string        `mapstructure:"provider" env:"GEO_PROVIDER"` // 地理编码服务提供商：local-离线（按行政区划中心点），amap-高德地图
+	Timeout  int           `mapstructure:"timeout" env:"GEO_TIMEOUT"`   // 调用在线地理编码服务的超时时间（毫秒）
+	Amap     AmapGeoConfig `mapstructure:"amap"`                        // 高德地图配置
+}
+
+// AmapGeoConfig 高德地图地理编码配置
+type AmapGeoConfig struct {
+	Key string `mapstructure:"key" env:"GEO_AMAP_KEY"` // 高德地图Web服务Key
+}
+
+// RateLimitConfig 请求限流配置
+type RateLimitConfig struct {
+	Default   RateLimitPolicy   `mapstructure:"default"`                              // 未单独配置的接口使用的规则
+	Routes    []RateLimitPolicy `mapstructure:"routes"`                               // 按接口配置的规则，匹配的接口不再使用默认规则
+	Allowlist []string          `mapstructure:"allowlist" env:"RATE_LIMIT_ALLOWLIST"` // 不限流的IP或CIDR网段，环境变量中多个用逗号分隔
+}
+
+// RateLimitPolicy 限流规则，每period秒最多rate个请求，允许瞬时突发burst个请求
+type RateLimitPolicy struct {
+	Method string `mapstructure:"method"`                                 // 请求方法，为空时匹配全部方法，仅按接口配置的规则使用
+	Path   string `mapstructure:"path"`                                   // 接口路由，如/api/house/:id，仅按接口配置的规则使用
+	Key    string `mapstructure:"key" env:"RATE_LIMIT_DEFAULT_KEY"`       // 限流维度：ip-客户端IP，user-登录用户（未登录时按IP），route-接口的全部请求共用额度
+	Rate   int    `mapstructure:"rate" env:"RATE_LIMIT_DEFAULT_RATE"`     // 每个周期允许的请求数，为0时不限流
+	Period int    `mapstructure:"period" env:"RATE_LIMIT_DEFAULT_PERIOD"` // 周期（秒）
+	Burst  int    `mapstructure:"burst" env:"RATE_LIMIT_DEFAULT_BURST"`   // 瞬时突发请求数，为0时等于rate
+}
+
+// defaultRateLimitRoutes 未配置按接口的规则时使用的规则，发送验证码和登录接口按IP限制得更严格
+var defaultRateLimitRoutes = []RateLimitPolicy{
+	{Method: "POST", Path: "/api/user/sms/code", Key: "ip", Rate: 5, Period: 60, Burst: 1},
+	{Method: "POST", Path: "/api/user/sms/login", Key: "ip", Rate: 10, Period: 60, Burst: 5},
+	{Method: "POST", Path: "/api/user/login", Key: "ip", Rate: 10, Period: 60, Burst: 5},
+}
+
+// CORSConfig 跨域资源共享配置
+type CORSConfig struct {
+	AllowOrigins     []string `mapstructure:"allow_origins" env:"CORS_ALLOW_ORIGINS"`         // 允许的来源，支持*和https://*.example.com形式的通配子域名
+	AllowMethods     []string `mapstructure:"allow_methods" env:"CORS_ALLOW_METHODS"`         // 允许的请求方法
+	AllowHeaders     []string `mapstructure:"allow_headers" env:"CORS_ALLOW_HEADERS"`         // 允许携带的请求头
+	ExposeHeaders    []string `mapstructure:"expose_headers" env:"CORS_EXPOSE_HEADERS"`       // 允许浏览器读取的响应头
+	AllowCredentials bool     `mapstructure:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"` // 是否允许携带Cookie等凭证
+	MaxAge           int      `mapstructure:"max_age" env:"CORS_MAX_AGE"`                     // 预检请求结果的缓存时间（秒）
+}
+
+// Validate 检查跨域配置，允许携带凭证时不能允许任意来源，否则任意网站都能以用户的身份调用接口
+func (c CORSConfig) Validate() error {
+	if !c.AllowCredentials {
+		return nil
+	}
+	for _, origin := range c.AllowOrigins {
+		if strings.TrimSpace(origin) == "*" {
+			return fmt.Errorf("cors.allow_credentials为true时allow_origins不能包含\"*\"，请配置具体的来源")
+		}
+	}
+	return nil
+}
+
 var Conf *Config
 
 // InitConfig 初始化配置文件
@@ -101,6 +187,7 @@ func InitConfig() {
 
 	// 绑定环境变量
 	// 数据库配置
+	viper.BindEnv("database.driver", "DATABASE_DRIVER")
 	viper.BindEnv("database.host", "DATABASE_HOST")
 	viper.BindEnv("database.port", "DATABASE_PORT")
 	viper.BindEnv("database.user", "DATABASE_USER")
@@ -118,6 +205,9 @@ func InitConfig() {
 	// 服务器配置
 	viper.BindEnv("server.port", "SERVER_PORT")
 	viper.BindEnv("server.mode", "SERVER_MODE")
+	viper.BindEnv("server.request_timeout", "SERVER_REQUEST_TIMEOUT")
+	viper.BindEnv("server.trusted_proxies", "SERVER_TRUSTED_PROXIES")
+	viper.BindEnv("server.metrics_addr", "SERVER_METRICS_ADDR")
 
 	// 短信服务配置
 	viper.BindEnv("sms.provider", "SMS_PROVIDER")
@@ -126,6 +216,38 @@ func InitConfig() {
 	viper.BindEnv("sms.aliyun.region_id", "SMS_ALIYUN_REGION_ID")
 	viper.BindEnv("sms.aliyun.sign_name", "SMS_ALIYUN_SIGN_NAME")
 	viper.BindEnv("sms.aliyun.template_code", "SMS_ALIYUN_TEMPLATE_CODE")
+	viper.BindEnv("sms.search_alert_template", "SMS_SEARCH_ALERT_TEMPLATE")
+
+	// 房源配置
+	viper.BindEnv("house.listing_ttl_days", "HOUSE_LISTING_TTL_DAYS")
+	viper.BindEnv("house.expire_check_interval", "HOUSE_EXPIRE_CHECK_INTERVAL")
+	viper.BindEnv("house.view_dedupe_window", "HOUSE_VIEW_DEDUPE_WINDOW")
+	viper.BindEnv("house.view_flush_interval", "HOUSE_VIEW_FLUSH_INTERVAL")
+	viper.BindEnv("house.trending_half_life", "HOUSE_TRENDING_HALF_LIFE")
+	viper.BindEnv("house.alert_interval", "HOUSE_ALERT_INTERVAL")
+
+	// 行政区划配置
+	viper.BindEnv("region.dataset_file", "REGION_DATASET_FILE")
+
+	// 地理编码配置
+	viper.BindEnv("geo.provider", "GEO_PROVIDER")
+	viper.BindEnv("geo.timeout", "GEO_TIMEOUT")
+	viper.BindEnv("geo.amap.key", "GEO_AMAP_KEY")
+
+	// 请求限流配置
+	viper.BindEnv("rate_limit.default.key", "RATE_LIMIT_DEFAULT_KEY")
+	viper.BindEnv("rate_limit.default.rate", "RATE_LIMIT_DEFAULT_RATE")
+	viper.BindEnv("rate_limit.default.period", "RATE_LIMIT_DEFAULT_PERIOD")
+	viper.BindEnv("rate_limit.default.burst", "RATE_LIMIT_DEFAULT_BURST")
+	viper.BindEnv("rate_limit.allowlist", "RATE_LIMIT_ALLOWLIST")
+
+	// 跨域配置
+	viper.BindEnv("cors.allow_origins", "CORS_ALLOW_ORIGINS")
+	viper.BindEnv("cors.allow_methods", "CORS_ALLOW_METHODS")
+	viper.BindEnv("cors.allow_headers", "CORS_ALLOW_HEADERS")
+	viper.BindEnv("cors.expose_headers", "CORS_EXPOSE_HEADERS")
+	viper.BindEnv("cors.allow_credentials", "CORS_ALLOW_CREDENTIALS")
+	viper.BindEnv("cors.max_age", "CORS_MAX_AGE")
 
 	// 将配置文件中的内容映射到结构体Config
 	if err := viper.Unmarshal(&Conf); err != nil {
@@ -133,8 +255,11 @@ func InitConfig() {
 	}
 
 	// 检查配置文件中是否有必需的配置项
-	// 如果缺少必要的数据库配置，直接报错
-	if Conf.Database.Host == "" || Conf.Database.Port == 0 || Conf.Database.User == "" || Conf.Database.DBName == "" {
+	// 如果缺少必要的数据库配置，直接报错，sqlite驱动只需要数据库文件路径
+	if Conf.Database.Driver == "" {
+		Conf.Database.Driver = "mysql"
+	}
+	if Conf.Database.DBName == "" || Conf.Database.Driver == "mysql" && (Conf.Database.Host == "" || Conf.Database.Port == 0 || Conf.Database.User == "") {
 		log.Fatal("缺少必需的数据库配置项")
 	}
 
@@ -160,6 +285,77 @@ func InitConfig() {
 	if Conf.Server.Mode == "" {
 		Conf.Server.Mode = "debug"
 	}
+	if Conf.Server.RequestTimeout <= 0 {
+		Conf.Server.RequestTimeout = 30
+	}
+
+	// 房源配置未设置时使用默认值
+	if Conf.House.ListingTTLDays <= 0 {
+		Conf.House.ListingTTLDays = 30
+	}
+	if Conf.House.ExpireCheckInterval <= 0 {
+		Conf.House.ExpireCheckInterval = 3600
+	}
+	if Conf.House.PriceOutlierRatio <= 1 {
+		Conf.House.PriceOutlierRatio = 3
+	}
+	if Conf.House.ViewDedupeWindow <= 0 {
+		Conf.House.ViewDedupeWindow = 1800
+	}
+	if Conf.House.ViewFlushInterval <= 0 {
+		Conf.House.ViewFlushInterval = 60
+	}
+	if Conf.House.TrendingHalfLife <= 0 {
+		Conf.House.TrendingHalfLife = 48
+	}
+	if Conf.House.AlertInterval <= 0 {
+		Conf.House.AlertInterval = 5
+	}
+
+	// 地理编码配置未设置时使用离线实现
+	if Conf.Geo.Provider == "" {
+		Conf.Geo.Provider = "local"
+	}
+	if Conf.Geo.Timeout <= 0 {
+		Conf.Geo.Timeout = 3000
+	}
+
+	// 限流配置未设置时每个IP每秒最多100个请求
+	if !viper.IsSet("rate_limit.default.rate") {
+		Conf.RateLimit.Default.Rate = 100
+	}
+	if Conf.RateLimit.Default.Period <= 0 {
+		Conf.RateLimit.Default.Period = 1
+	}
+	if Conf.RateLimit.Default.Key == "" {
+		Conf.RateLimit.Default.Key = "ip"
+	}
+	if Conf.RateLimit.Routes == nil {
+		Conf.RateLimit.Routes = defaultRateLimitRoutes
+	}
+
+	// 跨域配置未设置允许的来源时，release模式只允许同源请求，其他模式允许任意来源便于本地调试
+	if !viper.IsSet("cors.allow_origins") && Conf.Server.Mode != "release" {
+		Conf.CORS.AllowOrigins = []string{"*"}
+	}
+	if len(Conf.CORS.AllowMethods) == 0 {
+		Conf.CORS.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	}
+	if len(Conf.CORS.AllowHeaders) == 0 {
+		Conf.CORS.AllowHeaders = []string{"Content-Type", "Authorization"}
+	}
+	if !viper.IsSet("cors.expose_headers") {
+		Conf.CORS.ExposeHeaders = []string{"Content-Disposition", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"}
+	}
+	if !viper.IsSet("cors.max_age") {
+		Conf.CORS.MaxAge = 600
+		if Conf.Server.Mode == "release" {
+			Conf.CORS.MaxAge = 86400
+		}
+	}
+	if err := Conf.CORS.Validate(); err != nil {
+		log.Fatal(err)
+	}
 
 	fmt.Println("服务器端口:", Conf.Server.Port)
 	fmt.Println("服务器模式:", Conf.Server.Mode)
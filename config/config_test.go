@@ -0,0 +1,20 @@
+package config
+
+import "testing"
+
+func TestCORSConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     CORSConfig
+		wantErr bool
+	}{
+		{"任意来源不携带凭证", CORSConfig{AllowOrigins: []string{"*"}}, false},
+		{"具体来源携带凭证", CORSConfig{AllowOrigins: []string{"https://www.example.com", "https://*.example.com"}, AllowCredentials: true}, false},
+		{"任意来源携带凭证", CORSConfig{AllowOrigins: []string{"https://www.example.com", " * "}, AllowCredentials: true}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v，期望返回错误为%v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -47,3 +47,17 @@ type PaginationResponse struct {
 	PageSize int   `json:"page_size"` // 每页数量
 	Pages    int   `json:"pages"`     // 总页数
 }
+
+// NewPaginationResponse 根据总记录数和分页参数构建分页响应
+func NewPaginationResponse(total int64, page, pageSize int) PaginationResponse {
+	pages := 0
+	if pageSize > 0 {
+		pages = int((total + int64(pageSize) - 1) / int64(pageSize))
+	}
+	return PaginationResponse{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Pages:    pages,
+	}
+}
@@ -8,8 +8,36 @@ import (
 
 // 添加收藏请求DTO
 type AddRequest struct {
-	HouseID uint   `json:"house_id" binding:"required" example:"1"`            // 房源ID
-	Notes   string `json:"notes" binding:"omitempty" example:"这套房子采光很好，地段也不错"` // 收藏备注
+	HouseID  uint   `json:"house_id" binding:"required" example:"1"`                    // 房源ID
+	Notes    string `json:"notes" binding:"omitempty,max=500" example:"这套房子采光很好，地段也不错"` // 收藏备注
+	FolderID uint   `json:"folder_id" example:"0"`                                      // 收藏夹ID，0表示默认收藏夹
+}
+
+// 设置降价提醒请求DTO
+type PriceAlertRequest struct {
+	Enabled *bool `json:"enabled" binding:"required" example:"true"` // 是否开启降价提醒
+}
+
+// 修改收藏备注请求DTO
+type NotesRequest struct {
+	Notes string `json:"notes" binding:"omitempty,max=500" example:"周末约了看房"` // 收藏备注，为空表示清空备注
+}
+
+// 移动收藏到收藏夹请求DTO
+type MoveRequest struct {
+	FolderID *uint `json:"folder_id" binding:"required" example:"1"` // 目标收藏夹ID，0表示默认收藏夹
+}
+
+// 创建或重命名收藏夹请求DTO
+type FolderRequest struct {
+	Name string `json:"name" binding:"required,max=50" example:"通勤方便"` // 收藏夹名称
+}
+
+// 收藏列表查询请求DTO
+type ListQueryRequest struct {
+	FolderID                 *uint  `form:"folder_id" example:"1"` // 收藏夹ID，0表示默认收藏夹，不传表示全部
+	Keyword                  string `form:"keyword" example:"采光好"` // 关键词（搜索备注）
+	common.PaginationRequest        // 分页参数
 }
 
 // 收藏查询请求DTO
@@ -25,3 +53,15 @@ func ValidateAddRequest(req AddRequest) error {
 	validate := validator.New()
 	return validate.Struct(req)
 }
+
+// ValidateNotesRequest 验证修改收藏备注请求
+func ValidateNotesRequest(req NotesRequest) error {
+	validate := validator.New()
+	return validate.Struct(req)
+}
+
+// ValidateFolderRequest 验证创建或重命名收藏夹请求
+func ValidateFolderRequest(req FolderRequest) error {
+	validate := validator.New()
+	return validate.Struct(req)
+}
@@ -1,34 +1,58 @@
 package favorite
 
 import (
+	"myApp/dto/common"
 	"myApp/dto/house"
 	"time"
 )
 
 // 收藏基本信息DTO
 type BasicInfoDTO struct {
-	ID        uint      `json:"id"`         // 收藏ID
-	UserID    uint      `json:"user_id"`    // 用户ID
-	HouseID   uint      `json:"house_id"`   // 房源ID
-	Notes     string    `json:"notes"`      // 收藏备注
-	CreatedAt time.Time `json:"created_at"` // 创建时间
+	ID              uint      `json:"id"`                // 收藏ID
+	UserID          uint      `json:"user_id"`           // 用户ID
+	HouseID         uint      `json:"house_id"`          // 房源ID
+	FolderID        uint      `json:"folder_id"`         // 收藏夹ID，0表示默认收藏夹
+	Notes           string    `json:"notes"`             // 收藏备注
+	PriceAtFavorite float64   `json:"price_at_favorite"` // 收藏时的租金
+	CreatedAt       time.Time `json:"created_at"`        // 创建时间
 }
 
 // 收藏详细信息DTO
 type DetailDTO struct {
-	ID        uint               `json:"id"`         // 收藏ID
-	UserID    uint               `json:"user_id"`    // 用户ID
-	HouseID   uint               `json:"house_id"`   // 房源ID
-	Notes     string             `json:"notes"`      // 收藏备注
-	House     house.BasicInfoDTO `json:"house"`      // 房源基本信息
-	CreatedAt time.Time          `json:"created_at"` // 创建时间
-	UpdatedAt time.Time          `json:"updated_at"` // 更新时间
+	ID              uint                `json:"id"`                // 收藏ID
+	UserID          uint                `json:"user_id"`           // 用户ID
+	HouseID         uint                `json:"house_id"`          // 房源ID
+	FolderID        uint                `json:"folder_id"`         // 收藏夹ID，0表示默认收藏夹
+	Notes           string              `json:"notes"`             // 收藏备注
+	NotifyPriceDrop bool                `json:"notify_price_drop"` // 是否开启降价提醒
+	PriceAtFavorite float64             `json:"price_at_favorite"` // 收藏时的租金
+	PriceChange     float64             `json:"price_change"`      // 当前租金相对收藏时的变化，负数表示降价
+	PriceChanged    bool                `json:"price_changed"`     // 收藏后租金是否有变化
+	HouseAvailable  bool                `json:"house_available"`   // 房源当前是否可租
+	HouseStatusText string              `json:"house_status_text"` // 房源当前状态说明，如在租、已下架、已删除
+	House           *house.BasicInfoDTO `json:"house"`             // 房源基本信息，房源已删除时为null
+	CreatedAt       time.Time           `json:"created_at"`        // 创建时间
+	UpdatedAt       time.Time           `json:"updated_at"`        // 更新时间
 }
 
 // 收藏列表响应DTO
 type ListResponse struct {
-	Total int         `json:"total"` // 总数
-	List  []DetailDTO `json:"list"`  // 列表
+	List       []DetailDTO               `json:"list"`       // 列表
+	Pagination common.PaginationResponse `json:"pagination"` // 分页信息
+}
+
+// 收藏夹DTO
+type FolderDTO struct {
+	ID        uint      `json:"id"`         // 收藏夹ID
+	Name      string    `json:"name"`       // 收藏夹名称
+	Count     int64     `json:"count"`      // 收藏数量
+	CreatedAt time.Time `json:"created_at"` // 创建时间
+}
+
+// 收藏夹列表响应DTO
+type FolderListResponse struct {
+	DefaultCount int64       `json:"default_count"` // 默认收藏夹中的收藏数量
+	List         []FolderDTO `json:"list"`          // 收藏夹列表
 }
 
 // 收藏状态响应DTO
@@ -11,6 +11,7 @@ type CreateRequest struct {
 	Title       string  `json:"title" binding:"required" example:"精装修两居室"`                                // 房源标题
 	Description string  `json:"description" binding:"required" example:"位于市中心的精装修两居室，交通便利"`               // 房源描述
 	Address     string  `json:"address" binding:"required" example:"北京市朝阳区建国路1号"`                         // 房源地址
+	RegionCode  string  `json:"region_code" binding:"omitempty,max=12" example:"110105001"`               // 所在区县或街道的区划代码，不传时根据地址自动识别
 	Area        float64 `json:"area" binding:"required,gt=0" example:"80.5"`                              // 房屋面积(平方米)
 	Floor       int     `json:"floor" binding:"required,gte=0" example:"8"`                               // 所在楼层
 	TotalFloor  int     `json:"total_floor" binding:"required,gt=0" example:"20"`                         // 总楼层
@@ -28,37 +29,37 @@ type CreateRequest struct {
 	Latitude    float64 `json:"latitude" binding:"omitempty" example:"39.9087243"`                        // 纬度
 	Longitude   float64 `json:"longitude" binding:"omitempty" example:"116.3952859"`                      // 经度
 	IsElevator  bool    `json:"is_elevator" example:"true"`                                               // 是否有电梯
+	SaveAsDraft bool    `json:"save_as_draft" example:"false"`                                            // 是否仅保存为草稿，否则直接提交审核
 }
 
-// 更新房源请求DTO
+// 更新房源请求DTO，未传入的字段保持不变
 type UpdateRequest struct {
-	Title       string  `json:"title" binding:"omitempty" example:"精装修两居室"`                               // 房源标题
-	Description string  `json:"description" binding:"omitempty" example:"位于市中心的精装修两居室，交通便利"`              // 房源描述
-	Address     string  `json:"address" binding:"omitempty" example:"北京市朝阳区建国路1号"`                        // 房源地址
-	Area        float64 `json:"area" binding:"omitempty,gt=0" example:"80.5"`                             // 房屋面积(平方米)
-	Floor       int     `json:"floor" binding:"omitempty,gte=0" example:"8"`                              // 所在楼层
-	TotalFloor  int     `json:"total_floor" binding:"omitempty,gt=0" example:"20"`                        // 总楼层
-	Rooms       int     `json:"rooms" binding:"omitempty,gte=1" example:"2"`                              // 房间数
-	Halls       int     `json:"halls" binding:"omitempty,gte=0" example:"1"`                              // 客厅数
-	Bathrooms   int     `json:"bathrooms" binding:"omitempty,gte=1" example:"1"`                          // 卫生间数
-	RentPrice   float64 `json:"rent_price" binding:"omitempty,gt=0" example:"5000"`                       // 租金(元/月)
-	Deposit     float64 `json:"deposit" binding:"omitempty,gte=0" example:"10000"`                        // 押金(元)
-	PaymentType int     `json:"payment_type" binding:"omitempty,oneof=1 2 3 4" example:"1"`               // 支付方式：1-月付，2-季付，3-半年付，4-年付
-	HouseType   int     `json:"house_type" binding:"omitempty,oneof=1 2 3 4" example:"1"`                 // 房屋类型：1-普通住宅，2-公寓，3-别墅，4-商铺
-	Orientation string  `json:"orientation" binding:"omitempty" example:"南"`                              // 朝向
-	Decoration  int     `json:"decoration" binding:"omitempty,oneof=1 2 3" example:"2"`                   // 装修情况：1-简装，2-精装，3-豪装
-	Facilities  string  `json:"facilities" binding:"omitempty" example:"[\"空调\",\"热水器\",\"冰箱\",\"洗衣机\"]"` // 配套设施，JSON格式字符串
-	Images      string  `json:"images" binding:"omitempty" example:"[\"http://example.com/img1.jpg\"]"`   // 房源图片URL，JSON格式字符串
-	Latitude    float64 `json:"latitude" binding:"omitempty" example:"39.9087243"`                        // 纬度
-	Longitude   float64 `json:"longitude" binding:"omitempty" example:"116.3952859"`                      // 经度
-	IsElevator  bool    `json:"is_elevator" example:"true"`                                               // 是否有电梯
-	Status      int     `json:"status" binding:"omitempty,oneof=0 1" example:"1"`                         // 状态：0-下架，1-上架
+	Title       *string  `json:"title" binding:"omitempty" example:"精装修两居室"`                               // 房源标题
+	Description *string  `json:"description" binding:"omitempty" example:"位于市中心的精装修两居室，交通便利"`              // 房源描述
+	Address     *string  `json:"address" binding:"omitempty" example:"北京市朝阳区建国路1号"`                        // 房源地址
+	RegionCode  *string  `json:"region_code" binding:"omitempty,max=12" example:"110105001"`               // 所在区县或街道的区划代码，只修改地址时根据新地址自动识别
+	Area        *float64 `json:"area" binding:"omitempty,gt=0" example:"80.5"`                             // 房屋面积(平方米)
+	Floor       *int     `json:"floor" binding:"omitempty,gte=0" example:"8"`                              // 所在楼层
+	TotalFloor  *int     `json:"total_floor" binding:"omitempty,gt=0" example:"20"`                        // 总楼层
+	Rooms       *int     `json:"rooms" binding:"omitempty,gte=1" example:"2"`                              // 房间数
+	Halls       *int     `json:"halls" binding:"omitempty,gte=0" example:"1"`                              // 客厅数
+	Bathrooms   *int     `json:"bathrooms" binding:"omitempty,gte=1" example:"1"`                          // 卫生间数
+	RentPrice   *float64 `json:"rent_price" binding:"omitempty,gt=0" example:"5000"`                       // 租金(元/月)
+	Deposit     *float64 `json:"deposit" binding:"omitempty,gte=0" example:"10000"`                        // 押金(元)
+	PaymentType *int     `json:"payment_type" binding:"omitempty,oneof=1 2 3 4" example:"1"`               // 支付方式：1-月付，2-季付，3-半年付，4-年付
+	HouseType   *int     `json:"house_type" binding:"omitempty,oneof=1 2 3 4" example:"1"`                 // 房屋类型：1-普通住宅，2-公寓，3-别墅，4-商铺
+	Orientation *string  `json:"orientation" binding:"omitempty" example:"南"`                              // 朝向
+	Decoration  *int     `json:"decoration" binding:"omitempty,oneof=1 2 3" example:"2"`                   // 装修情况：1-简装，2-精装，3-豪装
+	Facilities  *string  `json:"facilities" binding:"omitempty" example:"[\"空调\",\"热水器\",\"冰箱\",\"洗衣机\"]"` // 配套设施，JSON格式字符串
+	Images      *string  `json:"images" binding:"omitempty" example:"[\"http://example.com/img1.jpg\"]"`   // 房源图片URL，JSON格式字符串
+	Latitude    *float64 `json:"latitude" binding:"omitempty" example:"39.9087243"`                        // 纬度
+	Longitude   *float64 `json:"longitude" binding:"omitempty" example:"116.3952859"`                      // 经度
+	IsElevator  *bool    `json:"is_elevator" example:"true"`                                               // 是否有电梯
 }
 
 // 房源查询请求DTO
 type QueryRequest struct {
 	Keyword                      string  `json:"keyword" form:"keyword" example:"精装修"`          // 关键词
-	Status                       int     `json:"status" form:"status" example:"1"`              // 状态：0-下架，1-上架
 	LandlordID                   uint    `json:"landlord_id" form:"landlord_id" example:"1"`    // 房东ID
 	MinPrice                     float64 `json:"min_price" form:"min_price" example:"3000"`     // 最低价格
 	MaxPrice                     float64 `json:"max_price" form:"max_price" example:"6000"`     // 最高价格
@@ -73,6 +74,48 @@ type QueryRequest struct {
 	common.PaginationSortRequest         // 分页和排序参数
 }
 
+// 房源筛选条件DTO，与房源列表接口的查询参数一致
+type FilterRequest struct {
+	LandlordID    uint    `json:"landlord_id" form:"landlord_id" example:"1"`                                          // 房东ID
+	ProvinceCode  string  `json:"province_code" form:"province_code" binding:"omitempty,max=12" example:"110000"`      // 省级区划代码
+	CityCode      string  `json:"city_code" form:"city_code" binding:"omitempty,max=12" example:"110100"`              // 市级区划代码
+	DistrictCode  string  `json:"district_code" form:"district_code" binding:"omitempty,max=12" example:"110105"`      // 区县区划代码
+	CommunityCode string  `json:"community_code" form:"community_code" binding:"omitempty,max=12" example:"110105001"` // 街道区划代码
+	MinPrice      float64 `json:"min_price" form:"min_price" binding:"omitempty,min=0" example:"3000"`                 // 最低价格
+	MaxPrice      float64 `json:"max_price" form:"max_price" binding:"omitempty,min=0" example:"6000"`                 // 最高价格
+	Rooms         int     `json:"rooms" form:"rooms" binding:"omitempty,min=1" example:"2"`                            // 房间数
+	HouseType     int     `json:"house_type" form:"house_type" binding:"omitempty,oneof=1 2 3 4" example:"1"`          // 房屋类型
+	Keyword       string  `json:"keyword" form:"keyword" binding:"omitempty,max=50" example:"精装修"`                     // 关键词
+}
+
+// 房源修改记录查询请求DTO
+type HistoryQueryRequest struct {
+	common.PaginationRequest // 分页参数
+}
+
+// 驳回房源请求DTO
+type RejectRequest struct {
+	Reason string `json:"reason" binding:"required,max=255" example:"房源图片与描述不符"` // 驳回原因
+}
+
+// 待审核房源查询请求DTO
+type ModerationQueryRequest struct {
+	common.PaginationRequest // 分页参数
+}
+
+// 热门房源查询请求DTO
+type TrendingQueryRequest struct {
+	CityCode     string `json:"city_code" form:"city_code" binding:"omitempty,max=12" example:"110100"`         // 市级区划代码
+	DistrictCode string `json:"district_code" form:"district_code" binding:"omitempty,max=12" example:"110105"` // 区县区划代码
+	HouseType    int    `json:"house_type" form:"house_type" binding:"omitempty,oneof=1 2 3 4" example:"1"`     // 房屋类型
+	Limit        int    `json:"limit" form:"limit" binding:"omitempty,min=1,max=50" example:"20"`               // 返回数量，默认20
+}
+
+// 推荐房源和相似房源查询请求DTO
+type RecommendQueryRequest struct {
+	Limit int `json:"limit" form:"limit" binding:"omitempty,min=1,max=50" example:"10"` // 返回数量，默认10
+}
+
 // ValidateCreateRequest 验证创建房源请求
 func ValidateCreateRequest(req CreateRequest) error {
 	validate := validator.New()
@@ -84,3 +127,16 @@ func ValidateUpdateRequest(req UpdateRequest) error {
 	validate := validator.New()
 	return validate.Struct(req)
 }
+
+// ValidateRejectRequest 验证驳回房源请求
+func ValidateRejectRequest(req RejectRequest) error {
+	validate := validator.New()
+	return validate.Struct(req)
+}
+
+// 房源对比请求DTO
+type CompareRequest struct {
+	HouseIDs  []uint   `json:"house_ids" binding:"required,min=2,max=5" example:"1,2,3"`     // 对比的房源ID，2到5套
+	Latitude  *float64 `json:"latitude" binding:"omitempty,latitude" example:"39.908823"`    // 参考位置纬度（如公司），用于计算距离
+	Longitude *float64 `json:"longitude" binding:"omitempty,longitude" example:"116.397470"` // 参考位置经度
+}
@@ -1,56 +1,73 @@
 package house
 
 import (
+	"myApp/dto/common"
+	"myApp/dto/review"
 	"time"
 )
 
 // 房源基本信息DTO
 type BasicInfoDTO struct {
-	ID         uint      `json:"id"`          // 房源ID
-	Title      string    `json:"title"`       // 房源标题
-	Address    string    `json:"address"`     // 房源地址
-	Area       float64   `json:"area"`        // 房屋面积(平方米)
-	Rooms      int       `json:"rooms"`       // 房间数
-	Halls      int       `json:"halls"`       // 客厅数
-	Bathrooms  int       `json:"bathrooms"`   // 卫生间数
-	RentPrice  float64   `json:"rent_price"`  // 租金(元/月)
-	HouseType  int       `json:"house_type"`  // 房屋类型
-	Decoration int       `json:"decoration"`  // 装修情况
-	Images     string    `json:"images"`      // 房源图片URL
-	LandlordID uint      `json:"landlord_id"` // 房东ID
-	Status     int       `json:"status"`      // 状态
-	ViewCount  int       `json:"view_count"`  // 浏览次数
-	CreatedAt  time.Time `json:"created_at"`  // 创建时间
+	ID            uint      `json:"id"`             // 房源ID
+	Title         string    `json:"title"`          // 房源标题
+	Address       string    `json:"address"`        // 房源地址
+	ProvinceCode  string    `json:"province_code"`  // 省级区划代码
+	CityCode      string    `json:"city_code"`      // 市级区划代码
+	DistrictCode  string    `json:"district_code"`  // 区县区划代码
+	CommunityCode string    `json:"community_code"` // 街道区划代码
+	Area          float64   `json:"area"`           // 房屋面积(平方米)
+	Rooms         int       `json:"rooms"`          // 房间数
+	Halls         int       `json:"halls"`          // 客厅数
+	Bathrooms     int       `json:"bathrooms"`      // 卫生间数
+	RentPrice     float64   `json:"rent_price"`     // 租金(元/月)
+	HouseType     int       `json:"house_type"`     // 房屋类型
+	Decoration    int       `json:"decoration"`     // 装修情况
+	Images        string    `json:"images"`         // 房源图片URL
+	LandlordID    uint      `json:"landlord_id"`    // 房东ID
+	Status        int       `json:"status"`         // 状态
+	ViewCount     int       `json:"view_count"`     // 浏览次数
+	CreatedAt     time.Time `json:"created_at"`     // 创建时间
 }
 
 // 房源详细信息DTO
 type DetailDTO struct {
-	ID          uint      `json:"id"`           // 房源ID
-	Title       string    `json:"title"`        // 房源标题
-	Description string    `json:"description"`  // 房源描述
-	Address     string    `json:"address"`      // 房源地址
-	Area        float64   `json:"area"`         // 房屋面积(平方米)
-	Floor       int       `json:"floor"`        // 所在楼层
-	TotalFloor  int       `json:"total_floor"`  // 总楼层
-	Rooms       int       `json:"rooms"`        // 房间数
-	Halls       int       `json:"halls"`        // 客厅数
-	Bathrooms   int       `json:"bathrooms"`    // 卫生间数
-	RentPrice   float64   `json:"rent_price"`   // 租金(元/月)
-	Deposit     float64   `json:"deposit"`      // 押金(元)
-	PaymentType int       `json:"payment_type"` // 支付方式
-	HouseType   int       `json:"house_type"`   // 房屋类型
-	Orientation string    `json:"orientation"`  // 朝向
-	Decoration  int       `json:"decoration"`   // 装修情况
-	Facilities  string    `json:"facilities"`   // 配套设施
-	Status      int       `json:"status"`       // 状态
-	LandlordID  uint      `json:"landlord_id"`  // 房东ID
-	Images      string    `json:"images"`       // 房源图片URL
-	Latitude    float64   `json:"latitude"`     // 纬度
-	Longitude   float64   `json:"longitude"`    // 经度
-	IsElevator  bool      `json:"is_elevator"`  // 是否有电梯
-	ViewCount   int       `json:"view_count"`   // 浏览次数
-	CreatedAt   time.Time `json:"created_at"`   // 创建时间
-	UpdatedAt   time.Time `json:"updated_at"`   // 更新时间
+	ID            uint               `json:"id"`                      // 房源ID
+	Title         string             `json:"title"`                   // 房源标题
+	Description   string             `json:"description"`             // 房源描述
+	Address       string             `json:"address"`                 // 房源地址
+	ProvinceCode  string             `json:"province_code"`           // 省级区划代码
+	CityCode      string             `json:"city_code"`               // 市级区划代码
+	DistrictCode  string             `json:"district_code"`           // 区县区划代码
+	CommunityCode string             `json:"community_code"`          // 街道区划代码
+	Area          float64            `json:"area"`                    // 房屋面积(平方米)
+	Floor         int                `json:"floor"`                   // 所在楼层
+	TotalFloor    int                `json:"total_floor"`             // 总楼层
+	Rooms         int                `json:"rooms"`                   // 房间数
+	Halls         int                `json:"halls"`                   // 客厅数
+	Bathrooms     int                `json:"bathrooms"`               // 卫生间数
+	RentPrice     float64            `json:"rent_price"`              // 租金(元/月)
+	Deposit       float64            `json:"deposit"`                 // 押金(元)
+	PaymentType   int                `json:"payment_type"`            // 支付方式
+	HouseType     int                `json:"house_type"`              // 房屋类型
+	Orientation   string             `json:"orientation"`             // 朝向
+	Decoration    int                `json:"decoration"`              // 装修情况
+	Facilities    string             `json:"facilities"`              // 配套设施
+	Status        int                `json:"status"`                  // 状态
+	StatusText    string             `json:"status_text"`             // 状态描述
+	RejectReason  string             `json:"reject_reason,omitempty"` // 审核驳回原因
+	LandlordID    uint               `json:"landlord_id"`             // 房东ID
+	Images        string             `json:"images"`                  // 房源图片URL
+	Latitude      float64            `json:"latitude"`                // 纬度
+	Longitude     float64            `json:"longitude"`               // 经度
+	IsElevator    bool               `json:"is_elevator"`             // 是否有电梯
+	ViewCount     int                `json:"view_count"`              // 浏览次数
+	Rating        float64            `json:"rating"`                  // 房源评分
+	ReviewCount   int                `json:"review_count"`            // 评价数量
+	Reviews       []review.DetailDTO `json:"reviews,omitempty"`       // 最新评价
+	PublishedAt   *time.Time         `json:"published_at"`            // 发布时间
+	ExpireAt      *time.Time         `json:"expire_at"`               // 上架到期时间
+	CreatedAt     time.Time          `json:"created_at"`              // 创建时间
+	UpdatedAt     time.Time          `json:"updated_at"`              // 更新时间
 }
 
 // 房源列表响应DTO
@@ -58,3 +75,180 @@ type ListResponse struct {
 	Total int            `json:"total"` // 总数
 	List  []BasicInfoDTO `json:"list"`  // 列表
 }
+
+// 行政区划统计DTO
+type RegionFacetDTO struct {
+	Code  string `json:"code"`  // 区划代码
+	Name  string `json:"name"`  // 区划名称
+	Count int64  `json:"count"` // 符合筛选条件的房源数量
+}
+
+// 房源搜索响应DTO
+type SearchResponse struct {
+	Total  int64            `json:"total"`  // 符合筛选条件的房源总数
+	List   []BasicInfoDTO   `json:"list"`   // 列表
+	Facets []RegionFacetDTO `json:"facets"` // 符合筛选条件的房源在下一级行政区划中的分布
+}
+
+// 热门房源DTO
+type TrendingDTO struct {
+	BasicInfoDTO
+	Score float64 `json:"score"` // 热度，综合近期浏览、收藏和预约看房并随时间衰减
+}
+
+// 热门房源列表响应DTO
+type TrendingListResponse struct {
+	List []TrendingDTO `json:"list"` // 列表
+}
+
+// 推荐房源列表响应DTO
+type RecommendListResponse struct {
+	List         []BasicInfoDTO `json:"list"`         // 列表
+	Personalized bool           `json:"personalized"` // 是否为个性化推荐，false表示暂无偏好数据，返回的是热门房源
+}
+
+// 房源对比项DTO
+type CompareItemDTO struct {
+	BasicInfoDTO
+	RentPerSqm  float64  `json:"rent_per_sqm"` // 每平方米月租金，面积未填写时为0
+	Deposit     float64  `json:"deposit"`      // 押金(元)
+	PaymentType int      `json:"payment_type"` // 支付方式
+	Floor       int      `json:"floor"`        // 所在楼层
+	TotalFloor  int      `json:"total_floor"`  // 总楼层
+	IsElevator  bool     `json:"is_elevator"`  // 是否有电梯
+	Orientation string   `json:"orientation"`  // 朝向
+	Facilities  []string `json:"facilities"`   // 配套设施
+	Distance    *float64 `json:"distance"`     // 距参考位置的距离（千米），未指定位置或房源无坐标时为null
+}
+
+// 配套设施对比DTO
+type CompareFacilityDTO struct {
+	Name   string `json:"name"`   // 配套设施名称
+	Houses []bool `json:"houses"` // 各房源是否有该设施，顺序与房源列表一致
+}
+
+// 房源对比响应DTO
+type CompareResponse struct {
+	List       []CompareItemDTO     `json:"list"`       // 对比的房源，顺序与请求一致
+	Facilities []CompareFacilityDTO `json:"facilities"` // 配套设施对比
+}
+
+// 房源审核信息DTO
+type ModerationDTO struct {
+	BasicInfoDTO
+	Description     string     `json:"description"`      // 房源描述
+	RejectReason    string     `json:"reject_reason"`    // 审核驳回原因
+	ModerationFlags []string   `json:"moderation_flags"` // 自动审核提示
+	PublishedAt     *time.Time `json:"published_at"`     // 发布时间
+	ExpireAt        *time.Time `json:"expire_at"`        // 上架到期时间
+	UpdatedAt       time.Time  `json:"updated_at"`       // 更新时间
+}
+
+// 房源审核列表响应DTO
+type ModerationListResponse struct {
+	List       []ModerationDTO           `json:"list"`       // 列表
+	Pagination common.PaginationResponse `json:"pagination"` // 分页信息
+}
+
+// GetStatusText 获取房源状态描述
+func GetStatusText(status int) string {
+	switch status {
+	case 0:
+		return "offline"
+	case 1:
+		return "published"
+	case 2:
+		return "draft"
+	case 3:
+		return "pending"
+	case 4:
+		return "rented"
+	case 5:
+		return "rejected"
+	default:
+		return "unknown"
+	}
+}
+
+// 房源字段变更DTO
+type FieldChangeDTO struct {
+	Field string      `json:"field"` // 字段名
+	Old   interface{} `json:"old"`   // 修改前的值
+	New   interface{} `json:"new"`   // 修改后的值
+}
+
+// 房源修改记录DTO
+type RevisionDTO struct {
+	ID         uint             `json:"id"`          // 记录ID
+	OperatorID uint             `json:"operator_id"` // 操作人用户ID，0表示系统
+	Changes    []FieldChangeDTO `json:"changes"`     // 变更字段
+	CreatedAt  time.Time        `json:"created_at"`  // 修改时间
+}
+
+// 房源修改记录列表响应DTO
+type RevisionListResponse struct {
+	List       []RevisionDTO             `json:"list"`       // 列表
+	Pagination common.PaginationResponse `json:"pagination"` // 分页信息
+}
+
+// 租金变动记录DTO
+type PricePointDTO struct {
+	Price     float64   `json:"price"`      // 租金(元/月)
+	PrevPrice float64   `json:"prev_price"` // 变动前租金，初始记录为0
+	ChangedAt time.Time `json:"changed_at"` // 变动时间
+}
+
+// 租金走势响应DTO
+type PriceHistoryResponse struct {
+	HouseID      uint            `json:"house_id"`      // 房源ID
+	CurrentPrice float64         `json:"current_price"` // 当前租金
+	LowestPrice  float64         `json:"lowest_price"`  // 历史最低租金
+	HighestPrice float64         `json:"highest_price"` // 历史最高租金
+	Points       []PricePointDTO `json:"points"`        // 租金变动记录
+}
+
+// GetHouseTypeText 获取房屋类型描述
+func GetHouseTypeText(houseType int) string {
+	switch houseType {
+	case 1:
+		return "普通住宅"
+	case 2:
+		return "公寓"
+	case 3:
+		return "别墅"
+	case 4:
+		return "商铺"
+	default:
+		return "其他"
+	}
+}
+
+// GetPaymentTypeText 获取支付方式描述
+func GetPaymentTypeText(paymentType int) string {
+	switch paymentType {
+	case 1:
+		return "月付"
+	case 2:
+		return "季付"
+	case 3:
+		return "半年付"
+	case 4:
+		return "年付"
+	default:
+		return "其他"
+	}
+}
+
+// GetDecorationText 获取装修情况描述
+func GetDecorationText(decoration int) string {
+	switch decoration {
+	case 1:
+		return "简装"
+	case 2:
+		return "精装"
+	case 3:
+		return "豪装"
+	default:
+		return "其他"
+	}
+}
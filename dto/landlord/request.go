@@ -2,7 +2,7 @@ package landlord
 
 import (
 	"myApp/dto/common"
-	
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -10,7 +10,7 @@ import (
 type RegisterRequest struct {
 	UserID       uint   `json:"user_id" binding:"required" example:"1"`                                      // 关联的用户ID
 	RealName     string `json:"real_name" binding:"required" example:"张三"`                                   // 真实姓名
-	IDNumber     string `json:"id_number" binding:"required,len=18" example:"110101199001011234"`            // 身份证号
+	IDNumber     string `json:"id_number" binding:"required,len=18" example:"110101199001011237"`            // 身份证号
 	PhoneNumber  string `json:"phone_number" binding:"required,len=11" example:"13800138000"`                // 联系电话
 	Address      string `json:"address" binding:"required" example:"北京市朝阳区建国路1号"`                            // 联系地址
 	IdCardFront  string `json:"id_card_front" binding:"required,url" example:"http://example.com/front.jpg"` // 身份证正面照片URL
@@ -19,6 +19,7 @@ type RegisterRequest struct {
 	BankName     string `json:"bank_name" binding:"required" example:"中国工商银行"`                               // 开户行名称
 	AccountName  string `json:"account_name" binding:"required" example:"张三"`                                // 开户人姓名
 	Introduction string `json:"introduction" binding:"omitempty" example:"专业的房产经纪人，有多年租赁经验"`                 // 房东自我介绍
+	Documents    string `json:"documents" binding:"omitempty" example:"[\"http://example.com/deed.jpg\"]"`   // 其他证明材料URL，JSON格式字符串
 }
 
 // 房东信息更新请求DTO
@@ -32,7 +33,26 @@ type UpdateRequest struct {
 	BankName     string `json:"bank_name" binding:"omitempty" example:"中国工商银行"`                               // 开户行名称
 	AccountName  string `json:"account_name" binding:"omitempty" example:"张三"`                                // 开户人姓名
 	Introduction string `json:"introduction" binding:"omitempty" example:"专业的房产经纪人，有多年租赁经验"`                  // 房东自我介绍
-	Verified     bool   `json:"verified" example:"true"`                                                      // 是否已认证
+}
+
+// 房东认证申请请求DTO
+type VerificationRequest struct {
+	RealName    string `json:"real_name" binding:"required" example:"张三"`                                   // 真实姓名
+	IDNumber    string `json:"id_number" binding:"required,len=18" example:"110101199001011237"`            // 身份证号
+	IdCardFront string `json:"id_card_front" binding:"required,url" example:"http://example.com/front.jpg"` // 身份证正面照片URL
+	IdCardBack  string `json:"id_card_back" binding:"required,url" example:"http://example.com/back.jpg"`   // 身份证背面照片URL
+	Documents   string `json:"documents" binding:"omitempty" example:"[\"http://example.com/deed.jpg\"]"`   // 其他证明材料URL，JSON格式字符串
+}
+
+// 驳回认证申请请求DTO
+type RejectVerificationRequest struct {
+	Reason string `json:"reason" binding:"required,max=255" example:"身份证照片模糊，请重新上传"` // 驳回原因
+}
+
+// 认证申请查询请求DTO
+type VerificationQueryRequest struct {
+	Status                   *int `json:"status" form:"status" example:"0"` // 状态：0-待审核，1-已通过，2-已驳回，默认查询待审核
+	common.PaginationRequest      // 分页参数
 }
 
 // 房东查询请求DTO
@@ -55,3 +75,15 @@ func ValidateUpdateRequest(req UpdateRequest) error {
 	validate := validator.New()
 	return validate.Struct(req)
 }
+
+// ValidateVerificationRequest 验证房东认证申请请求
+func ValidateVerificationRequest(req VerificationRequest) error {
+	validate := validator.New()
+	return validate.Struct(req)
+}
+
+// ValidateRejectVerificationRequest 验证驳回认证申请请求
+func ValidateRejectVerificationRequest(req RejectVerificationRequest) error {
+	validate := validator.New()
+	return validate.Struct(req)
+}
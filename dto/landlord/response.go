@@ -1,6 +1,9 @@
 package landlord
 
 import (
+	"myApp/dto/common"
+	"myApp/dto/house"
+	"myApp/dto/review"
 	"time"
 )
 
@@ -17,21 +20,39 @@ type BasicInfoDTO struct {
 
 // 房东详细信息DTO
 type DetailDTO struct {
-	ID           uint      `json:"id"`            // 房东ID
-	UserID       uint      `json:"user_id"`       // 关联的用户ID
-	RealName     string    `json:"real_name"`     // 真实姓名
-	IDNumber     string    `json:"id_number"`     // 身份证号
-	PhoneNumber  string    `json:"phone_number"`  // 联系电话
-	Address      string    `json:"address"`       // 联系地址
-	Verified     bool      `json:"verified"`      // 是否已认证
-	IdCardFront  string    `json:"id_card_front"` // 身份证正面照片URL
-	IdCardBack   string    `json:"id_card_back"`  // 身份证背面照片URL
-	BankAccount  string    `json:"bank_account"`  // 银行账号
-	BankName     string    `json:"bank_name"`     // 开户行名称
-	AccountName  string    `json:"account_name"`  // 开户人姓名
-	Introduction string    `json:"introduction"`  // 房东自我介绍
-	Rating       float64   `json:"rating"`        // 房东评分
-	CreatedAt    time.Time `json:"created_at"`    // 创建时间
+	ID           uint               `json:"id"`                // 房东ID
+	UserID       uint               `json:"user_id"`           // 关联的用户ID
+	RealName     string             `json:"real_name"`         // 真实姓名
+	IDNumber     string             `json:"id_number"`         // 身份证号
+	PhoneNumber  string             `json:"phone_number"`      // 联系电话
+	Address      string             `json:"address"`           // 联系地址
+	Verified     bool               `json:"verified"`          // 是否已认证
+	IdCardFront  string             `json:"id_card_front"`     // 身份证正面照片URL
+	IdCardBack   string             `json:"id_card_back"`      // 身份证背面照片URL
+	BankAccount  string             `json:"bank_account"`      // 银行账号
+	BankName     string             `json:"bank_name"`         // 开户行名称
+	AccountName  string             `json:"account_name"`      // 开户人姓名
+	Introduction string             `json:"introduction"`      // 房东自我介绍
+	Rating       float64            `json:"rating"`            // 房东评分
+	ReviewCount  int                `json:"review_count"`      // 评价数量
+	Reviews      []review.DetailDTO `json:"reviews,omitempty"` // 最新评价
+	CreatedAt    time.Time          `json:"created_at"`        // 创建时间
+}
+
+// 房东公开主页DTO，仅包含可对租客公开的信息
+type PublicProfileDTO struct {
+	ID             uint                      `json:"id"`              // 房东ID
+	DisplayName    string                    `json:"display_name"`    // 展示名称（脱敏后的姓名）
+	Verified       bool                      `json:"verified"`        // 是否已认证
+	Rating         float64                   `json:"rating"`          // 房东评分
+	ReviewCount    int                       `json:"review_count"`    // 评价数量
+	Introduction   string                    `json:"introduction"`    // 房东自我介绍
+	ResponseRate   *float64                  `json:"response_rate"`   // 看房预约响应率(0-1)，即已确认或已完成的预约占比，暂无预约时为null
+	ActiveListings int64                     `json:"active_listings"` // 上架中的房源数量
+	Houses         []house.BasicInfoDTO      `json:"houses"`          // 上架中的房源
+	Pagination     common.PaginationResponse `json:"pagination"`      // 房源分页信息
+	Reviews        []review.DetailDTO        `json:"reviews"`         // 最新评价
+	CreatedAt      time.Time                 `json:"created_at"`      // 入驻时间
 }
 
 // 房东列表响应DTO
@@ -39,3 +60,40 @@ type ListResponse struct {
 	Total int            `json:"total"` // 总数
 	List  []BasicInfoDTO `json:"list"`  // 列表
 }
+
+// 房东认证申请DTO
+type VerificationDTO struct {
+	ID           uint       `json:"id"`                    // 申请ID
+	LandlordID   uint       `json:"landlord_id"`           // 房东ID
+	UserID       uint       `json:"user_id"`               // 用户ID
+	RealName     string     `json:"real_name"`             // 真实姓名
+	IDNumber     string     `json:"id_number"`             // 身份证号
+	IdCardFront  string     `json:"id_card_front"`         // 身份证正面照片URL
+	IdCardBack   string     `json:"id_card_back"`          // 身份证背面照片URL
+	Documents    string     `json:"documents"`             // 其他证明材料URL
+	Status       int        `json:"status"`                // 状态：0-待审核，1-已通过，2-已驳回
+	StatusText   string     `json:"status_text"`           // 状态文本描述
+	RejectReason string     `json:"reject_reason"`         // 驳回原因
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"` // 审核时间
+	CreatedAt    time.Time  `json:"created_at"`            // 提交时间
+}
+
+// 房东认证申请列表响应DTO
+type VerificationListResponse struct {
+	List       []VerificationDTO         `json:"list"`       // 申请列表
+	Pagination common.PaginationResponse `json:"pagination"` // 分页信息
+}
+
+// GetVerificationStatusText 获取认证申请状态文本描述
+func GetVerificationStatusText(status int) string {
+	switch status {
+	case 0:
+		return "pending"
+	case 1:
+		return "approved"
+	case 2:
+		return "rejected"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,11 @@
+package notification
+
+import (
+	"myApp/dto/common"
+)
+
+// 通知查询请求DTO
+type QueryRequest struct {
+	Unread                   bool `json:"unread" form:"unread" example:"true"` // 是否只查询未读通知
+	common.PaginationRequest      // 分页参数
+}
@@ -0,0 +1,29 @@
+package notification
+
+import (
+	"myApp/dto/common"
+	"time"
+)
+
+// 通知详情DTO
+type DetailDTO struct {
+	ID        uint       `json:"id"`         // 通知ID
+	Type      string     `json:"type"`       // 通知类型
+	Title     string     `json:"title"`      // 通知标题
+	Content   string     `json:"content"`    // 通知内容
+	RelatedID uint       `json:"related_id"` // 关联对象ID
+	IsRead    bool       `json:"is_read"`    // 是否已读
+	ReadAt    *time.Time `json:"read_at"`    // 阅读时间
+	CreatedAt time.Time  `json:"created_at"` // 创建时间
+}
+
+// 通知列表响应DTO
+type ListResponse struct {
+	List       []DetailDTO               `json:"list"`       // 列表
+	Pagination common.PaginationResponse `json:"pagination"` // 分页信息
+}
+
+// 未读通知数量响应DTO
+type UnreadCountResponse struct {
+	Count int64 `json:"count"` // 未读数量
+}
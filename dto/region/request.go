@@ -0,0 +1,6 @@
+package region
+
+// 下级行政区划查询请求DTO
+type ChildrenRequest struct {
+	ParentCode string `json:"parent_code" form:"parent_code" binding:"omitempty,max=12" example:"110100"` // 上级区划代码，不传时返回省级区划
+}
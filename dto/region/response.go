@@ -0,0 +1,14 @@
+package region
+
+// 行政区划DTO
+type DetailDTO struct {
+	Code       string `json:"code"`        // 行政区划代码
+	ParentCode string `json:"parent_code"` // 上级区划代码，省级为空
+	Name       string `json:"name"`        // 名称
+	Level      int    `json:"level"`       // 级别：1-省，2-市，3-区县，4-街道
+}
+
+// 行政区划列表响应DTO
+type ListResponse struct {
+	List []DetailDTO `json:"list"` // 列表
+}
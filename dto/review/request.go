@@ -0,0 +1,65 @@
+package review
+
+import (
+	"myApp/dto/common"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// 创建评价请求DTO
+type CreateRequest struct {
+	ViewingID      uint   `json:"viewing_id" binding:"required" example:"1"`                         // 已完成的看房预约ID
+	LandlordRating int    `json:"landlord_rating" binding:"required,min=1,max=5" example:"5"`        // 房东评分(1-5星)
+	HouseRating    int    `json:"house_rating" binding:"required,min=1,max=5" example:"4"`           // 房源评分(1-5星)
+	Content        string `json:"content" binding:"omitempty,max=1000" example:"房东很热情，房子和照片一致，采光很好"` // 评价内容
+}
+
+// 房东回复评价请求DTO
+type ReplyRequest struct {
+	Reply string `json:"reply" binding:"required,max=500" example:"感谢您的认可，欢迎随时联系"` // 回复内容
+}
+
+// 举报评价请求DTO
+type ReportRequest struct {
+	Reason string `json:"reason" binding:"required,max=255" example:"评价内容包含广告信息"` // 举报原因
+}
+
+// 处理举报请求DTO
+type HandleReportRequest struct {
+	Action string `json:"action" binding:"required,oneof=hide dismiss" example:"hide"` // 处理方式：hide-隐藏评价，dismiss-驳回举报
+}
+
+// 评价查询请求DTO
+type QueryRequest struct {
+	common.PaginationRequest // 分页参数
+}
+
+// 举报查询请求DTO
+type ReportQueryRequest struct {
+	Status                   *int `json:"status" form:"status" example:"0"` // 状态：0-待处理，1-已隐藏评价，2-已驳回，默认查询待处理
+	common.PaginationRequest      // 分页参数
+}
+
+// ValidateCreateRequest 验证创建评价请求
+func ValidateCreateRequest(req CreateRequest) error {
+	validate := validator.New()
+	return validate.Struct(req)
+}
+
+// ValidateReplyRequest 验证回复评价请求
+func ValidateReplyRequest(req ReplyRequest) error {
+	validate := validator.New()
+	return validate.Struct(req)
+}
+
+// ValidateReportRequest 验证举报评价请求
+func ValidateReportRequest(req ReportRequest) error {
+	validate := validator.New()
+	return validate.Struct(req)
+}
+
+// ValidateHandleReportRequest 验证处理举报请求
+func ValidateHandleReportRequest(req HandleReportRequest) error {
+	validate := validator.New()
+	return validate.Struct(req)
+}
@@ -0,0 +1,43 @@
+package review
+
+import (
+	"myApp/dto/common"
+	"time"
+)
+
+// 评价详细信息DTO
+type DetailDTO struct {
+	ID             uint       `json:"id"`                   // 评价ID
+	UserID         uint       `json:"user_id"`              // 评价用户ID
+	HouseID        uint       `json:"house_id"`             // 房源ID
+	LandlordID     uint       `json:"landlord_id"`          // 房东ID
+	LandlordRating int        `json:"landlord_rating"`      // 房东评分
+	HouseRating    int        `json:"house_rating"`         // 房源评分
+	Content        string     `json:"content"`              // 评价内容
+	Reply          string     `json:"reply,omitempty"`      // 房东回复
+	ReplyTime      *time.Time `json:"reply_time,omitempty"` // 回复时间
+	CreatedAt      time.Time  `json:"created_at"`           // 评价时间
+}
+
+// 评价列表响应DTO
+type ListResponse struct {
+	List       []DetailDTO               `json:"list"`       // 评价列表
+	Pagination common.PaginationResponse `json:"pagination"` // 分页信息
+}
+
+// 评价举报DTO
+type ReportDTO struct {
+	ID        uint       `json:"id"`                   // 举报ID
+	ReviewID  uint       `json:"review_id"`            // 评价ID
+	UserID    uint       `json:"user_id"`              // 举报用户ID
+	Reason    string     `json:"reason"`               // 举报原因
+	Status    int        `json:"status"`               // 状态：0-待处理，1-已隐藏评价，2-已驳回
+	HandledAt *time.Time `json:"handled_at,omitempty"` // 处理时间
+	CreatedAt time.Time  `json:"created_at"`           // 举报时间
+}
+
+// 评价举报列表响应DTO
+type ReportListResponse struct {
+	List       []ReportDTO               `json:"list"`       // 举报列表
+	Pagination common.PaginationResponse `json:"pagination"` // 分页信息
+}
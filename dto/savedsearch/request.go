@@ -0,0 +1,41 @@
+package savedsearch
+
+import (
+	"myApp/dto/common"
+	"myApp/dto/house"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// 保存搜索请求DTO
+type CreateRequest struct {
+	Name        string              `json:"name" binding:"required,max=50" example:"公司附近两居"` // 搜索名称
+	Filters     house.FilterRequest `json:"filters"`                                         // 筛选条件，与房源列表的查询参数一致，至少设置一项
+	NotifyInApp *bool               `json:"notify_in_app" example:"true"`                    // 有新房源时是否发送站内通知，默认开启
+	NotifySMS   bool                `json:"notify_sms" example:"false"`                      // 有新房源时是否发送短信
+}
+
+// 修改保存的搜索请求DTO，未传的字段保持不变
+type UpdateRequest struct {
+	Name        *string              `json:"name" binding:"omitempty,min=1,max=50" example:"公司附近两居"` // 搜索名称
+	Filters     *house.FilterRequest `json:"filters"`                                                // 筛选条件
+	NotifyInApp *bool                `json:"notify_in_app" example:"true"`                           // 有新房源时是否发送站内通知
+	NotifySMS   *bool                `json:"notify_sms" example:"false"`                             // 有新房源时是否发送短信
+}
+
+// 匹配房源查询请求DTO
+type MatchQueryRequest struct {
+	common.PaginationRequest // 分页参数
+}
+
+// ValidateCreateRequest 验证保存搜索请求
+func ValidateCreateRequest(req CreateRequest) error {
+	validate := validator.New()
+	return validate.Struct(req)
+}
+
+// ValidateUpdateRequest 验证修改保存的搜索请求
+func ValidateUpdateRequest(req UpdateRequest) error {
+	validate := validator.New()
+	return validate.Struct(req)
+}
@@ -0,0 +1,37 @@
+package savedsearch
+
+import (
+	"myApp/dto/common"
+	"myApp/dto/house"
+	"time"
+)
+
+// 保存的搜索详情DTO
+type DetailDTO struct {
+	ID            uint                `json:"id"`              // 搜索ID
+	Name          string              `json:"name"`            // 搜索名称
+	Filters       house.FilterRequest `json:"filters"`         // 筛选条件
+	NotifyInApp   bool                `json:"notify_in_app"`   // 是否开启站内提醒
+	NotifySMS     bool                `json:"notify_sms"`      // 是否开启短信提醒
+	NewMatchCount int64               `json:"new_match_count"` // 上次查看后新发布的匹配房源数量
+	LastCheckedAt *time.Time          `json:"last_checked_at"` // 上次查看时间
+	CreatedAt     time.Time           `json:"created_at"`      // 创建时间
+}
+
+// 保存的搜索列表响应DTO
+type ListResponse struct {
+	List []DetailDTO `json:"list"` // 列表
+}
+
+// 匹配房源DTO
+type MatchDTO struct {
+	house.BasicInfoDTO
+	IsNew       bool       `json:"is_new"`       // 是否为上次查看后新发布的房源
+	PublishedAt *time.Time `json:"published_at"` // 发布时间
+}
+
+// 匹配房源列表响应DTO
+type MatchListResponse struct {
+	List       []MatchDTO                `json:"list"`       // 列表
+	Pagination common.PaginationResponse `json:"pagination"` // 分页信息
+}
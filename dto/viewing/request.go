@@ -10,11 +10,11 @@ import (
 
 // 创建预约看房请求DTO
 type CreateRequest struct {
-	HouseID      uint      `json:"house_id" binding:"required" example:"1"`                                      // 房源ID
-	ViewDate     time.Time `json:"view_date" binding:"required,gtfield=time.Now" example:"2023-07-01T14:00:00Z"` // 预约看房时间
-	Message      string    `json:"message" binding:"omitempty" example:"希望周末下午看房，最好能详细介绍下周边设施"`                  // 备注信息
-	ContactName  string    `json:"contact_name" binding:"required" example:"张三"`                                 // 联系人姓名
-	ContactPhone string    `json:"contact_phone" binding:"required,len=11" example:"13800138000"`                // 联系人电话
+	HouseID      uint      `json:"house_id" binding:"required" example:"1"`                       // 房源ID
+	ViewDate     time.Time `json:"view_date" binding:"required" example:"2023-07-01T14:00:00Z"`   // 预约看房时间
+	Message      string    `json:"message" binding:"omitempty" example:"希望周末下午看房，最好能详细介绍下周边设施"`   // 备注信息
+	ContactName  string    `json:"contact_name" binding:"required" example:"张三"`                  // 联系人姓名
+	ContactPhone string    `json:"contact_phone" binding:"required,len=11" example:"13800138000"` // 联系人电话
 }
 
 // 更新预约看房状态请求DTO
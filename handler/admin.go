@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"myApp/pkg/redis/cache"
+	"myApp/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler 管理员运维处理器结构体，负责处理系统运行状态相关的HTTP请求
+type AdminHandler struct{}
+
+// NewAdminHandler 创建管理员运维处理器实例
+func NewAdminHandler() *AdminHandler {
+	return &AdminHandler{}
+}
+
+// GetCacheStats 获取各缓存命名空间的命中统计
+func (h *AdminHandler) GetCacheStats(c *gin.Context) {
+	response.Success(c, cache.GetStats())
+}
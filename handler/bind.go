@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// init 参数校验失败时使用json或form标签作为字段名，使错误详情中的字段名与请求中的一致
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+// requestFieldName 返回结构体字段在请求中的名称，没有json和form标签时返回空字符串，使用字段名
+func requestFieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name := strings.SplitN(field.Tag.Get(key), ",", 2)[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return ""
+}
@@ -3,9 +3,11 @@ package handler
 import (
 	"strconv"
 
+	"myApp/dto/common"
 	"myApp/dto/favorite"
 	"myApp/dto/house"
 	"myApp/model"
+	"myApp/pkg/errors"
 	"myApp/pkg/response"
 	"myApp/service"
 
@@ -26,13 +28,13 @@ func NewFavoriteHandler(s service.FavoriteService) *FavoriteHandler {
 func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
 	var req favorite.AddRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "无效的请求参数")
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
 	// 验证请求参数
 	if err := favorite.ValidateAddRequest(req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
@@ -45,23 +47,26 @@ func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
 
 	// 将DTO转换为模型
 	favoriteModel := model.Favorite{
-		UserID:  userID.(uint),
-		HouseID: req.HouseID,
-		Notes:   req.Notes,
+		UserID:   userID.(uint),
+		HouseID:  req.HouseID,
+		FolderID: req.FolderID,
+		Notes:    req.Notes,
 	}
 
-	if err := h.service.AddFavorite(&favoriteModel); err != nil {
-		response.ServerError(c, "添加收藏失败")
+	if err := h.service.AddFavorite(c.Request.Context(), &favoriteModel); err != nil {
+		response.Error(c, err)
 		return
 	}
 
 	// 将模型转换为DTO
 	favoriteDTO := favorite.BasicInfoDTO{
-		ID:        favoriteModel.ID,
-		UserID:    favoriteModel.UserID,
-		HouseID:   favoriteModel.HouseID,
-		Notes:     favoriteModel.Notes,
-		CreatedAt: favoriteModel.CreatedAt,
+		ID:              favoriteModel.ID,
+		UserID:          favoriteModel.UserID,
+		HouseID:         favoriteModel.HouseID,
+		FolderID:        favoriteModel.FolderID,
+		Notes:           favoriteModel.Notes,
+		PriceAtFavorite: favoriteModel.PriceAtFavorite,
+		CreatedAt:       favoriteModel.CreatedAt,
 	}
 
 	response.Success(c, favoriteDTO)
@@ -77,9 +82,9 @@ func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
 	}
 
 	// 检查收藏是否存在
-	favorite, err := h.service.GetFavoriteByID(uint(id))
+	favorite, err := h.service.GetFavoriteByID(c.Request.Context(), uint(id))
 	if err != nil {
-		response.NotFound(c, "收藏记录不存在")
+		response.Error(c, err)
 		return
 	}
 
@@ -96,15 +101,15 @@ func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.RemoveFavorite(uint(id)); err != nil {
-		response.ServerError(c, "删除收藏失败")
+	if err := h.service.RemoveFavorite(c.Request.Context(), uint(id)); err != nil {
+		response.Error(c, err)
 		return
 	}
 
 	response.Success(c, nil)
 }
 
-// GetUserFavorites 获取用户的所有收藏
+// GetUserFavorites 分页获取用户的收藏，附带房源基本信息及收藏后的状态和租金变化
 func (h *FavoriteHandler) GetUserFavorites(c *gin.Context) {
 	// 从上下文获取用户ID（由JWT中间件设置）
 	userID, exists := c.Get("userID")
@@ -113,39 +118,62 @@ func (h *FavoriteHandler) GetUserFavorites(c *gin.Context) {
 		return
 	}
 
-	favoriteModels, err := h.service.GetUserFavorites(userID.(uint))
-	if err != nil {
-		response.ServerError(c, "获取收藏列表失败")
+	var req favorite.ListQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
+	page, pageSize := req.GetDefaultPage(), req.GetDefaultPageSize()
+
+	params := map[string]interface{}{
+		"limit":  pageSize,
+		"offset": (page - 1) * pageSize,
+	}
+	if req.FolderID != nil {
+		params["folder_id"] = *req.FolderID
+	}
+	if req.Keyword != "" {
+		params["keyword"] = req.Keyword
+	}
 
-	// 将模型列表转换为DTO列表
-	favoriteDTOs := make([]favorite.DetailDTO, 0, len(favoriteModels))
-	for _, f := range favoriteModels {
-		// 注意：model.Favorite中没有House字段，需要单独获取房源信息
-		// 这里应该调用房源服务获取房源信息
-		houseDTO := house.BasicInfoDTO{}
+	items, total, err := h.service.GetUserFavorites(c.Request.Context(), userID.(uint), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
 
-		// 这里需要通过房源ID获取房源信息
-		// TODO: 调用房源服务获取房源信息
-		// 示例: houseInfo, err := houseService.GetHouseByID(f.HouseID)
-		// 如果有房源服务，应该注入到FavoriteHandler中
+	// 将收藏及房源转换为DTO列表
+	favoriteDTOs := make([]favorite.DetailDTO, 0, len(items))
+	for _, item := range items {
+		f := item.Favorite
+		var houseDTO *house.BasicInfoDTO
+		if item.House != nil {
+			houseDTO = &toHouseBasicInfoDTOs([]model.House{*item.House})[0]
+		}
 
+		priceChange := item.PriceChange()
 		favoriteDTOs = append(favoriteDTOs, favorite.DetailDTO{
-			ID:        f.ID,
-			UserID:    f.UserID,
-			HouseID:   f.HouseID,
-			Notes:     f.Notes,
-			House:     houseDTO,
-			CreatedAt: f.CreatedAt,
-			UpdatedAt: f.UpdatedAt,
+			ID:              f.ID,
+			UserID:          f.UserID,
+			HouseID:         f.HouseID,
+			FolderID:        f.FolderID,
+			Notes:           f.Notes,
+			NotifyPriceDrop: f.NotifyPriceDrop,
+			PriceAtFavorite: f.PriceAtFavorite,
+			PriceChange:     priceChange,
+			PriceChanged:    priceChange != 0,
+			HouseAvailable:  item.HouseAvailable(),
+			HouseStatusText: item.HouseStatusText(),
+			House:           houseDTO,
+			CreatedAt:       f.CreatedAt,
+			UpdatedAt:       f.UpdatedAt,
 		})
 	}
 
 	// 创建列表响应DTO
 	listResponse := favorite.ListResponse{
-		Total: len(favoriteDTOs),
-		List:  favoriteDTOs,
+		List:       favoriteDTOs,
+		Pagination: common.NewPaginationResponse(total, page, pageSize),
 	}
 
 	response.Success(c, listResponse)
@@ -175,15 +203,15 @@ func (h *FavoriteHandler) ToggleFavorite(c *gin.Context) {
 		data.Notes = ""
 	}
 
-	if err := h.service.ToggleFavorite(userID.(uint), uint(houseID), data.Notes); err != nil {
-		response.ServerError(c, "操作收藏失败")
+	if err := h.service.ToggleFavorite(c.Request.Context(), userID.(uint), uint(houseID), data.Notes); err != nil {
+		response.Error(c, err)
 		return
 	}
 
 	// 检查当前状态
-	isFav, err := h.service.IsFavorite(userID.(uint), uint(houseID))
+	isFav, err := h.service.IsFavorite(c.Request.Context(), userID.(uint), uint(houseID))
 	if err != nil {
-		response.ServerError(c, "获取收藏状态失败")
+		response.Error(c, err)
 		return
 	}
 
@@ -210,9 +238,9 @@ func (h *FavoriteHandler) CheckFavorite(c *gin.Context) {
 		return
 	}
 
-	isFav, err := h.service.IsFavorite(userID.(uint), uint(houseID))
+	isFav, err := h.service.IsFavorite(c.Request.Context(), userID.(uint), uint(houseID))
 	if err != nil {
-		response.ServerError(c, "获取收藏状态失败")
+		response.Error(c, err)
 		return
 	}
 
@@ -222,3 +250,201 @@ func (h *FavoriteHandler) CheckFavorite(c *gin.Context) {
 	}
 	response.Success(c, statusDTO)
 }
+
+// SetPriceAlert 开启或关闭收藏房源的降价提醒
+func (h *FavoriteHandler) SetPriceAlert(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的收藏ID")
+		return
+	}
+
+	var req favorite.PriceAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	if err := h.service.SetPriceDropAlert(c.Request.Context(), uint(id), userID.(uint), *req.Enabled); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// UpdateNotes 修改收藏备注
+func (h *FavoriteHandler) UpdateNotes(c *gin.Context) {
+	id, userID, ok := parseFavoriteRequest(c)
+	if !ok {
+		return
+	}
+
+	var req favorite.NotesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	if err := favorite.ValidateNotesRequest(req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	if err := h.service.UpdateNotes(c.Request.Context(), id, userID, req.Notes); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// MoveFavorite 将收藏移动到指定收藏夹
+func (h *FavoriteHandler) MoveFavorite(c *gin.Context) {
+	id, userID, ok := parseFavoriteRequest(c)
+	if !ok {
+		return
+	}
+
+	var req favorite.MoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	if err := h.service.MoveToFolder(c.Request.Context(), id, userID, *req.FolderID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// GetFolders 获取当前用户的收藏夹及各收藏夹中的收藏数量
+func (h *FavoriteHandler) GetFolders(c *gin.Context) {
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	summaries, defaultCount, err := h.service.GetFolders(c.Request.Context(), userID.(uint))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	list := make([]favorite.FolderDTO, 0, len(summaries))
+	for _, summary := range summaries {
+		list = append(list, favorite.FolderDTO{
+			ID:        summary.Folder.ID,
+			Name:      summary.Folder.Name,
+			Count:     summary.Count,
+			CreatedAt: summary.Folder.CreatedAt,
+		})
+	}
+
+	response.Success(c, favorite.FolderListResponse{
+		DefaultCount: defaultCount,
+		List:         list,
+	})
+}
+
+// CreateFolder 创建收藏夹
+func (h *FavoriteHandler) CreateFolder(c *gin.Context) {
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	var req favorite.FolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	if err := favorite.ValidateFolderRequest(req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	folder := &model.FavoriteFolder{
+		UserID: userID.(uint),
+		Name:   req.Name,
+	}
+	if err := h.service.CreateFolder(c.Request.Context(), folder); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, favorite.FolderDTO{
+		ID:        folder.ID,
+		Name:      folder.Name,
+		CreatedAt: folder.CreatedAt,
+	})
+}
+
+// RenameFolder 重命名收藏夹
+func (h *FavoriteHandler) RenameFolder(c *gin.Context) {
+	id, userID, ok := parseFavoriteRequest(c)
+	if !ok {
+		return
+	}
+
+	var req favorite.FolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	if err := favorite.ValidateFolderRequest(req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	if err := h.service.RenameFolder(c.Request.Context(), id, userID, req.Name); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// DeleteFolder 删除收藏夹，其中的收藏移回默认收藏夹
+func (h *FavoriteHandler) DeleteFolder(c *gin.Context) {
+	id, userID, ok := parseFavoriteRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteFolder(c.Request.Context(), id, userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// parseFavoriteRequest 解析路径中的收藏或收藏夹ID和当前用户ID，失败时已写入响应
+func parseFavoriteRequest(c *gin.Context) (uint, uint, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return 0, 0, false
+	}
+	return uint(id), userID.(uint), true
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"myApp/pkg/logger"
+	"myApp/pkg/redis"
+	"myApp/pkg/response"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// readinessTimeout 就绪检查中每项依赖的超时时间
+const readinessTimeout = 2 * time.Second
+
+// HealthHandler 健康检查处理器，供负载均衡和容器编排探测服务状态
+type HealthHandler struct {
+	db    *gorm.DB
+	redis *redis.Client
+}
+
+// NewHealthHandler 创建健康检查处理器实例，注入数据库和Redis客户端
+func NewHealthHandler(db *gorm.DB, rdb *redis.Client) *HealthHandler {
+	return &HealthHandler{db: db, redis: rdb}
+}
+
+// Healthz 存活检查，进程能处理请求即返回成功，不检查外部依赖，避免依赖故障时服务被反复重启
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	response.Success(c, gin.H{"status": "ok"})
+}
+
+// Readyz 就绪检查，数据库和Redis都能连通时返回成功，否则返回503和各项检查结果
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	checks := map[string]string{
+		"database": checkDependency(c.Request.Context(), "database", h.pingDB),
+		"redis":    checkDependency(c.Request.Context(), "redis", h.pingRedis),
+	}
+	for _, status := range checks {
+		if status != "ok" {
+			response.Fail(c, http.StatusServiceUnavailable, "服务未就绪", checks)
+			return
+		}
+	}
+	response.Success(c, checks)
+}
+
+// checkDependency 在超时时间内执行一项依赖检查，成功时返回ok，失败时返回unavailable，错误详情只写入日志
+func checkDependency(ctx context.Context, name string, ping func(ctx context.Context) error) string {
+	pingCtx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+	if err := ping(pingCtx); err != nil {
+		logger.FromContext(ctx).Warn("就绪检查失败", zap.String("dependency", name), zap.Error(err))
+		return "unavailable"
+	}
+	return "ok"
+}
+
+// pingDB 检查数据库连接
+func (h *HealthHandler) pingDB(ctx context.Context) error {
+	sqlDB, err := h.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// pingRedis 检查Redis连接
+func (h *HealthHandler) pingRedis(ctx context.Context) error {
+	return h.redis.Ping(ctx).Err()
+}
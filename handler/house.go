@@ -1,10 +1,18 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
 
+	"myApp/dto/common"
 	"myApp/dto/house"
 	"myApp/model"
+	"myApp/pkg/errors"
+	"myApp/pkg/geo"
 	"myApp/pkg/response"
 	"myApp/service"
 
@@ -13,25 +21,36 @@ import (
 
 // HouseHandler 房源处理器结构体，负责处理房源相关的HTTP请求
 type HouseHandler struct {
-	service service.HouseService
+	service       service.HouseService
+	reviewService service.ReviewService
+	regionService service.RegionService
 }
 
-// NewHouseHandler 创建房源处理器实例，注入房源服务依赖
-func NewHouseHandler(s service.HouseService) *HouseHandler {
-	return &HouseHandler{service: s}
+// 房源详情中展示的最新评价数量
+const houseDetailReviewLimit = 5
+
+// 热门房源默认返回数量
+const defaultTrendingLimit = 20
+
+// 推荐房源和相似房源默认返回数量
+const defaultRecommendLimit = 10
+
+// NewHouseHandler 创建房源处理器实例，注入房源服务、评价服务和行政区划服务依赖
+func NewHouseHandler(s service.HouseService, rs service.ReviewService, regionService service.RegionService) *HouseHandler {
+	return &HouseHandler{service: s, reviewService: rs, regionService: regionService}
 }
 
 // CreateHouse 创建房源
 func (h *HouseHandler) CreateHouse(c *gin.Context) {
 	var req house.CreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "无效的请求参数")
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
 	// 验证请求参数
 	if err := house.ValidateCreateRequest(req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
@@ -65,45 +84,29 @@ func (h *HouseHandler) CreateHouse(c *gin.Context) {
 		Longitude:   req.Longitude,
 		IsElevator:  req.IsElevator,
 		LandlordID:  userID.(uint),
-		Status:      1, // 默认上架状态
+		Status:      model.HouseStatusPending, // 默认提交审核
+	}
+	if req.SaveAsDraft {
+		houseModel.Status = model.HouseStatusDraft
 	}
 
-	if err := h.service.CreateHouse(&houseModel); err != nil {
-		response.ServerError(c, "创建房源失败")
-		return
+	// 指定了所在区域时补全上级区划，否则由服务层根据地址识别
+	if req.RegionCode != "" {
+		region, err := h.regionService.ResolveCode(c.Request.Context(), req.RegionCode)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		region.Apply(&houseModel)
 	}
 
-	// 将模型转换为DTO
-	houseDTO := house.DetailDTO{
-		ID:          houseModel.ID,
-		Title:       houseModel.Title,
-		Description: houseModel.Description,
-		Address:     houseModel.Address,
-		Area:        houseModel.Area,
-		Floor:       houseModel.Floor,
-		TotalFloor:  houseModel.TotalFloor,
-		Rooms:       houseModel.Rooms,
-		Halls:       houseModel.Halls,
-		Bathrooms:   houseModel.Bathrooms,
-		RentPrice:   houseModel.RentPrice,
-		Deposit:     houseModel.Deposit,
-		PaymentType: houseModel.PaymentType,
-		HouseType:   houseModel.HouseType,
-		Orientation: houseModel.Orientation,
-		Decoration:  houseModel.Decoration,
-		Facilities:  houseModel.Facilities,
-		Images:      houseModel.Images,
-		Latitude:    houseModel.Latitude,
-		Longitude:   houseModel.Longitude,
-		IsElevator:  houseModel.IsElevator,
-		Status:      houseModel.Status,
-		LandlordID:  houseModel.LandlordID,
-		ViewCount:   houseModel.ViewCount,
-		CreatedAt:   houseModel.CreatedAt,
-		UpdatedAt:   houseModel.UpdatedAt,
+	if err := h.service.CreateHouse(c.Request.Context(), &houseModel); err != nil {
+		response.Error(c, err)
+		return
 	}
 
-	response.Success(c, houseDTO)
+	// 将模型转换为DTO，自动审核未通过时包含驳回原因
+	response.Success(c, toHouseDetailDTO(&houseModel))
 }
 
 // GetHouse 获取房源详情
@@ -115,43 +118,30 @@ func (h *HouseHandler) GetHouse(c *gin.Context) {
 		return
 	}
 
-	// 增加浏览次数
-	h.service.IncrementViewCount(uint(id))
-
-	houseModel, err := h.service.GetHouseByID(uint(id))
+	houseModel, err := h.service.GetHouseByID(c.Request.Context(), uint(id))
 	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if !houseModel.IsPublic() {
 		response.NotFound(c, "房源不存在")
 		return
 	}
 
+	// 记录浏览次数，登录用户按用户去重，匿名访客按IP去重，记录失败不影响详情展示
+	visitor := "ip:" + c.ClientIP()
+	if userID, exists := c.Get("userID"); exists {
+		visitor = "u:" + strconv.FormatUint(uint64(userID.(uint)), 10)
+	}
+	_ = h.service.RecordView(c.Request.Context(), houseModel.ID, visitor)
+
 	// 将模型转换为DTO
-	houseDTO := house.DetailDTO{
-		ID:          houseModel.ID,
-		Title:       houseModel.Title,
-		Description: houseModel.Description,
-		Address:     houseModel.Address,
-		Area:        houseModel.Area,
-		Floor:       houseModel.Floor,
-		TotalFloor:  houseModel.TotalFloor,
-		Rooms:       houseModel.Rooms,
-		Halls:       houseModel.Halls,
-		Bathrooms:   houseModel.Bathrooms,
-		RentPrice:   houseModel.RentPrice,
-		Deposit:     houseModel.Deposit,
-		PaymentType: houseModel.PaymentType,
-		HouseType:   houseModel.HouseType,
-		Orientation: houseModel.Orientation,
-		Decoration:  houseModel.Decoration,
-		Facilities:  houseModel.Facilities,
-		Images:      houseModel.Images,
-		Latitude:    houseModel.Latitude,
-		Longitude:   houseModel.Longitude,
-		IsElevator:  houseModel.IsElevator,
-		Status:      houseModel.Status,
-		LandlordID:  houseModel.LandlordID,
-		ViewCount:   houseModel.ViewCount,
-		CreatedAt:   houseModel.CreatedAt,
-		UpdatedAt:   houseModel.UpdatedAt,
+	houseDTO := toHouseDetailDTO(houseModel)
+
+	// 附加最新评价，获取失败不影响房源详情展示
+	reviews, _, err := h.reviewService.GetHouseReviews(c.Request.Context(), houseModel.ID, map[string]interface{}{"limit": houseDetailReviewLimit})
+	if err == nil {
+		houseDTO.Reviews = toReviewDTOs(reviews)
 	}
 
 	response.Success(c, houseDTO)
@@ -159,60 +149,12 @@ func (h *HouseHandler) GetHouse(c *gin.Context) {
 
 // GetAllHouses 获取房源列表
 func (h *HouseHandler) GetAllHouses(c *gin.Context) {
-	// 解析查询参数
-	params := make(map[string]interface{})
-
-	// 状态筛选
-	if statusStr := c.Query("status"); statusStr != "" {
-		status, err := strconv.Atoi(statusStr)
-		if err == nil {
-			params["status"] = status
-		}
-	}
+	// 解析筛选条件
+	params := parseHouseFilter(c).ToParams()
 
-	// 房东ID筛选
-	if landlordIDStr := c.Query("landlord_id"); landlordIDStr != "" {
-		landlordID, err := strconv.ParseUint(landlordIDStr, 10, 64)
-		if err == nil {
-			params["landlord_id"] = uint(landlordID)
-		}
-	}
-
-	// 价格范围筛选
-	if minPriceStr := c.Query("min_price"); minPriceStr != "" {
-		minPrice, err := strconv.ParseFloat(minPriceStr, 64)
-		if err == nil {
-			params["min_price"] = minPrice
-		}
-	}
-
-	if maxPriceStr := c.Query("max_price"); maxPriceStr != "" {
-		maxPrice, err := strconv.ParseFloat(maxPriceStr, 64)
-		if err == nil {
-			params["max_price"] = maxPrice
-		}
-	}
-
-	// 房间数筛选
-	if roomsStr := c.Query("rooms"); roomsStr != "" {
-		rooms, err := strconv.Atoi(roomsStr)
-		if err == nil {
-			params["rooms"] = rooms
-		}
-	}
-
-	// 房屋类型筛选
-	if houseTypeStr := c.Query("house_type"); houseTypeStr != "" {
-		houseType, err := strconv.Atoi(houseTypeStr)
-		if err == nil {
-			params["house_type"] = houseType
-		}
-	}
-
-	// 关键词搜索
-	if keyword := c.Query("keyword"); keyword != "" {
-		params["keyword"] = keyword
-	}
+	// 公开列表只展示已发布且未过期的房源
+	params["status"] = model.HouseStatusPublished
+	params["not_expired"] = true
 
 	// 排序
 	if orderBy := c.Query("order_by"); orderBy != "" {
@@ -234,13 +176,80 @@ func (h *HouseHandler) GetAllHouses(c *gin.Context) {
 		}
 	}
 
-	houses, err := h.service.GetAllHouses(params)
+	// 登录用户的搜索条件用于个性化推荐，翻页时不重复记录
+	if userID, exists := c.Get("userID"); exists {
+		if offset, _ := params["offset"].(int); offset == 0 {
+			h.service.RecordSearch(c.Request.Context(), userID.(uint), params)
+		}
+	}
+
+	houses, err := h.service.GetAllHouses(c.Request.Context(), params)
 	if err != nil {
-		response.ServerError(c, "获取房源列表失败")
+		response.Error(c, err)
 		return
 	}
 
-	response.Success(c, houses)
+	// 总数和区划分布只按筛选条件统计，与分页和排序无关，区划分布统计失败不影响列表展示
+	facetParams := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		if key != "limit" && key != "offset" && key != "order_by" {
+			facetParams[key] = value
+		}
+	}
+	total, err := h.service.CountHouses(c.Request.Context(), facetParams)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	facets, _ := h.service.GetRegionFacets(c.Request.Context(), facetParams)
+	facetDTOs := make([]house.RegionFacetDTO, 0, len(facets))
+	for _, facet := range facets {
+		facetDTOs = append(facetDTOs, house.RegionFacetDTO{Code: facet.Code, Name: facet.Name, Count: facet.Count})
+	}
+
+	response.Success(c, house.SearchResponse{
+		Total:  total,
+		List:   toHouseBasicInfoDTOs(houses),
+		Facets: facetDTOs,
+	})
+}
+
+// parseHouseFilter 解析房源列表的筛选参数，格式错误的参数忽略
+func parseHouseFilter(c *gin.Context) service.HouseFilter {
+	var filter service.HouseFilter
+
+	// 房东ID筛选
+	if landlordID, err := strconv.ParseUint(c.Query("landlord_id"), 10, 64); err == nil {
+		filter.LandlordID = uint(landlordID)
+	}
+
+	// 行政区划筛选
+	filter.ProvinceCode = c.Query("province_code")
+	filter.CityCode = c.Query("city_code")
+	filter.DistrictCode = c.Query("district_code")
+	filter.CommunityCode = c.Query("community_code")
+
+	// 价格范围筛选
+	if minPrice, err := strconv.ParseFloat(c.Query("min_price"), 64); err == nil {
+		filter.MinPrice = minPrice
+	}
+	if maxPrice, err := strconv.ParseFloat(c.Query("max_price"), 64); err == nil {
+		filter.MaxPrice = maxPrice
+	}
+
+	// 房间数筛选
+	if rooms, err := strconv.Atoi(c.Query("rooms")); err == nil {
+		filter.Rooms = rooms
+	}
+
+	// 房屋类型筛选
+	if houseType, err := strconv.Atoi(c.Query("house_type")); err == nil {
+		filter.HouseType = houseType
+	}
+
+	// 关键词搜索
+	filter.Keyword = c.Query("keyword")
+	return filter
 }
 
 // UpdateHouse 更新房源
@@ -253,9 +262,9 @@ func (h *HouseHandler) UpdateHouse(c *gin.Context) {
 	}
 
 	// 检查房源是否存在
-	existingHouse, err := h.service.GetHouseByID(uint(id))
+	existingHouse, err := h.service.GetHouseByID(c.Request.Context(), uint(id))
 	if err != nil {
-		response.NotFound(c, "房源不存在")
+		response.Error(c, err)
 		return
 	}
 
@@ -272,22 +281,42 @@ func (h *HouseHandler) UpdateHouse(c *gin.Context) {
 		return
 	}
 
-	var house model.House
-	if err := c.ShouldBindJSON(&house); err != nil {
-		response.BadRequest(c, "无效的请求参数")
+	var req house.UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 验证请求参数
+	if err := house.ValidateUpdateRequest(req); err != nil {
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
-	// 设置ID和房东ID
-	house.ID = uint(id)
-	house.LandlordID = userID.(uint)
+	// 指定了所在区域时补全上级区划
+	var region *service.HouseRegion
+	if req.RegionCode != nil && *req.RegionCode != "" {
+		resolved, err := h.regionService.ResolveCode(c.Request.Context(), *req.RegionCode)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		region = &resolved
+	}
 
-	if err := h.service.UpdateHouse(&house); err != nil {
-		response.ServerError(c, "更新房源失败")
+	// 在最新的房源数据上应用修改，未传入的字段保持不变
+	houseModel, err := h.service.UpdateHouse(c.Request.Context(), uint(id), userID.(uint), func(hm *model.House) {
+		applyHouseUpdate(hm, req)
+		if region != nil {
+			region.Apply(hm)
+		}
+	})
+	if err != nil {
+		response.Error(c, err)
 		return
 	}
 
-	response.Success(c, house)
+	response.Success(c, toHouseDetailDTO(houseModel))
 }
 
 // DeleteHouse 删除房源
@@ -300,9 +329,9 @@ func (h *HouseHandler) DeleteHouse(c *gin.Context) {
 	}
 
 	// 检查房源是否存在
-	existingHouse, err := h.service.GetHouseByID(uint(id))
+	existingHouse, err := h.service.GetHouseByID(c.Request.Context(), uint(id))
 	if err != nil {
-		response.NotFound(c, "房源不存在")
+		response.Error(c, err)
 		return
 	}
 
@@ -319,8 +348,8 @@ func (h *HouseHandler) DeleteHouse(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteHouse(uint(id)); err != nil {
-		response.ServerError(c, "删除房源失败")
+	if err := h.service.DeleteHouse(c.Request.Context(), uint(id)); err != nil {
+		response.Error(c, err)
 		return
 	}
 
@@ -336,11 +365,712 @@ func (h *HouseHandler) GetLandlordHouses(c *gin.Context) {
 		return
 	}
 
-	houses, err := h.service.GetHousesByLandlordID(userID.(uint))
+	houses, err := h.service.GetHousesByLandlordID(c.Request.Context(), userID.(uint))
 	if err != nil {
-		response.ServerError(c, "获取房源列表失败")
+		response.Error(c, err)
 		return
 	}
 
 	response.Success(c, houses)
 }
+
+// SubmitHouse 房东提交房源审核
+func (h *HouseHandler) SubmitHouse(c *gin.Context) {
+	id, userID, ok := parseHouseOwnerRequest(c)
+	if !ok {
+		return
+	}
+
+	houseModel, err := h.service.SubmitHouse(c.Request.Context(), id, userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"status":        houseModel.Status,
+		"status_text":   house.GetStatusText(houseModel.Status),
+		"reject_reason": houseModel.RejectReason,
+	})
+}
+
+// OfflineHouse 房东下架房源
+func (h *HouseHandler) OfflineHouse(c *gin.Context) {
+	id, userID, ok := parseHouseOwnerRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.OfflineHouse(c.Request.Context(), id, userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// MarkHouseRented 房东将房源标记为已出租
+func (h *HouseHandler) MarkHouseRented(c *gin.Context) {
+	id, userID, ok := parseHouseOwnerRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.MarkHouseRented(c.Request.Context(), id, userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// RefreshHouse 房东刷新房源上架有效期
+func (h *HouseHandler) RefreshHouse(c *gin.Context) {
+	id, userID, ok := parseHouseOwnerRequest(c)
+	if !ok {
+		return
+	}
+
+	houseModel, err := h.service.RefreshHouse(c.Request.Context(), id, userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"expire_at": houseModel.ExpireAt})
+}
+
+// GetTrendingHouses 获取热门房源，可按城市、区县和房屋类型筛选
+func (h *HouseHandler) GetTrendingHouses(c *gin.Context) {
+	var req house.TrendingQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultTrendingLimit
+	}
+
+	params := make(map[string]interface{})
+	if req.CityCode != "" {
+		params["city_code"] = req.CityCode
+	}
+	if req.DistrictCode != "" {
+		params["district_code"] = req.DistrictCode
+	}
+	if req.HouseType > 0 {
+		params["house_type"] = req.HouseType
+	}
+
+	trending, err := h.service.GetTrendingHouses(c.Request.Context(), params, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	houses := make([]model.House, len(trending))
+	for i := range trending {
+		houses[i] = trending[i].House
+	}
+	basicInfos := toHouseBasicInfoDTOs(houses)
+	list := make([]house.TrendingDTO, len(trending))
+	for i := range trending {
+		list[i] = house.TrendingDTO{BasicInfoDTO: basicInfos[i], Score: trending[i].Score}
+	}
+
+	response.Success(c, house.TrendingListResponse{List: list})
+}
+
+// GetRecommendations 获取推荐房源，登录用户根据收藏、预约看房和搜索记录推荐，否则返回热门房源
+func (h *HouseHandler) GetRecommendations(c *gin.Context) {
+	var req house.RecommendQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultRecommendLimit
+	}
+
+	var userID uint
+	if id, exists := c.Get("userID"); exists {
+		userID = id.(uint)
+	}
+
+	houses, personalized, err := h.service.GetRecommendations(c.Request.Context(), userID, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, house.RecommendListResponse{
+		List:         toHouseBasicInfoDTOs(houses),
+		Personalized: personalized,
+	})
+}
+
+// GetSimilarHouses 获取与指定房源相似的房源
+func (h *HouseHandler) GetSimilarHouses(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的房源ID")
+		return
+	}
+
+	var req house.RecommendQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultRecommendLimit
+	}
+
+	houseModel, err := h.service.GetHouseByID(c.Request.Context(), uint(id))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if !houseModel.IsPublic() {
+		response.NotFound(c, "房源不存在")
+		return
+	}
+
+	houses, err := h.service.GetSimilarHouses(c.Request.Context(), houseModel.ID, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, house.ListResponse{
+		Total: len(houses),
+		List:  toHouseBasicInfoDTOs(houses),
+	})
+}
+
+// CompareHouses 对比多套房源的租金、押金、楼层、装修、配套设施及到参考位置的距离
+func (h *HouseHandler) CompareHouses(c *gin.Context) {
+	result, ok := h.compareHouses(c)
+	if !ok {
+		return
+	}
+
+	houses := make([]model.House, len(result.Items))
+	for i, item := range result.Items {
+		houses[i] = item.House
+	}
+	basicInfos := toHouseBasicInfoDTOs(houses)
+
+	list := make([]house.CompareItemDTO, 0, len(result.Items))
+	for i, item := range result.Items {
+		list = append(list, house.CompareItemDTO{
+			BasicInfoDTO: basicInfos[i],
+			RentPerSqm:   item.RentPerSqm,
+			Deposit:      item.House.Deposit,
+			PaymentType:  item.House.PaymentType,
+			Floor:        item.House.Floor,
+			TotalFloor:   item.House.TotalFloor,
+			IsElevator:   item.House.IsElevator,
+			Orientation:  item.House.Orientation,
+			Facilities:   item.Facilities,
+			Distance:     item.Distance,
+		})
+	}
+
+	facilities := make([]house.CompareFacilityDTO, 0, len(result.Facilities))
+	for _, name := range result.Facilities {
+		flags := make([]bool, len(result.Items))
+		for i := range result.Items {
+			flags[i] = result.HasFacility(i, name)
+		}
+		facilities = append(facilities, house.CompareFacilityDTO{Name: name, Houses: flags})
+	}
+
+	response.Success(c, house.CompareResponse{List: list, Facilities: facilities})
+}
+
+// ExportComparison 导出房源对比表（CSV格式，每列一套房源）
+func (h *HouseHandler) ExportComparison(c *gin.Context) {
+	result, ok := h.compareHouses(c)
+	if !ok {
+		return
+	}
+
+	data, err := buildComparisonSheet(result)
+	if err != nil {
+		response.Error(c, errors.Wrap(err, errors.CodeInternal, "导出对比表失败"))
+		return
+	}
+	response.Attachment(c, "house_compare.csv", "text/csv; charset=utf-8", data)
+}
+
+// compareHouses 解析对比请求并获取对比结果，失败时已写入响应
+func (h *HouseHandler) compareHouses(c *gin.Context) (*service.HouseCompareResult, bool) {
+	var req house.CompareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return nil, false
+	}
+
+	var origin *geo.Point
+	if req.Latitude != nil || req.Longitude != nil {
+		if req.Latitude == nil || req.Longitude == nil {
+			response.BadRequest(c, "参考位置需要同时提供经度和纬度")
+			return nil, false
+		}
+		origin = &geo.Point{Latitude: *req.Latitude, Longitude: *req.Longitude}
+	}
+
+	result, err := h.service.CompareHouses(c.Request.Context(), req.HouseIDs, origin)
+	if err != nil {
+		response.Error(c, err)
+		return nil, false
+	}
+	return result, true
+}
+
+// buildComparisonSheet 生成房源对比表，首列为对比项，之后每列一套房源
+// 文件以UTF-8 BOM开头，便于Excel正确识别中文
+func buildComparisonSheet(result *service.HouseCompareResult) ([]byte, error) {
+	number := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	fields := []struct {
+		label string
+		value func(item service.HouseComparison) string
+	}{
+		{"房源ID", func(i service.HouseComparison) string { return strconv.FormatUint(uint64(i.House.ID), 10) }},
+		{"标题", func(i service.HouseComparison) string { return i.House.Title }},
+		{"地址", func(i service.HouseComparison) string { return i.House.Address }},
+		{"租金(元/月)", func(i service.HouseComparison) string { return number(i.House.RentPrice) }},
+		{"面积(平方米)", func(i service.HouseComparison) string { return number(i.House.Area) }},
+		{"每平方米租金(元/月)", func(i service.HouseComparison) string { return number(i.RentPerSqm) }},
+		{"户型", func(i service.HouseComparison) string {
+			return fmt.Sprintf("%d室%d厅%d卫", i.House.Rooms, i.House.Halls, i.House.Bathrooms)
+		}},
+		{"房屋类型", func(i service.HouseComparison) string { return house.GetHouseTypeText(i.House.HouseType) }},
+		{"押金(元)", func(i service.HouseComparison) string { return number(i.House.Deposit) }},
+		{"支付方式", func(i service.HouseComparison) string { return house.GetPaymentTypeText(i.House.PaymentType) }},
+		{"楼层", func(i service.HouseComparison) string {
+			return fmt.Sprintf("%d/%d层", i.House.Floor, i.House.TotalFloor)
+		}},
+		{"电梯", func(i service.HouseComparison) string { return yesNo(i.House.IsElevator) }},
+		{"装修", func(i service.HouseComparison) string { return house.GetDecorationText(i.House.Decoration) }},
+		{"朝向", func(i service.HouseComparison) string { return i.House.Orientation }},
+		{"距离(千米)", func(i service.HouseComparison) string {
+			if i.Distance == nil {
+				return "-"
+			}
+			return number(*i.Distance)
+		}},
+	}
+
+	rows := make([][]string, 0, len(fields)+len(result.Facilities))
+	for _, field := range fields {
+		row := []string{field.label}
+		for _, item := range result.Items {
+			row = append(row, escapeFormula(field.value(item)))
+		}
+		rows = append(rows, row)
+	}
+	for _, name := range result.Facilities {
+		row := []string{escapeFormula(name)}
+		for i := range result.Items {
+			row = append(row, yesNo(result.HasFacility(i, name)))
+		}
+		rows = append(rows, row)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\uFEFF")
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// escapeFormula 防止CSV公式注入：以=、+、-、@、制表符或回车开头的单元格会被Excel当作公式执行，
+// 在开头加上单引号使其按文本显示
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// yesNo 将布尔值转换为“有/无”
+func yesNo(v bool) string {
+	if v {
+		return "有"
+	}
+	return "无"
+}
+
+// GetPendingHouses 管理员获取待审核房源列表
+func (h *HouseHandler) GetPendingHouses(c *gin.Context) {
+	var req house.ModerationQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	page, pageSize := req.GetDefaultPage(), req.GetDefaultPageSize()
+
+	houses, total, err := h.service.GetPendingHouses(c.Request.Context(), map[string]interface{}{
+		"limit":  pageSize,
+		"offset": (page - 1) * pageSize,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	basicInfos := toHouseBasicInfoDTOs(houses)
+	list := make([]house.ModerationDTO, 0, len(houses))
+	for i, hm := range houses {
+		flags := []string{}
+		if hm.ModerationFlags != "" {
+			_ = json.Unmarshal([]byte(hm.ModerationFlags), &flags)
+		}
+		list = append(list, house.ModerationDTO{
+			BasicInfoDTO:    basicInfos[i],
+			Description:     hm.Description,
+			RejectReason:    hm.RejectReason,
+			ModerationFlags: flags,
+			PublishedAt:     hm.PublishedAt,
+			ExpireAt:        hm.ExpireAt,
+			UpdatedAt:       hm.UpdatedAt,
+		})
+	}
+
+	response.Success(c, house.ModerationListResponse{
+		List:       list,
+		Pagination: common.NewPaginationResponse(total, page, pageSize),
+	})
+}
+
+// ApproveHouse 管理员审核通过房源
+func (h *HouseHandler) ApproveHouse(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的房源ID")
+		return
+	}
+
+	// 从上下文获取管理员ID（由JWT中间件设置）
+	reviewerID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	if err := h.service.ApproveHouse(c.Request.Context(), uint(id), reviewerID.(uint)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// RejectHouse 管理员驳回房源
+func (h *HouseHandler) RejectHouse(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的房源ID")
+		return
+	}
+
+	var req house.RejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 验证请求参数
+	if err := house.ValidateRejectRequest(req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 从上下文获取管理员ID（由JWT中间件设置）
+	reviewerID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	if err := h.service.RejectHouse(c.Request.Context(), uint(id), reviewerID.(uint), req.Reason); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// GetHouseHistory 获取房源修改记录，仅房东本人和管理员可查看
+func (h *HouseHandler) GetHouseHistory(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的房源ID")
+		return
+	}
+
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	houseModel, err := h.service.GetHouseByID(c.Request.Context(), uint(id))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// 管理员路由由AdminAuth中间件设置用户类型，其余情况只允许房东本人查看
+	if c.GetInt("userType") != model.UserTypeAdmin && houseModel.LandlordID != userID.(uint) {
+		response.Forbidden(c, "无权查看该房源的修改记录")
+		return
+	}
+
+	var req house.HistoryQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	page, pageSize := req.GetDefaultPage(), req.GetDefaultPageSize()
+
+	revisions, total, err := h.service.GetHouseRevisions(c.Request.Context(), houseModel.ID, map[string]interface{}{
+		"limit":  pageSize,
+		"offset": (page - 1) * pageSize,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	list := make([]house.RevisionDTO, 0, len(revisions))
+	for _, r := range revisions {
+		changes := []house.FieldChangeDTO{}
+		_ = json.Unmarshal([]byte(r.Changes), &changes)
+		list = append(list, house.RevisionDTO{
+			ID:         r.ID,
+			OperatorID: r.OperatorID,
+			Changes:    changes,
+			CreatedAt:  r.CreatedAt,
+		})
+	}
+
+	response.Success(c, house.RevisionListResponse{
+		List:       list,
+		Pagination: common.NewPaginationResponse(total, page, pageSize),
+	})
+}
+
+// GetPriceHistory 获取房源租金走势，公开可见
+func (h *HouseHandler) GetPriceHistory(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的房源ID")
+		return
+	}
+
+	houseModel, err := h.service.GetHouseByID(c.Request.Context(), uint(id))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if !houseModel.IsPublic() {
+		response.NotFound(c, "房源不存在")
+		return
+	}
+
+	histories, err := h.service.GetPriceHistory(c.Request.Context(), houseModel.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	resp := house.PriceHistoryResponse{
+		HouseID:      houseModel.ID,
+		CurrentPrice: houseModel.RentPrice,
+		LowestPrice:  houseModel.RentPrice,
+		HighestPrice: houseModel.RentPrice,
+		Points:       make([]house.PricePointDTO, 0, len(histories)),
+	}
+	for _, p := range histories {
+		resp.Points = append(resp.Points, house.PricePointDTO{
+			Price:     p.Price,
+			PrevPrice: p.PrevPrice,
+			ChangedAt: p.CreatedAt,
+		})
+		if p.Price < resp.LowestPrice {
+			resp.LowestPrice = p.Price
+		}
+		if p.Price > resp.HighestPrice {
+			resp.HighestPrice = p.Price
+		}
+	}
+
+	response.Success(c, resp)
+}
+
+// parseHouseOwnerRequest 解析房东操作房源请求中的房源ID和当前用户ID，解析失败时直接写入错误响应
+func parseHouseOwnerRequest(c *gin.Context) (uint, uint, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的房源ID")
+		return 0, 0, false
+	}
+
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return 0, 0, false
+	}
+
+	return uint(id), userID.(uint), true
+}
+
+// applyHouseUpdate 将更新请求中传入的字段应用到房源模型
+func applyHouseUpdate(h *model.House, req house.UpdateRequest) {
+	if req.Title != nil {
+		h.Title = *req.Title
+	}
+	if req.Description != nil {
+		h.Description = *req.Description
+	}
+	if req.Address != nil {
+		h.Address = *req.Address
+	}
+	if req.Area != nil {
+		h.Area = *req.Area
+	}
+	if req.Floor != nil {
+		h.Floor = *req.Floor
+	}
+	if req.TotalFloor != nil {
+		h.TotalFloor = *req.TotalFloor
+	}
+	if req.Rooms != nil {
+		h.Rooms = *req.Rooms
+	}
+	if req.Halls != nil {
+		h.Halls = *req.Halls
+	}
+	if req.Bathrooms != nil {
+		h.Bathrooms = *req.Bathrooms
+	}
+	if req.RentPrice != nil {
+		h.RentPrice = *req.RentPrice
+	}
+	if req.Deposit != nil {
+		h.Deposit = *req.Deposit
+	}
+	if req.PaymentType != nil {
+		h.PaymentType = *req.PaymentType
+	}
+	if req.HouseType != nil {
+		h.HouseType = *req.HouseType
+	}
+	if req.Orientation != nil {
+		h.Orientation = *req.Orientation
+	}
+	if req.Decoration != nil {
+		h.Decoration = *req.Decoration
+	}
+	if req.Facilities != nil {
+		h.Facilities = *req.Facilities
+	}
+	if req.Images != nil {
+		h.Images = *req.Images
+	}
+	if req.Latitude != nil {
+		h.Latitude = *req.Latitude
+	}
+	if req.Longitude != nil {
+		h.Longitude = *req.Longitude
+	}
+	if req.IsElevator != nil {
+		h.IsElevator = *req.IsElevator
+	}
+}
+
+// toHouseDetailDTO 将房源模型转换为详细信息DTO
+func toHouseDetailDTO(h *model.House) house.DetailDTO {
+	return house.DetailDTO{
+		ID:            h.ID,
+		Title:         h.Title,
+		Description:   h.Description,
+		Address:       h.Address,
+		ProvinceCode:  h.ProvinceCode,
+		CityCode:      h.CityCode,
+		DistrictCode:  h.DistrictCode,
+		CommunityCode: h.CommunityCode,
+		Area:          h.Area,
+		Floor:         h.Floor,
+		TotalFloor:    h.TotalFloor,
+		Rooms:         h.Rooms,
+		Halls:         h.Halls,
+		Bathrooms:     h.Bathrooms,
+		RentPrice:     h.RentPrice,
+		Deposit:       h.Deposit,
+		PaymentType:   h.PaymentType,
+		HouseType:     h.HouseType,
+		Orientation:   h.Orientation,
+		Decoration:    h.Decoration,
+		Facilities:    h.Facilities,
+		Images:        h.Images,
+		Latitude:      h.Latitude,
+		Longitude:     h.Longitude,
+		IsElevator:    h.IsElevator,
+		Status:        h.Status,
+		StatusText:    house.GetStatusText(h.Status),
+		RejectReason:  h.RejectReason,
+		LandlordID:    h.LandlordID,
+		ViewCount:     h.ViewCount,
+		Rating:        h.Rating,
+		ReviewCount:   h.ReviewCount,
+		PublishedAt:   h.PublishedAt,
+		ExpireAt:      h.ExpireAt,
+		CreatedAt:     h.CreatedAt,
+		UpdatedAt:     h.UpdatedAt,
+	}
+}
+
+// toHouseBasicInfoDTOs 将房源模型列表转换为基本信息DTO列表
+func toHouseBasicInfoDTOs(houses []model.House) []house.BasicInfoDTO {
+	list := make([]house.BasicInfoDTO, 0, len(houses))
+	for _, h := range houses {
+		list = append(list, house.BasicInfoDTO{
+			ID:            h.ID,
+			Title:         h.Title,
+			Address:       h.Address,
+			ProvinceCode:  h.ProvinceCode,
+			CityCode:      h.CityCode,
+			DistrictCode:  h.DistrictCode,
+			CommunityCode: h.CommunityCode,
+			Area:          h.Area,
+			Rooms:         h.Rooms,
+			Halls:         h.Halls,
+			Bathrooms:     h.Bathrooms,
+			RentPrice:     h.RentPrice,
+			HouseType:     h.HouseType,
+			Decoration:    h.Decoration,
+			Images:        h.Images,
+			LandlordID:    h.LandlordID,
+			Status:        h.Status,
+			ViewCount:     h.ViewCount,
+			CreatedAt:     h.CreatedAt,
+		})
+	}
+	return list
+}
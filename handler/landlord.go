@@ -2,9 +2,13 @@ package handler
 
 import (
 	"strconv"
+	"strings"
 
+	"myApp/dto/common"
 	"myApp/dto/landlord"
+	"myApp/dto/review"
 	"myApp/model"
+	"myApp/pkg/errors"
 	"myApp/pkg/response"
 	"myApp/service"
 
@@ -13,25 +17,30 @@ import (
 
 // LandlordHandler 房东处理器结构体，负责处理房东相关的HTTP请求
 type LandlordHandler struct {
-	service service.LandlordService
+	service       service.LandlordService
+	houseService  service.HouseService
+	reviewService service.ReviewService
 }
 
-// NewLandlordHandler 创建房东处理器实例，注入房东服务依赖
-func NewLandlordHandler(s service.LandlordService) *LandlordHandler {
-	return &LandlordHandler{service: s}
+// 房东资料中展示的最新评价数量
+const landlordProfileReviewLimit = 5
+
+// NewLandlordHandler 创建房东处理器实例，注入房东、房源和评价服务依赖
+func NewLandlordHandler(s service.LandlordService, hs service.HouseService, rs service.ReviewService) *LandlordHandler {
+	return &LandlordHandler{service: s, houseService: hs, reviewService: rs}
 }
 
 // CreateLandlord 创建房东信息
 func (h *LandlordHandler) CreateLandlord(c *gin.Context) {
 	var req landlord.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "无效的请求参数")
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
 	// 验证请求参数
 	if err := landlord.ValidateRegisterRequest(req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
@@ -58,8 +67,8 @@ func (h *LandlordHandler) CreateLandlord(c *gin.Context) {
 		Verified:     false, // 默认未认证
 	}
 
-	if err := h.service.CreateLandlord(&landlordModel); err != nil {
-		response.ServerError(c, err.Error())
+	if err := h.service.CreateLandlord(c.Request.Context(), &landlordModel, req.Documents); err != nil {
+		response.Error(c, err)
 		return
 	}
 
@@ -94,13 +103,99 @@ func (h *LandlordHandler) GetLandlordProfile(c *gin.Context) {
 		return
 	}
 
-	landlord, err := h.service.GetLandlordByUserID(userID.(uint))
+	landlordModel, err := h.service.GetLandlordByUserID(c.Request.Context(), userID.(uint))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// 将模型转换为DTO
+	landlordDTO := landlord.DetailDTO{
+		ID:           landlordModel.ID,
+		UserID:       landlordModel.UserID,
+		RealName:     landlordModel.RealName,
+		IDNumber:     landlordModel.IDNumber,
+		PhoneNumber:  landlordModel.PhoneNumber,
+		Address:      landlordModel.Address,
+		Verified:     landlordModel.Verified,
+		IdCardFront:  landlordModel.IdCardFront,
+		IdCardBack:   landlordModel.IdCardBack,
+		BankAccount:  landlordModel.BankAccount,
+		BankName:     landlordModel.BankName,
+		AccountName:  landlordModel.AccountName,
+		Introduction: landlordModel.Introduction,
+		Rating:       landlordModel.Rating,
+		ReviewCount:  landlordModel.ReviewCount,
+		CreatedAt:    landlordModel.CreatedAt,
+	}
+
+	// 附加最新评价，获取失败不影响资料展示
+	reviews, _, err := h.reviewService.GetLandlordReviews(c.Request.Context(), landlordModel.ID, map[string]interface{}{"limit": landlordProfileReviewLimit})
+	if err == nil {
+		landlordDTO.Reviews = toReviewDTOs(reviews)
+	}
+
+	response.Success(c, landlordDTO)
+}
+
+// GetPublicProfile 获取房东公开主页，包含脱敏的房东信息和分页的在租房源
+func (h *LandlordHandler) GetPublicProfile(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的房东ID")
+		return
+	}
+
+	var req common.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	page, pageSize := req.GetDefaultPage(), req.GetDefaultPageSize()
+
+	profile, err := h.service.GetPublicProfile(c.Request.Context(), uint(id))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	landlordModel := profile.Landlord
+
+	// 获取房东上架中的房源，房源的LandlordID为房东的用户ID
+	houses, err := h.houseService.GetAllHouses(c.Request.Context(), map[string]interface{}{
+		"landlord_id": landlordModel.UserID,
+		"status":      model.HouseStatusPublished,
+		"not_expired": true,
+		"limit":       pageSize,
+		"offset":      (page - 1) * pageSize,
+	})
 	if err != nil {
-		response.NotFound(c, "房东信息不存在")
+		response.Error(c, err)
 		return
 	}
 
-	response.Success(c, landlord)
+	profileDTO := landlord.PublicProfileDTO{
+		ID:             landlordModel.ID,
+		DisplayName:    maskName(landlordModel.RealName),
+		Verified:       landlordModel.Verified,
+		Rating:         landlordModel.Rating,
+		ReviewCount:    landlordModel.ReviewCount,
+		Introduction:   landlordModel.Introduction,
+		ResponseRate:   profile.ResponseRate,
+		ActiveListings: profile.ActiveListings,
+		Houses:         toHouseBasicInfoDTOs(houses),
+		Pagination:     common.NewPaginationResponse(profile.ActiveListings, page, pageSize),
+		Reviews:        []review.DetailDTO{},
+		CreatedAt:      landlordModel.CreatedAt,
+	}
+
+	// 附加最新评价，获取失败不影响主页展示
+	reviews, _, err := h.reviewService.GetLandlordReviews(c.Request.Context(), landlordModel.ID, map[string]interface{}{"limit": landlordProfileReviewLimit})
+	if err == nil {
+		profileDTO.Reviews = toReviewDTOs(reviews)
+	}
+
+	response.Success(c, profileDTO)
 }
 
 // UpdateLandlord 更新房东信息
@@ -113,20 +208,21 @@ func (h *LandlordHandler) UpdateLandlord(c *gin.Context) {
 	}
 
 	// 检查是否为本人操作
-	existingLandlord, err := h.service.GetLandlordByUserID(userID.(uint))
+	existingLandlord, err := h.service.GetLandlordByUserID(c.Request.Context(), userID.(uint))
 	if err != nil {
-		response.NotFound(c, "房东信息不存在")
+		response.Error(c, err)
 		return
 	}
 
 	// 解析请求数据
 	var updateData map[string]interface{}
 	if err := c.ShouldBindJSON(&updateData); err != nil {
-		response.BadRequest(c, "无效的请求参数")
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
 	// 更新房东信息，只更新请求中包含的字段
+	// 姓名、身份证号等身份信息需通过认证申请修改
 	for key, value := range updateData {
 		switch key {
 		case "phone_number":
@@ -137,14 +233,6 @@ func (h *LandlordHandler) UpdateLandlord(c *gin.Context) {
 			if address, ok := value.(string); ok {
 				existingLandlord.Address = address
 			}
-		case "id_card_front":
-			if idCardFront, ok := value.(string); ok {
-				existingLandlord.IdCardFront = idCardFront
-			}
-		case "id_card_back":
-			if idCardBack, ok := value.(string); ok {
-				existingLandlord.IdCardBack = idCardBack
-			}
 		case "bank_account":
 			if bankAccount, ok := value.(string); ok {
 				existingLandlord.BankAccount = bankAccount
@@ -161,45 +249,196 @@ func (h *LandlordHandler) UpdateLandlord(c *gin.Context) {
 			if introduction, ok := value.(string); ok {
 				existingLandlord.Introduction = introduction
 			}
-		case "real_name":
-			if realName, ok := value.(string); ok {
-				existingLandlord.RealName = realName
-			}
-		case "id_number":
-			if idNumber, ok := value.(string); ok {
-				existingLandlord.IDNumber = idNumber
-			}
 		}
 	}
 
-	if err := h.service.UpdateLandlord(existingLandlord); err != nil {
-		response.ServerError(c, err.Error())
+	if err := h.service.UpdateLandlord(c.Request.Context(), existingLandlord); err != nil {
+		response.Error(c, err)
 		return
 	}
 
 	response.Success(c, existingLandlord)
 }
 
-// VerifyLandlord 管理员验证房东身份
-func (h *LandlordHandler) VerifyLandlord(c *gin.Context) {
+// SubmitVerification 提交（或驳回后重新提交）房东认证申请
+func (h *LandlordHandler) SubmitVerification(c *gin.Context) {
+	var req landlord.VerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 验证请求参数
+	if err := landlord.ValidateVerificationRequest(req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	verificationModel := model.LandlordVerification{
+		RealName:    req.RealName,
+		IDNumber:    req.IDNumber,
+		IdCardFront: req.IdCardFront,
+		IdCardBack:  req.IdCardBack,
+		Documents:   req.Documents,
+	}
+
+	if err := h.service.SubmitVerification(c.Request.Context(), userID.(uint), &verificationModel); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, toVerificationDTO(&verificationModel))
+}
+
+// GetVerification 获取当前房东最近一次认证申请的审核状态
+func (h *LandlordHandler) GetVerification(c *gin.Context) {
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	verification, err := h.service.GetLatestVerification(c.Request.Context(), userID.(uint))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, toVerificationDTO(verification))
+}
+
+// GetVerificationQueue 管理员获取认证申请审核队列
+func (h *LandlordHandler) GetVerificationQueue(c *gin.Context) {
+	var req landlord.VerificationQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	page := req.GetDefaultPage()
+	pageSize := req.GetDefaultPageSize()
+
+	// 默认只查询待审核的申请
+	status := model.VerificationPending
+	if req.Status != nil {
+		status = *req.Status
+	}
+
+	params := map[string]interface{}{
+		"status": status,
+		"limit":  pageSize,
+		"offset": (page - 1) * pageSize,
+	}
+
+	verifications, total, err := h.service.GetVerificationQueue(c.Request.Context(), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	list := make([]landlord.VerificationDTO, 0, len(verifications))
+	for i := range verifications {
+		list = append(list, toVerificationDTO(&verifications[i]))
+	}
+
+	response.Success(c, landlord.VerificationListResponse{
+		List:       list,
+		Pagination: common.NewPaginationResponse(total, page, pageSize),
+	})
+}
+
+// ApproveVerification 管理员审核通过认证申请
+func (h *LandlordHandler) ApproveVerification(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(c, "无效的房东ID")
+		response.BadRequest(c, "无效的申请ID")
 		return
 	}
 
-	// 从上下文获取用户ID和类型（由JWT中间件设置）
-	userType, exists := c.Get("userType")
-	if !exists || userType.(int) != 2 { // 2-管理员
-		response.Forbidden(c, "无权进行此操作")
+	// 从上下文获取管理员ID（由JWT中间件设置）
+	reviewerID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
 		return
 	}
 
-	if err := h.service.VerifyLandlord(uint(id)); err != nil {
-		response.ServerError(c, err.Error())
+	if err := h.service.ApproveVerification(c.Request.Context(), uint(id), reviewerID.(uint)); err != nil {
+		response.Error(c, err)
 		return
 	}
 
-	response.Success(c, gin.H{"message": "房东验证成功"})
+	response.Success(c, gin.H{"message": "房东认证已通过"})
+}
+
+// RejectVerification 管理员驳回认证申请
+func (h *LandlordHandler) RejectVerification(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的申请ID")
+		return
+	}
+
+	var req landlord.RejectVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 验证请求参数
+	if err := landlord.ValidateRejectVerificationRequest(req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 从上下文获取管理员ID（由JWT中间件设置）
+	reviewerID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	if err := h.service.RejectVerification(c.Request.Context(), uint(id), reviewerID.(uint), req.Reason); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "已驳回认证申请"})
+}
+
+// maskName 对姓名脱敏，仅保留姓氏，例如"张三"显示为"张*"
+func maskName(name string) string {
+	runes := []rune(name)
+	if len(runes) == 0 {
+		return "房东"
+	}
+	return string(runes[0]) + strings.Repeat("*", len(runes)-1)
+}
+
+// toVerificationDTO 将认证申请模型转换为DTO
+func toVerificationDTO(v *model.LandlordVerification) landlord.VerificationDTO {
+	return landlord.VerificationDTO{
+		ID:           v.ID,
+		LandlordID:   v.LandlordID,
+		UserID:       v.UserID,
+		RealName:     v.RealName,
+		IDNumber:     v.IDNumber,
+		IdCardFront:  v.IdCardFront,
+		IdCardBack:   v.IdCardBack,
+		Documents:    v.Documents,
+		Status:       v.Status,
+		StatusText:   landlord.GetVerificationStatusText(v.Status),
+		RejectReason: v.RejectReason,
+		ReviewedAt:   v.ReviewedAt,
+		CreatedAt:    v.CreatedAt,
+	}
 }
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"strconv"
+
+	"myApp/dto/common"
+	"myApp/dto/notification"
+	"myApp/pkg/errors"
+	"myApp/pkg/response"
+	"myApp/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler 站内通知处理器结构体，负责处理通知相关的HTTP请求
+type NotificationHandler struct {
+	service service.NotificationService
+}
+
+// NewNotificationHandler 创建站内通知处理器实例，注入通知服务依赖
+func NewNotificationHandler(s service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: s}
+}
+
+// GetNotifications 获取当前用户的通知列表
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	var req notification.QueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	page, pageSize := req.GetDefaultPage(), req.GetDefaultPageSize()
+
+	params := map[string]interface{}{
+		"limit":  pageSize,
+		"offset": (page - 1) * pageSize,
+	}
+	if req.Unread {
+		params["is_read"] = false
+	}
+
+	notifications, total, err := h.service.GetUserNotifications(c.Request.Context(), userID.(uint), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	list := make([]notification.DetailDTO, 0, len(notifications))
+	for _, n := range notifications {
+		list = append(list, notification.DetailDTO{
+			ID:        n.ID,
+			Type:      n.Type,
+			Title:     n.Title,
+			Content:   n.Content,
+			RelatedID: n.RelatedID,
+			IsRead:    n.IsRead,
+			ReadAt:    n.ReadAt,
+			CreatedAt: n.CreatedAt,
+		})
+	}
+
+	response.Success(c, notification.ListResponse{
+		List:       list,
+		Pagination: common.NewPaginationResponse(total, page, pageSize),
+	})
+}
+
+// GetUnreadCount 获取当前用户的未读通知数量
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	count, err := h.service.GetUnreadCount(c.Request.Context(), userID.(uint))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, notification.UnreadCountResponse{Count: count})
+}
+
+// MarkRead 将通知标记为已读
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的通知ID")
+		return
+	}
+
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	if err := h.service.MarkRead(c.Request.Context(), uint(id), userID.(uint)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// MarkAllRead 将当前用户的全部通知标记为已读
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	if err := h.service.MarkAllRead(c.Request.Context(), userID.(uint)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"myApp/dto/region"
+	"myApp/model"
+	"myApp/pkg/errors"
+	"myApp/pkg/response"
+	"myApp/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegionHandler 行政区划处理器结构体，负责处理行政区划相关的HTTP请求
+type RegionHandler struct {
+	service service.RegionService
+}
+
+// NewRegionHandler 创建行政区划处理器实例，注入行政区划服务依赖
+func NewRegionHandler(s service.RegionService) *RegionHandler {
+	return &RegionHandler{service: s}
+}
+
+// GetChildren 获取下级行政区划，不传上级代码时返回省级区划
+func (h *RegionHandler) GetChildren(c *gin.Context) {
+	var req region.ChildrenRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	regions, err := h.service.GetChildren(c.Request.Context(), req.ParentCode)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, region.ListResponse{List: toRegionDTOs(regions)})
+}
+
+// GetPath 获取从省级到指定区划的完整路径
+func (h *RegionHandler) GetPath(c *gin.Context) {
+	regions, err := h.service.GetPath(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, region.ListResponse{List: toRegionDTOs(regions)})
+}
+
+// toRegionDTOs 将行政区划列表转换为DTO列表
+func toRegionDTOs(regions []model.Region) []region.DetailDTO {
+	list := make([]region.DetailDTO, 0, len(regions))
+	for _, r := range regions {
+		list = append(list, region.DetailDTO{
+			Code:       r.Code,
+			ParentCode: r.ParentCode,
+			Name:       r.Name,
+			Level:      r.Level,
+		})
+	}
+	return list
+}
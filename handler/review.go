@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"strconv"
+
+	"myApp/dto/common"
+	"myApp/dto/review"
+	"myApp/model"
+	"myApp/pkg/errors"
+	"myApp/pkg/response"
+	"myApp/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewHandler 评价处理器结构体，负责处理评价相关的HTTP请求
+type ReviewHandler struct {
+	service service.ReviewService
+}
+
+// NewReviewHandler 创建评价处理器实例，注入评价服务依赖
+func NewReviewHandler(s service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{service: s}
+}
+
+// CreateReview 创建评价
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	var req review.CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 验证请求参数
+	if err := review.ValidateCreateRequest(req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	// 将DTO转换为模型
+	reviewModel := model.Review{
+		UserID:         userID.(uint),
+		ViewingID:      req.ViewingID,
+		LandlordRating: req.LandlordRating,
+		HouseRating:    req.HouseRating,
+		Content:        req.Content,
+	}
+
+	if err := h.service.CreateReview(c.Request.Context(), &reviewModel); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, toReviewDTO(&reviewModel))
+}
+
+// GetHouseReviews 获取房源的评价列表
+func (h *ReviewHandler) GetHouseReviews(c *gin.Context) {
+	houseIDStr := c.Param("house_id")
+	houseID, err := strconv.ParseUint(houseIDStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的房源ID")
+		return
+	}
+
+	var req review.QueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	page, pageSize := req.GetDefaultPage(), req.GetDefaultPageSize()
+
+	reviews, total, err := h.service.GetHouseReviews(c.Request.Context(), uint(houseID), map[string]interface{}{
+		"limit":  pageSize,
+		"offset": (page - 1) * pageSize,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, review.ListResponse{
+		List:       toReviewDTOs(reviews),
+		Pagination: common.NewPaginationResponse(total, page, pageSize),
+	})
+}
+
+// GetLandlordReviews 获取房东的评价列表
+func (h *ReviewHandler) GetLandlordReviews(c *gin.Context) {
+	landlordIDStr := c.Param("landlord_id")
+	landlordID, err := strconv.ParseUint(landlordIDStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的房东ID")
+		return
+	}
+
+	var req review.QueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	page, pageSize := req.GetDefaultPage(), req.GetDefaultPageSize()
+
+	reviews, total, err := h.service.GetLandlordReviews(c.Request.Context(), uint(landlordID), map[string]interface{}{
+		"limit":  pageSize,
+		"offset": (page - 1) * pageSize,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, review.ListResponse{
+		List:       toReviewDTOs(reviews),
+		Pagination: common.NewPaginationResponse(total, page, pageSize),
+	})
+}
+
+// ReplyReview 房东回复评价
+func (h *ReviewHandler) ReplyReview(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的评价ID")
+		return
+	}
+
+	var req review.ReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 验证请求参数
+	if err := review.ValidateReplyRequest(req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	if err := h.service.ReplyReview(c.Request.Context(), uint(id), userID.(uint), req.Reply); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ReportReview 举报评价
+func (h *ReviewHandler) ReportReview(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的评价ID")
+		return
+	}
+
+	var req review.ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 验证请求参数
+	if err := review.ValidateReportRequest(req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	if err := h.service.ReportReview(c.Request.Context(), uint(id), userID.(uint), req.Reason); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "举报已提交"})
+}
+
+// GetReports 管理员获取评价举报列表
+func (h *ReviewHandler) GetReports(c *gin.Context) {
+	var req review.ReportQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	page, pageSize := req.GetDefaultPage(), req.GetDefaultPageSize()
+
+	// 默认只查询待处理的举报
+	status := model.ReportPending
+	if req.Status != nil {
+		status = *req.Status
+	}
+
+	reports, total, err := h.service.GetReports(c.Request.Context(), map[string]interface{}{
+		"status": status,
+		"limit":  pageSize,
+		"offset": (page - 1) * pageSize,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	list := make([]review.ReportDTO, 0, len(reports))
+	for _, r := range reports {
+		list = append(list, review.ReportDTO{
+			ID:        r.ID,
+			ReviewID:  r.ReviewID,
+			UserID:    r.UserID,
+			Reason:    r.Reason,
+			Status:    r.Status,
+			HandledAt: r.HandledAt,
+			CreatedAt: r.CreatedAt,
+		})
+	}
+
+	response.Success(c, review.ReportListResponse{
+		List:       list,
+		Pagination: common.NewPaginationResponse(total, page, pageSize),
+	})
+}
+
+// HandleReport 管理员处理评价举报
+func (h *ReviewHandler) HandleReport(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的举报ID")
+		return
+	}
+
+	var req review.HandleReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 验证请求参数
+	if err := review.ValidateHandleReportRequest(req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	// 从上下文获取管理员ID（由JWT中间件设置）
+	handlerID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	if err := h.service.HandleReport(c.Request.Context(), uint(id), handlerID.(uint), req.Action == "hide"); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// toReviewDTO 将评价模型转换为DTO
+func toReviewDTO(r *model.Review) review.DetailDTO {
+	return review.DetailDTO{
+		ID:             r.ID,
+		UserID:         r.UserID,
+		HouseID:        r.HouseID,
+		LandlordID:     r.LandlordID,
+		LandlordRating: r.LandlordRating,
+		HouseRating:    r.HouseRating,
+		Content:        r.Content,
+		Reply:          r.Reply,
+		ReplyTime:      r.ReplyTime,
+		CreatedAt:      r.CreatedAt,
+	}
+}
+
+// toReviewDTOs 将评价模型列表转换为DTO列表
+func toReviewDTOs(reviews []model.Review) []review.DetailDTO {
+	list := make([]review.DetailDTO, 0, len(reviews))
+	for i := range reviews {
+		list = append(list, toReviewDTO(&reviews[i]))
+	}
+	return list
+}
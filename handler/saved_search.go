@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"strconv"
+
+	"myApp/dto/common"
+	"myApp/dto/house"
+	"myApp/dto/savedsearch"
+	"myApp/model"
+	"myApp/pkg/errors"
+	"myApp/pkg/response"
+	"myApp/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SavedSearchHandler 保存的搜索处理器结构体，负责处理保存的搜索相关的HTTP请求
+type SavedSearchHandler struct {
+	service service.SavedSearchService
+}
+
+// NewSavedSearchHandler 创建保存的搜索处理器实例，注入保存的搜索服务依赖
+func NewSavedSearchHandler(s service.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{service: s}
+}
+
+// CreateSavedSearch 保存当前用户的搜索条件
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	var req savedsearch.CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	if err := savedsearch.ValidateCreateRequest(req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	search := &model.SavedSearch{
+		UserID:      userID.(uint),
+		Name:        req.Name,
+		NotifyInApp: req.NotifyInApp == nil || *req.NotifyInApp,
+		NotifySMS:   req.NotifySMS,
+	}
+	if err := h.service.CreateSavedSearch(c.Request.Context(), search, toHouseFilter(req.Filters)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, toSavedSearchDTO(search, 0))
+}
+
+// GetSavedSearches 获取当前用户保存的搜索及新房源数量
+func (h *SavedSearchHandler) GetSavedSearches(c *gin.Context) {
+	// 从上下文获取用户ID（由JWT中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	summaries, err := h.service.GetUserSavedSearches(c.Request.Context(), userID.(uint))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	list := make([]savedsearch.DetailDTO, 0, len(summaries))
+	for i := range summaries {
+		list = append(list, toSavedSearchDTO(&summaries[i].Search, summaries[i].NewMatchCount))
+	}
+
+	response.Success(c, savedsearch.ListResponse{List: list})
+}
+
+// UpdateSavedSearch 修改保存的搜索的名称、筛选条件或提醒方式
+func (h *SavedSearchHandler) UpdateSavedSearch(c *gin.Context) {
+	id, userID, ok := parseSavedSearchRequest(c)
+	if !ok {
+		return
+	}
+
+	var req savedsearch.UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	if err := savedsearch.ValidateUpdateRequest(req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+
+	update := service.SavedSearchUpdate{
+		Name:        req.Name,
+		NotifyInApp: req.NotifyInApp,
+		NotifySMS:   req.NotifySMS,
+	}
+	if req.Filters != nil {
+		filter := toHouseFilter(*req.Filters)
+		update.Filter = &filter
+	}
+
+	search, err := h.service.UpdateSavedSearch(c.Request.Context(), id, userID, update)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, toSavedSearchDTO(search, 0))
+}
+
+// DeleteSavedSearch 删除保存的搜索
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	id, userID, ok := parseSavedSearchRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteSavedSearch(c.Request.Context(), id, userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// GetMatches 获取符合保存的搜索条件的房源，并标出上次查看后新发布的房源
+func (h *SavedSearchHandler) GetMatches(c *gin.Context) {
+	id, userID, ok := parseSavedSearchRequest(c)
+	if !ok {
+		return
+	}
+
+	var req savedsearch.MatchQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.FromBinding(err))
+		return
+	}
+	page, pageSize := req.GetDefaultPage(), req.GetDefaultPageSize()
+
+	search, houses, total, err := h.service.GetMatches(c.Request.Context(), id, userID, map[string]interface{}{
+		"limit":  pageSize,
+		"offset": (page - 1) * pageSize,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	since := search.CreatedAt
+	if search.LastCheckedAt != nil {
+		since = *search.LastCheckedAt
+	}
+	basicInfos := toHouseBasicInfoDTOs(houses)
+	list := make([]savedsearch.MatchDTO, 0, len(houses))
+	for i, hm := range houses {
+		list = append(list, savedsearch.MatchDTO{
+			BasicInfoDTO: basicInfos[i],
+			IsNew:        hm.PublishedAt != nil && hm.PublishedAt.After(since),
+			PublishedAt:  hm.PublishedAt,
+		})
+	}
+
+	response.Success(c, savedsearch.MatchListResponse{
+		List:       list,
+		Pagination: common.NewPaginationResponse(total, page, pageSize),
+	})
+}
+
+// MarkChecked 将保存的搜索标记为已查看，新房源数量清零
+func (h *SavedSearchHandler) MarkChecked(c *gin.Context) {
+	id, userID, ok := parseSavedSearchRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.MarkChecked(c.Request.Context(), id, userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// parseSavedSearchRequest 解析路径中的搜索ID和当前用户ID，失败时已写入响应
+func parseSavedSearchRequest(c *gin.Context) (uint, uint, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的搜索ID")
+		return 0, 0, false
+	}
+	return uint(id), userID.(uint), true
+}
+
+// toHouseFilter 将筛选条件DTO转换为服务层的筛选条件
+func toHouseFilter(req house.FilterRequest) service.HouseFilter {
+	return service.HouseFilter{
+		LandlordID:    req.LandlordID,
+		ProvinceCode:  req.ProvinceCode,
+		CityCode:      req.CityCode,
+		DistrictCode:  req.DistrictCode,
+		CommunityCode: req.CommunityCode,
+		MinPrice:      req.MinPrice,
+		MaxPrice:      req.MaxPrice,
+		Rooms:         req.Rooms,
+		HouseType:     req.HouseType,
+		Keyword:       req.Keyword,
+	}
+}
+
+// toSavedSearchDTO 将保存的搜索转换为DTO
+func toSavedSearchDTO(search *model.SavedSearch, newMatchCount int64) savedsearch.DetailDTO {
+	filter := service.DecodeHouseFilter(search)
+	return savedsearch.DetailDTO{
+		ID:   search.ID,
+		Name: search.Name,
+		Filters: house.FilterRequest{
+			LandlordID:    filter.LandlordID,
+			ProvinceCode:  filter.ProvinceCode,
+			CityCode:      filter.CityCode,
+			DistrictCode:  filter.DistrictCode,
+			CommunityCode: filter.CommunityCode,
+			MinPrice:      filter.MinPrice,
+			MaxPrice:      filter.MaxPrice,
+			Rooms:         filter.Rooms,
+			HouseType:     filter.HouseType,
+			Keyword:       filter.Keyword,
+		},
+		NotifyInApp:   search.NotifyInApp,
+		NotifySMS:     search.NotifySMS,
+		NewMatchCount: newMatchCount,
+		LastCheckedAt: search.LastCheckedAt,
+		CreatedAt:     search.CreatedAt,
+	}
+}
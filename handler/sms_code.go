@@ -3,8 +3,8 @@ package handler
 import (
 	"myApp/config"
 	"myApp/dto/user"
+	"myApp/pkg/errors"
 	"myApp/pkg/response"
-	"myApp/repository"
 	"myApp/service"
 	"time"
 
@@ -17,11 +17,8 @@ type SMSCodeHandler struct {
 	smsCodeService service.SMSCodeService
 }
 
-// NewSMSCodeHandler 创建短信验证码处理器实例
-func NewSMSCodeHandler() *SMSCodeHandler {
-	userRepo := repository.NewUserRepository()
-	smsRecordRepo := repository.NewSMSRecordRepository()
-	smsCodeService := service.NewSMSCodeService(userRepo, smsRecordRepo)
+// NewSMSCodeHandler 创建短信验证码处理器实例，注入短信验证码服务依赖
+func NewSMSCodeHandler(smsCodeService service.SMSCodeService) *SMSCodeHandler {
 	return &SMSCodeHandler{smsCodeService: smsCodeService}
 }
 
@@ -30,13 +27,13 @@ func (h *SMSCodeHandler) SendCode(c *gin.Context) {
 	// 绑定并验证请求参数
 	var req user.SendSMSCodeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "无效的请求参数")
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
 	// 验证请求参数
 	if err := user.ValidateSendSMSCodeRequest(req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
@@ -45,9 +42,9 @@ func (h *SMSCodeHandler) SendCode(c *gin.Context) {
 	userAgent := c.GetHeader("User-Agent")
 
 	// 调用服务层发送验证码
-	success, err := h.smsCodeService.SendCode(req.Phone, ipAddress, userAgent)
+	success, err := h.smsCodeService.SendCode(c.Request.Context(), req.Phone, ipAddress, userAgent)
 	if err != nil {
-		response.ServerError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 
@@ -65,20 +62,20 @@ func (h *SMSCodeHandler) LoginByCode(c *gin.Context) {
 	// 绑定并验证请求参数
 	var req user.SMSCodeLoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "无效的请求参数")
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
 	// 验证请求参数
 	if err := user.ValidateSMSCodeLoginRequest(req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
 	// 调用服务层验证码登录
-	userModel, err := h.smsCodeService.LoginByCode(req.Phone, req.Code)
+	userModel, err := h.smsCodeService.LoginByCode(c.Request.Context(), req.Phone, req.Code)
 	if err != nil {
-		response.Unauthorized(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 
@@ -91,7 +88,7 @@ func (h *SMSCodeHandler) LoginByCode(c *gin.Context) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString([]byte(config.Conf.JWT.Secret))
 	if err != nil {
-		response.ServerError(c, "生成令牌失败")
+		response.Error(c, errors.Wrap(err, errors.CodeInternal, "生成令牌失败"))
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"myApp/config"
 	"myApp/dto/user"
 	"myApp/model"
+	"myApp/pkg/errors"
 	"myApp/pkg/response"
 	"myApp/service"
 	"time"
@@ -27,13 +28,13 @@ func (h *UserHandler) Register(c *gin.Context) {
 	// 绑定并验证请求参数
 	var req user.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "无效的请求参数")
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
 	// 验证请求参数
 	if err := user.ValidateRegisterRequest(req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
@@ -49,9 +50,9 @@ func (h *UserHandler) Register(c *gin.Context) {
 	}
 
 	// 调用服务层进行用户注册
-	createdUser, err := h.service.Register(&userModel)
+	createdUser, err := h.service.Register(c.Request.Context(), &userModel)
 	if err != nil {
-		response.ServerError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 
@@ -75,18 +76,18 @@ func (h *UserHandler) Login(c *gin.Context) {
 	// 绑定并验证登录凭证
 	var req user.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "无效的请求参数")
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
 	// 验证请求参数
 	if err := user.ValidateLoginRequest(req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
 	// 调用服务层进行用户登录验证
-	userModel, err := h.service.Login(req.Username, req.Password)
+	userModel, err := h.service.Login(c.Request.Context(), req.Username, req.Password)
 	if err != nil {
 		response.Unauthorized(c, "无效的凭证")
 		return
@@ -101,7 +102,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString([]byte(config.Conf.JWT.Secret))
 	if err != nil {
-		response.ServerError(c, "生成令牌失败")
+		response.Error(c, errors.Wrap(err, errors.CodeInternal, "生成令牌失败"))
 		return
 	}
 
@@ -137,9 +138,9 @@ func (h *UserHandler) GetUserInfo(c *gin.Context) {
 	}
 
 	// 调用服务层获取用户信息
-	userModel, err := h.service.GetUserProfile(userID.(uint))
+	userModel, err := h.service.GetUserProfile(c.Request.Context(), userID.(uint))
 	if err != nil {
-		response.NotFound(c, "用户不存在")
+		response.Error(c, err)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 
 	"myApp/dto/viewing"
 	"myApp/model"
+	"myApp/pkg/errors"
 	"myApp/pkg/response"
 	"myApp/service"
 
@@ -28,13 +29,13 @@ func (h *ViewingHandler) CreateViewing(c *gin.Context) {
 	// 绑定并验证请求参数
 	var req viewing.CreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "无效的请求参数")
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
 	// 验证请求参数
 	if err := viewing.ValidateCreateRequest(req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.Error(c, errors.FromBinding(err))
 		return
 	}
 
@@ -45,6 +46,28 @@ func (h *ViewingHandler) CreateViewing(c *gin.Context) {
 		return
 	}
 
+	// 草稿、待审核和审核驳回的房源对租客不可见
+	houseModel, err := h.houseService.GetHouseByID(c.Request.Context(), req.HouseID)
+	if err != nil && !errors.Is(err, service.ErrHouseNotFound) {
+		response.Error(c, err)
+		return
+	}
+	if houseModel == nil || !houseModel.IsPublic() {
+		response.BadRequest(c, "房源不存在或未发布")
+		return
+	}
+	// 已下架、已出租或已过上架有效期的房源不能预约
+	if houseModel.Status != model.HouseStatusPublished || (houseModel.ExpireAt != nil && houseModel.ExpireAt.Before(time.Now())) {
+		response.Error(c, errors.Conflict("房源已下架或已出租，不能预约"))
+		return
+	}
+
+	// 房东不能预约自己的房源
+	if houseModel.LandlordID == userID.(uint) {
+		response.Forbidden(c, "不能预约自己的房源")
+		return
+	}
+
 	// 验证预约时间是否合法（不能是过去的时间）
 	if req.ViewDate.Before(time.Now()) {
 		response.BadRequest(c, "预约时间不能是过去的时间")
@@ -62,8 +85,8 @@ func (h *ViewingHandler) CreateViewing(c *gin.Context) {
 		Status:       0, // 默认待确认状态
 	}
 
-	if err := h.service.CreateViewing(&viewingModel); err != nil {
-		response.ServerError(c, "创建预约看房失败")
+	if err := h.service.CreateViewing(c.Request.Context(), &viewingModel); err != nil {
+		response.Error(c, err)
 		return
 	}
 
@@ -80,9 +103,9 @@ func (h *ViewingHandler) GetViewing(c *gin.Context) {
 		return
 	}
 
-	viewingModel, err := h.service.GetViewingByID(uint(id))
+	viewingModel, err := h.service.GetViewingByID(c.Request.Context(), uint(id))
 	if err != nil {
-		response.NotFound(c, "预约记录不存在")
+		response.Error(c, err)
 		return
 	}
 
@@ -96,8 +119,12 @@ func (h *ViewingHandler) GetViewing(c *gin.Context) {
 	// 检查是否为预约用户本人或房东
 	if viewingModel.UserID != userID.(uint) {
 		// 获取房源信息，检查当前用户是否为房东
-		house, err := h.houseService.GetHouseByID(viewingModel.HouseID)
-		if err != nil || house.LandlordID != userID.(uint) {
+		house, err := h.houseService.GetHouseByID(c.Request.Context(), viewingModel.HouseID)
+		if err != nil && !errors.Is(err, service.ErrHouseNotFound) {
+			response.Error(c, err)
+			return
+		}
+		if house == nil || house.LandlordID != userID.(uint) {
 			response.Forbidden(c, "无权查看该预约记录")
 			return
 		}
@@ -132,9 +159,9 @@ func (h *ViewingHandler) GetUserViewings(c *gin.Context) {
 		return
 	}
 
-	viewings, err := h.service.GetViewingsByUserID(userID.(uint))
+	viewings, err := h.service.GetViewingsByUserID(c.Request.Context(), userID.(uint))
 	if err != nil {
-		response.ServerError(c, "获取预约记录失败")
+		response.Error(c, err)
 		return
 	}
 
@@ -178,15 +205,19 @@ func (h *ViewingHandler) GetHouseViewings(c *gin.Context) {
 	}
 
 	// 检查当前用户是否为房东
-	house, err := h.houseService.GetHouseByID(uint(houseID))
-	if err != nil || house.LandlordID != userID.(uint) {
+	house, err := h.houseService.GetHouseByID(c.Request.Context(), uint(houseID))
+	if err != nil && !errors.Is(err, service.ErrHouseNotFound) {
+		response.Error(c, err)
+		return
+	}
+	if house == nil || house.LandlordID != userID.(uint) {
 		response.Forbidden(c, "无权查看该房源的预约记录")
 		return
 	}
 
-	viewings, err := h.service.GetViewingsByHouseID(uint(houseID))
+	viewings, err := h.service.GetViewingsByHouseID(c.Request.Context(), uint(houseID))
 	if err != nil {
-		response.ServerError(c, "获取预约记录失败")
+		response.Error(c, err)
 		return
 	}
 
@@ -223,9 +254,9 @@ func (h *ViewingHandler) ConfirmViewing(c *gin.Context) {
 	}
 
 	// 获取预约记录
-	viewingModel, err := h.service.GetViewingByID(uint(id))
+	viewingModel, err := h.service.GetViewingByID(c.Request.Context(), uint(id))
 	if err != nil {
-		response.NotFound(c, "预约记录不存在")
+		response.Error(c, err)
 		return
 	}
 
@@ -237,15 +268,19 @@ func (h *ViewingHandler) ConfirmViewing(c *gin.Context) {
 	}
 
 	// 检查当前用户是否为房东
-	house, err := h.houseService.GetHouseByID(viewingModel.HouseID)
-	if err != nil || house.LandlordID != userID.(uint) {
+	house, err := h.houseService.GetHouseByID(c.Request.Context(), viewingModel.HouseID)
+	if err != nil && !errors.Is(err, service.ErrHouseNotFound) {
+		response.Error(c, err)
+		return
+	}
+	if house == nil || house.LandlordID != userID.(uint) {
 		response.Forbidden(c, "无权确认该预约")
 		return
 	}
 
 	// 确认预约
-	if err := h.service.ConfirmViewing(uint(id)); err != nil {
-		response.ServerError(c, "确认预约失败")
+	if err := h.service.ConfirmViewing(c.Request.Context(), uint(id)); err != nil {
+		response.Error(c, err)
 		return
 	}
 
@@ -262,9 +297,9 @@ func (h *ViewingHandler) CompleteViewing(c *gin.Context) {
 	}
 
 	// 获取预约记录
-	viewingModel, err := h.service.GetViewingByID(uint(id))
+	viewingModel, err := h.service.GetViewingByID(c.Request.Context(), uint(id))
 	if err != nil {
-		response.NotFound(c, "预约记录不存在")
+		response.Error(c, err)
 		return
 	}
 
@@ -276,15 +311,19 @@ func (h *ViewingHandler) CompleteViewing(c *gin.Context) {
 	}
 
 	// 检查当前用户是否为房东
-	house, err := h.houseService.GetHouseByID(viewingModel.HouseID)
-	if err != nil || house.LandlordID != userID.(uint) {
+	house, err := h.houseService.GetHouseByID(c.Request.Context(), viewingModel.HouseID)
+	if err != nil && !errors.Is(err, service.ErrHouseNotFound) {
+		response.Error(c, err)
+		return
+	}
+	if house == nil || house.LandlordID != userID.(uint) {
 		response.Forbidden(c, "无权完成该预约")
 		return
 	}
 
 	// 完成预约
-	if err := h.service.CompleteViewing(uint(id)); err != nil {
-		response.ServerError(c, "完成预约失败")
+	if err := h.service.CompleteViewing(c.Request.Context(), uint(id)); err != nil {
+		response.Error(c, err)
 		return
 	}
 
@@ -301,9 +340,9 @@ func (h *ViewingHandler) CancelViewing(c *gin.Context) {
 	}
 
 	// 获取预约记录
-	viewingModel, err := h.service.GetViewingByID(uint(id))
+	viewingModel, err := h.service.GetViewingByID(c.Request.Context(), uint(id))
 	if err != nil {
-		response.NotFound(c, "预约记录不存在")
+		response.Error(c, err)
 		return
 	}
 
@@ -316,8 +355,12 @@ func (h *ViewingHandler) CancelViewing(c *gin.Context) {
 
 	// 检查当前用户是否为预约用户本人或房东
 	isLandlord := false
-	house, err := h.houseService.GetHouseByID(viewingModel.HouseID)
-	if err == nil && house.LandlordID == userID.(uint) {
+	house, err := h.houseService.GetHouseByID(c.Request.Context(), viewingModel.HouseID)
+	if err != nil && !errors.Is(err, service.ErrHouseNotFound) {
+		response.Error(c, err)
+		return
+	}
+	if house != nil && house.LandlordID == userID.(uint) {
 		isLandlord = true
 	}
 
@@ -335,8 +378,8 @@ func (h *ViewingHandler) CancelViewing(c *gin.Context) {
 	}
 
 	// 取消预约
-	if err := h.service.CancelViewing(uint(id), cancelData.Reason); err != nil {
-		response.ServerError(c, "取消预约失败")
+	if err := h.service.CancelViewing(c.Request.Context(), uint(id), cancelData.Reason); err != nil {
+		response.Error(c, err)
 		return
 	}
 
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"myApp/model"
+	"myApp/pkg/response"
+	"myApp/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth 管理员权限校验中间件
+// 需要在JWTAuth之后使用，根据令牌中的用户ID查询用户类型，仅允许管理员访问
+func AdminAuth(userRepo repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			response.Unauthorized(c, "用户未认证")
+			c.Abort()
+			return
+		}
+
+		user, err := userRepo.FindByID(c.Request.Context(), userID.(uint))
+		if err != nil || user.UserType != model.UserTypeAdmin {
+			response.Forbidden(c, "无权进行此操作")
+			c.Abort()
+			return
+		}
+
+		c.Set("userType", user.UserType)
+		c.Next()
+	}
+}
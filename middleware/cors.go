@@ -1,22 +1,118 @@
 package middleware
 
 import (
+	"myApp/config"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CORS() gin.HandlerFunc {
+// CORS 跨域资源共享中间件
+// 只为允许的来源设置跨域响应头，允许任意来源时返回*，否则原样返回请求的来源并设置Vary: Origin；
+// 允许任意来源时不允许携带凭证（配置加载时已拒绝该组合），预检请求直接返回，来源不被允许的预检请求返回403
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	origins := newOriginMatcher(cfg.AllowOrigins)
+	allowMethods := strings.Join(cfg.AllowMethods, ", ")
+	allowHeaders := strings.Join(cfg.AllowHeaders, ", ")
+	exposeHeaders := strings.Join(cfg.ExposeHeaders, ", ")
+	allowCredentials := cfg.AllowCredentials && !origins.any
+	// 响应头随请求来源变化时，需要告知缓存按Origin区分
+	varyOrigin := !origins.any
+
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		header := c.Writer.Header()
+		if varyOrigin {
+			header.Add("Vary", "Origin")
+		}
+
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if origin == "" {
+			c.Next()
+			return
+		}
+		if !origins.match(origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if varyOrigin {
+			header.Set("Access-Control-Allow-Origin", origin)
+		} else {
+			header.Set("Access-Control-Allow-Origin", "*")
+		}
+		if allowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.Writer.WriteHeader(http.StatusNoContent)
-			c.Abort()
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			header.Set("Access-Control-Allow-Methods", allowMethods)
+			if allowHeaders != "" {
+				header.Set("Access-Control-Allow-Headers", allowHeaders)
+			}
+			if cfg.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAge))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
+		if exposeHeaders != "" {
+			header.Set("Access-Control-Expose-Headers", exposeHeaders)
+		}
 		c.Next()
 	}
 }
+
+// originMatcher 允许的跨域来源
+type originMatcher struct {
+	any       bool                // 是否允许任意来源
+	exact     map[string]struct{} // 完整匹配的来源
+	wildcards [][2]string         // 通配子域名的来源，分别为*前后的部分
+}
+
+// newOriginMatcher 解析允许的来源，来源不区分大小写，末尾的/会被忽略
+func newOriginMatcher(origins []string) *originMatcher {
+	m := &originMatcher{exact: make(map[string]struct{}, len(origins))}
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		switch {
+		case origin == "":
+		case origin == "*":
+			m.any = true
+		case strings.Contains(origin, "*"):
+			prefix, suffix, _ := strings.Cut(origin, "*")
+			m.wildcards = append(m.wildcards, [2]string{prefix, suffix})
+		default:
+			m.exact[origin] = struct{}{}
+		}
+	}
+	return m
+}
+
+// match 判断请求的来源是否被允许，通配子域名不匹配主域名本身
+func (m *originMatcher) match(origin string) bool {
+	if m.any {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if _, ok := m.exact[origin]; ok {
+		return true
+	}
+	for _, w := range m.wildcards {
+		if len(origin) <= len(w[0])+len(w[1]) || !strings.HasPrefix(origin, w[0]) || !strings.HasSuffix(origin, w[1]) {
+			continue
+		}
+		if sub := origin[len(w[0]) : len(origin)-len(w[1])]; !strings.ContainsAny(sub, "/:@") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"myApp/pkg/errors"
+	"myApp/pkg/logger"
+	"myApp/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrorHandler 错误处理中间件
+// 处理器通过response.Error记录错误后，根据业务错误码统一设置HTTP状态码和响应中的code；
+// 记录不存在、请求超时之外的非业务错误按服务器内部错误返回，错误详情只写入日志
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		appErr := toAppError(err)
+		if appErr.Code.HTTPStatus() >= 500 {
+			logger.FromContext(c.Request.Context()).Error("请求处理失败", zap.Error(err))
+		}
+		response.AppError(c, appErr)
+	}
+}
+
+// toAppError 将错误转换为业务错误
+func toAppError(err error) *errors.Error {
+	var appErr *errors.Error
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return errors.Wrap(err, errors.CodeNotFound, "资源不存在")
+	case errors.Is(err, context.DeadlineExceeded):
+		return errors.Wrap(err, errors.CodeTimeout, "请求处理超时")
+	}
+	return errors.Internal(err)
+}
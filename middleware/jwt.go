@@ -11,19 +11,13 @@ import (
 
 func JWTAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenString := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
-
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(config.Conf.JWT.Secret), nil
-		})
-
-		if err != nil || !token.Valid {
+		claims, ok := parseToken(c)
+		if !ok {
 			response.Unauthorized(c, "无效的访问令牌")
 			c.Abort()
 			return
 		}
 
-		claims := token.Claims.(jwt.MapClaims)
 		// 将userID转换为uint类型
 		userIDFloat, ok := claims["userID"].(float64)
 		if !ok {
@@ -35,3 +29,34 @@ func JWTAuth() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// OptionalJWTAuth 可选的JWT认证中间件
+// 携带有效令牌时设置userID，未携带或令牌无效时按匿名用户继续处理，用于登录后可获得个性化结果的公开接口
+func OptionalJWTAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, ok := parseToken(c); ok {
+			if userIDFloat, ok := claims["userID"].(float64); ok {
+				c.Set("userID", uint(userIDFloat))
+			}
+		}
+		c.Next()
+	}
+}
+
+// parseToken 解析请求头中的访问令牌，令牌无效时返回false
+func parseToken(c *gin.Context) (jwt.MapClaims, bool) {
+	tokenString := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if tokenString == "" {
+		return nil, false
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(config.Conf.JWT.Secret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, false
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	return claims, ok
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"myApp/pkg/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics 请求监控中间件
+// 按请求方法、路由模板和响应状态码统计请求处理耗时，未匹配到路由的请求的路由记为unmatched，避免指标维度随路径无限增长
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
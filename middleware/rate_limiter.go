@@ -1,22 +1,144 @@
 package middleware
 
 import (
+	"math"
+	"myApp/config"
+	"myApp/pkg/errors"
+	"myApp/pkg/logger"
+	"myApp/pkg/ratelimit"
 	"myApp/pkg/response"
-	"net/http"
+	"net"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/juju/ratelimit"
+	"go.uber.org/zap"
 )
 
-func RateLimiter() gin.HandlerFunc {
-	bucket := ratelimit.NewBucketWithQuantum(1*time.Second, 100, 100) // 每秒100个请求
+// RateLimiter 请求限流中间件
+// 按配置的规则以客户端IP、登录用户或接口为维度在Redis中限流，多个实例共享限流额度；
+// 按接口配置了规则的请求使用该规则，其余请求使用默认规则，白名单中的IP不限流，Redis不可用时放行请求；
+// exempt为不限流的路由，如供探针调用的健康检查接口，按路由模板匹配，与请求来源无关
+func RateLimiter(cfg config.RateLimitConfig, exempt ...string) gin.HandlerFunc {
+	allowlist := parseAllowlist(cfg.Allowlist)
+	routes := make(map[string]config.RateLimitPolicy, len(cfg.Routes))
+	for _, policy := range cfg.Routes {
+		routes[strings.ToUpper(policy.Method)+" "+policy.Path] = policy
+	}
+	exemptRoutes := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		exemptRoutes[path] = true
+	}
+
 	return func(c *gin.Context) {
-		if bucket.TakeAvailable(1) < 1 {
-			response.Fail(c, http.StatusTooManyRequests, "请求过于频繁，请稍后再试")
-			c.Abort()
+		ip := c.ClientIP()
+		if exemptRoutes[c.FullPath()] || allowlist.contains(ip) {
+			c.Next()
+			return
+		}
+
+		// 先匹配指定了请求方法的规则，再匹配全部方法的规则
+		scope := c.Request.Method + " " + c.FullPath()
+		policy, ok := routes[scope]
+		if !ok {
+			scope = " " + c.FullPath()
+			policy, ok = routes[scope]
+		}
+		if !ok {
+			scope, policy = "default", cfg.Default
+		}
+		if policy.Rate <= 0 || policy.Period <= 0 {
+			c.Next()
+			return
+		}
+
+		burst := policy.Burst
+		if burst <= 0 {
+			burst = policy.Rate
+		}
+		key := strings.TrimSpace(scope) + ":" + rateLimitSubject(c, policy.Key, ip)
+		result, err := ratelimit.Allow(c.Request.Context(), key, ratelimit.Limit{
+			Rate:   policy.Rate,
+			Period: time.Duration(policy.Period) * time.Second,
+			Burst:  burst,
+		})
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Warn("限流检查失败，放行请求", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			response.Error(c, errors.RateLimited("请求过于频繁，请稍后再试"))
 			return
 		}
 		c.Next()
 	}
 }
+
+// rateLimitSubject 返回限流维度对应的标识，user维度在未登录时按IP限流
+func rateLimitSubject(c *gin.Context, key, ip string) string {
+	switch key {
+	case "route":
+		return "route"
+	case "user":
+		if claims, ok := parseToken(c); ok {
+			if userID, ok := claims["userID"].(float64); ok {
+				return "user:" + strconv.FormatUint(uint64(userID), 10)
+			}
+		}
+	}
+	return "ip:" + ip
+}
+
+// ceilSeconds 将时长向上取整为秒，用于响应头
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// ipAllowlist 限流白名单
+type ipAllowlist []*net.IPNet
+
+// parseAllowlist 解析白名单中的IP和CIDR网段，无效的条目记录日志后忽略
+func parseAllowlist(entries []string) ipAllowlist {
+	var list ipAllowlist
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			logger.Warn("忽略无效的限流白名单条目", zap.String("entry", entry))
+			continue
+		}
+		list = append(list, ipNet)
+	}
+	return list
+}
+
+// contains 判断ip是否在白名单中
+func (l ipAllowlist) contains(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range l {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
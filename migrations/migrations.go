@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+// files 内置的版本迁移脚本，按数据库驱动分目录存放，文件名格式为“版本号_名称.up.sql”和“版本号_名称.down.sql”
+// 两个目录中的版本号和名称一一对应，执行到同一版本后的表结构保持一致
+//
+//go:embed mysql/*.sql sqlite/*.sql
+var files embed.FS
+
+// For 返回指定数据库驱动使用的迁移脚本目录，驱动为空时使用mysql
+func For(driver string) (fs.FS, error) {
+	switch driver {
+	case "", "mysql":
+		return fs.Sub(files, "mysql")
+	case "sqlite":
+		return fs.Sub(files, "sqlite")
+	default:
+		return nil, fmt.Errorf("不支持的数据库驱动：%s", driver)
+	}
+}
@@ -0,0 +1,174 @@
+package migrations
+
+import (
+	"testing"
+	"time"
+
+	"myApp/config"
+	"myApp/model"
+	"myApp/pkg/migrate"
+
+	"gorm.io/gorm"
+)
+
+// models 迁移脚本需要覆盖的全部模型
+var models = []interface{}{
+	&model.User{},
+	&model.House{},
+	&model.Favorite{},
+	&model.FavoriteFolder{},
+	&model.Viewing{},
+	&model.Landlord{},
+	&model.SMSRecord{},
+	&model.LandlordVerification{},
+	&model.Review{},
+	&model.ReviewReport{},
+	&model.HouseRevision{},
+	&model.HousePriceHistory{},
+	&model.Notification{},
+	&model.SavedSearch{},
+	&model.Region{},
+}
+
+// load 读取指定数据库驱动的迁移脚本
+func load(t *testing.T, driver string) []migrate.Migration {
+	t.Helper()
+	fsys, err := For(driver)
+	if err != nil {
+		t.Fatalf("读取%s迁移脚本失败: %v", driver, err)
+	}
+	list, err := migrate.Load(fsys)
+	if err != nil {
+		t.Fatalf("读取%s迁移脚本失败: %v", driver, err)
+	}
+	return list
+}
+
+// newSQLiteMigrator 创建使用内存SQLite数据库的迁移执行器
+func newSQLiteMigrator(t *testing.T) (*gorm.DB, *migrate.Migrator) {
+	t.Helper()
+	db, err := model.OpenDB(config.DatabaseConfig{Driver: "sqlite", DBName: ":memory:"})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db, migrate.NewMigrator(db, load(t, "sqlite"))
+}
+
+func TestDialectsMatch(t *testing.T) {
+	mysql, sqlite := load(t, "mysql"), load(t, "sqlite")
+	if len(mysql) != len(sqlite) {
+		t.Fatalf("mysql有%d个迁移，sqlite有%d个迁移", len(mysql), len(sqlite))
+	}
+	for i := range mysql {
+		if mysql[i].Version != sqlite[i].Version || mysql[i].Name != sqlite[i].Name {
+			t.Errorf("第%d个迁移不一致：mysql为%04d_%s，sqlite为%04d_%s",
+				i+1, mysql[i].Version, mysql[i].Name, sqlite[i].Version, sqlite[i].Name)
+		}
+	}
+	if _, err := For("postgres"); err == nil {
+		t.Error("不支持的数据库驱动应返回错误")
+	}
+}
+
+func TestSQLiteSchemaMatchesModels(t *testing.T) {
+	db, migrator := newSQLiteMigrator(t)
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+
+	// 执行全部迁移后的表结构与模型的字段和索引一致
+	for _, m := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			t.Fatalf("解析模型失败: %v", err)
+		}
+		table := stmt.Schema.Table
+		columns, err := db.Migrator().ColumnTypes(m)
+		if err != nil {
+			t.Fatalf("读取%s的列失败: %v", table, err)
+		}
+		if len(columns) != len(stmt.Schema.DBNames) {
+			t.Errorf("%s有%d列，模型有%d个字段", table, len(columns), len(stmt.Schema.DBNames))
+		}
+		for _, name := range stmt.Schema.DBNames {
+			if !db.Migrator().HasColumn(m, name) {
+				t.Errorf("%s缺少列%s", table, name)
+			}
+		}
+		for name := range stmt.Schema.ParseIndexes() {
+			if !db.Migrator().HasIndex(m, name) {
+				t.Errorf("%s缺少索引%s", table, name)
+			}
+		}
+	}
+
+	// 全部回滚后只保留迁移记录表，回滚后可以重新执行
+	if _, err := migrator.To(0); err != nil {
+		t.Fatalf("回滚全部迁移失败: %v", err)
+	}
+	for _, m := range models {
+		if db.Migrator().HasTable(m) {
+			t.Errorf("回滚后%T对应的表仍然存在", m)
+		}
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("回滚后重新执行迁移失败: %v", err)
+	}
+}
+
+func TestDataMigrations(t *testing.T) {
+	db, migrator := newSQLiteMigrator(t)
+	if _, err := migrator.To(3); err != nil {
+		t.Fatalf("迁移到版本3失败: %v", err)
+	}
+
+	// 版本3的表结构中已上架的房源没有上架有效期，收藏没有唯一索引
+	err := db.Exec("INSERT INTO houses (id, title, address, area, rooms, halls, bathrooms, rent_price, house_type, status) VALUES " +
+		"(1, '已上架', '北京市朝阳区', 80, 2, 1, 1, 5000, 1, 1), (2, '已下架', '北京市朝阳区', 80, 2, 1, 1, 6000, 1, 0)").Error
+	if err != nil {
+		t.Fatalf("写入测试房源失败: %v", err)
+	}
+	err = db.Exec("INSERT INTO favorites (id, user_id, house_id, deleted_at) VALUES (1, 1, 1, NULL), (2, 1, 1, NULL), (3, 1, 2, ?), (4, 2, 2, NULL)", time.Now()).Error
+	if err != nil {
+		t.Fatalf("写入测试收藏失败: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+
+	// 已上架的房源补充发布时间和30天的上架有效期，其他状态的房源不变
+	var houses []model.House
+	if err := db.Order("id").Find(&houses).Error; err != nil {
+		t.Fatalf("查询房源失败: %v", err)
+	}
+	published := houses[0]
+	if published.PublishedAt == nil || published.ExpireAt == nil {
+		t.Fatalf("已上架的房源应补充发布时间和上架到期时间，实际为%v、%v", published.PublishedAt, published.ExpireAt)
+	}
+	if ttl := published.ExpireAt.Sub(*published.PublishedAt); ttl != 30*24*time.Hour {
+		t.Errorf("上架有效期为%v，期望30天", ttl)
+	}
+	if houses[1].PublishedAt != nil || houses[1].ExpireAt != nil {
+		t.Errorf("已下架的房源不应补充上架时间，实际为%v、%v", houses[1].PublishedAt, houses[1].ExpireAt)
+	}
+
+	// 软删除和重复的收藏被清理，保留最早的一条，并补充收藏时的租金
+	var favorites []model.Favorite
+	if err := db.Unscoped().Order("id").Find(&favorites).Error; err != nil {
+		t.Fatalf("查询收藏失败: %v", err)
+	}
+	if len(favorites) != 2 || favorites[0].ID != 1 || favorites[1].ID != 4 {
+		t.Fatalf("清理后的收藏为%+v，期望保留1、4", favorites)
+	}
+	if favorites[0].PriceAtFavorite != 5000 || favorites[1].PriceAtFavorite != 6000 {
+		t.Errorf("收藏时的租金为%v、%v，期望5000、6000", favorites[0].PriceAtFavorite, favorites[1].PriceAtFavorite)
+	}
+	if err := db.Exec("INSERT INTO favorites (user_id, house_id) VALUES (1, 1)").Error; err == nil {
+		t.Error("同一用户重复收藏同一房源应违反唯一索引")
+	}
+}
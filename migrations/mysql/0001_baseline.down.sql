@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS `sms_records`;
+DROP TABLE IF EXISTS `landlords`;
+DROP TABLE IF EXISTS `viewings`;
+DROP TABLE IF EXISTS `favorites`;
+DROP TABLE IF EXISTS `houses`;
+DROP TABLE IF EXISTS `users`;
@@ -0,0 +1,119 @@
+-- 基线：改用版本迁移前AutoMigrate生成的表结构（用户、房源、收藏、预约看房、房东、短信记录）
+
+CREATE TABLE `users` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `username` varchar(50) COMMENT '用户名',
+    `password` varchar(100) COMMENT '密码',
+    `phone` varchar(20) COMMENT '手机号',
+    `avatar` varchar(255) COMMENT '头像URL',
+    `last_login` datetime DEFAULT null COMMENT '最后登录时间',
+    `real_name` varchar(50) COMMENT '真实姓名',
+    `id_card` varchar(18) COMMENT '身份证号',
+    `email` varchar(100) COMMENT '电子邮箱',
+    `user_type` tinyint DEFAULT 0 COMMENT '用户类型：0-普通用户，1-房东，2-管理员',
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `houses` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `title` varchar(100) NOT NULL COMMENT '房源标题',
+    `description` text COMMENT '房源描述',
+    `address` varchar(255) NOT NULL COMMENT '房源地址',
+    `area` decimal(10,2) NOT NULL COMMENT '房屋面积(平方米)',
+    `floor` int COMMENT '所在楼层',
+    `total_floor` int COMMENT '总楼层',
+    `rooms` int NOT NULL COMMENT '房间数',
+    `halls` int NOT NULL COMMENT '客厅数',
+    `bathrooms` int NOT NULL COMMENT '卫生间数',
+    `rent_price` decimal(10,2) NOT NULL COMMENT '租金(元/月)',
+    `deposit` decimal(10,2) COMMENT '押金(元)',
+    `payment_type` tinyint DEFAULT 1 COMMENT '支付方式：1-月付，2-季付，3-半年付，4-年付',
+    `house_type` tinyint NOT NULL COMMENT '房屋类型：1-普通住宅，2-公寓，3-别墅，4-商铺',
+    `orientation` varchar(20) COMMENT '朝向',
+    `decoration` tinyint DEFAULT 1 COMMENT '装修情况：1-简装，2-精装，3-豪装',
+    `facilities` text COMMENT '配套设施，JSON格式字符串',
+    `status` tinyint DEFAULT 1 COMMENT '状态：0-下架，1-上架',
+    `landlord_id` int unsigned COMMENT '房东ID',
+    `images` text COMMENT '房源图片URL，JSON格式字符串',
+    `latitude` decimal(10,6) COMMENT '纬度',
+    `longitude` decimal(10,6) COMMENT '经度',
+    `is_elevator` tinyint(1) DEFAULT false COMMENT '是否有电梯',
+    `view_count` int DEFAULT 0 COMMENT '浏览次数',
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `favorites` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `user_id` int unsigned COMMENT '用户ID',
+    `house_id` int unsigned COMMENT '房源ID',
+    `notes` text COMMENT '收藏备注',
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `viewings` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `house_id` int unsigned COMMENT '房源ID',
+    `user_id` int unsigned COMMENT '用户ID',
+    `viewing_time` datetime NOT NULL COMMENT '预约看房时间',
+    `status` tinyint DEFAULT 0 COMMENT '状态：0-待确认，1-已确认，2-已完成，3-已取消',
+    `remark` text COMMENT '备注信息',
+    `contact_name` varchar(50) COMMENT '联系人姓名',
+    `contact_phone` varchar(20) COMMENT '联系人电话',
+    `confirm_time` datetime DEFAULT null COMMENT '确认时间',
+    `cancel_time` datetime DEFAULT null COMMENT '取消时间',
+    `cancel_reason` text COMMENT '取消原因',
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `landlords` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `user_id` int unsigned COMMENT '关联的用户ID',
+    `real_name` varchar(50) COMMENT '真实姓名',
+    `id_number` varchar(18) COMMENT '身份证号',
+    `phone_number` varchar(20) COMMENT '联系电话',
+    `address` varchar(255) COMMENT '联系地址',
+    `verified` tinyint(1) DEFAULT false COMMENT '是否已认证',
+    `id_card_front` varchar(255) COMMENT '身份证正面照片URL',
+    `id_card_back` varchar(255) COMMENT '身份证背面照片URL',
+    `bank_account` varchar(50) COMMENT '银行账号',
+    `bank_name` varchar(100) COMMENT '开户行名称',
+    `account_name` varchar(50) COMMENT '开户人姓名',
+    `introduction` text COMMENT '房东自我介绍',
+    `rating` decimal(2,1) DEFAULT 5 COMMENT '房东评分',
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `sms_records` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `phone` varchar(20) COMMENT '手机号码',
+    `code` varchar(10) COMMENT '验证码内容',
+    `template_id` varchar(50) COMMENT '短信模板ID',
+    `content` varchar(255) COMMENT '短信内容',
+    `status` tinyint(1) COMMENT '发送状态(0失败,1成功)',
+    `fail_reason` varchar(255) COMMENT '失败原因',
+    `provider` varchar(50) COMMENT '短信服务提供商',
+    `ip_address` varchar(50) COMMENT '请求IP地址',
+    `user_agent` varchar(255) COMMENT '用户代理',
+    `biz_id` varchar(50) COMMENT '发送回执ID',
+    `request_id` varchar(50) COMMENT '请求ID',
+    PRIMARY KEY (`id`),
+    INDEX `idx_sms_records_phone` (`phone`)
+);
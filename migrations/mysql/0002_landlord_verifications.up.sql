@@ -0,0 +1,23 @@
+-- 房东身份认证申请
+
+CREATE TABLE `landlord_verifications` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `landlord_id` int unsigned COMMENT '房东ID',
+    `user_id` int unsigned COMMENT '用户ID',
+    `real_name` varchar(50) COMMENT '真实姓名',
+    `id_number` varchar(18) COMMENT '身份证号',
+    `id_card_front` varchar(255) COMMENT '身份证正面照片URL',
+    `id_card_back` varchar(255) COMMENT '身份证背面照片URL',
+    `documents` text COMMENT '其他证明材料URL，JSON格式字符串',
+    `status` tinyint DEFAULT 0 COMMENT '状态：0-待审核，1-已通过，2-已驳回',
+    `reject_reason` varchar(255) COMMENT '驳回原因',
+    `reviewer_id` int unsigned COMMENT '审核管理员ID',
+    `reviewed_at` datetime COMMENT '审核时间',
+    PRIMARY KEY (`id`),
+    INDEX `idx_landlord_verifications_landlord_id` (`landlord_id`),
+    INDEX `idx_landlord_verifications_user_id` (`user_id`),
+    INDEX `idx_landlord_verifications_status` (`status`)
+);
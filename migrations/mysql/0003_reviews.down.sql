@@ -0,0 +1,4 @@
+ALTER TABLE `landlords` DROP COLUMN `review_count`;
+ALTER TABLE `houses` DROP COLUMN `review_count`, DROP COLUMN `rating`;
+DROP TABLE IF EXISTS `review_reports`;
+DROP TABLE IF EXISTS `reviews`;
@@ -0,0 +1,47 @@
+-- 租客评价和评价举报，房源和房东的评分汇总
+
+CREATE TABLE `reviews` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `user_id` int unsigned COMMENT '评价用户ID',
+    `house_id` int unsigned COMMENT '房源ID',
+    `landlord_id` int unsigned COMMENT '房东ID',
+    `viewing_id` int unsigned COMMENT '关联的看房预约ID',
+    `landlord_rating` tinyint NOT NULL COMMENT '房东评分(1-5星)',
+    `house_rating` tinyint NOT NULL COMMENT '房源评分(1-5星)',
+    `content` text COMMENT '评价内容',
+    `reply` text COMMENT '房东回复',
+    `reply_time` datetime COMMENT '回复时间',
+    `status` tinyint DEFAULT 0 COMMENT '状态：0-正常，1-已隐藏',
+    `report_count` int DEFAULT 0 COMMENT '被举报次数',
+    PRIMARY KEY (`id`),
+    INDEX `idx_reviews_user_id` (`user_id`),
+    INDEX `idx_reviews_house_id` (`house_id`),
+    INDEX `idx_reviews_landlord_id` (`landlord_id`),
+    UNIQUE INDEX `idx_reviews_viewing_id` (`viewing_id`)
+);
+
+CREATE TABLE `review_reports` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `review_id` int unsigned COMMENT '评价ID',
+    `user_id` int unsigned COMMENT '举报用户ID',
+    `reason` varchar(255) COMMENT '举报原因',
+    `status` tinyint DEFAULT 0 COMMENT '状态：0-待处理，1-已隐藏评价，2-已驳回',
+    `handler_id` int unsigned COMMENT '处理管理员ID',
+    `handled_at` datetime COMMENT '处理时间',
+    PRIMARY KEY (`id`),
+    INDEX `idx_review_reports_review_id` (`review_id`),
+    INDEX `idx_review_reports_user_id` (`user_id`)
+);
+
+ALTER TABLE `houses`
+    ADD COLUMN `rating` decimal(2,1) DEFAULT 0 COMMENT '房源评分',
+    ADD COLUMN `review_count` int DEFAULT 0 COMMENT '评价数量';
+
+ALTER TABLE `landlords`
+    ADD COLUMN `review_count` int DEFAULT 0 COMMENT '评价数量';
@@ -0,0 +1,8 @@
+-- 回滚后草稿、待审核、已出租和审核驳回状态的房源需人工处理
+ALTER TABLE `houses`
+    DROP INDEX `idx_houses_expire_at`,
+    DROP COLUMN `expire_at`,
+    DROP COLUMN `published_at`,
+    DROP COLUMN `moderation_flags`,
+    DROP COLUMN `reject_reason`,
+    MODIFY COLUMN `status` tinyint DEFAULT 1 COMMENT '状态：0-下架，1-上架';
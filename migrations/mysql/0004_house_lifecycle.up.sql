@@ -0,0 +1,13 @@
+-- 房源上架流程：审核状态、驳回原因、自动审核提示和上架有效期
+
+ALTER TABLE `houses`
+    MODIFY COLUMN `status` tinyint DEFAULT 2 COMMENT '状态：0-已下架，1-已发布，2-草稿，3-待审核，4-已出租，5-审核驳回',
+    ADD COLUMN `reject_reason` varchar(255) COMMENT '审核驳回原因',
+    ADD COLUMN `moderation_flags` text COMMENT '自动审核提示，JSON格式字符串',
+    ADD COLUMN `published_at` datetime COMMENT '发布时间',
+    ADD COLUMN `expire_at` datetime COMMENT '上架到期时间',
+    ADD INDEX `idx_houses_expire_at` (`expire_at`);
+
+-- 为已上架的历史房源补充发布时间和上架到期时间，有效期为 house.listing_ttl_days 的默认值30天
+UPDATE `houses` SET `published_at` = NOW(), `expire_at` = NOW() + INTERVAL 30 DAY
+WHERE `status` = 1 AND `expire_at` IS NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE `favorites` DROP COLUMN `notify_price_drop`;
+DROP TABLE IF EXISTS `notifications`;
+DROP TABLE IF EXISTS `house_price_histories`;
+DROP TABLE IF EXISTS `house_revisions`;
@@ -0,0 +1,44 @@
+-- 房源修改记录、租金变动记录、站内通知和收藏降价提醒
+
+CREATE TABLE `house_revisions` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `house_id` int unsigned COMMENT '房源ID',
+    `operator_id` int unsigned COMMENT '操作人用户ID，0表示系统',
+    `changes` text COMMENT '变更字段，JSON格式字符串',
+    PRIMARY KEY (`id`),
+    INDEX `idx_house_revisions_house_id` (`house_id`)
+);
+
+CREATE TABLE `house_price_histories` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `house_id` int unsigned COMMENT '房源ID',
+    `price` decimal(10,2) NOT NULL COMMENT '租金(元/月)',
+    `prev_price` decimal(10,2) DEFAULT 0 COMMENT '变动前租金',
+    PRIMARY KEY (`id`),
+    INDEX `idx_house_price_histories_house_id` (`house_id`)
+);
+
+CREATE TABLE `notifications` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `user_id` int unsigned COMMENT '接收用户ID',
+    `type` varchar(32) NOT NULL COMMENT '通知类型',
+    `title` varchar(100) NOT NULL COMMENT '通知标题',
+    `content` text COMMENT '通知内容',
+    `related_id` int unsigned DEFAULT 0 COMMENT '关联对象ID',
+    `is_read` tinyint(1) DEFAULT false COMMENT '是否已读',
+    `read_at` datetime COMMENT '阅读时间',
+    PRIMARY KEY (`id`),
+    INDEX `idx_notifications_user_id` (`user_id`)
+);
+
+ALTER TABLE `favorites`
+    ADD COLUMN `notify_price_drop` tinyint(1) DEFAULT false COMMENT '是否开启降价提醒';
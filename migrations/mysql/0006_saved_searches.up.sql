@@ -0,0 +1,17 @@
+-- 保存的搜索条件，站内提醒不设默认值以保证关闭的设置能写入
+
+CREATE TABLE `saved_searches` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `user_id` int unsigned COMMENT '用户ID',
+    `name` varchar(50) NOT NULL COMMENT '搜索名称',
+    `filters` text COMMENT '筛选条件，JSON格式字符串',
+    `notify_in_app` tinyint(1) COMMENT '是否开启站内提醒',
+    `notify_sms` tinyint(1) DEFAULT false COMMENT '是否开启短信提醒',
+    `last_checked_at` datetime COMMENT '上次查看时间',
+    `last_notified_at` datetime COMMENT '上次短信提醒时间',
+    PRIMARY KEY (`id`),
+    INDEX `idx_saved_searches_user_id` (`user_id`)
+);
@@ -0,0 +1,7 @@
+-- 清理的软删除和重复收藏不会恢复
+ALTER TABLE `favorites`
+    DROP INDEX `idx_favorites_folder_id`,
+    DROP INDEX `uk_favorites_user_house`,
+    DROP COLUMN `price_at_favorite`,
+    DROP COLUMN `folder_id`;
+DROP TABLE IF EXISTS `favorite_folders`;
@@ -0,0 +1,27 @@
+-- 收藏夹、收藏时的租金，同一用户对同一房源只能收藏一次
+
+-- 新增(user_id, house_id)唯一索引前，清理软删除的收藏和重复收藏（保留最早的一条）
+DELETE FROM `favorites` WHERE `deleted_at` IS NOT NULL;
+DELETE f1 FROM `favorites` f1 JOIN `favorites` f2
+    ON f1.`user_id` = f2.`user_id` AND f1.`house_id` = f2.`house_id` AND f1.`id` > f2.`id`;
+
+CREATE TABLE `favorite_folders` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `user_id` int unsigned COMMENT '用户ID',
+    `name` varchar(50) NOT NULL COMMENT '收藏夹名称',
+    PRIMARY KEY (`id`),
+    INDEX `idx_favorite_folders_user_id` (`user_id`)
+);
+
+ALTER TABLE `favorites`
+    ADD COLUMN `folder_id` int unsigned DEFAULT 0 COMMENT '收藏夹ID，0表示默认收藏夹',
+    ADD COLUMN `price_at_favorite` decimal(10,2) DEFAULT 0 COMMENT '收藏时的租金',
+    ADD UNIQUE INDEX `uk_favorites_user_house` (`user_id`,`house_id`),
+    ADD INDEX `idx_favorites_folder_id` (`folder_id`);
+
+-- 为历史收藏补充收藏时的租金，以房源当前租金为准
+UPDATE `favorites` f JOIN `houses` h ON f.`house_id` = h.`id`
+SET f.`price_at_favorite` = h.`rent_price` WHERE f.`price_at_favorite` = 0;
@@ -0,0 +1,10 @@
+ALTER TABLE `houses`
+    DROP INDEX `idx_houses_community_code`,
+    DROP INDEX `idx_houses_district_code`,
+    DROP INDEX `idx_houses_city_code`,
+    DROP INDEX `idx_houses_province_code`,
+    DROP COLUMN `community_code`,
+    DROP COLUMN `district_code`,
+    DROP COLUMN `city_code`,
+    DROP COLUMN `province_code`;
+DROP TABLE IF EXISTS `regions`;
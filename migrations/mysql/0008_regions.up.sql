@@ -0,0 +1,25 @@
+-- 行政区划和房源所在区域，区划数据和历史房源的所在区域在执行迁移后由迁移工具导入和识别
+
+CREATE TABLE `regions` (
+    `id` int unsigned AUTO_INCREMENT COMMENT '主键ID',
+    `created_at` datetime COMMENT '创建时间',
+    `updated_at` datetime COMMENT '更新时间',
+    `deleted_at` datetime COMMENT '删除时间',
+    `code` varchar(12) NOT NULL COMMENT '行政区划代码',
+    `parent_code` varchar(12) COMMENT '上级区划代码',
+    `name` varchar(50) NOT NULL COMMENT '名称',
+    `level` tinyint NOT NULL COMMENT '级别：1-省，2-市，3-区县，4-街道',
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_regions_code` (`code`),
+    INDEX `idx_regions_parent_code` (`parent_code`)
+);
+
+ALTER TABLE `houses`
+    ADD COLUMN `province_code` varchar(12) COMMENT '省级区划代码',
+    ADD COLUMN `city_code` varchar(12) COMMENT '市级区划代码',
+    ADD COLUMN `district_code` varchar(12) COMMENT '区县区划代码',
+    ADD COLUMN `community_code` varchar(12) COMMENT '街道区划代码',
+    ADD INDEX `idx_houses_province_code` (`province_code`),
+    ADD INDEX `idx_houses_city_code` (`city_code`),
+    ADD INDEX `idx_houses_district_code` (`district_code`),
+    ADD INDEX `idx_houses_community_code` (`community_code`);
@@ -0,0 +1 @@
+ALTER TABLE `regions` DROP COLUMN `radius`, DROP COLUMN `longitude`, DROP COLUMN `latitude`;
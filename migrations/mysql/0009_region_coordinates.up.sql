@@ -0,0 +1,6 @@
+-- 行政区划中心点和覆盖范围，用于离线地理编码和坐标校验
+
+ALTER TABLE `regions`
+    ADD COLUMN `latitude` decimal(10,6) COMMENT '中心点纬度',
+    ADD COLUMN `longitude` decimal(10,6) COMMENT '中心点经度',
+    ADD COLUMN `radius` decimal(8,2) COMMENT '覆盖范围半径(千米)';
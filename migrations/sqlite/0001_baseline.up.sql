@@ -0,0 +1,113 @@
+-- 基线：改用版本迁移前AutoMigrate生成的表结构（用户、房源、收藏、预约看房、房东、短信记录）
+
+CREATE TABLE `users` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `username` varchar(50),
+    `password` varchar(100),
+    `phone` varchar(20),
+    `avatar` varchar(255),
+    `last_login` datetime DEFAULT null,
+    `real_name` varchar(50),
+    `id_card` varchar(18),
+    `email` varchar(100),
+    `user_type` tinyint DEFAULT 0
+);
+
+CREATE TABLE `houses` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `title` varchar(100) NOT NULL,
+    `description` text,
+    `address` varchar(255) NOT NULL,
+    `area` decimal(10,2) NOT NULL,
+    `floor` int,
+    `total_floor` int,
+    `rooms` int NOT NULL,
+    `halls` int NOT NULL,
+    `bathrooms` int NOT NULL,
+    `rent_price` decimal(10,2) NOT NULL,
+    `deposit` decimal(10,2),
+    `payment_type` tinyint DEFAULT 1,
+    `house_type` tinyint NOT NULL,
+    `orientation` varchar(20),
+    `decoration` tinyint DEFAULT 1,
+    `facilities` text,
+    `status` tinyint DEFAULT 1,
+    `landlord_id` int unsigned,
+    `images` text,
+    `latitude` decimal(10,6),
+    `longitude` decimal(10,6),
+    `is_elevator` tinyint(1) DEFAULT false,
+    `view_count` int DEFAULT 0
+);
+
+CREATE TABLE `favorites` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `user_id` int unsigned,
+    `house_id` int unsigned,
+    `notes` text
+);
+
+CREATE TABLE `viewings` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `house_id` int unsigned,
+    `user_id` int unsigned,
+    `viewing_time` datetime NOT NULL,
+    `status` tinyint DEFAULT 0,
+    `remark` text,
+    `contact_name` varchar(50),
+    `contact_phone` varchar(20),
+    `confirm_time` datetime DEFAULT null,
+    `cancel_time` datetime DEFAULT null,
+    `cancel_reason` text
+);
+
+CREATE TABLE `landlords` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `user_id` int unsigned,
+    `real_name` varchar(50),
+    `id_number` varchar(18),
+    `phone_number` varchar(20),
+    `address` varchar(255),
+    `verified` tinyint(1) DEFAULT false,
+    `id_card_front` varchar(255),
+    `id_card_back` varchar(255),
+    `bank_account` varchar(50),
+    `bank_name` varchar(100),
+    `account_name` varchar(50),
+    `introduction` text,
+    `rating` decimal(2,1) DEFAULT 5
+);
+
+CREATE TABLE `sms_records` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `phone` varchar(20),
+    `code` varchar(10),
+    `template_id` varchar(50),
+    `content` varchar(255),
+    `status` tinyint(1),
+    `fail_reason` varchar(255),
+    `provider` varchar(50),
+    `ip_address` varchar(50),
+    `user_agent` varchar(255),
+    `biz_id` varchar(50),
+    `request_id` varchar(50)
+);
+CREATE INDEX `idx_sms_records_phone` ON `sms_records` (`phone`);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `landlord_verifications`;
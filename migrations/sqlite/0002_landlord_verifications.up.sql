@@ -0,0 +1,22 @@
+-- 房东身份认证申请
+
+CREATE TABLE `landlord_verifications` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `landlord_id` int unsigned,
+    `user_id` int unsigned,
+    `real_name` varchar(50),
+    `id_number` varchar(18),
+    `id_card_front` varchar(255),
+    `id_card_back` varchar(255),
+    `documents` text,
+    `status` tinyint DEFAULT 0,
+    `reject_reason` varchar(255),
+    `reviewer_id` int unsigned,
+    `reviewed_at` datetime
+);
+CREATE INDEX `idx_landlord_verifications_landlord_id` ON `landlord_verifications` (`landlord_id`);
+CREATE INDEX `idx_landlord_verifications_user_id` ON `landlord_verifications` (`user_id`);
+CREATE INDEX `idx_landlord_verifications_status` ON `landlord_verifications` (`status`);
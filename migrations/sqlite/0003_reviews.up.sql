@@ -0,0 +1,42 @@
+-- 租客评价和评价举报，房源和房东的评分汇总
+
+CREATE TABLE `reviews` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `user_id` int unsigned,
+    `house_id` int unsigned,
+    `landlord_id` int unsigned,
+    `viewing_id` int unsigned,
+    `landlord_rating` tinyint NOT NULL,
+    `house_rating` tinyint NOT NULL,
+    `content` text,
+    `reply` text,
+    `reply_time` datetime,
+    `status` tinyint DEFAULT 0,
+    `report_count` int DEFAULT 0
+);
+CREATE INDEX `idx_reviews_user_id` ON `reviews` (`user_id`);
+CREATE INDEX `idx_reviews_house_id` ON `reviews` (`house_id`);
+CREATE INDEX `idx_reviews_landlord_id` ON `reviews` (`landlord_id`);
+CREATE UNIQUE INDEX `idx_reviews_viewing_id` ON `reviews` (`viewing_id`);
+
+CREATE TABLE `review_reports` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `review_id` int unsigned,
+    `user_id` int unsigned,
+    `reason` varchar(255),
+    `status` tinyint DEFAULT 0,
+    `handler_id` int unsigned,
+    `handled_at` datetime
+);
+CREATE INDEX `idx_review_reports_review_id` ON `review_reports` (`review_id`);
+CREATE INDEX `idx_review_reports_user_id` ON `review_reports` (`user_id`);
+
+ALTER TABLE `houses` ADD COLUMN `rating` decimal(2,1) DEFAULT 0;
+ALTER TABLE `houses` ADD COLUMN `review_count` int DEFAULT 0;
+ALTER TABLE `landlords` ADD COLUMN `review_count` int DEFAULT 0;
@@ -0,0 +1,36 @@
+-- 回滚后草稿、待审核、已出租和审核驳回状态的房源需人工处理
+CREATE TABLE `houses_new` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `title` varchar(100) NOT NULL,
+    `description` text,
+    `address` varchar(255) NOT NULL,
+    `area` decimal(10,2) NOT NULL,
+    `floor` int,
+    `total_floor` int,
+    `rooms` int NOT NULL,
+    `halls` int NOT NULL,
+    `bathrooms` int NOT NULL,
+    `rent_price` decimal(10,2) NOT NULL,
+    `deposit` decimal(10,2),
+    `payment_type` tinyint DEFAULT 1,
+    `house_type` tinyint NOT NULL,
+    `orientation` varchar(20),
+    `decoration` tinyint DEFAULT 1,
+    `facilities` text,
+    `status` tinyint DEFAULT 1,
+    `landlord_id` int unsigned,
+    `images` text,
+    `latitude` decimal(10,6),
+    `longitude` decimal(10,6),
+    `is_elevator` tinyint(1) DEFAULT false,
+    `view_count` int DEFAULT 0,
+    `rating` decimal(2,1) DEFAULT 0,
+    `review_count` int DEFAULT 0
+);
+INSERT INTO `houses_new` (`id`, `created_at`, `updated_at`, `deleted_at`, `title`, `description`, `address`, `area`, `floor`, `total_floor`, `rooms`, `halls`, `bathrooms`, `rent_price`, `deposit`, `payment_type`, `house_type`, `orientation`, `decoration`, `facilities`, `status`, `landlord_id`, `images`, `latitude`, `longitude`, `is_elevator`, `view_count`, `rating`, `review_count`)
+SELECT `id`, `created_at`, `updated_at`, `deleted_at`, `title`, `description`, `address`, `area`, `floor`, `total_floor`, `rooms`, `halls`, `bathrooms`, `rent_price`, `deposit`, `payment_type`, `house_type`, `orientation`, `decoration`, `facilities`, `status`, `landlord_id`, `images`, `latitude`, `longitude`, `is_elevator`, `view_count`, `rating`, `review_count` FROM `houses`;
+DROP TABLE `houses`;
+ALTER TABLE `houses_new` RENAME TO `houses`;
@@ -0,0 +1,47 @@
+-- 房源上架流程：审核状态、驳回原因、自动审核提示和上架有效期
+
+-- SQLite不支持修改列的默认值，重建房源表
+CREATE TABLE `houses_new` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `title` varchar(100) NOT NULL,
+    `description` text,
+    `address` varchar(255) NOT NULL,
+    `area` decimal(10,2) NOT NULL,
+    `floor` int,
+    `total_floor` int,
+    `rooms` int NOT NULL,
+    `halls` int NOT NULL,
+    `bathrooms` int NOT NULL,
+    `rent_price` decimal(10,2) NOT NULL,
+    `deposit` decimal(10,2),
+    `payment_type` tinyint DEFAULT 1,
+    `house_type` tinyint NOT NULL,
+    `orientation` varchar(20),
+    `decoration` tinyint DEFAULT 1,
+    `facilities` text,
+    `status` tinyint DEFAULT 2,
+    `landlord_id` int unsigned,
+    `images` text,
+    `latitude` decimal(10,6),
+    `longitude` decimal(10,6),
+    `is_elevator` tinyint(1) DEFAULT false,
+    `view_count` int DEFAULT 0,
+    `rating` decimal(2,1) DEFAULT 0,
+    `review_count` int DEFAULT 0,
+    `reject_reason` varchar(255),
+    `moderation_flags` text,
+    `published_at` datetime,
+    `expire_at` datetime
+);
+INSERT INTO `houses_new` (`id`, `created_at`, `updated_at`, `deleted_at`, `title`, `description`, `address`, `area`, `floor`, `total_floor`, `rooms`, `halls`, `bathrooms`, `rent_price`, `deposit`, `payment_type`, `house_type`, `orientation`, `decoration`, `facilities`, `status`, `landlord_id`, `images`, `latitude`, `longitude`, `is_elevator`, `view_count`, `rating`, `review_count`)
+SELECT `id`, `created_at`, `updated_at`, `deleted_at`, `title`, `description`, `address`, `area`, `floor`, `total_floor`, `rooms`, `halls`, `bathrooms`, `rent_price`, `deposit`, `payment_type`, `house_type`, `orientation`, `decoration`, `facilities`, `status`, `landlord_id`, `images`, `latitude`, `longitude`, `is_elevator`, `view_count`, `rating`, `review_count` FROM `houses`;
+DROP TABLE `houses`;
+ALTER TABLE `houses_new` RENAME TO `houses`;
+CREATE INDEX `idx_houses_expire_at` ON `houses` (`expire_at`);
+
+-- 为已上架的历史房源补充发布时间和上架到期时间，有效期为 house.listing_ttl_days 的默认值30天
+UPDATE `houses` SET `published_at` = datetime('now', 'localtime'), `expire_at` = datetime('now', 'localtime', '+30 days')
+WHERE `status` = 1 AND `expire_at` IS NULL;
@@ -0,0 +1,40 @@
+-- 房源修改记录、租金变动记录、站内通知和收藏降价提醒
+
+CREATE TABLE `house_revisions` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `house_id` int unsigned,
+    `operator_id` int unsigned,
+    `changes` text
+);
+CREATE INDEX `idx_house_revisions_house_id` ON `house_revisions` (`house_id`);
+
+CREATE TABLE `house_price_histories` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `house_id` int unsigned,
+    `price` decimal(10,2) NOT NULL,
+    `prev_price` decimal(10,2) DEFAULT 0
+);
+CREATE INDEX `idx_house_price_histories_house_id` ON `house_price_histories` (`house_id`);
+
+CREATE TABLE `notifications` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `user_id` int unsigned,
+    `type` varchar(32) NOT NULL,
+    `title` varchar(100) NOT NULL,
+    `content` text,
+    `related_id` int unsigned DEFAULT 0,
+    `is_read` tinyint(1) DEFAULT false,
+    `read_at` datetime
+);
+CREATE INDEX `idx_notifications_user_id` ON `notifications` (`user_id`);
+
+ALTER TABLE `favorites` ADD COLUMN `notify_price_drop` tinyint(1) DEFAULT false;
@@ -0,0 +1,16 @@
+-- 保存的搜索条件，站内提醒不设默认值以保证关闭的设置能写入
+
+CREATE TABLE `saved_searches` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `user_id` int unsigned,
+    `name` varchar(50) NOT NULL,
+    `filters` text,
+    `notify_in_app` tinyint(1),
+    `notify_sms` tinyint(1) DEFAULT false,
+    `last_checked_at` datetime,
+    `last_notified_at` datetime
+);
+CREATE INDEX `idx_saved_searches_user_id` ON `saved_searches` (`user_id`);
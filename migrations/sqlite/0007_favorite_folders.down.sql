@@ -0,0 +1,6 @@
+-- 清理的软删除和重复收藏不会恢复
+DROP INDEX `idx_favorites_folder_id`;
+DROP INDEX `uk_favorites_user_house`;
+ALTER TABLE `favorites` DROP COLUMN `price_at_favorite`;
+ALTER TABLE `favorites` DROP COLUMN `folder_id`;
+DROP TABLE IF EXISTS `favorite_folders`;
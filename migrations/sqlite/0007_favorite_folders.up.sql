@@ -0,0 +1,24 @@
+-- 收藏夹、收藏时的租金，同一用户对同一房源只能收藏一次
+
+-- 新增(user_id, house_id)唯一索引前，清理软删除的收藏和重复收藏（保留最早的一条）
+DELETE FROM `favorites` WHERE `deleted_at` IS NOT NULL;
+DELETE FROM `favorites` WHERE `id` NOT IN (SELECT MIN(`id`) FROM `favorites` GROUP BY `user_id`, `house_id`);
+
+CREATE TABLE `favorite_folders` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `user_id` int unsigned,
+    `name` varchar(50) NOT NULL
+);
+CREATE INDEX `idx_favorite_folders_user_id` ON `favorite_folders` (`user_id`);
+
+ALTER TABLE `favorites` ADD COLUMN `folder_id` int unsigned DEFAULT 0;
+ALTER TABLE `favorites` ADD COLUMN `price_at_favorite` decimal(10,2) DEFAULT 0;
+CREATE UNIQUE INDEX `uk_favorites_user_house` ON `favorites` (`user_id`,`house_id`);
+CREATE INDEX `idx_favorites_folder_id` ON `favorites` (`folder_id`);
+
+-- 为历史收藏补充收藏时的租金，以房源当前租金为准
+UPDATE `favorites` SET `price_at_favorite` = (SELECT `rent_price` FROM `houses` WHERE `houses`.`id` = `favorites`.`house_id`)
+WHERE `price_at_favorite` = 0 AND `house_id` IN (SELECT `id` FROM `houses`);
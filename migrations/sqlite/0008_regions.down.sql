@@ -0,0 +1,9 @@
+DROP INDEX `idx_houses_community_code`;
+DROP INDEX `idx_houses_district_code`;
+DROP INDEX `idx_houses_city_code`;
+DROP INDEX `idx_houses_province_code`;
+ALTER TABLE `houses` DROP COLUMN `community_code`;
+ALTER TABLE `houses` DROP COLUMN `district_code`;
+ALTER TABLE `houses` DROP COLUMN `city_code`;
+ALTER TABLE `houses` DROP COLUMN `province_code`;
+DROP TABLE IF EXISTS `regions`;
@@ -0,0 +1,23 @@
+-- 行政区划和房源所在区域，区划数据和历史房源的所在区域在执行迁移后由迁移工具导入和识别
+
+CREATE TABLE `regions` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `code` varchar(12) NOT NULL,
+    `parent_code` varchar(12),
+    `name` varchar(50) NOT NULL,
+    `level` tinyint NOT NULL
+);
+CREATE UNIQUE INDEX `idx_regions_code` ON `regions` (`code`);
+CREATE INDEX `idx_regions_parent_code` ON `regions` (`parent_code`);
+
+ALTER TABLE `houses` ADD COLUMN `province_code` varchar(12);
+ALTER TABLE `houses` ADD COLUMN `city_code` varchar(12);
+ALTER TABLE `houses` ADD COLUMN `district_code` varchar(12);
+ALTER TABLE `houses` ADD COLUMN `community_code` varchar(12);
+CREATE INDEX `idx_houses_province_code` ON `houses` (`province_code`);
+CREATE INDEX `idx_houses_city_code` ON `houses` (`city_code`);
+CREATE INDEX `idx_houses_district_code` ON `houses` (`district_code`);
+CREATE INDEX `idx_houses_community_code` ON `houses` (`community_code`);
@@ -0,0 +1,3 @@
+ALTER TABLE `regions` DROP COLUMN `radius`;
+ALTER TABLE `regions` DROP COLUMN `longitude`;
+ALTER TABLE `regions` DROP COLUMN `latitude`;
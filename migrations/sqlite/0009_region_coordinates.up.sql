@@ -0,0 +1,5 @@
+-- 行政区划中心点和覆盖范围，用于离线地理编码和坐标校验
+
+ALTER TABLE `regions` ADD COLUMN `latitude` decimal(10,6);
+ALTER TABLE `regions` ADD COLUMN `longitude` decimal(10,6);
+ALTER TABLE `regions` ADD COLUMN `radius` decimal(8,2);
@@ -2,7 +2,17 @@ package model
 
 type Favorite struct {
 	BaseModel
-	UserID  uint `gorm:"type:int unsigned;comment:用户ID" json:"user_id"`  // 用户ID
-	HouseID uint `gorm:"type:int unsigned;comment:房源ID" json:"house_id"` // 房源ID
+	UserID  uint `gorm:"type:int unsigned;uniqueIndex:uk_favorites_user_house;comment:用户ID" json:"user_id"`  // 用户ID
+	HouseID uint `gorm:"type:int unsigned;uniqueIndex:uk_favorites_user_house;comment:房源ID" json:"house_id"` // 房源ID
+	FolderID uint `gorm:"type:int unsigned;default:0;index;comment:收藏夹ID，0表示默认收藏夹" json:"folder_id"` // 收藏夹ID，0表示默认收藏夹
 	Notes   string `gorm:"type:text;comment:收藏备注" json:"notes"` // 收藏备注
-}
\ No newline at end of file
+	PriceAtFavorite float64 `gorm:"type:decimal(10,2);default:0;comment:收藏时的租金" json:"price_at_favorite"` // 收藏时的租金，用于提示租金变动
+	NotifyPriceDrop bool `gorm:"type:tinyint(1);default:false;comment:是否开启降价提醒" json:"notify_price_drop"` // 是否开启降价提醒
+}
+
+// FavoriteFolder 收藏夹模型
+type FavoriteFolder struct {
+	BaseModel
+	UserID uint   `gorm:"type:int unsigned;index;comment:用户ID" json:"user_id"`   // 用户ID
+	Name   string `gorm:"type:varchar(50);not null;comment:收藏夹名称" json:"name"` // 收藏夹名称
+}
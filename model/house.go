@@ -1,10 +1,18 @@
 package model
 
+import (
+	"time"
+)
+
 type House struct {
 	BaseModel
 	Title       string  `gorm:"type:varchar(100);not null;comment:房源标题" json:"title"`       // 房源标题
 	Description string  `gorm:"type:text;comment:房源描述" json:"description"`         // 房源描述
 	Address     string  `gorm:"type:varchar(255);not null;comment:房源地址" json:"address"`     // 房源地址
+	ProvinceCode  string `gorm:"type:varchar(12);index;comment:省级区划代码" json:"province_code"`   // 省级区划代码
+	CityCode      string `gorm:"type:varchar(12);index;comment:市级区划代码" json:"city_code"`       // 市级区划代码
+	DistrictCode  string `gorm:"type:varchar(12);index;comment:区县区划代码" json:"district_code"`   // 区县区划代码
+	CommunityCode string `gorm:"type:varchar(12);index;comment:街道区划代码" json:"community_code"`  // 街道区划代码
 	Area        float64 `gorm:"type:decimal(10,2);not null;comment:房屋面积(平方米)" json:"area"` // 房屋面积(平方米)
 	Floor       int     `gorm:"type:int;comment:所在楼层" json:"floor"`                        // 所在楼层
 	TotalFloor  int     `gorm:"type:int;comment:总楼层" json:"total_floor"`                  // 总楼层
@@ -18,11 +26,32 @@ type House struct {
 	Orientation string  `gorm:"type:varchar(20);comment:朝向" json:"orientation"`           // 朝向
 	Decoration  int     `gorm:"type:tinyint;default:1;comment:装修情况：1-简装，2-精装，3-豪装" json:"decoration"`          // 装修情况：1-简装，2-精装，3-豪装
 	Facilities  string  `gorm:"type:text;comment:配套设施，JSON格式字符串" json:"facilities"`          // 配套设施，JSON格式字符串
-	Status      int     `gorm:"type:tinyint;default:1;comment:状态：0-下架，1-上架" json:"status"`              // 状态：0-下架，1-上架
+	Status      int     `gorm:"type:tinyint;default:2;comment:状态：0-已下架，1-已发布，2-草稿，3-待审核，4-已出租，5-审核驳回" json:"status"` // 状态：0-已下架，1-已发布，2-草稿，3-待审核，4-已出租，5-审核驳回
+	RejectReason    string     `gorm:"type:varchar(255);comment:审核驳回原因" json:"reject_reason"`            // 审核驳回原因
+	ModerationFlags string     `gorm:"type:text;comment:自动审核提示，JSON格式字符串" json:"moderation_flags"`       // 自动审核提示，JSON格式字符串
+	PublishedAt     *time.Time `gorm:"type:datetime;comment:发布时间" json:"published_at"`        // 发布时间
+	ExpireAt        *time.Time `gorm:"type:datetime;index;comment:上架到期时间" json:"expire_at"` // 上架到期时间，到期需房东刷新
 	LandlordID  uint    `gorm:"type:int unsigned;comment:房东ID" json:"landlord_id"`                  // 房东ID
 	Images      string  `gorm:"type:text;comment:房源图片URL，JSON格式字符串" json:"images"`              // 房源图片URL，JSON格式字符串
 	Latitude    float64 `gorm:"type:decimal(10,6);comment:纬度" json:"latitude"`    // 纬度
 	Longitude   float64 `gorm:"type:decimal(10,6);comment:经度" json:"longitude"`   // 经度
 	IsElevator  bool    `gorm:"type:tinyint(1);default:false;comment:是否有电梯" json:"is_elevator"`      // 是否有电梯
 	ViewCount   int     `gorm:"type:int;default:0;comment:浏览次数" json:"view_count"`          // 浏览次数
-}
\ No newline at end of file
+	Rating      float64 `gorm:"type:decimal(2,1);default:0;comment:房源评分" json:"rating"`            // 房源评分
+	ReviewCount int     `gorm:"type:int;default:0;comment:评价数量" json:"review_count"`        // 评价数量
+}
+
+// 房源状态常量
+const (
+	HouseStatusOffline   = 0 // 已下架
+	HouseStatusPublished = 1 // 已发布
+	HouseStatusDraft     = 2 // 草稿
+	HouseStatusPending   = 3 // 待审核
+	HouseStatusRented    = 4 // 已出租
+	HouseStatusRejected  = 5 // 审核驳回
+)
+
+// IsPublic 房源是否对租客公开可见，草稿、待审核和审核驳回的房源仅房东本人可见
+func (h *House) IsPublic() bool {
+	return h.Status == HouseStatusPublished || h.Status == HouseStatusRented || h.Status == HouseStatusOffline
+}
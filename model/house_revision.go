@@ -0,0 +1,26 @@
+package model
+
+// HouseRevision 房源修改记录
+// 每次修改房源信息或变更房源状态时记录变更的字段、操作人和时间
+type HouseRevision struct {
+	BaseModel
+	HouseID    uint   `gorm:"type:int unsigned;index;comment:房源ID" json:"house_id"`       // 房源ID
+	OperatorID uint   `gorm:"type:int unsigned;comment:操作人用户ID，0表示系统" json:"operator_id"` // 操作人用户ID，0表示系统
+	Changes    string `gorm:"type:text;comment:变更字段，JSON格式字符串" json:"changes"`            // 变更字段，JSON格式字符串
+}
+
+// HouseFieldChange 房源单个字段的变更内容
+type HouseFieldChange struct {
+	Field string      `json:"field"` // 字段名
+	Old   interface{} `json:"old"`   // 修改前的值
+	New   interface{} `json:"new"`   // 修改后的值
+}
+
+// HousePriceHistory 房源租金变动记录
+// 房源创建时记录初始租金，此后每次租金变动追加一条记录
+type HousePriceHistory struct {
+	BaseModel
+	HouseID   uint    `gorm:"type:int unsigned;index;comment:房源ID" json:"house_id"`         // 房源ID
+	Price     float64 `gorm:"type:decimal(10,2);not null;comment:租金(元/月)" json:"price"`     // 租金(元/月)
+	PrevPrice float64 `gorm:"type:decimal(10,2);default:0;comment:变动前租金" json:"prev_price"` // 变动前租金，初始记录为0
+}
@@ -15,4 +15,5 @@ type Landlord struct {
 	AccountName  string `gorm:"type:varchar(50);comment:开户人姓名" json:"account_name"`            // 开户人姓名
 	Introduction string `gorm:"type:text;comment:房东自我介绍" json:"introduction"`           // 房东自我介绍
 	Rating       float64 `gorm:"type:decimal(2,1);default:5.0;comment:房东评分" json:"rating"` // 房东评分
+	ReviewCount  int     `gorm:"type:int;default:0;comment:评价数量" json:"review_count"`     // 评价数量
 }
\ No newline at end of file
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+)
+
+// LandlordVerification 房东实名认证申请模型
+// 记录房东提交的认证资料和管理员的审核结果，被驳回后房东可以重新提交新的申请
+type LandlordVerification struct {
+	BaseModel
+	LandlordID   uint       `gorm:"type:int unsigned;index;comment:房东ID" json:"landlord_id"`                 // 房东ID
+	UserID       uint       `gorm:"type:int unsigned;index;comment:用户ID" json:"user_id"`                     // 用户ID
+	RealName     string     `gorm:"type:varchar(50);comment:真实姓名" json:"real_name"`                          // 真实姓名
+	IDNumber     string     `gorm:"type:varchar(18);comment:身份证号" json:"id_number"`                          // 身份证号
+	IdCardFront  string     `gorm:"type:varchar(255);comment:身份证正面照片URL" json:"id_card_front"`               // 身份证正面照片URL
+	IdCardBack   string     `gorm:"type:varchar(255);comment:身份证背面照片URL" json:"id_card_back"`                // 身份证背面照片URL
+	Documents    string     `gorm:"type:text;comment:其他证明材料URL，JSON格式字符串" json:"documents"`                  // 其他证明材料URL，JSON格式字符串
+	Status       int        `gorm:"type:tinyint;default:0;index;comment:状态：0-待审核，1-已通过，2-已驳回" json:"status"` // 状态：0-待审核，1-已通过，2-已驳回
+	RejectReason string     `gorm:"type:varchar(255);comment:驳回原因" json:"reject_reason"`                     // 驳回原因
+	ReviewerID   uint       `gorm:"type:int unsigned;comment:审核管理员ID" json:"reviewer_id"`                    // 审核管理员ID
+	ReviewedAt   *time.Time `gorm:"type:datetime;comment:审核时间" json:"reviewed_at"`              // 审核时间
+}
+
+// 房东认证申请状态常量
+const (
+	VerificationPending  = 0 // 待审核
+	VerificationApproved = 1 // 已通过
+	VerificationRejected = 2 // 已驳回
+)
@@ -5,6 +5,7 @@ import (
 	"myApp/config"
 	"time"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -12,35 +13,47 @@ import (
 // BaseModel 定义了所有模型共享的基础字段
 // 这个结构体可以被其他模型嵌入，以提供统一的ID、时间戳和软删除功能
 type BaseModel struct {
-	ID        uint           `gorm:"type:int unsigned;primaryKey;comment:主键ID" json:"id"`
+	ID        uint           `gorm:"primaryKey;size:32;comment:主键ID" json:"id"`
 	CreatedAt time.Time      `gorm:"type:datetime;comment:创建时间" json:"created_at"`
 	UpdatedAt time.Time      `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"type:datetime;comment:删除时间" json:"-"`
 }
 
-var db *gorm.DB
+// OpenDB 根据配置的驱动打开数据库连接，支持mysql和sqlite
+// sqlite用于本地开发和测试，表结构同样由migrations目录下的迁移脚本创建
+// 开启错误转换，违反唯一索引时返回gorm.ErrDuplicatedKey，与数据库驱动无关
+func OpenDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	switch cfg.Driver {
+	case "", "mysql":
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			cfg.User,
+			cfg.Password,
+			cfg.Host,
+			cfg.Port,
+			cfg.DBName)
+		return gorm.Open(mysql.Open(dsn), &gorm.Config{TranslateError: true})
+	case "sqlite":
+		db, err := gorm.Open(sqlite.Open(cfg.DBName), &gorm.Config{TranslateError: true})
+		if err != nil {
+			return nil, err
+		}
+		// SQLite同一时间只允许一个写连接，内存数据库每个连接相互独立，因此只使用一个连接
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+		return db, nil
+	default:
+		return nil, fmt.Errorf("不支持的数据库驱动：%s", cfg.Driver)
+	}
+}
 
-// InitDB 初始化数据库连接
+// InitDB 根据全局配置初始化数据库连接，失败时直接退出
 func InitDB() *gorm.DB {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		config.Conf.Database.User,
-		config.Conf.Database.Password,
-		config.Conf.Database.Host,
-		config.Conf.Database.Port,
-		config.Conf.Database.DBName)
-
-	var err error
-	db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := OpenDB(config.Conf.Database)
 	if err != nil {
 		panic("数据库连接失败: " + err.Error())
 	}
 	return db
 }
-
-// GetDB 返回已初始化的数据库连接实例
-func GetDB() *gorm.DB {
-	if db == nil {
-		db = InitDB()
-	}
-	return db
-}
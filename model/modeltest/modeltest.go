@@ -0,0 +1,89 @@
+// Package modeltest 提供测试共用的数据库和测试数据：
+// 执行SQLite迁移脚本建表的内存数据库，以及按默认字段创建模型的fixture
+package modeltest
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"myApp/config"
+	"myApp/migrations"
+	"myApp/model"
+	"myApp/pkg/migrate"
+
+	"gorm.io/gorm"
+)
+
+// NewDB 创建独立的内存SQLite数据库，并执行全部迁移脚本建表，表结构与生产环境一致，每个测试互不影响
+func NewDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := model.OpenDB(config.DatabaseConfig{Driver: "sqlite", DBName: ":memory:"})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	fsys, err := migrations.For("sqlite")
+	if err != nil {
+		t.Fatalf("读取迁移脚本失败: %v", err)
+	}
+	list, err := migrate.Load(fsys)
+	if err != nil {
+		t.Fatalf("读取迁移脚本失败: %v", err)
+	}
+	if _, err := migrate.NewMigrator(db, list).Up(); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+	return db
+}
+
+// Create 用modify调整fixture的默认字段后写入数据库，modify为nil时使用默认字段
+func Create[T any](t *testing.T, db *gorm.DB, fixture *T, modify func(*T)) *T {
+	t.Helper()
+	if modify != nil {
+		modify(fixture)
+	}
+	if err := db.WithContext(t.Context()).Create(fixture).Error; err != nil {
+		t.Fatalf("创建测试数据%T失败: %v", fixture, err)
+	}
+	return fixture
+}
+
+// userSeq 测试用户的序号，保证用户名不重复
+var userSeq atomic.Int64
+
+// User 返回一个普通用户，用户名不重复
+func User() *model.User {
+	return &model.User{
+		Username: "user" + strconv.FormatInt(userSeq.Add(1), 10),
+		Password: "not-a-real-hash",
+		Phone:    "13800000000",
+		UserType: model.UserTypeNormal,
+	}
+}
+
+// House 返回一个已发布且在上架有效期内的房源，房东ID为1
+func House() *model.House {
+	publishedAt := time.Now().Add(-time.Hour)
+	expireAt := time.Now().Add(24 * time.Hour)
+	return &model.House{
+		Title:       "阳光花园 两室一厅",
+		Address:     "北京市朝阳区建国路1号",
+		Area:        80,
+		Rooms:       2,
+		Halls:       1,
+		Bathrooms:   1,
+		RentPrice:   5000,
+		HouseType:   1,
+		Status:      model.HouseStatusPublished,
+		PublishedAt: &publishedAt,
+		ExpireAt:    &expireAt,
+		LandlordID:  1,
+	}
+}
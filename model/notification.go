@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+// Notification 站内通知模型
+type Notification struct {
+	BaseModel
+	UserID    uint       `gorm:"type:int unsigned;index;comment:接收用户ID" json:"user_id"`        // 接收用户ID
+	Type      string     `gorm:"type:varchar(32);not null;comment:通知类型" json:"type"`           // 通知类型
+	Title     string     `gorm:"type:varchar(100);not null;comment:通知标题" json:"title"`         // 通知标题
+	Content   string     `gorm:"type:text;comment:通知内容" json:"content"`                        // 通知内容
+	RelatedID uint       `gorm:"type:int unsigned;default:0;comment:关联对象ID" json:"related_id"` // 关联对象ID，如房源ID
+	IsRead    bool       `gorm:"type:tinyint(1);default:false;comment:是否已读" json:"is_read"`    // 是否已读
+	ReadAt    *time.Time `gorm:"type:datetime;comment:阅读时间" json:"read_at"`       // 阅读时间
+}
+
+// 通知类型常量
+const (
+	NotificationPriceDrop   = "price_drop"   // 收藏房源降价
+	NotificationSearchMatch = "search_match" // 保存的搜索有新房源
+)
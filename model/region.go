@@ -0,0 +1,13 @@
+package model
+
+// Region 行政区划（省、市、区县、街道），数据由迁移程序从内置数据集导入
+type Region struct {
+	BaseModel
+	Code       string `gorm:"type:varchar(12);not null;uniqueIndex;comment:行政区划代码" json:"code"` // 行政区划代码
+	ParentCode string `gorm:"type:varchar(12);index;comment:上级区划代码" json:"parent_code"`         // 上级区划代码，省级为空
+	Name       string `gorm:"type:varchar(50);not null;comment:名称" json:"name"`                 // 名称
+	Level      int    `gorm:"type:tinyint;not null;comment:级别：1-省，2-市，3-区县，4-街道" json:"level"`  // 级别：1-省，2-市，3-区县，4-街道
+	Latitude   float64 `gorm:"type:decimal(10,6);comment:中心点纬度" json:"latitude"`     // 中心点纬度
+	Longitude  float64 `gorm:"type:decimal(10,6);comment:中心点经度" json:"longitude"`    // 中心点经度
+	Radius     float64 `gorm:"type:decimal(8,2);comment:覆盖范围半径(千米)" json:"radius"` // 覆盖范围半径(千米)，用于粗略校验坐标是否位于区划内
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+)
+
+// Review 租客评价模型
+// 租客完成看房后可对房东和房源分别打分并撰写评价，房东可以进行回复
+type Review struct {
+	BaseModel
+	UserID         uint       `gorm:"type:int unsigned;index;comment:评价用户ID" json:"user_id"`             // 评价用户ID
+	HouseID        uint       `gorm:"type:int unsigned;index;comment:房源ID" json:"house_id"`              // 房源ID
+	LandlordID     uint       `gorm:"type:int unsigned;index;comment:房东ID" json:"landlord_id"`           // 房东ID
+	ViewingID      uint       `gorm:"type:int unsigned;uniqueIndex;comment:关联的看房预约ID" json:"viewing_id"` // 关联的看房预约ID，每次看房只能评价一次
+	LandlordRating int        `gorm:"type:tinyint;not null;comment:房东评分(1-5星)" json:"landlord_rating"`   // 房东评分(1-5星)
+	HouseRating    int        `gorm:"type:tinyint;not null;comment:房源评分(1-5星)" json:"house_rating"`      // 房源评分(1-5星)
+	Content        string     `gorm:"type:text;comment:评价内容" json:"content"`                             // 评价内容
+	Reply          string     `gorm:"type:text;comment:房东回复" json:"reply"`                               // 房东回复
+	ReplyTime      *time.Time `gorm:"type:datetime;comment:回复时间" json:"reply_time"`         // 回复时间
+	Status         int        `gorm:"type:tinyint;default:0;comment:状态：0-正常，1-已隐藏" json:"status"`        // 状态：0-正常，1-已隐藏
+	ReportCount    int        `gorm:"type:int;default:0;comment:被举报次数" json:"report_count"`              // 被举报次数
+}
+
+// 评价状态常量
+const (
+	ReviewNormal = 0 // 正常
+	ReviewHidden = 1 // 已隐藏
+)
+
+// ReviewReport 评价举报模型
+// 用户可以举报不当评价，由管理员处理后决定是否隐藏该评价
+type ReviewReport struct {
+	BaseModel
+	ReviewID  uint       `gorm:"type:int unsigned;index;comment:评价ID" json:"review_id"`               // 评价ID
+	UserID    uint       `gorm:"type:int unsigned;index;comment:举报用户ID" json:"user_id"`               // 举报用户ID
+	Reason    string     `gorm:"type:varchar(255);comment:举报原因" json:"reason"`                        // 举报原因
+	Status    int        `gorm:"type:tinyint;default:0;comment:状态：0-待处理，1-已隐藏评价，2-已驳回" json:"status"` // 状态：0-待处理，1-已隐藏评价，2-已驳回
+	HandlerID uint       `gorm:"type:int unsigned;comment:处理管理员ID" json:"handler_id"`                 // 处理管理员ID
+	HandledAt *time.Time `gorm:"type:datetime;comment:处理时间" json:"handled_at"`           // 处理时间
+}
+
+// 举报处理状态常量
+const (
+	ReportPending   = 0 // 待处理
+	ReportHidden    = 1 // 已隐藏评价
+	ReportDismissed = 2 // 已驳回
+)
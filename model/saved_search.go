@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+// SavedSearch 用户保存的房源搜索条件
+type SavedSearch struct {
+	BaseModel
+	UserID         uint       `gorm:"type:int unsigned;index;comment:用户ID" json:"user_id"`                 // 用户ID
+	Name           string     `gorm:"type:varchar(50);not null;comment:搜索名称" json:"name"`                  // 搜索名称
+	Filters        string     `gorm:"type:text;comment:筛选条件，JSON格式字符串" json:"filters"`                     // 筛选条件，JSON格式字符串，与房源列表的查询参数一致
+	NotifyInApp    bool       `gorm:"type:tinyint(1);comment:是否开启站内提醒" json:"notify_in_app"`               // 有新房源匹配时是否发送站内通知，不设默认值以保证关闭的设置能写入
+	NotifySMS      bool       `gorm:"type:tinyint(1);default:false;comment:是否开启短信提醒" json:"notify_sms"`    // 有新房源匹配时是否发送短信
+	LastCheckedAt  *time.Time `gorm:"type:datetime;comment:上次查看时间" json:"last_checked_at"`    // 上次查看匹配结果的时间，之后发布的房源计为新房源
+	LastNotifiedAt *time.Time `gorm:"type:datetime;comment:上次短信提醒时间" json:"last_notified_at"` // 上次发送短信提醒的时间
+}
@@ -13,8 +13,8 @@ type Viewing struct {
 	Remark      string     `gorm:"type:text;comment:备注信息" json:"remark"`          // 备注信息
 	ContactName string     `gorm:"type:varchar(50);comment:联系人姓名" json:"contact_name"`      // 联系人姓名
 	ContactPhone string    `gorm:"type:varchar(20);comment:联系人电话" json:"contact_phone"`     // 联系人电话
-	ConfirmTime *time.Time `gorm:"type:datetime;default:null;comment:确认时间" json:"confirm_time"` // 确认时间
-	CancelTime  *time.Time `gorm:"type:datetime;default:null;comment:取消时间" json:"cancel_time"`  // 取消时间
+	ConfirmTime *time.Time `gorm:"type:datetime;comment:确认时间" json:"confirm_time"` // 确认时间
+	CancelTime  *time.Time `gorm:"type:datetime;comment:取消时间" json:"cancel_time"`  // 取消时间
 	CancelReason string    `gorm:"type:text;comment:取消原因" json:"cancel_reason"`   // 取消原因
 }
 
@@ -0,0 +1,152 @@
+// Package errors 定义带错误码的业务错误
+// 服务层返回这里的错误，错误处理中间件根据错误码统一设置HTTP状态码和响应中的业务code，
+// 其他错误一律按服务器内部错误处理，不向客户端暴露错误详情
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Code 业务错误码，前三位为对应的HTTP状态码
+type Code int
+
+const (
+	CodeValidation   Code = 40000 // 请求参数校验失败
+	CodeUnauthorized Code = 40100 // 未登录或登录已失效
+	CodeForbidden    Code = 40300 // 无权操作
+	CodeNotFound     Code = 40400 // 资源不存在
+	CodeConflict     Code = 40900 // 资源已存在或当前状态不允许该操作
+	CodeRateLimited  Code = 42900 // 请求过于频繁
+	CodeInternal     Code = 50000 // 服务器内部错误
+	CodeTimeout      Code = 50400 // 请求处理超时
+)
+
+// HTTPStatus 返回错误码对应的HTTP状态码
+func (c Code) HTTPStatus() int {
+	return int(c) / 100
+}
+
+// CodeFromStatus 返回HTTP状态码对应的业务错误码，没有对应错误码的状态码原样返回
+func CodeFromStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeValidation
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusInternalServerError:
+		return CodeInternal
+	case http.StatusGatewayTimeout:
+		return CodeTimeout
+	}
+	return Code(status)
+}
+
+// FieldError 字段级的校验错误
+type FieldError struct {
+	Field   string `json:"field"`   // 字段名，与请求中的JSON字段名一致
+	Message string `json:"message"` // 错误说明
+}
+
+// Error 业务错误，Message会返回给客户端，cause只用于日志
+type Error struct {
+	Code    Code
+	Message string
+	Fields  []FieldError // 参数校验失败的字段，仅CodeValidation使用
+	cause   error
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+// Unwrap 返回底层错误，使errors.Is可以匹配被包装的错误
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// New 创建指定错误码的业务错误
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Newf 创建指定错误码的业务错误，message按format格式化
+func Newf(code Code, format string, args ...interface{}) *Error {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+// Wrap 包装底层错误，返回给客户端的是message，err保留用于日志和errors.Is判断
+func Wrap(err error, code Code, message string) *Error {
+	return &Error{Code: code, Message: message, cause: err}
+}
+
+// NotFound 资源不存在
+func NotFound(message string) *Error {
+	return New(CodeNotFound, message)
+}
+
+// Conflict 资源已存在或当前状态不允许该操作
+func Conflict(message string) *Error {
+	return New(CodeConflict, message)
+}
+
+// Forbidden 无权操作
+func Forbidden(message string) *Error {
+	return New(CodeForbidden, message)
+}
+
+// Unauthorized 未登录或认证失败
+func Unauthorized(message string) *Error {
+	return New(CodeUnauthorized, message)
+}
+
+// Validation 请求参数校验失败，fields为具体出错的字段
+func Validation(message string, fields ...FieldError) *Error {
+	return &Error{Code: CodeValidation, Message: message, Fields: fields}
+}
+
+// RateLimited 请求过于频繁
+func RateLimited(message string) *Error {
+	return New(CodeRateLimited, message)
+}
+
+// Internal 服务器内部错误，err只记录到日志，不返回给客户端
+func Internal(err error) *Error {
+	return Wrap(err, CodeInternal, "服务器内部错误")
+}
+
+// From 返回err链中的业务错误，不存在时按服务器内部错误包装
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return Internal(err)
+}
+
+// CodeOf 返回err的业务错误码，不是业务错误时返回CodeInternal
+func CodeOf(err error) Code {
+	return From(err).Code
+}
+
+// Is 同标准库errors.Is，便于引入本包后无需再引入标准库errors
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As 同标准库errors.As
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
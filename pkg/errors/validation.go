@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FromBinding 将请求参数绑定或校验失败的错误转换为带字段详情的参数校验错误
+// 字段名取自validator的字段名，注册了按json标签取名的函数时与请求中的字段名一致
+func FromBinding(err error) *Error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, FieldError{Field: fe.Field(), Message: fieldMessage(fe)})
+		}
+		return Validation("请求参数校验失败", fields...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return Validation("请求参数校验失败", FieldError{Field: typeErr.Field, Message: "类型应为" + typeErr.Type.String()})
+	}
+
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return Validation("请求参数格式错误：" + numErr.Num)
+	}
+	return Wrap(err, CodeValidation, "无效的请求参数")
+}
+
+// fieldMessage 返回校验规则对应的错误说明
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "不能为空"
+	case "email":
+		return "邮箱格式不正确"
+	case "len":
+		return "长度应为" + fe.Param()
+	case "min", "gte":
+		return "不能小于" + fe.Param()
+	case "max", "lte":
+		return "不能大于" + fe.Param()
+	case "gt":
+		return "应大于" + fe.Param()
+	case "lt":
+		return "应小于" + fe.Param()
+	case "oneof":
+		return "应为以下值之一：" + strings.ReplaceAll(fe.Param(), " ", "、")
+	}
+	return "不符合校验规则：" + fe.Tag()
+}
@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// amapGeocodeURL 高德地图地理编码接口地址
+const amapGeocodeURL = "https://restapi.amap.com/v3/geocode/geo"
+
+// AmapGeocoder 高德地图地理编码
+type AmapGeocoder struct {
+	key    string
+	client *http.Client
+}
+
+// amapGeocodeResponse 高德地图地理编码接口的响应
+type amapGeocodeResponse struct {
+	Status   string `json:"status"`
+	Info     string `json:"info"`
+	Geocodes []struct {
+		Location string `json:"location"` // 格式为“经度,纬度”
+	} `json:"geocodes"`
+}
+
+// NewAmapGeocoder 创建高德地图地理编码实例
+func NewAmapGeocoder(key string, timeout time.Duration) (*AmapGeocoder, error) {
+	if key == "" {
+		return nil, errors.New("高德地图Key未配置")
+	}
+	return &AmapGeocoder{
+		key:    key,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Geocode 调用高德地图地理编码接口解析地址，返回第一个匹配结果的坐标
+func (g *AmapGeocoder) Geocode(address string) (Point, error) {
+	query := url.Values{}
+	query.Set("key", g.key)
+	query.Set("address", address)
+
+	resp, err := g.client.Get(amapGeocodeURL + "?" + query.Encode())
+	if err != nil {
+		return Point{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Point{}, fmt.Errorf("高德地图地理编码请求失败，状态码：%d", resp.StatusCode)
+	}
+
+	var result amapGeocodeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Point{}, err
+	}
+	if result.Status != "1" {
+		return Point{}, fmt.Errorf("高德地图地理编码失败：%s", result.Info)
+	}
+	if len(result.Geocodes) == 0 {
+		return Point{}, ErrAddressNotFound
+	}
+	return parseAmapLocation(result.Geocodes[0].Location)
+}
+
+// GetName 获取地理编码服务提供商名称
+func (g *AmapGeocoder) GetName() string {
+	return "amap"
+}
+
+// parseAmapLocation 解析“经度,纬度”格式的坐标
+func parseAmapLocation(location string) (Point, error) {
+	parts := strings.Split(location, ",")
+	if len(parts) != 2 {
+		return Point{}, ErrAddressNotFound
+	}
+	lng, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return Point{}, ErrAddressNotFound
+	}
+	lat, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return Point{}, ErrAddressNotFound
+	}
+	point := Point{Latitude: lat, Longitude: lng}
+	if !point.Valid() {
+		return Point{}, ErrAddressNotFound
+	}
+	return point, nil
+}
@@ -0,0 +1,34 @@
+package geo
+
+import "math"
+
+// earthRadiusKm 地球平均半径（千米）
+const earthRadiusKm = 6371.0
+
+// Point 经纬度坐标
+type Point struct {
+	Latitude  float64 // 纬度
+	Longitude float64 // 经度
+}
+
+// Valid 坐标是否有效，未填写坐标的房源经纬度均为0
+func (p Point) Valid() bool {
+	if p.Latitude == 0 && p.Longitude == 0 {
+		return false
+	}
+	return p.Latitude >= -90 && p.Latitude <= 90 && p.Longitude >= -180 && p.Longitude <= 180
+}
+
+// Distance 使用Haversine公式计算两点间的球面距离（千米）
+func Distance(a, b Point) float64 {
+	lat1, lat2 := toRadians(a.Latitude), toRadians(b.Latitude)
+	dLat := lat2 - lat1
+	dLng := toRadians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
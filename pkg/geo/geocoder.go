@@ -0,0 +1,40 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"myApp/config"
+	"myApp/pkg/region"
+	"time"
+)
+
+// ErrAddressNotFound 地址无法解析为坐标时返回的错误
+var ErrAddressNotFound = errors.New("无法识别地址对应的位置")
+
+// Geocoder 定义地理编码服务提供商的通用接口
+type Geocoder interface {
+	// Geocode 将地址文本解析为经纬度坐标，无法解析时返回ErrAddressNotFound
+	Geocode(address string) (Point, error)
+
+	// GetName 获取地理编码服务提供商名称
+	GetName() string
+}
+
+// CreateGeocoder 根据配置创建地理编码服务提供商，离线实现会在创建时加载行政区划数据集
+// 创建开销较大，应用启动时创建一次并注入到需要的服务中
+func CreateGeocoder() (Geocoder, error) {
+	geoConfig := config.Conf.Geo
+
+	switch geoConfig.Provider {
+	case "local":
+		regions, err := region.Load(config.Conf.Region.DatasetFile)
+		if err != nil {
+			return nil, err
+		}
+		return NewLocalGeocoder(regions), nil
+	case "amap":
+		return NewAmapGeocoder(geoConfig.Amap.Key, time.Duration(geoConfig.Timeout)*time.Millisecond)
+	default:
+		return nil, fmt.Errorf("不支持的地理编码服务提供商类型：%s", geoConfig.Provider)
+	}
+}
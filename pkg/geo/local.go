@@ -0,0 +1,30 @@
+package geo
+
+import "myApp/pkg/region"
+
+// LocalGeocoder 离线地理编码，按地址中识别出的最细一级行政区划的中心点返回坐标
+// 精度只到区县，适用于开发、测试环境和在线服务不可用时的兜底
+type LocalGeocoder struct {
+	index *region.Index
+}
+
+// NewLocalGeocoder 根据行政区划数据集创建离线地理编码实例
+func NewLocalGeocoder(regions []region.Region) *LocalGeocoder {
+	return &LocalGeocoder{index: region.NewIndex(regions)}
+}
+
+// Geocode 返回地址所在区划的中心点，街道没有中心点时使用所在区县的中心点
+func (g *LocalGeocoder) Geocode(address string) (Point, error) {
+	path := g.index.ParseAddress(address)
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].HasLocation() {
+			return Point{Latitude: path[i].Latitude, Longitude: path[i].Longitude}, nil
+		}
+	}
+	return Point{}, ErrAddressNotFound
+}
+
+// GetName 获取地理编码服务提供商名称
+func (g *LocalGeocoder) GetName() string {
+	return "local"
+}
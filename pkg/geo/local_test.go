@@ -0,0 +1,39 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+
+	"myApp/pkg/region"
+)
+
+func TestLocalGeocoder(t *testing.T) {
+	g := NewLocalGeocoder([]region.Region{
+		{Code: "110000", Name: "北京市", Level: region.LevelProvince, Latitude: 39.9042, Longitude: 116.4074, Radius: 90},
+		{Code: "110100", ParentCode: "110000", Name: "北京市", Level: region.LevelCity, Latitude: 39.9042, Longitude: 116.4074, Radius: 90},
+		{Code: "110105", ParentCode: "110100", Name: "朝阳区", Level: region.LevelDistrict, Latitude: 39.9219, Longitude: 116.4436, Radius: 16},
+		{Code: "110105025", ParentCode: "110105", Name: "望京街道", Level: region.LevelCommunity},
+		{Code: "310000", Name: "上海市", Level: region.LevelProvince},
+	})
+
+	tests := []struct {
+		address string
+		want    Point
+		err     error
+	}{
+		{"北京市朝阳区建国路1号", Point{Latitude: 39.9219, Longitude: 116.4436}, nil},
+		{"北京市朝阳区望京街道阜通东大街6号", Point{Latitude: 39.9219, Longitude: 116.4436}, nil}, // 街道没有中心点时使用区县的中心点
+		{"北京市某某路", Point{Latitude: 39.9042, Longitude: 116.4074}, nil},
+		{"上海市黄浦区", Point{}, ErrAddressNotFound}, // 识别出的区划都没有中心点
+		{"火星基地1号", Point{}, ErrAddressNotFound},
+	}
+	for _, tt := range tests {
+		got, err := g.Geocode(tt.address)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("Geocode(%q) = %+v, %v，期望 %+v, %v", tt.address, got, err, tt.want, tt.err)
+		}
+	}
+	if g.GetName() != "local" {
+		t.Errorf("GetName() = %q，期望 local", g.GetName())
+	}
+}
@@ -0,0 +1,68 @@
+package idcard
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 身份证号校验相关常量
+var (
+	// weights 前17位数字对应的加权因子（GB 11643-1999）
+	weights = [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	// checkCodes 加权和对11取模后对应的校验码
+	checkCodes = "10X98765432"
+)
+
+// Normalize 规范化身份证号，去除首尾空白并将末位x转为大写
+func Normalize(id string) string {
+	return strings.ToUpper(strings.TrimSpace(id))
+}
+
+// Validate 校验18位居民身份证号
+// 依次检查长度、字符、省级地区码、出生日期以及末位校验码
+func Validate(id string) error {
+	id = Normalize(id)
+	if len(id) != 18 {
+		return errors.New("身份证号长度必须为18位")
+	}
+
+	// 前17位必须为数字，末位为数字或X
+	for i := 0; i < 17; i++ {
+		if id[i] < '0' || id[i] > '9' {
+			return errors.New("身份证号格式错误")
+		}
+	}
+	last := id[17]
+	if (last < '0' || last > '9') && last != 'X' {
+		return errors.New("身份证号格式错误")
+	}
+
+	// 省级地区码范围为11-82
+	province, _ := strconv.Atoi(id[:2])
+	if province < 11 || province > 82 {
+		return errors.New("身份证号地区码无效")
+	}
+
+	// 出生日期必须是合法且不晚于当前的日期
+	birthday, err := time.ParseInLocation("20060102", id[6:14], time.Local)
+	if err != nil || birthday.Year() < 1900 || birthday.After(time.Now()) {
+		return errors.New("身份证号出生日期无效")
+	}
+
+	if CheckCode(id[:17]) != last {
+		return errors.New("身份证号校验码错误")
+	}
+
+	return nil
+}
+
+// CheckCode 根据身份证号前17位数字计算末位校验码
+func CheckCode(body string) byte {
+	sum := 0
+	for i := 0; i < 17; i++ {
+		sum += int(body[i]-'0') * weights[i]
+	}
+	return checkCodes[sum%11]
+}
@@ -0,0 +1,45 @@
+package idcard
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string // 期望的错误信息，为空表示校验通过
+	}{
+		{"11010519491231002X", ""},
+		{" 11010519491231002x ", ""}, // 首尾空白和小写x会被规范化
+		{"440304200002291236", ""},   // 闰年2月29日
+		{"1101051949123100", "身份证号长度必须为18位"},
+		{"11010519491231002Y", "身份证号格式错误"},
+		{"1101051949123100AX", "身份证号格式错误"},
+		{"990105194912310023", "身份证号地区码无效"},
+		{"100105194912310029", "身份证号地区码无效"},
+		{"440304199902291236", "身份证号出生日期无效"}, // 平年没有2月29日
+		{"110105189912310023", "身份证号出生日期无效"},
+		{"110105299912310021", "身份证号出生日期无效"}, // 晚于当前日期
+		{"110105194912310021", "身份证号校验码错误"},
+		{"110105194912310020", "身份证号校验码错误"},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.id)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("Validate(%q) = %q，期望 %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestCheckCode(t *testing.T) {
+	for body, want := range map[string]byte{
+		"11010519491231002": 'X',
+		"44030420000229123": '6',
+	} {
+		if got := CheckCode(body); got != want {
+			t.Errorf("CheckCode(%q) = %q，期望 %q", body, got, want)
+		}
+	}
+}
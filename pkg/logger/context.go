@@ -1,6 +1,8 @@
 package logger
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -16,6 +18,23 @@ const (
 	LoggerKey ContextKey = "logger"
 )
 
+// ctxLoggerKey 日志实例在context.Context中的键
+type ctxLoggerKey struct{}
+
+// NewContext 返回携带日志实例的context
+func NewContext(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, ctxLoggerKey{}, logger)
+}
+
+// FromContext 从context中获取日志实例，请求处理过程中返回带有请求ID等信息的日志实例，不存在时返回全局日志实例
+// 服务层、数据存取层等拿不到gin.Context的代码通过该函数记录带请求信息的日志
+func FromContext(ctx context.Context) *zap.Logger {
+	if logger, ok := ctx.Value(ctxLoggerKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return Logger
+}
+
 // GetRequestID 从上下文中获取请求ID
 func GetRequestID(c *gin.Context) string {
 	if requestID, exists := c.Get(string(RequestIDKey)); exists {
@@ -32,9 +51,10 @@ func GetContextLogger(c *gin.Context) *zap.Logger {
 	return Logger
 }
 
-// SetContextLogger 设置上下文日志实例
+// SetContextLogger 设置上下文日志实例，同时写入请求的context，下层可通过FromContext获取
 func SetContextLogger(c *gin.Context, logger *zap.Logger) {
 	c.Set(string(LoggerKey), logger)
+	c.Request = c.Request.WithContext(NewContext(c.Request.Context(), logger))
 }
 
 // WithContext 创建带有上下文信息的日志实例
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"myApp/pkg/redis/cache"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	prometheus.MustRegister(cacheCollector{})
+}
+
+var (
+	cacheRequestsDesc = prometheus.NewDesc(namespace+"_cache_requests_total",
+		"缓存读取次数，result为hit、local_hit、stale或miss", []string{"namespace", "result"}, nil)
+	cacheErrorsDesc = prometheus.NewDesc(namespace+"_cache_errors_total",
+		"缓存读写出错次数", []string{"namespace"}, nil)
+	cacheHitRatioDesc = prometheus.NewDesc(namespace+"_cache_hit_ratio",
+		"缓存命中率，返回旧值计为命中", []string{"namespace"}, nil)
+)
+
+// cacheCollector 在采集时读取各缓存命名空间的命中统计
+type cacheCollector struct{}
+
+// Describe 实现prometheus.Collector
+func (cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheRequestsDesc
+	ch <- cacheErrorsDesc
+	ch <- cacheHitRatioDesc
+}
+
+// Collect 实现prometheus.Collector
+func (cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	for ns, s := range cache.GetStats() {
+		ch <- prometheus.MustNewConstMetric(cacheRequestsDesc, prometheus.CounterValue, float64(s.Hits), ns, "hit")
+		ch <- prometheus.MustNewConstMetric(cacheRequestsDesc, prometheus.CounterValue, float64(s.LocalHits), ns, "local_hit")
+		ch <- prometheus.MustNewConstMetric(cacheRequestsDesc, prometheus.CounterValue, float64(s.Stale), ns, "stale")
+		ch <- prometheus.MustNewConstMetric(cacheRequestsDesc, prometheus.CounterValue, float64(s.Misses), ns, "miss")
+		ch <- prometheus.MustNewConstMetric(cacheErrorsDesc, prometheus.CounterValue, float64(s.Errors), ns)
+		ch <- prometheus.MustNewConstMetric(cacheHitRatioDesc, prometheus.GaugeValue, s.HitRatio, ns)
+	}
+}
@@ -0,0 +1,45 @@
+// Package metrics 定义Prometheus监控指标
+// 包括按路由统计的HTTP请求耗时、数据库查询耗时、各命名空间的缓存命中情况和短信发送结果，
+// 指标注册到默认注册表，通过Handler以Prometheus文本格式导出
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace 指标名称的前缀
+const namespace = "myapp"
+
+var (
+	// HTTPRequestDuration HTTP请求处理耗时，按请求方法、路由和响应状态码统计
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP请求处理耗时（秒）",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// DBQueryDuration 数据库操作耗时，按操作类型和表名统计
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "数据库操作耗时（秒）",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	// SMSSendTotal 短信发送次数，按短信服务提供商和发送结果统计
+	SMSSendTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sms_send_total",
+		Help:      "短信发送次数",
+	}, []string{"provider", "result"})
+)
+
+// Handler 返回导出全部指标的HTTP处理器
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
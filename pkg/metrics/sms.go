@@ -0,0 +1,27 @@
+package metrics
+
+import "myApp/pkg/sms"
+
+// InstrumentSMS 包装短信服务提供商，统计每次发送的结果，provider为nil时返回nil
+func InstrumentSMS(provider sms.SMSProvider) sms.SMSProvider {
+	if provider == nil {
+		return nil
+	}
+	return &smsProvider{SMSProvider: provider}
+}
+
+// smsProvider 统计发送结果的短信服务提供商
+type smsProvider struct {
+	sms.SMSProvider
+}
+
+// SendSMS 发送短信，发送失败或服务商返回失败时计为failure
+func (p *smsProvider) SendSMS(phoneNumbers []string, signName, templateCode, templateParam string) (bool, string, string, error) {
+	success, bizID, requestID, err := p.SMSProvider.SendSMS(phoneNumbers, signName, templateCode, templateParam)
+	result := "success"
+	if err != nil || !success {
+		result = "failure"
+	}
+	SMSSendTotal.WithLabelValues(p.GetName(), result).Inc()
+	return success, bizID, requestID, err
+}
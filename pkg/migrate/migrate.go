@@ -0,0 +1,336 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// lockName 迁移使用的MySQL咨询锁名称，保证同一时间只有一个迁移在执行
+	lockName = "myapp_schema_migrations"
+	// lockTimeout 等待咨询锁的最长时间（秒）
+	lockTimeout = 10
+)
+
+// ErrLocked 其他进程正在执行迁移时返回的错误
+var ErrLocked = errors.New("其他进程正在执行数据库迁移，请稍后重试")
+
+// fileNamePattern 迁移脚本文件名格式：版本号_名称.up.sql 或 版本号_名称.down.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration 一个版本的迁移脚本
+type Migration struct {
+	Version int64  // 版本号
+	Name    string // 名称
+	Up      string // 升级脚本
+	Down    string // 回滚脚本
+}
+
+// Status 迁移的执行状态
+type Status struct {
+	Migration
+	AppliedAt *time.Time // 执行时间，未执行时为nil
+}
+
+// schemaMigration 已执行的迁移记录
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `gorm:"type:datetime;not null"`
+}
+
+// TableName 迁移记录表名
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Load 从目录中读取迁移脚本，按版本号升序返回
+// 每个版本必须同时提供升级和回滚脚本，版本号不能重复
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := migrations[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			migrations[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("迁移版本%d重复：%s、%s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	list := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+			return nil, fmt.Errorf("迁移版本%d缺少升级或回滚脚本", m.Version)
+		}
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// Migrator 版本迁移执行器
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator 创建版本迁移执行器，migrations需按版本号升序排列
+func NewMigrator(db *gorm.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Latest 最新的版本号，没有迁移时为0
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status 查询全部迁移的执行状态
+func (m *Migrator) Status() ([]Status, error) {
+	var statuses []Status
+	err := m.db.Connection(func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		statuses = make([]Status, len(m.migrations))
+		for i, migration := range m.migrations {
+			statuses[i] = Status{Migration: migration}
+			if record, ok := applied[migration.Version]; ok {
+				appliedAt := record.AppliedAt
+				statuses[i].AppliedAt = &appliedAt
+			}
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Up 执行全部未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up() ([]Migration, error) {
+	return m.To(m.Latest())
+}
+
+// Down 回滚最近执行的一个迁移，没有已执行的迁移时返回nil
+func (m *Migrator) Down() (*Migration, error) {
+	var rolledBack *Migration
+	err := m.withLock(func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[m.migrations[i].Version]; ok {
+				rolledBack = &m.migrations[i]
+				return m.run(conn, *rolledBack, false)
+			}
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// To 升级或回滚到指定版本：执行不超过该版本的未执行迁移，并按版本倒序回滚高于该版本的已执行迁移
+// version为0时回滚全部迁移，返回本次执行或回滚的迁移
+func (m *Migrator) To(version int64) ([]Migration, error) {
+	if version != 0 && m.find(version) == nil {
+		return nil, fmt.Errorf("迁移版本%d不存在", version)
+	}
+
+	var done []Migration
+	err := m.withLock(func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok || migration.Version <= version {
+				continue
+			}
+			if err := m.run(conn, migration, false); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok || migration.Version > version {
+				continue
+			}
+			if err := m.run(conn, migration, true); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Baseline 将不超过指定版本的迁移标记为已执行而不实际执行，用于接管已有的数据库
+// 已有迁移记录时不做任何修改，返回是否进行了标记
+func (m *Migrator) Baseline(version int64) (bool, error) {
+	if m.find(version) == nil {
+		return false, fmt.Errorf("迁移版本%d不存在", version)
+	}
+
+	marked := false
+	err := m.withLock(func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil || len(applied) > 0 {
+			return err
+		}
+		// 在持有锁的连接上开启事务写入，全部标记成功或全部不标记
+		err = conn.Transaction(func(tx *gorm.DB) error {
+			for _, migration := range m.migrations {
+				if migration.Version > version {
+					break
+				}
+				if err := tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		marked = err == nil
+		return err
+	})
+	return marked, err
+}
+
+// run 在事务中执行一个迁移的升级或回滚脚本并更新迁移记录
+// MySQL的DDL语句会隐式提交事务，脚本中途失败时需要人工检查已执行的部分
+func (m *Migrator) run(conn *gorm.DB, migration Migration, up bool) error {
+	script := migration.Down
+	if up {
+		script = migration.Up
+	}
+
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range splitStatements(script) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		if up {
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		}
+		return tx.Delete(&schemaMigration{}, migration.Version).Error
+	})
+	if err != nil {
+		action := "回滚"
+		if up {
+			action = "执行"
+		}
+		return fmt.Errorf("%s迁移%d_%s失败: %w", action, migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// applied 查询已执行的迁移，迁移记录表不存在时自动创建
+func (m *Migrator) applied(conn *gorm.DB) (map[int64]schemaMigration, error) {
+	if err := conn.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, err
+	}
+	var records []schemaMigration
+	if err := conn.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// WithLock 在持有迁移锁的数据库连接上执行fn，用于导入数据等需要与版本迁移互斥的维护任务
+// fn中的数据库操作需使用传入的conn，conn为新的会话，可以像db一样链式构造多个查询
+func (m *Migrator) WithLock(fn func(conn *gorm.DB) error) error {
+	return m.withLock(func(conn *gorm.DB) error {
+		return fn(conn.Session(&gorm.Session{NewDB: true}))
+	})
+}
+
+// withLock 在持有咨询锁的同一个数据库连接上执行fn，其他进程持有锁时等待至超时
+// 非MySQL数据库（如测试使用的SQLite）只有单个进程访问，不加锁
+func (m *Migrator) withLock(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		if conn.Dialector.Name() != "mysql" {
+			return fn(conn)
+		}
+
+		var locked *int
+		if err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, lockTimeout).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if locked == nil || *locked != 1 {
+			return ErrLocked
+		}
+		defer conn.Exec("SELECT RELEASE_LOCK(?)", lockName)
+
+		return fn(conn)
+	})
+}
+
+// find 根据版本号查找迁移
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// splitStatements 将脚本拆分为单条语句，语句以行尾的分号结束，忽略空行和以--开头的注释行
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
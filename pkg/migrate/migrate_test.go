@@ -0,0 +1,218 @@
+package migrate
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"myApp/config"
+	"myApp/model"
+
+	"gorm.io/gorm"
+)
+
+// testFS 三个版本的迁移脚本，每个版本创建一张表
+var testFS = fstest.MapFS{
+	"0001_a.up.sql":   {Data: []byte("CREATE TABLE a (id integer);")},
+	"0001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+	"0002_b.up.sql":   {Data: []byte("-- 两条语句\nCREATE TABLE b (id integer);\nINSERT INTO b (id) VALUES (1);")},
+	"0002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+	"0010_c.up.sql":   {Data: []byte("CREATE TABLE c (id integer);")},
+	"0010_c.down.sql": {Data: []byte("DROP TABLE c;")},
+	"README.md":       {Data: []byte("不是迁移脚本")},
+}
+
+// newTestMigrator 创建使用内存SQLite数据库的迁移执行器
+func newTestMigrator(t *testing.T) (*gorm.DB, *Migrator) {
+	t.Helper()
+	db, err := model.OpenDB(config.DatabaseConfig{Driver: "sqlite", DBName: ":memory:"})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	list, err := Load(testFS)
+	if err != nil {
+		t.Fatalf("读取迁移脚本失败: %v", err)
+	}
+	return db, NewMigrator(db, list)
+}
+
+// versions 返回迁移的版本号
+func versions(list []Migration) []int64 {
+	var result []int64
+	for _, m := range list {
+		result = append(result, m.Version)
+	}
+	return result
+}
+
+// tables 返回测试表是否存在，顺序为a、b、c
+func tables(db *gorm.DB) []bool {
+	return []bool{db.Migrator().HasTable("a"), db.Migrator().HasTable("b"), db.Migrator().HasTable("c")}
+}
+
+func TestLoad(t *testing.T) {
+	list, err := Load(testFS)
+	if err != nil {
+		t.Fatalf("读取迁移脚本失败: %v", err)
+	}
+	if got := versions(list); !slices.Equal(got, []int64{1, 2, 10}) {
+		t.Fatalf("版本号为%v，期望按数值升序排列", got)
+	}
+	if list[1].Name != "b" || !strings.Contains(list[1].Up, "INSERT INTO b") || list[1].Down != "DROP TABLE b;" {
+		t.Fatalf("版本2的迁移为%+v", list[1])
+	}
+
+	// 缺少回滚脚本或同一版本号有不同名称时返回错误
+	for name, fsys := range map[string]fstest.MapFS{
+		"缺少回滚脚本": {"0001_a.up.sql": {Data: []byte("SELECT 1;")}},
+		"回滚脚本为空": {"0001_a.up.sql": {Data: []byte("SELECT 1;")}, "0001_a.down.sql": {Data: []byte("\n")}},
+		"版本号重复": {
+			"0001_a.up.sql": {Data: []byte("SELECT 1;")}, "0001_a.down.sql": {Data: []byte("SELECT 1;")},
+			"0001_b.up.sql": {Data: []byte("SELECT 1;")}, "0001_b.down.sql": {Data: []byte("SELECT 1;")},
+		},
+	} {
+		if _, err := Load(fsys); err == nil {
+			t.Errorf("%s时应返回错误", name)
+		}
+	}
+}
+
+func TestUpDownAndTo(t *testing.T) {
+	db, m := newTestMigrator(t)
+	if m.Latest() != 10 {
+		t.Fatalf("最新版本为%d，期望10", m.Latest())
+	}
+
+	// 升级到指定版本只执行不超过该版本的迁移
+	done, err := m.To(2)
+	if err != nil || !slices.Equal(versions(done), []int64{1, 2}) {
+		t.Fatalf("To(2) = %v, %v，期望执行1、2", versions(done), err)
+	}
+	if got := tables(db); !slices.Equal(got, []bool{true, true, false}) {
+		t.Fatalf("To(2)后表的存在情况为%v", got)
+	}
+	var count int64
+	if err := db.Table("b").Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("版本2的第二条语句应执行，b表有%d行, %v", count, err)
+	}
+
+	// Status反映执行情况，Up执行剩余的迁移
+	statuses, err := m.Status()
+	if err != nil {
+		t.Fatalf("查询迁移状态失败: %v", err)
+	}
+	if statuses[0].AppliedAt == nil || statuses[1].AppliedAt == nil || statuses[2].AppliedAt != nil {
+		t.Fatalf("迁移状态不正确: %+v", statuses)
+	}
+	if done, err := m.Up(); err != nil || !slices.Equal(versions(done), []int64{10}) {
+		t.Fatalf("Up() = %v, %v，期望执行10", versions(done), err)
+	}
+
+	// Down回滚最近执行的一个迁移
+	rolledBack, err := m.Down()
+	if err != nil || rolledBack == nil || rolledBack.Version != 10 {
+		t.Fatalf("Down() = %v, %v，期望回滚10", rolledBack, err)
+	}
+	if got := tables(db); !slices.Equal(got, []bool{true, true, false}) {
+		t.Fatalf("Down()后表的存在情况为%v", got)
+	}
+
+	// 回滚到指定版本时按版本倒序回滚，To(0)回滚全部
+	if done, err := m.To(0); err != nil || !slices.Equal(versions(done), []int64{2, 1}) {
+		t.Fatalf("To(0) = %v, %v，期望依次回滚2、1", versions(done), err)
+	}
+	if got := tables(db); !slices.Equal(got, []bool{false, false, false}) {
+		t.Fatalf("To(0)后表的存在情况为%v", got)
+	}
+	if rolledBack, err := m.Down(); err != nil || rolledBack != nil {
+		t.Fatalf("没有已执行的迁移时Down() = %v, %v，期望nil", rolledBack, err)
+	}
+	if _, err := m.To(3); err == nil {
+		t.Fatal("迁移到不存在的版本应返回错误")
+	}
+}
+
+func TestRunFailureRollsBack(t *testing.T) {
+	db, err := model.OpenDB(config.DatabaseConfig{Driver: "sqlite", DBName: ":memory:"})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	m := NewMigrator(db, []Migration{
+		{Version: 1, Name: "a", Up: "CREATE TABLE a (id integer);", Down: "DROP TABLE a;"},
+		{Version: 2, Name: "broken", Up: "CREATE TABLE b (id integer);\nINSERT INTO missing VALUES (1);", Down: "DROP TABLE b;"},
+	})
+
+	// 失败的迁移不记录为已执行，SQLite中已执行的语句随事务回滚，之前的迁移保持已执行
+	done, err := m.Up()
+	if err == nil || !strings.Contains(err.Error(), "执行迁移2_broken失败") {
+		t.Fatalf("执行失败的迁移应返回错误，实际为%v", err)
+	}
+	if !slices.Equal(versions(done), []int64{1}) {
+		t.Fatalf("已执行的迁移为%v，期望1", versions(done))
+	}
+	if !db.Migrator().HasTable("a") || db.Migrator().HasTable("b") {
+		t.Fatal("失败的迁移中已执行的语句应回滚")
+	}
+	statuses, err := m.Status()
+	if err != nil || statuses[1].AppliedAt != nil {
+		t.Fatalf("失败的迁移不应记录为已执行: %+v, %v", statuses, err)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	db, m := newTestMigrator(t)
+
+	// 只标记不执行，之后的迁移正常执行
+	marked, err := m.Baseline(2)
+	if err != nil || !marked {
+		t.Fatalf("Baseline(2) = %v, %v，期望标记成功", marked, err)
+	}
+	if done, err := m.Up(); err != nil || !slices.Equal(versions(done), []int64{10}) {
+		t.Fatalf("标记后Up() = %v, %v，期望只执行10", versions(done), err)
+	}
+	if got := tables(db); !slices.Equal(got, []bool{false, false, true}) {
+		t.Fatalf("标记的迁移不应执行，表的存在情况为%v", got)
+	}
+
+	// 已有迁移记录时不做修改
+	if marked, err := m.Baseline(10); err != nil || marked {
+		t.Fatalf("已有迁移记录时Baseline() = %v, %v，期望不标记", marked, err)
+	}
+	if _, err := m.Baseline(3); err == nil {
+		t.Fatal("标记不存在的版本应返回错误")
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- 注释行
+
+CREATE TABLE a (
+    id integer, -- 行尾注释保留在语句中
+    name text
+);
+  -- 缩进的注释行
+INSERT INTO a VALUES (1, 'x;y');
+UPDATE a SET name = 'z'`
+	want := []string{
+		"CREATE TABLE a (\n    id integer, -- 行尾注释保留在语句中\n    name text\n)",
+		"INSERT INTO a VALUES (1, 'x;y')",
+		"UPDATE a SET name = 'z'",
+	}
+	if got := splitStatements(script); !slices.Equal(got, want) {
+		t.Fatalf("splitStatements() = %q，期望 %q", got, want)
+	}
+	if got := splitStatements("\n-- 只有注释\n"); len(got) != 0 {
+		t.Fatalf("只有注释时应返回空列表，实际为%q", got)
+	}
+}
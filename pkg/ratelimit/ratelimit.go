@@ -0,0 +1,87 @@
+// Package ratelimit 基于Redis的GCRA（通用信元速率算法）限流器
+// 每个限流键只保存一个理论到达时间，多个实例共用同一Redis时限流额度在实例间共享
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"myApp/pkg/redis"
+)
+
+// keyPrefix 限流键在Redis中的前缀
+const keyPrefix = "ratelimit:"
+
+// Limit 限流规则：每Period最多Rate个请求，允许瞬时突发Burst个请求
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// Result 一次限流检查的结果
+type Result struct {
+	Allowed    bool          // 是否允许本次请求
+	Limit      int           // 突发上限，即额度全部恢复时可连续发送的请求数
+	Remaining  int           // 剩余可连续发送的请求数
+	RetryAfter time.Duration // 被拒绝时需要等待的时间
+	ResetAfter time.Duration // 额度全部恢复需要的时间
+}
+
+// gcraScript 按GCRA检查并记录一次请求，时间单位为毫秒
+// 当前时间取Redis服务器时间，避免各实例时钟不一致时互相放大或缩小额度
+// 返回是否允许、剩余请求数、需要等待的时间和额度全部恢复需要的时间
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+local emission = tonumber(ARGV[1])
+local tolerance = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+local newTat = tat + emission
+local allowAt = newTat - tolerance
+if now < allowAt then
+	return {0, 0, allowAt - now, tat - now}
+end
+redis.call('SET', KEYS[1], newTat, 'PX', math.ceil(newTat - now))
+return {1, math.floor((now - allowAt) / emission), 0, newTat - now}
+`)
+
+// Allow 检查key是否还能发送一个请求，允许时记录本次请求
+func Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	if limit.Rate <= 0 || limit.Period <= 0 {
+		return nil, fmt.Errorf("无效的限流规则: %+v", limit)
+	}
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	emission := float64(limit.Period.Milliseconds()) / float64(limit.Rate)
+	value, err := redis.RunScript(ctx, gcraScript, []string{keyPrefix + key}, emission, emission*float64(burst))
+	if err != nil {
+		return nil, err
+	}
+	values, ok := value.([]interface{})
+	if !ok || len(values) != 4 {
+		return nil, fmt.Errorf("限流脚本返回值格式错误: %v", value)
+	}
+
+	nums := make([]int64, len(values))
+	for i, v := range values {
+		if nums[i], ok = v.(int64); !ok {
+			return nil, fmt.Errorf("限流脚本返回值格式错误: %v", value)
+		}
+	}
+	return &Result{
+		Allowed:    nums[0] == 1,
+		Limit:      burst,
+		Remaining:  int(nums[1]),
+		RetryAfter: time.Duration(nums[2]) * time.Millisecond,
+		ResetAfter: time.Duration(nums[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,301 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"myApp/pkg/redis"
+)
+
+// 标签集合键前缀，集合中保存注册到该标签下的缓存键
+const tagKeyPrefix = "cache:tag:"
+
+// 失效中的标签集合键前缀，失效时先将标签集合改名，失效期间新注册的缓存键写入新的标签集合
+const invalidatingKeyPrefix = "cache:invalidating:"
+
+// 失效中的标签集合的最长保留时间，进程在失效过程中退出时由Redis清理
+const invalidatingKeyTTL = time.Hour
+
+// 每次写入时抽查的标签集合成员数，已过期的成员会被移除
+const pruneSamples = 3
+
+// 批量删除键时每批的数量
+const deleteBatchSize = 500
+
+// Cache 按命名空间划分的缓存，缓存键为“命名空间:键”
+// 写入时可以为缓存键指定标签，失效时按标签删除相关的缓存键
+type Cache struct {
+	namespace string
+	stats     *namespaceStats
+}
+
+// New 创建指定命名空间的缓存，同一命名空间共享命中统计
+func New(namespace string) *Cache {
+	return &Cache{namespace: namespace, stats: getStats(namespace)}
+}
+
+// Key 返回缓存键在Redis中的完整键名
+func (c *Cache) Key(key string) string {
+	return c.namespace + ":" + key
+}
+
+// Get 读取缓存，缓存不存在时返回redis.Nil，并记录命中和未命中次数
+func (c *Cache) Get(ctx context.Context, key string) (string, error) {
+	value, err := redis.Get(ctx, c.Key(key))
+	switch {
+	case err == nil:
+		c.stats.hits.Add(1)
+	case err == redis.Nil:
+		c.stats.misses.Add(1)
+	default:
+		c.stats.errors.Add(1)
+	}
+	return value, err
+}
+
+// Set 写入缓存，并将缓存键注册到指定标签下
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error {
+	return c.setRaw(ctx, c.Key(key), value, expiration, tags...)
+}
+
+// setScript 写入缓存键并注册到各标签集合
+// 标签集合的过期时间只在新成员比集合活得更久时延长，使集合与其中最晚过期的缓存键同时过期；
+// 存在永不过期的成员时集合也不过期
+// KEYS[1]为缓存键，KEYS[2...]为标签集合；ARGV[1]为缓存值，ARGV[2]为有效期（毫秒，0表示不过期）
+var setScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+for i = 2, #KEYS do
+	local tag = KEYS[i]
+	local existed = redis.call('EXISTS', tag) == 1
+	redis.call('SADD', tag, KEYS[1])
+	local current = redis.call('PTTL', tag)
+	if ttl <= 0 then
+		if current >= 0 then
+			redis.call('PERSIST', tag)
+		end
+	elseif not existed or (current >= 0 and current < ttl) then
+		redis.call('PEXPIRE', tag, ttl)
+	end
+end
+return 1
+`)
+
+// pruneScript 从标签集合中移除已过期的缓存键，检查和移除在同一脚本中执行，不会误删期间重新写入的键
+// KEYS[1]为标签集合，KEYS[2...]为待检查的成员
+var pruneScript = redis.NewScript(`
+for i = 2, #KEYS do
+	if redis.call('EXISTS', KEYS[i]) == 0 then
+		redis.call('SREM', KEYS[1], KEYS[i])
+	end
+end
+return 1
+`)
+
+// renameTagScript 标签集合存在时改名为失效中的键，并保证该键最终会过期
+// KEYS[1]为标签集合，KEYS[2]为失效中的键；ARGV[1]为失效中的键的最长保留时间（毫秒）
+var renameTagScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('RENAME', KEYS[1], KEYS[2])
+local current = redis.call('PTTL', KEYS[2])
+if current < 0 or current > tonumber(ARGV[1]) then
+	redis.call('PEXPIRE', KEYS[2], ARGV[1])
+end
+return 1
+`)
+
+// setRaw 按完整键名写入缓存，并将缓存键注册到指定标签下
+func (c *Cache) setRaw(ctx context.Context, fullKey string, value interface{}, expiration time.Duration, tags ...string) error {
+	keys := make([]string, 0, len(tags)+1)
+	keys = append(keys, fullKey)
+	for _, tag := range tags {
+		keys = append(keys, tagKey(tag))
+	}
+
+	_, err := redis.RunScript(ctx, setScript, keys, value, expiration.Milliseconds())
+	if err == nil {
+		err = pruneTags(ctx, keys[1:])
+	}
+	if err != nil {
+		c.stats.errors.Add(1)
+	}
+	return err
+}
+
+// pruneTags 随机抽查各标签集合的少量成员并移除已过期的键，避免集合持续写入时无限增长
+func pruneTags(ctx context.Context, tagKeys []string) error {
+	client := redis.GetRedisClient()
+	for _, key := range tagKeys {
+		members, err := client.SRandMemberN(ctx, key, pruneSamples).Result()
+		if err != nil {
+			return err
+		}
+		if len(members) == 0 {
+			continue
+		}
+		if _, err := redis.RunScript(ctx, pruneScript, append([]string{key}, members...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete 删除缓存
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	fullKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		fullKeys = append(fullKeys, c.Key(key))
+		deleteLocal(c.Key(key))
+	}
+	return redis.GetRedisClient().Del(ctx, fullKeys...).Err()
+}
+
+// InvalidateTags 删除注册在指定标签下的全部缓存键及标签集合本身
+// 先将标签集合改名再用SSCAN遍历，避免大集合阻塞Redis；遍历期间新注册的缓存键写入新的标签集合，不会丢失标签而无法失效。
+// 同时清除本进程的一级缓存，其他进程的一级缓存在LocalTTL后过期
+func InvalidateTags(ctx context.Context, tags ...string) error {
+	client := redis.GetRedisClient()
+	for _, tag := range tags {
+		key, err := detachTag(ctx, tag)
+		if err != nil {
+			return err
+		}
+		if key == "" {
+			continue
+		}
+		var cursor uint64
+		for {
+			members, next, err := client.SScan(ctx, key, cursor, "", deleteBatchSize).Result()
+			if err != nil {
+				return err
+			}
+			for _, member := range members {
+				deleteLocal(member)
+			}
+			if len(members) > 0 {
+				if err := client.Del(ctx, members...).Err(); err != nil {
+					return err
+				}
+			}
+			cursor = next
+			if cursor == 0 {
+				break
+			}
+		}
+		if err := client.Del(ctx, key).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detachTag 将标签集合改名为唯一的失效中的键并返回新键名，标签集合不存在时返回空字符串
+func detachTag(ctx context.Context, tag string) (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	key := invalidatingKeyPrefix + tag + ":" + hex.EncodeToString(buf)
+	renamed, err := redis.RunScript(ctx, renameTagScript, []string{tagKey(tag), key}, invalidatingKeyTTL.Milliseconds())
+	if err != nil {
+		return "", err
+	}
+	if n, _ := renamed.(int64); n == 0 {
+		return "", nil
+	}
+	return key, nil
+}
+
+// InvalidateTagPattern 失效名称匹配指定模式的全部标签，如"house:*"
+func InvalidateTagPattern(ctx context.Context, pattern string) error {
+	client := redis.GetRedisClient()
+	var tags []string
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, tagKey(pattern), deleteBatchSize).Result()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			tags = append(tags, strings.TrimPrefix(key, tagKeyPrefix))
+		}
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	return InvalidateTags(ctx, tags...)
+}
+
+// Tag 按格式生成标签名，如Tag("house:%d", 42)得到"house:42"
+func Tag(format string, args ...interface{}) string {
+	return fmt.Sprintf(format, args...)
+}
+
+// tagKey 返回标签集合在Redis中的键名
+func tagKey(tag string) string {
+	return tagKeyPrefix + tag
+}
+
+// namespaceStats 命名空间的缓存命中统计
+type namespaceStats struct {
+	hits      atomic.Int64
+	localHits atomic.Int64
+	stale     atomic.Int64
+	misses    atomic.Int64
+	errors    atomic.Int64
+}
+
+// Stats 缓存命中统计快照
+type Stats struct {
+	Hits      int64   `json:"hits"`       // 命中次数
+	LocalHits int64   `json:"local_hits"` // 一级缓存命中次数
+	Stale     int64   `json:"stale"`      // 返回旧值的次数
+	Misses    int64   `json:"misses"`     // 未命中次数
+	Errors    int64   `json:"errors"`     // 读写出错次数
+	HitRatio  float64 `json:"hit_ratio"`  // 命中率
+}
+
+var statsRegistry sync.Map
+
+// getStats 获取命名空间的统计对象，不存在时创建
+func getStats(namespace string) *namespaceStats {
+	stats, _ := statsRegistry.LoadOrStore(namespace, &namespaceStats{})
+	return stats.(*namespaceStats)
+}
+
+// GetStats 获取各命名空间的缓存命中统计
+func GetStats() map[string]Stats {
+	result := make(map[string]Stats)
+	statsRegistry.Range(func(key, value interface{}) bool {
+		s := value.(*namespaceStats)
+		snapshot := Stats{
+			Hits:      s.hits.Load(),
+			LocalHits: s.localHits.Load(),
+			Stale:     s.stale.Load(),
+			Misses:    s.misses.Load(),
+			Errors:    s.errors.Load(),
+		}
+		if total := snapshot.Hits + snapshot.Stale + snapshot.Misses; total > 0 {
+			snapshot.HitRatio = float64(snapshot.Hits+snapshot.Stale) / float64(total)
+		}
+		result[key.(string)] = snapshot
+		return true
+	})
+	return result
+}
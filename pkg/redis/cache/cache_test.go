@@ -0,0 +1,222 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"myApp/config"
+	"myApp/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newTestRedis 启动miniredis并设置为缓存使用的Redis客户端
+func newTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	port, _ := strconv.Atoi(mr.Port())
+	client, err := redis.NewClient(config.RedisConfig{Host: mr.Host(), Port: port})
+	if err != nil {
+		t.Fatalf("连接miniredis失败: %v", err)
+	}
+	redis.SetClient(client)
+	t.Cleanup(func() { client.Close() })
+	return mr
+}
+
+func TestSetGetAndInvalidateTags(t *testing.T) {
+	mr := newTestRedis(t)
+	ctx := t.Context()
+	c := New("test")
+
+	if err := c.Set(ctx, "a", "1", time.Minute, "t1"); err != nil {
+		t.Fatalf("Set失败: %v", err)
+	}
+	if err := c.Set(ctx, "b", "2", 10*time.Minute, "t1", "t2"); err != nil {
+		t.Fatalf("Set失败: %v", err)
+	}
+	if err := c.Set(ctx, "c", "3", 10*time.Minute, "t2"); err != nil {
+		t.Fatalf("Set失败: %v", err)
+	}
+	if v, err := c.Get(ctx, "a"); err != nil || v != "1" {
+		t.Fatalf("Get = %q, %v，期望 1", v, err)
+	}
+	if _, err := c.Get(ctx, "missing"); err != redis.Nil {
+		t.Fatalf("读取不存在的键应返回redis.Nil，实际为%v", err)
+	}
+
+	// 标签集合与其中最晚过期的键同时过期，写入更早过期的键不会延长集合的过期时间
+	if ttl := mr.TTL(tagKey("t1")); ttl != 10*time.Minute {
+		t.Fatalf("标签集合的过期时间为%v，期望10m", ttl)
+	}
+	if err := c.Set(ctx, "a", "1", time.Minute, "t1"); err != nil {
+		t.Fatalf("Set失败: %v", err)
+	}
+	mr.FastForward(time.Minute)
+	if ttl := mr.TTL(tagKey("t1")); ttl != 9*time.Minute {
+		t.Fatalf("写入后标签集合的过期时间为%v，期望9m", ttl)
+	}
+
+	// 按标签失效只删除注册在该标签下的键
+	if err := c.Set(ctx, "a", "1", time.Minute, "t1"); err != nil {
+		t.Fatalf("Set失败: %v", err)
+	}
+	if err := InvalidateTags(ctx, "t1"); err != nil {
+		t.Fatalf("InvalidateTags失败: %v", err)
+	}
+	if mr.Exists(c.Key("a")) || mr.Exists(c.Key("b")) || mr.Exists(tagKey("t1")) {
+		t.Fatal("标签下的缓存键和标签集合应被删除")
+	}
+	if !mr.Exists(c.Key("c")) {
+		t.Fatal("其他标签下的缓存键不应被删除")
+	}
+}
+
+func TestInvalidateTagsDetachesTagSet(t *testing.T) {
+	mr := newTestRedis(t)
+	ctx := t.Context()
+	c := New("test")
+
+	if err := c.Set(ctx, "old", "1", 0, "t"); err != nil {
+		t.Fatalf("Set失败: %v", err)
+	}
+	detached, err := detachTag(ctx, "t")
+	if err != nil || detached == "" {
+		t.Fatalf("detachTag = %q, %v，期望返回失效中的键", detached, err)
+	}
+	if ttl := mr.TTL(detached); ttl != invalidatingKeyTTL {
+		t.Fatalf("失效中的键的过期时间为%v，期望%v", ttl, invalidatingKeyTTL)
+	}
+
+	// 失效期间写入的缓存键注册到新的标签集合，仍能被下一次失效删除
+	if err := c.Set(ctx, "new", "2", time.Minute, "t"); err != nil {
+		t.Fatalf("Set失败: %v", err)
+	}
+	if members, _ := mr.Members(tagKey("t")); len(members) != 1 || members[0] != c.Key("new") {
+		t.Fatalf("标签集合成员为%v，期望只有新写入的键", members)
+	}
+	if err := InvalidateTags(ctx, "t"); err != nil {
+		t.Fatalf("InvalidateTags失败: %v", err)
+	}
+	if mr.Exists(c.Key("new")) || mr.Exists(tagKey("t")) {
+		t.Fatal("失效期间写入的缓存键应被删除")
+	}
+
+	// 标签集合不存在时不做任何操作
+	if detached, err := detachTag(ctx, "missing"); err != nil || detached != "" {
+		t.Fatalf("detachTag = %q, %v，标签集合不存在时期望返回空字符串", detached, err)
+	}
+}
+
+func TestInvalidateTagPattern(t *testing.T) {
+	mr := newTestRedis(t)
+	ctx := t.Context()
+	c := New("test")
+
+	for key, tag := range map[string]string{"a": "item:1", "b": "item:2", "c": "other:1"} {
+		if err := c.Set(ctx, key, key, time.Minute, tag); err != nil {
+			t.Fatalf("Set失败: %v", err)
+		}
+	}
+	if err := InvalidateTagPattern(ctx, "item:*"); err != nil {
+		t.Fatalf("InvalidateTagPattern失败: %v", err)
+	}
+	if mr.Exists(c.Key("a")) || mr.Exists(c.Key("b")) || mr.Exists(tagKey("item:1")) || mr.Exists(tagKey("item:2")) {
+		t.Fatal("匹配的标签下的缓存键和标签集合应被删除")
+	}
+	if !mr.Exists(c.Key("c")) || !mr.Exists(tagKey("other:1")) {
+		t.Fatal("不匹配的标签不应被失效")
+	}
+}
+
+func TestSetPrunesExpiredTagMembers(t *testing.T) {
+	mr := newTestRedis(t)
+	ctx := t.Context()
+	c := New("test")
+
+	if err := c.Set(ctx, "short", "1", time.Minute, "t"); err != nil {
+		t.Fatalf("Set失败: %v", err)
+	}
+	if err := c.Set(ctx, "long", "2", time.Hour, "t"); err != nil {
+		t.Fatalf("Set失败: %v", err)
+	}
+	mr.FastForward(2 * time.Minute)
+
+	// 写入时移除集合中已过期的键
+	if err := c.Set(ctx, "new", "3", time.Minute, "t"); err != nil {
+		t.Fatalf("Set失败: %v", err)
+	}
+	members, err := mr.Members(tagKey("t"))
+	if err != nil {
+		t.Fatalf("读取标签集合失败: %v", err)
+	}
+	if len(members) != 2 || members[0] != c.Key("long") || members[1] != c.Key("new") {
+		t.Fatalf("标签集合成员为%v，已过期的键应被移除", members)
+	}
+}
+
+func TestGetOrLoadStaleRefresh(t *testing.T) {
+	newTestRedis(t)
+	ctx := t.Context()
+	c := New("test_stale")
+	opts := Options[int]{TTL: 50 * time.Millisecond, StaleTTL: time.Hour}
+
+	var loads atomic.Int32
+	load := func(ctx context.Context) (int, error) {
+		return int(loads.Add(1)), nil
+	}
+
+	if v, err := GetOrLoad(ctx, c, "k", opts, load); err != nil || v != 1 {
+		t.Fatalf("首次读取 = %d, %v，期望 1", v, err)
+	}
+	if v, _ := GetOrLoad(ctx, c, "k", opts, load); v != 1 || loads.Load() != 1 {
+		t.Fatalf("有效期内应直接返回缓存，实际为%d，加载%d次", v, loads.Load())
+	}
+
+	// 过期后仍在旧值窗口内时返回旧值，并在后台刷新
+	time.Sleep(60 * time.Millisecond)
+	if v, err := GetOrLoad(ctx, c, "k", opts, load); err != nil || v != 1 {
+		t.Fatalf("过期后应返回旧值，实际为%d, %v", v, err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for loads.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if loads.Load() != 2 {
+		t.Fatalf("后台刷新应重新加载一次，实际加载%d次", loads.Load())
+	}
+	var v int
+	for v != 2 && time.Now().Before(deadline) {
+		v, _ = GetOrLoad(ctx, c, "k", opts, load)
+		time.Sleep(5 * time.Millisecond)
+	}
+	if v != 2 {
+		t.Fatalf("后台刷新后应返回新值，实际为%d", v)
+	}
+}
+
+func TestGetOrLoadNotFound(t *testing.T) {
+	newTestRedis(t)
+	ctx := t.Context()
+	c := New("test_not_found")
+	opts := Options[string]{TTL: time.Minute, NegativeTTL: time.Minute}
+
+	var loads atomic.Int32
+	load := func(ctx context.Context) (string, error) {
+		loads.Add(1)
+		return "", ErrNotFound
+	}
+
+	// 空结果会被缓存，有效期内不再加载
+	for i := 0; i < 2; i++ {
+		if _, err := GetOrLoad(ctx, c, "k", opts, load); err != ErrNotFound {
+			t.Fatalf("应返回ErrNotFound，实际为%v", err)
+		}
+	}
+	if loads.Load() != 1 {
+		t.Fatalf("空结果缓存有效期内应只加载一次，实际加载%d次", loads.Load())
+	}
+}
@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"myApp/pkg/logger"
+	"myApp/pkg/redis"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotFound 数据不存在，加载函数返回该错误时会缓存空结果
+var ErrNotFound = errors.New("cache: not found")
+
+// 进程内一级缓存的最大条目数，超过后不再写入新条目
+const maxLocalEntries = 10000
+
+// Options 缓存读取选项
+type Options[T any] struct {
+	TTL         time.Duration          // 缓存有效期
+	NegativeTTL time.Duration          // 空结果缓存有效期，为0时不缓存空结果
+	StaleTTL    time.Duration          // 过期后仍返回旧值并在后台刷新的时间窗口，为0时不启用
+	Jitter      float64                // 有效期随机抖动比例，如0.1表示上下浮动10%，避免大量缓存同时过期
+	LocalTTL    time.Duration          // 进程内一级缓存有效期，为0时不启用
+	Tags        func(value T) []string // 根据加载的数据生成缓存标签
+}
+
+// entry 缓存条目，同时保存在Redis和进程内一级缓存中
+type entry struct {
+	Value      json.RawMessage `json:"v,omitempty"` // 数据
+	NotFound   bool            `json:"n,omitempty"` // 是否为空结果
+	FreshUntil int64           `json:"f"`           // 数据有效截止时间（Unix毫秒），之后为旧值
+}
+
+// fresh 条目是否仍在有效期内
+func (e *entry) fresh() bool {
+	return time.Now().UnixMilli() < e.FreshUntil
+}
+
+// localEntry 进程内一级缓存条目
+type localEntry struct {
+	entry     *entry
+	expiresAt time.Time
+}
+
+var (
+	group      singleflight.Group
+	localStore sync.Map
+	localCount atomic.Int64
+)
+
+// GetOrLoad 读取缓存，未命中时调用load加载数据并写入缓存
+// 依次读取进程内一级缓存和Redis；同一键的并发加载只会执行一次；
+// 数据过期但仍在StaleTTL窗口内时直接返回旧值，并在后台刷新。
+// 合并执行的加载和后台刷新不随ctx取消，避免一个请求断开导致其他等待的请求失败
+func GetOrLoad[T any](ctx context.Context, c *Cache, key string, opts Options[T], load func(ctx context.Context) (T, error)) (T, error) {
+	fullKey := c.Key(key)
+	loadCtx := context.WithoutCancel(ctx)
+
+	loadEntry := func() (interface{}, error) {
+		value, err := load(loadCtx)
+		if errors.Is(err, ErrNotFound) {
+			if opts.NegativeTTL <= 0 {
+				return nil, ErrNotFound
+			}
+			e := &entry{NotFound: true, FreshUntil: time.Now().Add(opts.NegativeTTL).UnixMilli()}
+			c.store(loadCtx, fullKey, e, opts.NegativeTTL, opts.LocalTTL, nil)
+			return e, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		ttl := jitter(opts.TTL, opts.Jitter)
+		e := &entry{Value: data, FreshUntil: time.Now().Add(ttl).UnixMilli()}
+		var tags []string
+		if opts.Tags != nil {
+			tags = opts.Tags(value)
+		}
+		c.store(loadCtx, fullKey, e, ttl+opts.StaleTTL, opts.LocalTTL, tags)
+		return e, nil
+	}
+
+	// 先读一级缓存，再读Redis
+	if e := c.lookup(ctx, fullKey, opts.LocalTTL); e != nil {
+		if e.fresh() {
+			c.stats.hits.Add(1)
+			return decode[T](e)
+		}
+		if opts.StaleTTL > 0 {
+			// 返回旧值，后台刷新
+			c.stats.stale.Add(1)
+			go func() {
+				if _, err, _ := group.Do(fullKey, loadEntry); err != nil && !errors.Is(err, ErrNotFound) {
+					logger.FromContext(ctx).With(zap.Error(err)).Warn(fmt.Sprintf("后台刷新缓存%s失败", fullKey))
+				}
+			}()
+			return decode[T](e)
+		}
+	}
+
+	c.stats.misses.Add(1)
+	result, err, _ := group.Do(fullKey, loadEntry)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return decode[T](result.(*entry))
+}
+
+// lookup 依次从一级缓存和Redis读取缓存条目，都不存在时返回nil
+func (c *Cache) lookup(ctx context.Context, fullKey string, localTTL time.Duration) *entry {
+	if localTTL > 0 {
+		if v, ok := localStore.Load(fullKey); ok {
+			le := v.(*localEntry)
+			if time.Now().Before(le.expiresAt) {
+				c.stats.localHits.Add(1)
+				return le.entry
+			}
+			deleteLocal(fullKey)
+		}
+	}
+
+	data, err := redis.Get(ctx, fullKey)
+	if err != nil {
+		if err != redis.Nil {
+			c.stats.errors.Add(1)
+		}
+		return nil
+	}
+
+	var e entry
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		return nil
+	}
+	if localTTL > 0 {
+		storeLocal(fullKey, &e, localTTL)
+	}
+	return &e
+}
+
+// store 将缓存条目写入Redis和一级缓存
+func (c *Cache) store(ctx context.Context, fullKey string, e *entry, redisTTL, localTTL time.Duration, tags []string) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := c.setRaw(ctx, fullKey, string(data), redisTTL, tags...); err != nil {
+		logger.FromContext(ctx).With(zap.Error(err)).Warn(fmt.Sprintf("写入缓存%s失败", fullKey))
+	}
+	if localTTL > 0 {
+		storeLocal(fullKey, e, localTTL)
+	}
+}
+
+// decode 将缓存条目解析为目标类型，空结果返回ErrNotFound
+func decode[T any](e *entry) (T, error) {
+	var value T
+	if e.NotFound {
+		return value, ErrNotFound
+	}
+	if err := json.Unmarshal(e.Value, &value); err != nil {
+		return value, err
+	}
+	return value, nil
+}
+
+// jitter 为有效期增加随机抖动
+func jitter(ttl time.Duration, ratio float64) time.Duration {
+	if ratio <= 0 || ttl <= 0 {
+		return ttl
+	}
+	delta := float64(ttl) * ratio * (rand.Float64()*2 - 1)
+	return ttl + time.Duration(delta)
+}
+
+// storeLocal 写入一级缓存，条目数达到上限时先清理过期条目
+func storeLocal(fullKey string, e *entry, ttl time.Duration) {
+	if localCount.Load() >= maxLocalEntries {
+		now := time.Now()
+		localStore.Range(func(key, value interface{}) bool {
+			if now.After(value.(*localEntry).expiresAt) {
+				deleteLocal(key.(string))
+			}
+			return true
+		})
+		if localCount.Load() >= maxLocalEntries {
+			return
+		}
+	}
+	if _, loaded := localStore.Swap(fullKey, &localEntry{entry: e, expiresAt: time.Now().Add(ttl)}); !loaded {
+		localCount.Add(1)
+	}
+}
+
+// deleteLocal 删除一级缓存条目
+func deleteLocal(fullKey string) {
+	if _, loaded := localStore.LoadAndDelete(fullKey); loaded {
+		localCount.Add(-1)
+	}
+}
+
+// ResetLocal 清空本进程的一级缓存，用于切换到新的数据源（如测试使用新的数据库）后丢弃旧数据
+func ResetLocal() {
+	localStore.Range(func(key, value interface{}) bool {
+		deleteLocal(key.(string))
+		return true
+	})
+}
@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// unlockScript 只有锁的值仍为加锁时的令牌才删除，避免锁过期后误删其他实例持有的锁
+var unlockScript = NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// TryLock 尝试获取分布式锁，成功时返回用于释放锁的令牌，锁已被其他实例持有时返回空令牌
+func TryLock(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+	ok, err := SetNX(ctx, key, token, ttl)
+	if err != nil || !ok {
+		return "", err
+	}
+	return token, nil
+}
+
+// Unlock 释放TryLock获取的锁，锁已过期或被其他实例持有时不做任何操作
+func Unlock(ctx context.Context, key, token string) error {
+	_, err := RunScript(ctx, unlockScript, []string{key}, token)
+	return err
+}
@@ -10,25 +10,42 @@ import (
 )
 
 var redisClient *redis.Client
-var ctx = context.Background()
 
 // 定义常量，用于判断缓存是否存在
 var Nil = redis.Nil
 
+// Client Redis客户端
+type Client = redis.Client
+
+// NewClient 根据配置创建Redis客户端并测试连接
+func NewClient(cfg config.RedisConfig) (*Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: "", // 如果有密码，可以在配置中添加
+		DB:       0,  // 使用默认DB
+	})
+
+	// 测试连接
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("Redis连接失败: %v", err)
+	}
+	return client, nil
+}
+
+// SetClient 设置缓存操作使用的Redis客户端，由应用容器在启动时注入
+func SetClient(client *Client) {
+	redisClient = client
+}
+
 // InitRedis 初始化Redis客户端
 func InitRedis() *redis.Client {
 	if redisClient == nil {
-		redisClient = redis.NewClient(&redis.Options{
-			Addr:     fmt.Sprintf("%s:%d", config.Conf.Redis.Host, config.Conf.Redis.Port),
-			Password: "", // 如果有密码，可以在配置中添加
-			DB:       0,  // 使用默认DB
-		})
-
-		// 测试连接
-		_, err := redisClient.Ping(ctx).Result()
+		client, err := NewClient(config.Conf.Redis)
 		if err != nil {
-			panic(fmt.Sprintf("Redis连接失败: %v", err))
+			panic(err.Error())
 		}
+		redisClient = client
 		fmt.Println("Redis连接成功")
 	}
 	return redisClient
@@ -43,36 +60,46 @@ func GetRedisClient() *redis.Client {
 }
 
 // Set 设置缓存
-func Set(key string, value interface{}, expiration time.Duration) error {
+func Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	return GetRedisClient().Set(ctx, key, value, expiration).Err()
 }
 
 // Get 获取缓存
-func Get(key string) (string, error) {
+func Get(ctx context.Context, key string) (string, error) {
 	return GetRedisClient().Get(ctx, key).Result()
 }
 
 // Delete 删除缓存
-func Delete(key string) error {
+func Delete(ctx context.Context, key string) error {
 	return GetRedisClient().Del(ctx, key).Err()
 }
 
 // DeleteByPattern 根据模式删除缓存
-func DeleteByPattern(pattern string) error {
-	keys, err := GetRedisClient().Keys(ctx, pattern).Result()
-	if err != nil {
-		return err
-	}
+// 使用SCAN分批遍历匹配的键，避免KEYS命令阻塞Redis
+func DeleteByPattern(ctx context.Context, pattern string) error {
+	client := GetRedisClient()
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, pattern, 500).Result()
+		if err != nil {
+			return err
+		}
 
-	if len(keys) > 0 {
-		return GetRedisClient().Del(ctx, keys...).Err()
-	}
+		if len(keys) > 0 {
+			if err := client.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+		}
 
-	return nil
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+	}
 }
 
 // Exists 检查键是否存在
-func Exists(key string) (bool, error) {
+func Exists(ctx context.Context, key string) (bool, error) {
 	result, err := GetRedisClient().Exists(ctx, key).Result()
 	if err != nil {
 		return false, err
@@ -81,11 +108,77 @@ func Exists(key string) (bool, error) {
 }
 
 // Expire 设置过期时间
-func Expire(key string, expiration time.Duration) error {
+func Expire(ctx context.Context, key string, expiration time.Duration) error {
 	return GetRedisClient().Expire(ctx, key, expiration).Err()
 }
 
 // Incr 自增
-func Incr(key string) (int64, error) {
+func Incr(ctx context.Context, key string) (int64, error) {
 	return GetRedisClient().Incr(ctx, key).Result()
-}
\ No newline at end of file
+}
+
+// SetNX 键不存在时设置缓存，返回是否设置成功
+func SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return GetRedisClient().SetNX(ctx, key, value, expiration).Result()
+}
+
+// HIncrBy 哈希字段自增
+func HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	return GetRedisClient().HIncrBy(ctx, key, field, incr).Result()
+}
+
+// HDel 删除哈希的指定字段
+func HDel(ctx context.Context, key string, fields ...string) error {
+	return GetRedisClient().HDel(ctx, key, fields...).Err()
+}
+
+// HGetAll 获取哈希的全部字段
+func HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return GetRedisClient().HGetAll(ctx, key).Result()
+}
+
+// HMGet 获取哈希的多个字段，字段不存在时对应位置为nil
+func HMGet(ctx context.Context, key string, fields ...string) ([]interface{}, error) {
+	return GetRedisClient().HMGet(ctx, key, fields...).Result()
+}
+
+// Rename 重命名键
+func Rename(ctx context.Context, key, newKey string) error {
+	return GetRedisClient().Rename(ctx, key, newKey).Err()
+}
+
+// Z 有序集合成员及分数
+type Z = redis.Z
+
+// ZRevRangeWithScores 按分数从高到低获取有序集合指定区间的成员及分数
+func ZRevRangeWithScores(ctx context.Context, key string, start, stop int64) ([]Z, error) {
+	return GetRedisClient().ZRevRangeWithScores(ctx, key, start, stop).Result()
+}
+
+// Script Lua脚本，执行时优先使用EVALSHA
+type Script = redis.Script
+
+// NewScript 创建Lua脚本
+func NewScript(src string) *Script {
+	return redis.NewScript(src)
+}
+
+// RunScript 执行Lua脚本
+func RunScript(ctx context.Context, script *Script, keys []string, args ...interface{}) (interface{}, error) {
+	return script.Run(ctx, GetRedisClient(), keys, args...).Result()
+}
+
+// LPush 将元素插入列表头部
+func LPush(ctx context.Context, key string, values ...interface{}) error {
+	return GetRedisClient().LPush(ctx, key, values...).Err()
+}
+
+// LTrim 只保留列表指定区间内的元素
+func LTrim(ctx context.Context, key string, start, stop int64) error {
+	return GetRedisClient().LTrim(ctx, key, start, stop).Err()
+}
+
+// LRange 获取列表指定区间内的元素
+func LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return GetRedisClient().LRange(ctx, key, start, stop).Result()
+}
@@ -0,0 +1,277 @@
+package region
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+//go:embed regions.json
+var dataset []byte
+
+// 行政区划级别
+const (
+	LevelProvince  = 1 // 省、直辖市、自治区
+	LevelCity      = 2 // 地级市，直辖市的城市级与省级同名
+	LevelDistrict  = 3 // 区、县
+	LevelCommunity = 4 // 街道、乡镇
+)
+
+// Region 行政区划
+type Region struct {
+	Code       string  // 行政区划代码
+	ParentCode string  // 上级区划代码，省级为空
+	Name       string  // 名称
+	Level      int     // 级别
+	Latitude   float64 // 中心点纬度，未知时为0
+	Longitude  float64 // 中心点经度，未知时为0
+	Radius     float64 // 覆盖范围半径（千米），用于粗略判断坐标是否位于区划内，未知时为0
+}
+
+// HasLocation 是否有中心点坐标和覆盖范围
+func (r Region) HasLocation() bool {
+	return (r.Latitude != 0 || r.Longitude != 0) && r.Radius > 0
+}
+
+// node 数据集中的区划节点，代码可以是6位、省市的2位或4位简写，或12位统计用区划代码
+type node struct {
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lng"`
+	Radius    float64 `json:"radius"`
+	Children  []node  `json:"children"`
+}
+
+// Dataset 读取内置的行政区划数据集，上级区划排在下级区划之前
+// 内置数据集只包含部分城市，用于开发和测试，生产环境应通过Load加载完整数据
+func Dataset() ([]Region, error) {
+	return parse(dataset)
+}
+
+// Load 从文件读取行政区划数据集，path为空时使用内置数据集
+// 文件格式与内置数据集相同，为按省、市、区县、街道嵌套的JSON数组，中心点和覆盖半径可省略
+func Load(path string) ([]Region, error) {
+	if path == "" {
+		return Dataset()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	regions, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析行政区划数据集%s失败: %w", path, err)
+	}
+	return regions, nil
+}
+
+// parse 解析嵌套的区划节点，上级区划排在下级区划之前
+func parse(data []byte) ([]Region, error) {
+	var nodes []node
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, err
+	}
+
+	var regions []Region
+	var walk func(nodes []node, parentCode string, level int) error
+	walk = func(nodes []node, parentCode string, level int) error {
+		if len(nodes) > 0 && level > LevelCommunity {
+			return fmt.Errorf("区划%s的下级区划超过街道级", parentCode)
+		}
+		for _, n := range nodes {
+			code, err := normalizeCode(n.Code, level)
+			if err != nil {
+				return err
+			}
+			regions = append(regions, Region{
+				Code:       code,
+				ParentCode: parentCode,
+				Name:       n.Name,
+				Level:      level,
+				Latitude:   n.Latitude,
+				Longitude:  n.Longitude,
+				Radius:     n.Radius,
+			})
+			if err := walk(n.Children, code, level+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(nodes, "", LevelProvince); err != nil {
+		return nil, err
+	}
+	return regions, nil
+}
+
+// normalizeCode 将区划代码统一为省市区县6位、街道9位
+// 省市的简写代码在末尾补0，12位统计用区划代码截取对应级别的前缀
+func normalizeCode(code string, level int) (string, error) {
+	width := 6
+	if level == LevelCommunity {
+		width = 9
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return "", fmt.Errorf("区划代码%q格式错误", code)
+		}
+	}
+	switch {
+	case len(code) == width:
+		return code, nil
+	case len(code) == 12:
+		return code[:width], nil
+	case level == LevelProvince && len(code) == 2, level == LevelCity && len(code) == 4:
+		return code + strings.Repeat("0", width-len(code)), nil
+	}
+	return "", fmt.Errorf("区划代码%q格式错误", code)
+}
+
+// Index 行政区划索引，支持按代码查询、查询下级区划和解析地址
+type Index struct {
+	regions  map[string]Region
+	children map[string][]Region
+	levels   map[int][]Region
+}
+
+// NewIndex 根据区划列表创建索引，下级区划保持列表中的顺序
+func NewIndex(regions []Region) *Index {
+	x := &Index{
+		regions:  make(map[string]Region, len(regions)),
+		children: make(map[string][]Region),
+		levels:   make(map[int][]Region),
+	}
+	for _, r := range regions {
+		x.regions[r.Code] = r
+		x.children[r.ParentCode] = append(x.children[r.ParentCode], r)
+		x.levels[r.Level] = append(x.levels[r.Level], r)
+	}
+	return x
+}
+
+// Get 根据代码查询区划
+func (x *Index) Get(code string) (Region, bool) {
+	r, ok := x.regions[code]
+	return r, ok
+}
+
+// Children 查询下级区划，parentCode为空时返回省级区划
+func (x *Index) Children(parentCode string) []Region {
+	return x.children[parentCode]
+}
+
+// Path 返回从省级到指定区划的完整路径，代码不存在时返回nil
+func (x *Index) Path(code string) []Region {
+	var path []Region
+	for code != "" {
+		r, ok := x.regions[code]
+		if !ok {
+			return nil
+		}
+		path = append([]Region{r}, path...)
+		code = r.ParentCode
+	}
+	return path
+}
+
+// ParseAddress 尽力从地址文本中解析行政区划，返回从省级开始的路径，无法识别时返回nil
+// 优先匹配区县，同名区县按上级区划是否出现在地址中区分，仍无法区分时回退到城市和省份
+func (x *Index) ParseAddress(address string) []Region {
+	address = strings.Join(strings.Fields(address), "")
+	if address == "" {
+		return nil
+	}
+
+	for _, level := range []int{LevelDistrict, LevelCity, LevelProvince} {
+		best, ok := x.bestMatch(address, x.levels[level])
+		if !ok {
+			continue
+		}
+		path := x.Path(best.Code)
+		if level == LevelDistrict {
+			if community, ok := x.bestMatch(address, x.children[best.Code]); ok {
+				path = append(path, community)
+			}
+		}
+		return path
+	}
+	return nil
+}
+
+// bestMatch 找出地址中出现的得分最高的区划，上级区划同时出现时加分，最高分不唯一时视为未匹配
+func (x *Index) bestMatch(address string, candidates []Region) (Region, bool) {
+	var best Region
+	bestScore, tie := 0, false
+	for _, r := range candidates {
+		score := matchScore(address, r.Name)
+		if score == 0 {
+			continue
+		}
+		for parent, ok := x.regions[r.ParentCode]; ok; parent, ok = x.regions[parent.ParentCode] {
+			if matchScore(address, parent.Name) > 0 {
+				score += 2
+			}
+		}
+
+		switch {
+		case score > bestScore:
+			best, bestScore, tie = r, score, false
+		case score == bestScore:
+			tie = true
+		}
+	}
+	return best, bestScore > 0 && !tie
+}
+
+// regionSuffixes 区划名称的通名后缀，较长的后缀排在前面
+var regionSuffixes = []string{"特别行政区", "自治区", "自治州", "自治县", "街道", "新区", "省", "市", "区", "县", "镇", "乡", "盟", "旗"}
+
+// matchScore 地址中出现区划全称得2分，出现简称（去掉通名后缀）得1分
+// 简称后紧跟通名时不算匹配，避免“朝阳区”被识别为“朝阳市”
+func matchScore(address, name string) int {
+	if strings.Contains(address, name) {
+		return 2
+	}
+
+	short := shortName(name)
+	if short == "" {
+		return 0
+	}
+	for rest := address; ; {
+		i := strings.Index(rest, short)
+		if i < 0 {
+			return 0
+		}
+		rest = rest[i+len(short):]
+		if !hasRegionSuffix(rest) {
+			return 1
+		}
+	}
+}
+
+// shortName 去掉通名后缀的区划简称，简称不足两个字时返回空字符串
+func shortName(name string) string {
+	for _, suffix := range regionSuffixes {
+		if short := strings.TrimSuffix(name, suffix); short != name {
+			if utf8.RuneCountInString(short) >= 2 {
+				return short
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// hasRegionSuffix 文本是否以通名后缀开头
+func hasRegionSuffix(s string) bool {
+	for _, suffix := range regionSuffixes {
+		if strings.HasPrefix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package region
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// codes 返回区划路径中的代码
+func codes(path []Region) []string {
+	var result []string
+	for _, r := range path {
+		result = append(result, r.Code)
+	}
+	return result
+}
+
+func TestParseAddress(t *testing.T) {
+	regions, err := Dataset()
+	if err != nil {
+		t.Fatalf("读取内置数据集失败: %v", err)
+	}
+	index := NewIndex(regions)
+
+	tests := []struct {
+		address string
+		want    []string
+	}{
+		{"北京市朝阳区建国路1号", []string{"110000", "110100", "110105"}},
+		{" 北京 朝阳区 望京街道 阜通东大街6号", []string{"110000", "110100", "110105", "110105025"}}, // 空白会被忽略
+		{"上海浦东张江镇碧波路", []string{"310000", "310100", "310115", "310115125"}},           // 区县简称
+		{"杭州西湖文新街道", []string{"330000", "330100", "330106", "330106004"}},
+		{"苏州市人民路100号", []string{"320000", "320500"}}, // 识别不到区县时回退到城市
+		{"江苏省某某路", []string{"320000"}},
+		{"长春市朝阳区", []string{"220000", "220100", "220104"}}, // 同名区县按上级区划区分
+		{"朝阳区建国路1号", nil},                                  // 同名区县无法区分时不猜测，“朝阳区”也不会识别为“朝阳市”
+		{"火星基地1号", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := codes(index.ParseAddress(tt.address)); !slices.Equal(got, tt.want) {
+			t.Errorf("ParseAddress(%q) = %v，期望 %v", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestParseAddressAmbiguousDistrict(t *testing.T) {
+	index := NewIndex([]Region{
+		{Code: "320000", Name: "江苏省", Level: LevelProvince},
+		{Code: "320100", ParentCode: "320000", Name: "南京市", Level: LevelCity},
+		{Code: "320106", ParentCode: "320100", Name: "鼓楼区", Level: LevelDistrict},
+		{Code: "350000", Name: "福建省", Level: LevelProvince},
+		{Code: "350100", ParentCode: "350000", Name: "福州市", Level: LevelCity},
+		{Code: "350102", ParentCode: "350100", Name: "鼓楼区", Level: LevelDistrict},
+	})
+
+	// 同名区县按地址中出现的上级区划区分，无法区分时不猜测
+	tests := []struct {
+		address string
+		want    []string
+	}{
+		{"福州市鼓楼区五四路", []string{"350000", "350100", "350102"}},
+		{"江苏鼓楼区中山路", []string{"320000", "320100", "320106"}},
+		{"鼓楼区中山路", nil},
+	}
+	for _, tt := range tests {
+		if got := codes(index.ParseAddress(tt.address)); !slices.Equal(got, tt.want) {
+			t.Errorf("ParseAddress(%q) = %v，期望 %v", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	// 路径为空时使用内置数据集
+	embedded, err := Load("")
+	if err != nil || len(embedded) == 0 {
+		t.Fatalf("读取内置数据集 = %d条, %v", len(embedded), err)
+	}
+
+	// 省市简写代码和12位统计用区划代码统一为6位，街道为9位
+	path := filepath.Join(t.TempDir(), "regions.json")
+	data := `[{"code": "44", "name": "广东省", "children": [
+		{"code": "4403", "name": "深圳市", "children": [
+			{"code": "440305000000", "name": "南山区", "lat": 22.53, "lng": 113.93, "radius": 12, "children": [
+				{"code": "440305001000", "name": "南头街道"}
+			]}
+		]}
+	]}]`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("写入数据集失败: %v", err)
+	}
+	regions, err := Load(path)
+	if err != nil {
+		t.Fatalf("读取数据集失败: %v", err)
+	}
+	want := []Region{
+		{Code: "440000", Name: "广东省", Level: LevelProvince},
+		{Code: "440300", ParentCode: "440000", Name: "深圳市", Level: LevelCity},
+		{Code: "440305", ParentCode: "440300", Name: "南山区", Level: LevelDistrict, Latitude: 22.53, Longitude: 113.93, Radius: 12},
+		{Code: "440305001", ParentCode: "440305", Name: "南头街道", Level: LevelCommunity},
+	}
+	if !slices.Equal(regions, want) {
+		t.Fatalf("读取的区划为%+v，期望%+v", regions, want)
+	}
+
+	// 代码格式错误时返回错误
+	for _, data := range []string{
+		`[{"code": "4a", "name": "广东省"}]`,
+		`[{"code": "4403", "name": "深圳市"}]`,
+		`[{"code": "440000", "name": "广东省", "children": [{"code": "44030", "name": "深圳市"}]}]`,
+	} {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatalf("写入数据集失败: %v", err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("数据集%s应返回错误", data)
+		}
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("文件不存在时应返回错误")
+	}
+}
@@ -1,6 +1,8 @@
 package response
 
 import (
+	"fmt"
+	"myApp/pkg/errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -8,7 +10,7 @@ import (
 
 // Response 统一API响应结构
 type Response struct {
-	Code    int         `json:"code"`    // 状态码
+	Code    int         `json:"code"`    // 状态码，失败时为业务错误码
 	Message string      `json:"message"` // 消息
 	Data    interface{} `json:"data"`    // 数据
 }
@@ -22,19 +24,38 @@ func Success(c *gin.Context, data interface{}) {
 	})
 }
 
-// Fail 失败响应
+// Fail 失败响应，code为HTTP状态码，响应中的code为对应的业务错误码
 func Fail(c *gin.Context, code int, message string, data ...interface{}) {
 	var responseData interface{} = nil
 	if len(data) > 0 {
 		responseData = data[0]
 	}
 	c.JSON(code, Response{
-		Code:    code,
+		Code:    int(errors.CodeFromStatus(code)),
 		Message: message,
 		Data:    responseData,
 	})
 }
 
+// Error 记录错误并中止后续处理，由错误处理中间件根据错误码统一返回失败响应
+func Error(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// AppError 按业务错误返回失败响应，参数校验失败时data中包含出错的字段
+func AppError(c *gin.Context, err *errors.Error) {
+	var data interface{}
+	if len(err.Fields) > 0 {
+		data = gin.H{"fields": err.Fields}
+	}
+	c.JSON(err.Code.HTTPStatus(), Response{
+		Code:    int(err.Code),
+		Message: err.Message,
+		Data:    data,
+	})
+}
+
 // BadRequest 请求参数错误
 func BadRequest(c *gin.Context, message string, data ...interface{}) {
 	if message == "" {
@@ -84,3 +105,9 @@ func Forbidden(c *gin.Context, message string, data ...interface{}) {
 	}
 	Fail(c, http.StatusForbidden, message, data...)
 }
+
+// Attachment 以附件形式返回文件，供浏览器下载
+func Attachment(c *gin.Context, filename, contentType string, data []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, contentType, data)
+}
@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"time"
+
+	"myApp/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// Every 在后台按固定间隔执行任务，任务出错只记录日志，不影响后续执行
+// 返回的stop函数用于停止任务，正在执行的任务结束后才返回
+func Every(name string, interval time.Duration, task func() error) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				run(name, task)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-exited
+	}
+}
+
+// run 执行一次任务，捕获任务中的panic避免后台协程退出
+func run(name string, task func() error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("定时任务异常", zap.String("task", name), zap.Any("panic", r))
+		}
+	}()
+
+	if err := task(); err != nil {
+		logger.Error("定时任务执行失败", zap.String("task", name), zap.Error(err))
+	}
+}
@@ -1,57 +1,53 @@
 package repository
 
 import (
+	"context"
 	"myApp/model"
 	
 	"gorm.io/gorm"
 )
 
 type FavoriteRepository interface {
-	Create(favorite *model.Favorite) error
-	GetByID(id uint) (*model.Favorite, error)
-	GetAll(params map[string]interface{}) ([]model.Favorite, error)
-	Update(favorite *model.Favorite) error
-	Delete(id uint) error
-	GetFavoritesByUserID(userID uint) ([]model.Favorite, error)
-	IsFavorite(userID, houseID uint) (bool, error)
-	DeleteByUserAndHouse(userID, houseID uint) error
+	Create(ctx context.Context, favorite *model.Favorite) error
+	GetByID(ctx context.Context, id uint) (*model.Favorite, error)
+	GetAll(ctx context.Context, params map[string]interface{}) ([]model.Favorite, error)
+	Count(ctx context.Context, params map[string]interface{}) (int64, error)
+	UpdateColumns(ctx context.Context, id uint, columns map[string]interface{}) error
+	CountByFolder(ctx context.Context, userID uint) (map[uint]int64, error)
+	ResetFolder(ctx context.Context, userID, folderID uint) error
+	Update(ctx context.Context, favorite *model.Favorite) error
+	Delete(ctx context.Context, id uint) error
+	GetFavoritesByUserID(ctx context.Context, userID uint) ([]model.Favorite, error)
+	IsFavorite(ctx context.Context, userID, houseID uint) (bool, error)
+	DeleteByUserAndHouse(ctx context.Context, userID, houseID uint) error
+	GetPriceDropSubscribers(ctx context.Context, houseID uint) ([]model.Favorite, error)
 }
 
 type favoriteRepository struct{
 	db *gorm.DB
 }
 
-func NewFavoriteRepository() FavoriteRepository {
+func NewFavoriteRepository(db *gorm.DB) FavoriteRepository {
 	return &favoriteRepository{
-		db: model.GetDB(),
+		db: db,
 	}
 }
 
-func (r *favoriteRepository) Create(favorite *model.Favorite) error {
-	return r.db.Create(favorite).Error
+func (r *favoriteRepository) Create(ctx context.Context, favorite *model.Favorite) error {
+	return dbFromContext(ctx, r.db).Create(favorite).Error
 }
 
-func (r *favoriteRepository) GetByID(id uint) (*model.Favorite, error) {
+func (r *favoriteRepository) GetByID(ctx context.Context, id uint) (*model.Favorite, error) {
 	var favorite model.Favorite
-	if err := r.db.First(&favorite, id).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).First(&favorite, id).Error; err != nil {
 		return nil, err
 	}
 	return &favorite, nil
 }
 
-func (r *favoriteRepository) GetAll(params map[string]interface{}) ([]model.Favorite, error) {
+func (r *favoriteRepository) GetAll(ctx context.Context, params map[string]interface{}) ([]model.Favorite, error) {
 	var favorites []model.Favorite
-	db := r.db
-
-	// 根据参数构建查询条件
-	if params != nil {
-		if userID, ok := params["user_id"].(uint); ok {
-			db = db.Where("user_id = ?", userID)
-		}
-		if houseID, ok := params["house_id"].(uint); ok {
-			db = db.Where("house_id = ?", houseID)
-		}
-	}
+	db := applyFavoriteFilters(dbFromContext(ctx, r.db), params)
 
 	// 排序
 	if orderBy, ok := params["order_by"].(string); ok && orderBy != "" {
@@ -74,28 +70,101 @@ func (r *favoriteRepository) GetAll(params map[string]interface{}) ([]model.Favo
 	return favorites, nil
 }
 
-func (r *favoriteRepository) Update(favorite *model.Favorite) error {
-	return r.db.Save(favorite).Error
+// Count 统计符合条件的收藏数量
+func (r *favoriteRepository) Count(ctx context.Context, params map[string]interface{}) (int64, error) {
+	var count int64
+	if err := applyFavoriteFilters(dbFromContext(ctx, r.db).Model(&model.Favorite{}), params).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
 }
 
-func (r *favoriteRepository) Delete(id uint) error {
-	return r.db.Delete(&model.Favorite{}, id).Error
+// applyFavoriteFilters 根据查询参数构建收藏筛选条件，供列表查询和计数共用
+func applyFavoriteFilters(db *gorm.DB, params map[string]interface{}) *gorm.DB {
+	if params == nil {
+		return db
+	}
+	if userID, ok := params["user_id"].(uint); ok {
+		db = db.Where("user_id = ?", userID)
+	}
+	if houseID, ok := params["house_id"].(uint); ok {
+		db = db.Where("house_id = ?", houseID)
+	}
+	if folderID, ok := params["folder_id"].(uint); ok {
+		db = db.Where("folder_id = ?", folderID)
+	}
+	if keyword, ok := params["keyword"].(string); ok && keyword != "" {
+		db = db.Where("notes LIKE ?", "%"+keyword+"%")
+	}
+	return db
+}
+
+func (r *favoriteRepository) Update(ctx context.Context, favorite *model.Favorite) error {
+	return dbFromContext(ctx, r.db).Save(favorite).Error
 }
 
-func (r *favoriteRepository) GetFavoritesByUserID(userID uint) ([]model.Favorite, error) {
+// Delete 删除收藏，直接物理删除以免软删除的记录占用用户与房源的唯一索引
+func (r *favoriteRepository) Delete(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Unscoped().Delete(&model.Favorite{}, id).Error
+}
+
+func (r *favoriteRepository) GetFavoritesByUserID(ctx context.Context, userID uint) ([]model.Favorite, error) {
 	var favorites []model.Favorite
-	if err := r.db.Where("user_id = ?", userID).Find(&favorites).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Where("user_id = ?", userID).Find(&favorites).Error; err != nil {
 		return nil, err
 	}
 	return favorites, nil
 }
 
-func (r *favoriteRepository) IsFavorite(userID, houseID uint) (bool, error) {
+func (r *favoriteRepository) IsFavorite(ctx context.Context, userID, houseID uint) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.Favorite{}).Where("user_id = ? AND house_id = ?", userID, houseID).Count(&count).Error
+	err := dbFromContext(ctx, r.db).Model(&model.Favorite{}).Where("user_id = ? AND house_id = ?", userID, houseID).Count(&count).Error
 	return count > 0, err
 }
 
-func (r *favoriteRepository) DeleteByUserAndHouse(userID, houseID uint) error {
-	return r.db.Where("user_id = ? AND house_id = ?", userID, houseID).Delete(&model.Favorite{}).Error
-}
\ No newline at end of file
+func (r *favoriteRepository) DeleteByUserAndHouse(ctx context.Context, userID, houseID uint) error {
+	return dbFromContext(ctx, r.db).Unscoped().Where("user_id = ? AND house_id = ?", userID, houseID).Delete(&model.Favorite{}).Error
+}
+
+// GetPriceDropSubscribers 获取开启了降价提醒的房源收藏记录
+func (r *favoriteRepository) GetPriceDropSubscribers(ctx context.Context, houseID uint) ([]model.Favorite, error) {
+	var favorites []model.Favorite
+	if err := dbFromContext(ctx, r.db).Where("house_id = ? AND notify_price_drop = ?", houseID, true).Find(&favorites).Error; err != nil {
+		return nil, err
+	}
+	return favorites, nil
+}
+
+// UpdateColumns 更新收藏的指定字段
+func (r *favoriteRepository) UpdateColumns(ctx context.Context, id uint, columns map[string]interface{}) error {
+	return dbFromContext(ctx, r.db).Model(&model.Favorite{}).Where("id = ?", id).Updates(columns).Error
+}
+
+// CountByFolder 统计用户各收藏夹中的收藏数量，键为收藏夹ID，0表示默认收藏夹
+func (r *favoriteRepository) CountByFolder(ctx context.Context, userID uint) (map[uint]int64, error) {
+	var rows []struct {
+		FolderID uint
+		Count    int64
+	}
+	err := dbFromContext(ctx, r.db).Model(&model.Favorite{}).
+		Select("folder_id, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Group("folder_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.FolderID] = row.Count
+	}
+	return counts, nil
+}
+
+// ResetFolder 将收藏夹中的收藏移回默认收藏夹
+func (r *favoriteRepository) ResetFolder(ctx context.Context, userID, folderID uint) error {
+	return dbFromContext(ctx, r.db).Model(&model.Favorite{}).
+		Where("user_id = ? AND folder_id = ?", userID, folderID).
+		Update("folder_id", 0).Error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"myApp/model"
+
+	"gorm.io/gorm"
+)
+
+// FavoriteFolderRepository 收藏夹仓库接口
+type FavoriteFolderRepository interface {
+	Create(ctx context.Context, folder *model.FavoriteFolder) error
+	GetByID(ctx context.Context, id uint) (*model.FavoriteFolder, error)
+	GetByUserID(ctx context.Context, userID uint) ([]model.FavoriteFolder, error)
+	CountByUserID(ctx context.Context, userID uint) (int64, error)
+	ExistsByName(ctx context.Context, userID uint, name string, excludeID uint) (bool, error)
+	UpdateName(ctx context.Context, id uint, name string) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// favoriteFolderRepository 收藏夹仓库实现
+type favoriteFolderRepository struct {
+	db *gorm.DB
+}
+
+// NewFavoriteFolderRepository 创建收藏夹仓库实例
+func NewFavoriteFolderRepository(db *gorm.DB) FavoriteFolderRepository {
+	return &favoriteFolderRepository{
+		db: db,
+	}
+}
+
+// Create 创建收藏夹
+func (r *favoriteFolderRepository) Create(ctx context.Context, folder *model.FavoriteFolder) error {
+	return dbFromContext(ctx, r.db).Create(folder).Error
+}
+
+// GetByID 根据ID查询收藏夹
+func (r *favoriteFolderRepository) GetByID(ctx context.Context, id uint) (*model.FavoriteFolder, error) {
+	var folder model.FavoriteFolder
+	if err := dbFromContext(ctx, r.db).First(&folder, id).Error; err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// GetByUserID 查询用户的全部收藏夹，按创建时间排序
+func (r *favoriteFolderRepository) GetByUserID(ctx context.Context, userID uint) ([]model.FavoriteFolder, error) {
+	var folders []model.FavoriteFolder
+	if err := dbFromContext(ctx, r.db).Where("user_id = ?", userID).Order("created_at ASC, id ASC").Find(&folders).Error; err != nil {
+		return nil, err
+	}
+	return folders, nil
+}
+
+// CountByUserID 统计用户的收藏夹数量
+func (r *favoriteFolderRepository) CountByUserID(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := dbFromContext(ctx, r.db).Model(&model.FavoriteFolder{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ExistsByName 检查用户是否已有同名收藏夹，excludeID用于重命名时排除自身
+func (r *favoriteFolderRepository) ExistsByName(ctx context.Context, userID uint, name string, excludeID uint) (bool, error) {
+	var count int64
+	err := dbFromContext(ctx, r.db).Model(&model.FavoriteFolder{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userID, name, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateName 修改收藏夹名称
+func (r *favoriteFolderRepository) UpdateName(ctx context.Context, id uint, name string) error {
+	return dbFromContext(ctx, r.db).Model(&model.FavoriteFolder{}).Where("id = ?", id).Update("name", name).Error
+}
+
+// Delete 删除收藏夹
+func (r *favoriteFolderRepository) Delete(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Delete(&model.FavoriteFolder{}, id).Error
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"myApp/model"
+	"myApp/model/modeltest"
+
+	"gorm.io/gorm"
+)
+
+func TestFavoriteRepositoryUniqueAndDelete(t *testing.T) {
+	ctx := t.Context()
+	repo := NewFavoriteRepository(modeltest.NewDB(t))
+
+	favorite := &model.Favorite{UserID: 1, HouseID: 10}
+	if err := repo.Create(ctx, favorite); err != nil {
+		t.Fatalf("Create失败: %v", err)
+	}
+	if err := repo.Create(ctx, &model.Favorite{UserID: 1, HouseID: 10}); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("重复收藏应违反唯一索引并返回gorm.ErrDuplicatedKey，实际为%v", err)
+	}
+
+	ok, err := repo.IsFavorite(ctx, 1, 10)
+	if err != nil || !ok {
+		t.Fatalf("IsFavorite = %v, %v，期望 true", ok, err)
+	}
+
+	// 删除为物理删除，删除后可以重新收藏
+	if err := repo.Delete(ctx, favorite.ID); err != nil {
+		t.Fatalf("Delete失败: %v", err)
+	}
+	if err := repo.Create(ctx, &model.Favorite{UserID: 1, HouseID: 10}); err != nil {
+		t.Fatalf("删除后重新收藏失败: %v", err)
+	}
+	if err := repo.DeleteByUserAndHouse(ctx, 1, 10); err != nil {
+		t.Fatalf("DeleteByUserAndHouse失败: %v", err)
+	}
+	if ok, _ := repo.IsFavorite(ctx, 1, 10); ok {
+		t.Error("取消收藏后仍显示已收藏")
+	}
+}
+
+func TestFavoriteRepositoryFolders(t *testing.T) {
+	ctx := t.Context()
+	repo := NewFavoriteRepository(modeltest.NewDB(t))
+	favorites := []*model.Favorite{
+		{UserID: 1, HouseID: 1},
+		{UserID: 1, HouseID: 2, FolderID: 5, Notes: "离公司近"},
+		{UserID: 1, HouseID: 3, FolderID: 5},
+		{UserID: 2, HouseID: 1, FolderID: 5},
+	}
+	for _, f := range favorites {
+		if err := repo.Create(ctx, f); err != nil {
+			t.Fatalf("Create失败: %v", err)
+		}
+	}
+
+	counts, err := repo.CountByFolder(ctx, 1)
+	if err != nil {
+		t.Fatalf("CountByFolder失败: %v", err)
+	}
+	if counts[0] != 1 || counts[5] != 2 {
+		t.Errorf("CountByFolder = %v", counts)
+	}
+
+	list, err := repo.GetAll(ctx, map[string]interface{}{"user_id": uint(1), "keyword": "公司"})
+	if err != nil {
+		t.Fatalf("GetAll失败: %v", err)
+	}
+	if len(list) != 1 || list[0].HouseID != 2 {
+		t.Errorf("按备注搜索结果不正确: %+v", list)
+	}
+
+	if err := repo.ResetFolder(ctx, 1, 5); err != nil {
+		t.Fatalf("ResetFolder失败: %v", err)
+	}
+	count, err := repo.Count(ctx, map[string]interface{}{"user_id": uint(1), "folder_id": uint(0)})
+	if err != nil {
+		t.Fatalf("Count失败: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("移回默认收藏夹后数量为%d，期望 3", count)
+	}
+	// 其他用户的收藏不受影响
+	if count, _ := repo.Count(ctx, map[string]interface{}{"user_id": uint(2), "folder_id": uint(5)}); count != 1 {
+		t.Errorf("其他用户的收藏夹数量为%d，期望 1", count)
+	}
+}
+
+func TestFavoriteRepositoryPriceDropSubscribers(t *testing.T) {
+	ctx := t.Context()
+	repo := NewFavoriteRepository(modeltest.NewDB(t))
+	for _, f := range []*model.Favorite{
+		{UserID: 1, HouseID: 1, NotifyPriceDrop: true},
+		{UserID: 2, HouseID: 1},
+		{UserID: 3, HouseID: 2, NotifyPriceDrop: true},
+	} {
+		if err := repo.Create(ctx, f); err != nil {
+			t.Fatalf("Create失败: %v", err)
+		}
+	}
+
+	subscribers, err := repo.GetPriceDropSubscribers(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetPriceDropSubscribers失败: %v", err)
+	}
+	if len(subscribers) != 1 || subscribers[0].UserID != 1 {
+		t.Errorf("GetPriceDropSubscribers = %+v", subscribers)
+	}
+}
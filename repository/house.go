@@ -1,72 +1,69 @@
 package repository
 
 import (
+	"context"
+	"fmt"
 	"myApp/model"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type HouseRepository interface {
-	Create(house *model.House) error
-	GetByID(id uint) (*model.House, error)
-	GetAll(params map[string]interface{}) ([]model.House, error)
-	Update(house *model.House) error
-	Delete(id uint) error
-	GetHousesByLandlordID(landlordID uint) ([]model.House, error)
-	IncrementViewCount(id uint) error
+	Create(ctx context.Context, house *model.House) error
+	GetByID(ctx context.Context, id uint) (*model.House, error)
+	GetByIDForUpdate(ctx context.Context, id uint) (*model.House, error)
+	GetAll(ctx context.Context, params map[string]interface{}) ([]model.House, error)
+	Count(ctx context.Context, params map[string]interface{}) (int64, error)
+	Update(ctx context.Context, house *model.House) error
+	Delete(ctx context.Context, id uint) error
+	GetHousesByLandlordID(ctx context.Context, landlordID uint) ([]model.House, error)
+	IncrementViewCount(ctx context.Context, id uint, delta int64) error
+	UpdateRating(ctx context.Context, id uint, rating float64, reviewCount int64) error
+	UpdateColumns(ctx context.Context, id uint, columns map[string]interface{}) error
+	GetExpired(ctx context.Context, before time.Time) ([]model.House, error)
+	GetAvgPricePerArea(ctx context.Context, houseType int) (float64, int64, error)
+	CountByRegion(ctx context.Context, params map[string]interface{}, column string) (map[string]int64, error)
 }
 
+// houseRegionColumns 房源的行政区划字段，从省级到街道
+var houseRegionColumns = []string{"province_code", "city_code", "district_code", "community_code"}
+
 type houseRepository struct {
 	db *gorm.DB
 }
 
-func NewHouseRepository() HouseRepository {
+func NewHouseRepository(db *gorm.DB) HouseRepository {
 	return &houseRepository{
-		db: model.GetDB(),
+		db: db,
 	}
 }
 
-func (r *houseRepository) Create(house *model.House) error {
-	return r.db.Create(house).Error
+func (r *houseRepository) Create(ctx context.Context, house *model.House) error {
+	return dbFromContext(ctx, r.db).Create(house).Error
 }
 
-func (r *houseRepository) GetByID(id uint) (*model.House, error) {
+func (r *houseRepository) GetByID(ctx context.Context, id uint) (*model.House, error) {
 	var house model.House
-	if err := r.db.First(&house, id).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).First(&house, id).Error; err != nil {
 		return nil, err
 	}
 	return &house, nil
 }
 
-func (r *houseRepository) GetAll(params map[string]interface{}) ([]model.House, error) {
-	var houses []model.House
-	db := r.db
-
-	// 根据参数构建查询条件
-	if params != nil {
-		if status, ok := params["status"].(int); ok {
-			db = db.Where("status = ?", status)
-		}
-		if landlordID, ok := params["landlord_id"].(uint); ok {
-			db = db.Where("landlord_id = ?", landlordID)
-		}
-		if minPrice, ok := params["min_price"].(float64); ok {
-			db = db.Where("rent_price >= ?", minPrice)
-		}
-		if maxPrice, ok := params["max_price"].(float64); ok {
-			db = db.Where("rent_price <= ?", maxPrice)
-		}
-		if rooms, ok := params["rooms"].(int); ok {
-			db = db.Where("rooms = ?", rooms)
-		}
-		if houseType, ok := params["house_type"].(int); ok {
-			db = db.Where("house_type = ?", houseType)
-		}
-		if keyword, ok := params["keyword"].(string); ok && keyword != "" {
-			keyword = "%" + keyword + "%"
-			db = db.Where("title LIKE ? OR description LIKE ? OR address LIKE ?", keyword, keyword, keyword)
-		}
+// GetByIDForUpdate 加行锁读取房源，需在事务中调用，锁在事务结束时释放
+func (r *houseRepository) GetByIDForUpdate(ctx context.Context, id uint) (*model.House, error) {
+	var house model.House
+	if err := dbFromContext(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&house, id).Error; err != nil {
+		return nil, err
 	}
+	return &house, nil
+}
+
+func (r *houseRepository) GetAll(ctx context.Context, params map[string]interface{}) ([]model.House, error) {
+	var houses []model.House
+	db := applyHouseFilters(dbFromContext(ctx, r.db), params)
 
 	// 排序
 	if orderBy, ok := params["order_by"].(string); ok && orderBy != "" {
@@ -89,22 +86,146 @@ func (r *houseRepository) GetAll(params map[string]interface{}) ([]model.House,
 	return houses, nil
 }
 
-func (r *houseRepository) Update(house *model.House) error {
-	return r.db.Save(house).Error
+func (r *houseRepository) Count(ctx context.Context, params map[string]interface{}) (int64, error) {
+	var count int64
+	if err := applyHouseFilters(dbFromContext(ctx, r.db).Model(&model.House{}), params).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
 }
 
-func (r *houseRepository) Delete(id uint) error {
-	return r.db.Delete(&model.House{}, id).Error
+// applyHouseFilters 根据查询参数构建房源筛选条件，供列表查询和计数共用
+func applyHouseFilters(db *gorm.DB, params map[string]interface{}) *gorm.DB {
+	if params == nil {
+		return db
+	}
+	if status, ok := params["status"].(int); ok {
+		db = db.Where("status = ?", status)
+	}
+	if publishedAfter, ok := params["published_after"].(time.Time); ok {
+		db = db.Where("published_at > ?", publishedAfter)
+	}
+	if notExpired, ok := params["not_expired"].(bool); ok && notExpired {
+		db = db.Where("expire_at IS NULL OR expire_at > ?", time.Now())
+	}
+	if landlordID, ok := params["landlord_id"].(uint); ok {
+		db = db.Where("landlord_id = ?", landlordID)
+	}
+	if ids, ok := params["ids"].([]uint); ok {
+		db = db.Where("id IN ?", ids)
+	}
+	if excludeIDs, ok := params["exclude_ids"].([]uint); ok && len(excludeIDs) > 0 {
+		db = db.Where("id NOT IN ?", excludeIDs)
+	}
+	// 行政区划筛选
+	for _, column := range houseRegionColumns {
+		if code, ok := params[column].(string); ok && code != "" {
+			db = db.Where(column+" = ?", code)
+		}
+	}
+	if minPrice, ok := params["min_price"].(float64); ok {
+		db = db.Where("rent_price >= ?", minPrice)
+	}
+	if maxPrice, ok := params["max_price"].(float64); ok {
+		db = db.Where("rent_price <= ?", maxPrice)
+	}
+	if rooms, ok := params["rooms"].(int); ok {
+		db = db.Where("rooms = ?", rooms)
+	}
+	if houseType, ok := params["house_type"].(int); ok {
+		db = db.Where("house_type = ?", houseType)
+	}
+	if keyword, ok := params["keyword"].(string); ok && keyword != "" {
+		keyword = "%" + keyword + "%"
+		db = db.Where("title LIKE ? OR description LIKE ? OR address LIKE ?", keyword, keyword, keyword)
+	}
+	return db
+}
+
+func (r *houseRepository) Update(ctx context.Context, house *model.House) error {
+	return dbFromContext(ctx, r.db).Save(house).Error
+}
+
+func (r *houseRepository) Delete(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Delete(&model.House{}, id).Error
 }
 
-func (r *houseRepository) GetHousesByLandlordID(landlordID uint) ([]model.House, error) {
+func (r *houseRepository) GetHousesByLandlordID(ctx context.Context, landlordID uint) ([]model.House, error) {
 	var houses []model.House
-	if err := r.db.Where("landlord_id = ?", landlordID).Find(&houses).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Where("landlord_id = ?", landlordID).Find(&houses).Error; err != nil {
 		return nil, err
 	}
 	return houses, nil
 }
 
-func (r *houseRepository) IncrementViewCount(id uint) error {
-	return r.db.Model(&model.House{}).Where("id = ?", id).UpdateColumn("view_count", r.db.Raw("view_count + 1")).Error
+// IncrementViewCount 增加房源浏览次数
+func (r *houseRepository) IncrementViewCount(ctx context.Context, id uint, delta int64) error {
+	return dbFromContext(ctx, r.db).Model(&model.House{}).Where("id = ?", id).UpdateColumn("view_count", gorm.Expr("view_count + ?", delta)).Error
+}
+
+func (r *houseRepository) UpdateRating(ctx context.Context, id uint, rating float64, reviewCount int64) error {
+	return dbFromContext(ctx, r.db).Model(&model.House{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"rating":       rating,
+		"review_count": reviewCount,
+	}).Error
+}
+
+// UpdateColumns 更新房源的指定字段
+func (r *houseRepository) UpdateColumns(ctx context.Context, id uint, columns map[string]interface{}) error {
+	return dbFromContext(ctx, r.db).Model(&model.House{}).Where("id = ?", id).Updates(columns).Error
+}
+
+// GetExpired 获取上架到期时间早于指定时间的已发布房源
+func (r *houseRepository) GetExpired(ctx context.Context, before time.Time) ([]model.House, error) {
+	var houses []model.House
+	if err := dbFromContext(ctx, r.db).Where("status = ? AND expire_at IS NOT NULL AND expire_at < ?", model.HouseStatusPublished, before).Find(&houses).Error; err != nil {
+		return nil, err
+	}
+	return houses, nil
+}
+
+// GetAvgPricePerArea 获取同类型已发布房源的平均每平米租金及样本数量
+func (r *houseRepository) GetAvgPricePerArea(ctx context.Context, houseType int) (float64, int64, error) {
+	var result struct {
+		AvgPrice float64
+		Samples  int64
+	}
+	err := dbFromContext(ctx, r.db).Model(&model.House{}).
+		Select("COALESCE(AVG(rent_price / area), 0) AS avg_price, COUNT(*) AS samples").
+		Where("status = ? AND house_type = ? AND area > 0", model.HouseStatusPublished, houseType).
+		Scan(&result).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return result.AvgPrice, result.Samples, nil
+}
+
+// CountByRegion 按行政区划字段分组统计符合条件的房源数量，未填写该级区划的房源不计入
+func (r *houseRepository) CountByRegion(ctx context.Context, params map[string]interface{}, column string) (map[string]int64, error) {
+	valid := false
+	for _, c := range houseRegionColumns {
+		valid = valid || c == column
+	}
+	if !valid {
+		return nil, fmt.Errorf("不支持按%s统计", column)
+	}
+
+	var rows []struct {
+		Code  string
+		Count int64
+	}
+	err := applyHouseFilters(dbFromContext(ctx, r.db).Model(&model.House{}), params).
+		Select(column + " AS code, COUNT(*) AS count").
+		Where(column + " <> ''").
+		Group(column).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Code] = row.Count
+	}
+	return counts, nil
 }
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"myApp/model"
+
+	"gorm.io/gorm"
+)
+
+// HousePriceHistoryRepository 房源租金变动记录仓库接口
+type HousePriceHistoryRepository interface {
+	Create(ctx context.Context, history *model.HousePriceHistory) error
+	GetByHouseID(ctx context.Context, houseID uint) ([]model.HousePriceHistory, error)
+}
+
+// housePriceHistoryRepository 房源租金变动记录仓库实现
+type housePriceHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewHousePriceHistoryRepository 创建房源租金变动记录仓库实例
+func NewHousePriceHistoryRepository(db *gorm.DB) HousePriceHistoryRepository {
+	return &housePriceHistoryRepository{
+		db: db,
+	}
+}
+
+// Create 创建租金变动记录
+func (r *housePriceHistoryRepository) Create(ctx context.Context, history *model.HousePriceHistory) error {
+	return dbFromContext(ctx, r.db).Create(history).Error
+}
+
+// GetByHouseID 按时间先后获取房源的全部租金变动记录
+func (r *housePriceHistoryRepository) GetByHouseID(ctx context.Context, houseID uint) ([]model.HousePriceHistory, error) {
+	var histories []model.HousePriceHistory
+	if err := dbFromContext(ctx, r.db).Where("house_id = ?", houseID).Order("created_at ASC, id ASC").Find(&histories).Error; err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"myApp/model"
+
+	"gorm.io/gorm"
+)
+
+// HouseRevisionRepository 房源修改记录仓库接口
+type HouseRevisionRepository interface {
+	Create(ctx context.Context, revision *model.HouseRevision) error
+	GetByHouseID(ctx context.Context, houseID uint, params map[string]interface{}) ([]model.HouseRevision, int64, error)
+}
+
+// houseRevisionRepository 房源修改记录仓库实现
+type houseRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewHouseRevisionRepository 创建房源修改记录仓库实例
+func NewHouseRevisionRepository(db *gorm.DB) HouseRevisionRepository {
+	return &houseRevisionRepository{
+		db: db,
+	}
+}
+
+// Create 创建修改记录
+func (r *houseRevisionRepository) Create(ctx context.Context, revision *model.HouseRevision) error {
+	return dbFromContext(ctx, r.db).Create(revision).Error
+}
+
+// GetByHouseID 查询房源的修改记录，按时间倒序返回当前页数据和总记录数
+func (r *houseRevisionRepository) GetByHouseID(ctx context.Context, houseID uint, params map[string]interface{}) ([]model.HouseRevision, int64, error) {
+	var revisions []model.HouseRevision
+	db := dbFromContext(ctx, r.db).Model(&model.HouseRevision{}).Where("house_id = ?", houseID)
+
+	// 统计总数
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	db = db.Order("created_at DESC, id DESC")
+
+	// 分页
+	if limit, ok := params["limit"].(int); ok && limit > 0 {
+		db = db.Limit(limit)
+		if offset, ok := params["offset"].(int); ok && offset >= 0 {
+			db = db.Offset(offset)
+		}
+	}
+
+	if err := db.Find(&revisions).Error; err != nil {
+		return nil, 0, err
+	}
+	return revisions, total, nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"myApp/model"
+	"myApp/model/modeltest"
+)
+
+func TestHouseRepositoryFilters(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	repo := NewHouseRepository(db)
+
+	cheap := modeltest.Create(t, db, modeltest.House(), func(h *model.House) {
+		h.RentPrice = 3000
+		h.DistrictCode = "110105"
+	})
+	modeltest.Create(t, db, modeltest.House(), func(h *model.House) {
+		h.RentPrice = 8000
+		h.Rooms = 3
+		h.DistrictCode = "110108"
+	})
+	modeltest.Create(t, db, modeltest.House(), func(h *model.House) {
+		h.Title = "地铁口精装公寓"
+		h.Status = model.HouseStatusDraft
+	})
+	expired := time.Now().Add(-time.Minute)
+	modeltest.Create(t, db, modeltest.House(), func(h *model.House) {
+		h.ExpireAt = &expired
+	})
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		want   int64
+	}{
+		{"全部", nil, 4},
+		{"按状态", map[string]interface{}{"status": model.HouseStatusPublished}, 3},
+		{"未过期", map[string]interface{}{"status": model.HouseStatusPublished, "not_expired": true}, 2},
+		{"租金区间", map[string]interface{}{"min_price": 2000.0, "max_price": 4000.0}, 1},
+		{"户型", map[string]interface{}{"rooms": 3}, 1},
+		{"区县", map[string]interface{}{"district_code": "110105"}, 1},
+		{"关键词", map[string]interface{}{"keyword": "地铁"}, 1},
+		{"指定ID", map[string]interface{}{"ids": []uint{cheap.ID}}, 1},
+		{"排除ID", map[string]interface{}{"exclude_ids": []uint{cheap.ID}}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := repo.Count(ctx, tt.params)
+			if err != nil {
+				t.Fatalf("Count失败: %v", err)
+			}
+			if count != tt.want {
+				t.Errorf("Count = %d，期望 %d", count, tt.want)
+			}
+			houses, err := repo.GetAll(ctx, tt.params)
+			if err != nil {
+				t.Fatalf("GetAll失败: %v", err)
+			}
+			if int64(len(houses)) != tt.want {
+				t.Errorf("GetAll返回%d条，期望 %d", len(houses), tt.want)
+			}
+		})
+	}
+}
+
+func TestHouseRepositoryPagination(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	repo := NewHouseRepository(db)
+	for i := 1; i <= 5; i++ {
+		price := float64(i * 1000)
+		modeltest.Create(t, db, modeltest.House(), func(h *model.House) { h.RentPrice = price })
+	}
+
+	houses, err := repo.GetAll(ctx, map[string]interface{}{"order_by": "rent_price DESC", "limit": 2, "offset": 2})
+	if err != nil {
+		t.Fatalf("GetAll失败: %v", err)
+	}
+	if len(houses) != 2 || houses[0].RentPrice != 3000 || houses[1].RentPrice != 2000 {
+		t.Errorf("分页结果不正确: %+v", houses)
+	}
+}
+
+func TestHouseRepositoryCountByRegion(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	repo := NewHouseRepository(db)
+	for _, code := range []string{"110105", "110105", "110108", ""} {
+		district := code
+		modeltest.Create(t, db, modeltest.House(), func(h *model.House) {
+			h.CityCode = "110100"
+			h.DistrictCode = district
+		})
+	}
+
+	counts, err := repo.CountByRegion(ctx, map[string]interface{}{"city_code": "110100"}, "district_code")
+	if err != nil {
+		t.Fatalf("CountByRegion失败: %v", err)
+	}
+	if len(counts) != 2 || counts["110105"] != 2 || counts["110108"] != 1 {
+		t.Errorf("CountByRegion = %v", counts)
+	}
+
+	if _, err := repo.CountByRegion(ctx, nil, "title"); err == nil {
+		t.Error("按非区划字段统计应返回错误")
+	}
+}
+
+func TestHouseRepositoryUpdates(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	repo := NewHouseRepository(db)
+	house := modeltest.Create(t, db, modeltest.House(), nil)
+
+	if err := repo.IncrementViewCount(ctx, house.ID, 3); err != nil {
+		t.Fatalf("IncrementViewCount失败: %v", err)
+	}
+	if err := repo.IncrementViewCount(ctx, house.ID, 2); err != nil {
+		t.Fatalf("IncrementViewCount失败: %v", err)
+	}
+	if err := repo.UpdateColumns(ctx, house.ID, map[string]interface{}{"rent_price": 4500}); err != nil {
+		t.Fatalf("UpdateColumns失败: %v", err)
+	}
+	if err := repo.UpdateRating(ctx, house.ID, 4.5, 2); err != nil {
+		t.Fatalf("UpdateRating失败: %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, house.ID)
+	if err != nil {
+		t.Fatalf("GetByID失败: %v", err)
+	}
+	if got.ViewCount != 5 || got.RentPrice != 4500 || got.Rating != 4.5 || got.ReviewCount != 2 {
+		t.Errorf("更新后的房源不正确: view_count=%d rent_price=%v rating=%v review_count=%d",
+			got.ViewCount, got.RentPrice, got.Rating, got.ReviewCount)
+	}
+
+	if err := repo.Delete(ctx, house.ID); err != nil {
+		t.Fatalf("Delete失败: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, house.ID); err == nil {
+		t.Error("删除后仍能查询到房源")
+	}
+}
+
+func TestHouseRepositoryGetExpired(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	repo := NewHouseRepository(db)
+	past := time.Now().Add(-time.Hour)
+	expired := modeltest.Create(t, db, modeltest.House(), func(h *model.House) { h.ExpireAt = &past })
+	modeltest.Create(t, db, modeltest.House(), nil)
+	modeltest.Create(t, db, modeltest.House(), func(h *model.House) {
+		h.Status = model.HouseStatusRented
+		h.ExpireAt = &past
+	})
+
+	houses, err := repo.GetExpired(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("GetExpired失败: %v", err)
+	}
+	if len(houses) != 1 || houses[0].ID != expired.ID {
+		t.Errorf("GetExpired = %+v，期望只返回房源%d", houses, expired.ID)
+	}
+}
+
+func TestHouseRepositoryGetAvgPricePerArea(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	repo := NewHouseRepository(db)
+	modeltest.Create(t, db, modeltest.House(), func(h *model.House) { h.RentPrice, h.Area = 6000, 60 })
+	modeltest.Create(t, db, modeltest.House(), func(h *model.House) { h.RentPrice, h.Area = 6000, 100 })
+	modeltest.Create(t, db, modeltest.House(), func(h *model.House) { h.HouseType = 2 })
+
+	avg, samples, err := repo.GetAvgPricePerArea(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetAvgPricePerArea失败: %v", err)
+	}
+	if samples != 2 || avg != 80 {
+		t.Errorf("GetAvgPricePerArea = %v, %d，期望 80, 2", avg, samples)
+	}
+}
@@ -1,53 +1,71 @@
 package repository
 
 import (
+	"context"
 	"myApp/model"
 
 	"gorm.io/gorm"
 )
 
 type LandlordRepository interface {
-	Create(landlord *model.Landlord) error
-	FindByID(id uint) (*model.Landlord, error)
-	FindByUserID(userID uint) (*model.Landlord, error)
-	Update(landlord *model.Landlord) error
-	Delete(id uint) error
+	Create(ctx context.Context, landlord *model.Landlord) error
+	FindByID(ctx context.Context, id uint) (*model.Landlord, error)
+	FindByUserID(ctx context.Context, userID uint) (*model.Landlord, error)
+	FindVerifiedByIDNumber(ctx context.Context, idNumber string) (*model.Landlord, error)
+	Update(ctx context.Context, landlord *model.Landlord) error
+	Delete(ctx context.Context, id uint) error
+	UpdateRating(ctx context.Context, id uint, rating float64, reviewCount int64) error
 }
 
 type landlordRepository struct {
 	db *gorm.DB
 }
 
-func NewLandlordRepository() LandlordRepository {
+func NewLandlordRepository(db *gorm.DB) LandlordRepository {
 	return &landlordRepository{
-		db: model.GetDB(),
+		db: db,
 	}
 }
 
-func (r *landlordRepository) Create(landlord *model.Landlord) error {
-	return r.db.Create(landlord).Error
+func (r *landlordRepository) Create(ctx context.Context, landlord *model.Landlord) error {
+	return dbFromContext(ctx, r.db).Create(landlord).Error
 }
 
-func (r *landlordRepository) FindByID(id uint) (*model.Landlord, error) {
+func (r *landlordRepository) FindByID(ctx context.Context, id uint) (*model.Landlord, error) {
 	var landlord model.Landlord
-	if err := r.db.First(&landlord, id).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).First(&landlord, id).Error; err != nil {
 		return nil, err
 	}
 	return &landlord, nil
 }
 
-func (r *landlordRepository) FindByUserID(userID uint) (*model.Landlord, error) {
+func (r *landlordRepository) FindByUserID(ctx context.Context, userID uint) (*model.Landlord, error) {
 	var landlord model.Landlord
-	if err := r.db.Where("user_id = ?", userID).First(&landlord).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Where("user_id = ?", userID).First(&landlord).Error; err != nil {
 		return nil, err
 	}
 	return &landlord, nil
 }
 
-func (r *landlordRepository) Update(landlord *model.Landlord) error {
-	return r.db.Save(landlord).Error
+func (r *landlordRepository) FindVerifiedByIDNumber(ctx context.Context, idNumber string) (*model.Landlord, error) {
+	var landlord model.Landlord
+	if err := dbFromContext(ctx, r.db).Where("id_number = ? AND verified = ?", idNumber, true).First(&landlord).Error; err != nil {
+		return nil, err
+	}
+	return &landlord, nil
+}
+
+func (r *landlordRepository) Update(ctx context.Context, landlord *model.Landlord) error {
+	return dbFromContext(ctx, r.db).Save(landlord).Error
+}
+
+func (r *landlordRepository) Delete(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Delete(&model.Landlord{}, id).Error
 }
 
-func (r *landlordRepository) Delete(id uint) error {
-	return r.db.Delete(&model.Landlord{}, id).Error
+func (r *landlordRepository) UpdateRating(ctx context.Context, id uint, rating float64, reviewCount int64) error {
+	return dbFromContext(ctx, r.db).Model(&model.Landlord{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"rating":       rating,
+		"review_count": reviewCount,
+	}).Error
 }
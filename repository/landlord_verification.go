@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"myApp/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LandlordVerificationRepository 房东认证申请仓库接口
+type LandlordVerificationRepository interface {
+	Create(ctx context.Context, verification *model.LandlordVerification) error
+	GetByID(ctx context.Context, id uint) (*model.LandlordVerification, error)
+	GetByIDForUpdate(ctx context.Context, id uint) (*model.LandlordVerification, error)
+	GetLatestByLandlordID(ctx context.Context, landlordID uint) (*model.LandlordVerification, error)
+	GetAll(ctx context.Context, params map[string]interface{}) ([]model.LandlordVerification, int64, error)
+	Update(ctx context.Context, verification *model.LandlordVerification) error
+}
+
+// landlordVerificationRepository 房东认证申请仓库实现
+type landlordVerificationRepository struct {
+	db *gorm.DB
+}
+
+// NewLandlordVerificationRepository 创建房东认证申请仓库实例
+func NewLandlordVerificationRepository(db *gorm.DB) LandlordVerificationRepository {
+	return &landlordVerificationRepository{
+		db: db,
+	}
+}
+
+// Create 创建认证申请
+func (r *landlordVerificationRepository) Create(ctx context.Context, verification *model.LandlordVerification) error {
+	return dbFromContext(ctx, r.db).Create(verification).Error
+}
+
+// GetByID 根据ID获取认证申请
+func (r *landlordVerificationRepository) GetByID(ctx context.Context, id uint) (*model.LandlordVerification, error) {
+	var verification model.LandlordVerification
+	if err := dbFromContext(ctx, r.db).First(&verification, id).Error; err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// GetByIDForUpdate 加行锁读取认证申请，需在事务中调用，锁在事务结束时释放
+func (r *landlordVerificationRepository) GetByIDForUpdate(ctx context.Context, id uint) (*model.LandlordVerification, error) {
+	var verification model.LandlordVerification
+	if err := dbFromContext(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&verification, id).Error; err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// GetLatestByLandlordID 获取房东最近一次提交的认证申请
+func (r *landlordVerificationRepository) GetLatestByLandlordID(ctx context.Context, landlordID uint) (*model.LandlordVerification, error) {
+	var verification model.LandlordVerification
+	if err := dbFromContext(ctx, r.db).Where("landlord_id = ?", landlordID).Order("id DESC").First(&verification).Error; err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// GetAll 查询认证申请列表，返回当前页数据和总记录数
+func (r *landlordVerificationRepository) GetAll(ctx context.Context, params map[string]interface{}) ([]model.LandlordVerification, int64, error) {
+	var verifications []model.LandlordVerification
+	db := dbFromContext(ctx, r.db).Model(&model.LandlordVerification{})
+
+	// 根据参数构建查询条件
+	if params != nil {
+		if status, ok := params["status"].(int); ok {
+			db = db.Where("status = ?", status)
+		}
+		if landlordID, ok := params["landlord_id"].(uint); ok {
+			db = db.Where("landlord_id = ?", landlordID)
+		}
+	}
+
+	// 统计总数
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 排序，默认按提交时间先后处理
+	if orderBy, ok := params["order_by"].(string); ok && orderBy != "" {
+		db = db.Order(orderBy)
+	} else {
+		db = db.Order("created_at ASC")
+	}
+
+	// 分页
+	if limit, ok := params["limit"].(int); ok && limit > 0 {
+		db = db.Limit(limit)
+		if offset, ok := params["offset"].(int); ok && offset >= 0 {
+			db = db.Offset(offset)
+		}
+	}
+
+	if err := db.Find(&verifications).Error; err != nil {
+		return nil, 0, err
+	}
+	return verifications, total, nil
+}
+
+// Update 更新认证申请
+func (r *landlordVerificationRepository) Update(ctx context.Context, verification *model.LandlordVerification) error {
+	return dbFromContext(ctx, r.db).Save(verification).Error
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"myApp/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NotificationRepository 站内通知仓库接口
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *model.Notification) error
+	GetAll(ctx context.Context, params map[string]interface{}) ([]model.Notification, int64, error)
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+	MarkRead(ctx context.Context, id, userID uint) (int64, error)
+	MarkAllRead(ctx context.Context, userID uint) error
+}
+
+// notificationRepository 站内通知仓库实现
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository 创建站内通知仓库实例
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+// Create 创建通知
+func (r *notificationRepository) Create(ctx context.Context, notification *model.Notification) error {
+	return dbFromContext(ctx, r.db).Create(notification).Error
+}
+
+// GetAll 查询通知列表，返回当前页数据和总记录数
+func (r *notificationRepository) GetAll(ctx context.Context, params map[string]interface{}) ([]model.Notification, int64, error) {
+	var notifications []model.Notification
+	db := dbFromContext(ctx, r.db).Model(&model.Notification{})
+
+	// 根据参数构建查询条件
+	if params != nil {
+		if userID, ok := params["user_id"].(uint); ok {
+			db = db.Where("user_id = ?", userID)
+		}
+		if isRead, ok := params["is_read"].(bool); ok {
+			db = db.Where("is_read = ?", isRead)
+		}
+	}
+
+	// 统计总数
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	db = db.Order("created_at DESC, id DESC")
+
+	// 分页
+	if limit, ok := params["limit"].(int); ok && limit > 0 {
+		db = db.Limit(limit)
+		if offset, ok := params["offset"].(int); ok && offset >= 0 {
+			db = db.Offset(offset)
+		}
+	}
+
+	if err := db.Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+// CountUnread 统计用户的未读通知数量
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := dbFromContext(ctx, r.db).Model(&model.Notification{}).Where("user_id = ? AND is_read = ?", userID, false).Count(&count).Error
+	return count, err
+}
+
+// MarkRead 将用户的指定通知标记为已读，返回受影响的行数
+func (r *notificationRepository) MarkRead(ctx context.Context, id, userID uint) (int64, error) {
+	result := dbFromContext(ctx, r.db).Model(&model.Notification{}).
+		Where("id = ? AND user_id = ? AND is_read = ?", id, userID, false).
+		Updates(map[string]interface{}{"is_read": true, "read_at": time.Now()})
+	return result.RowsAffected, result.Error
+}
+
+// MarkAllRead 将用户的全部未读通知标记为已读
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uint) error {
+	return dbFromContext(ctx, r.db).Model(&model.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Updates(map[string]interface{}{"is_read": true, "read_at": time.Now()}).Error
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"myApp/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RegionRepository 行政区划仓库接口
+type RegionRepository interface {
+	GetAll(ctx context.Context) ([]model.Region, error)
+	Upsert(ctx context.Context, regions []model.Region) error
+}
+
+// regionRepository 行政区划仓库实现
+type regionRepository struct {
+	db *gorm.DB
+}
+
+// NewRegionRepository 创建行政区划仓库实例
+func NewRegionRepository(db *gorm.DB) RegionRepository {
+	return &regionRepository{
+		db: db,
+	}
+}
+
+// GetAll 查询全部行政区划，按级别和代码排序
+func (r *regionRepository) GetAll(ctx context.Context) ([]model.Region, error) {
+	var regions []model.Region
+	if err := dbFromContext(ctx, r.db).Order("level ASC, code ASC").Find(&regions).Error; err != nil {
+		return nil, err
+	}
+	return regions, nil
+}
+
+// Upsert 批量导入行政区划，代码已存在时更新名称、上级、级别和中心点
+func (r *regionRepository) Upsert(ctx context.Context, regions []model.Region) error {
+	if len(regions) == 0 {
+		return nil
+	}
+	return dbFromContext(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"parent_code", "name", "level", "latitude", "longitude", "radius", "updated_at"}),
+	}).CreateInBatches(regions, 500).Error
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"testing"
+
+	"myApp/model"
+	"myApp/model/modeltest"
+)
+
+func TestRegionRepositoryUpsert(t *testing.T) {
+	ctx := t.Context()
+	repo := NewRegionRepository(modeltest.NewDB(t))
+
+	regions := []model.Region{
+		{Code: "110000", Name: "北京市", Level: 1},
+		{Code: "110105", ParentCode: "110000", Name: "朝阳", Level: 3},
+	}
+	if err := repo.Upsert(ctx, regions); err != nil {
+		t.Fatalf("Upsert失败: %v", err)
+	}
+
+	// 再次导入时按代码更新已有区划，不产生重复记录
+	regions[1].Name = "朝阳区"
+	regions[1].Latitude, regions[1].Longitude, regions[1].Radius = 39.92, 116.44, 15
+	if err := repo.Upsert(ctx, regions); err != nil {
+		t.Fatalf("重复Upsert失败: %v", err)
+	}
+
+	list, err := repo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll失败: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("GetAll返回%d条，期望 2", len(list))
+	}
+	if list[0].Code != "110000" || list[1].Name != "朝阳区" || list[1].Radius != 15 {
+		t.Errorf("Upsert后的区划不正确: %+v", list)
+	}
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"myApp/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReviewRepository 评价仓库接口
+type ReviewRepository interface {
+	Create(ctx context.Context, review *model.Review) error
+	GetByID(ctx context.Context, id uint) (*model.Review, error)
+	GetByIDForUpdate(ctx context.Context, id uint) (*model.Review, error)
+	GetByViewingID(ctx context.Context, viewingID uint) (*model.Review, error)
+	GetAll(ctx context.Context, params map[string]interface{}) ([]model.Review, int64, error)
+	Update(ctx context.Context, review *model.Review) error
+	UpdateColumns(ctx context.Context, id uint, columns map[string]interface{}) error
+	GetHouseRatingStats(ctx context.Context, houseID uint) (float64, int64, error)
+	GetLandlordRatingStats(ctx context.Context, landlordID uint) (float64, int64, error)
+}
+
+// reviewRepository 评价仓库实现
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository 创建评价仓库实例
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{
+		db: db,
+	}
+}
+
+// ratingStats 评分统计结果
+type ratingStats struct {
+	Average float64
+	Total   int64
+}
+
+// Create 创建评价
+func (r *reviewRepository) Create(ctx context.Context, review *model.Review) error {
+	return dbFromContext(ctx, r.db).Create(review).Error
+}
+
+// GetByID 根据ID获取评价
+func (r *reviewRepository) GetByID(ctx context.Context, id uint) (*model.Review, error) {
+	var review model.Review
+	if err := dbFromContext(ctx, r.db).First(&review, id).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetByIDForUpdate 加行锁读取评价，需在事务中调用，锁在事务结束时释放
+func (r *reviewRepository) GetByIDForUpdate(ctx context.Context, id uint) (*model.Review, error) {
+	var review model.Review
+	if err := dbFromContext(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&review, id).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetByViewingID 根据看房预约ID获取评价
+func (r *reviewRepository) GetByViewingID(ctx context.Context, viewingID uint) (*model.Review, error) {
+	var review model.Review
+	if err := dbFromContext(ctx, r.db).Where("viewing_id = ?", viewingID).First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetAll 查询评价列表，返回当前页数据和总记录数
+func (r *reviewRepository) GetAll(ctx context.Context, params map[string]interface{}) ([]model.Review, int64, error) {
+	var reviews []model.Review
+	db := dbFromContext(ctx, r.db).Model(&model.Review{})
+
+	// 根据参数构建查询条件
+	if params != nil {
+		if status, ok := params["status"].(int); ok {
+			db = db.Where("status = ?", status)
+		}
+		if houseID, ok := params["house_id"].(uint); ok {
+			db = db.Where("house_id = ?", houseID)
+		}
+		if landlordID, ok := params["landlord_id"].(uint); ok {
+			db = db.Where("landlord_id = ?", landlordID)
+		}
+		if userID, ok := params["user_id"].(uint); ok {
+			db = db.Where("user_id = ?", userID)
+		}
+	}
+
+	// 统计总数
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 排序
+	if orderBy, ok := params["order_by"].(string); ok && orderBy != "" {
+		db = db.Order(orderBy)
+	} else {
+		db = db.Order("created_at DESC")
+	}
+
+	// 分页
+	if limit, ok := params["limit"].(int); ok && limit > 0 {
+		db = db.Limit(limit)
+		if offset, ok := params["offset"].(int); ok && offset >= 0 {
+			db = db.Offset(offset)
+		}
+	}
+
+	if err := db.Find(&reviews).Error; err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+// Update 更新评价
+func (r *reviewRepository) Update(ctx context.Context, review *model.Review) error {
+	return dbFromContext(ctx, r.db).Save(review).Error
+}
+
+// UpdateColumns 更新评价的指定字段
+func (r *reviewRepository) UpdateColumns(ctx context.Context, id uint, columns map[string]interface{}) error {
+	return dbFromContext(ctx, r.db).Model(&model.Review{}).Where("id = ?", id).Updates(columns).Error
+}
+
+// GetHouseRatingStats 统计房源的平均评分和有效评价数量（不含已隐藏的评价）
+func (r *reviewRepository) GetHouseRatingStats(ctx context.Context, houseID uint) (float64, int64, error) {
+	var stats ratingStats
+	err := dbFromContext(ctx, r.db).Model(&model.Review{}).
+		Select("COALESCE(AVG(house_rating), 0) AS average, COUNT(*) AS total").
+		Where("house_id = ? AND status = ?", houseID, model.ReviewNormal).
+		Scan(&stats).Error
+	return stats.Average, stats.Total, err
+}
+
+// GetLandlordRatingStats 统计房东的平均评分和有效评价数量（不含已隐藏的评价）
+func (r *reviewRepository) GetLandlordRatingStats(ctx context.Context, landlordID uint) (float64, int64, error) {
+	var stats ratingStats
+	err := dbFromContext(ctx, r.db).Model(&model.Review{}).
+		Select("COALESCE(AVG(landlord_rating), 0) AS average, COUNT(*) AS total").
+		Where("landlord_id = ? AND status = ?", landlordID, model.ReviewNormal).
+		Scan(&stats).Error
+	return stats.Average, stats.Total, err
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"myApp/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReviewReportRepository 评价举报仓库接口
+type ReviewReportRepository interface {
+	Create(ctx context.Context, report *model.ReviewReport) error
+	GetByID(ctx context.Context, id uint) (*model.ReviewReport, error)
+	GetAll(ctx context.Context, params map[string]interface{}) ([]model.ReviewReport, int64, error)
+	Update(ctx context.Context, report *model.ReviewReport) error
+	ExistsByUserAndReview(ctx context.Context, userID, reviewID uint) (bool, error)
+	UpdateStatusByReviewID(ctx context.Context, reviewID uint, status int, handlerID uint) error
+}
+
+// reviewReportRepository 评价举报仓库实现
+type reviewReportRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewReportRepository 创建评价举报仓库实例
+func NewReviewReportRepository(db *gorm.DB) ReviewReportRepository {
+	return &reviewReportRepository{
+		db: db,
+	}
+}
+
+// Create 创建举报记录
+func (r *reviewReportRepository) Create(ctx context.Context, report *model.ReviewReport) error {
+	return dbFromContext(ctx, r.db).Create(report).Error
+}
+
+// GetByID 根据ID获取举报记录
+func (r *reviewReportRepository) GetByID(ctx context.Context, id uint) (*model.ReviewReport, error) {
+	var report model.ReviewReport
+	if err := dbFromContext(ctx, r.db).First(&report, id).Error; err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetAll 查询举报列表，返回当前页数据和总记录数
+func (r *reviewReportRepository) GetAll(ctx context.Context, params map[string]interface{}) ([]model.ReviewReport, int64, error) {
+	var reports []model.ReviewReport
+	db := dbFromContext(ctx, r.db).Model(&model.ReviewReport{})
+
+	// 根据参数构建查询条件
+	if params != nil {
+		if status, ok := params["status"].(int); ok {
+			db = db.Where("status = ?", status)
+		}
+		if reviewID, ok := params["review_id"].(uint); ok {
+			db = db.Where("review_id = ?", reviewID)
+		}
+	}
+
+	// 统计总数
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 排序，默认按举报时间先后处理
+	db = db.Order("created_at ASC")
+
+	// 分页
+	if limit, ok := params["limit"].(int); ok && limit > 0 {
+		db = db.Limit(limit)
+		if offset, ok := params["offset"].(int); ok && offset >= 0 {
+			db = db.Offset(offset)
+		}
+	}
+
+	if err := db.Find(&reports).Error; err != nil {
+		return nil, 0, err
+	}
+	return reports, total, nil
+}
+
+// Update 更新举报记录
+func (r *reviewReportRepository) Update(ctx context.Context, report *model.ReviewReport) error {
+	return dbFromContext(ctx, r.db).Save(report).Error
+}
+
+// ExistsByUserAndReview 检查用户是否已举报过该评价
+func (r *reviewReportRepository) ExistsByUserAndReview(ctx context.Context, userID, reviewID uint) (bool, error) {
+	var count int64
+	err := dbFromContext(ctx, r.db).Model(&model.ReviewReport{}).Where("user_id = ? AND review_id = ?", userID, reviewID).Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateStatusByReviewID 批量处理同一评价下所有待处理的举报
+func (r *reviewReportRepository) UpdateStatusByReviewID(ctx context.Context, reviewID uint, status int, handlerID uint) error {
+	return dbFromContext(ctx, r.db).Model(&model.ReviewReport{}).
+		Where("review_id = ? AND status = ?", reviewID, model.ReportPending).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"handler_id": handlerID,
+			"handled_at": time.Now(),
+		}).Error
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"myApp/model"
+
+	"gorm.io/gorm"
+)
+
+// SavedSearchRepository 保存的搜索仓库接口
+type SavedSearchRepository interface {
+	Create(ctx context.Context, search *model.SavedSearch) error
+	GetByID(ctx context.Context, id uint) (*model.SavedSearch, error)
+	GetByUserID(ctx context.Context, userID uint) ([]model.SavedSearch, error)
+	CountByUserID(ctx context.Context, userID uint) (int64, error)
+	UpdateColumns(ctx context.Context, id uint, columns map[string]interface{}) error
+	Delete(ctx context.Context, id uint) error
+	GetAlertEnabled(ctx context.Context, afterID uint, limit int) ([]model.SavedSearch, error)
+}
+
+// savedSearchRepository 保存的搜索仓库实现
+type savedSearchRepository struct {
+	db *gorm.DB
+}
+
+// NewSavedSearchRepository 创建保存的搜索仓库实例
+func NewSavedSearchRepository(db *gorm.DB) SavedSearchRepository {
+	return &savedSearchRepository{
+		db: db,
+	}
+}
+
+// Create 创建保存的搜索
+func (r *savedSearchRepository) Create(ctx context.Context, search *model.SavedSearch) error {
+	return dbFromContext(ctx, r.db).Create(search).Error
+}
+
+// GetByID 根据ID查询保存的搜索
+func (r *savedSearchRepository) GetByID(ctx context.Context, id uint) (*model.SavedSearch, error) {
+	var search model.SavedSearch
+	if err := dbFromContext(ctx, r.db).First(&search, id).Error; err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+// GetByUserID 查询用户保存的全部搜索，按创建时间倒序
+func (r *savedSearchRepository) GetByUserID(ctx context.Context, userID uint) ([]model.SavedSearch, error) {
+	var searches []model.SavedSearch
+	if err := dbFromContext(ctx, r.db).Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&searches).Error; err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+// CountByUserID 统计用户保存的搜索数量
+func (r *savedSearchRepository) CountByUserID(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := dbFromContext(ctx, r.db).Model(&model.SavedSearch{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// UpdateColumns 更新保存的搜索的指定字段
+func (r *savedSearchRepository) UpdateColumns(ctx context.Context, id uint, columns map[string]interface{}) error {
+	return dbFromContext(ctx, r.db).Model(&model.SavedSearch{}).Where("id = ?", id).Updates(columns).Error
+}
+
+// Delete 删除保存的搜索
+func (r *savedSearchRepository) Delete(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Delete(&model.SavedSearch{}, id).Error
+}
+
+// GetAlertEnabled 按ID顺序分批查询开启了站内或短信提醒的搜索，afterID为上一批的最大ID
+func (r *savedSearchRepository) GetAlertEnabled(ctx context.Context, afterID uint, limit int) ([]model.SavedSearch, error) {
+	var searches []model.SavedSearch
+	err := dbFromContext(ctx, r.db).Where("id > ? AND (notify_in_app = ? OR notify_sms = ?)", afterID, true, true).
+		Order("id ASC").Limit(limit).Find(&searches).Error
+	if err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"testing"
+
+	"myApp/model"
+	"myApp/model/modeltest"
+)
+
+func TestSavedSearchRepositoryGetAlertEnabled(t *testing.T) {
+	ctx := t.Context()
+	repo := NewSavedSearchRepository(modeltest.NewDB(t))
+	for i, notify := range [][2]bool{{true, false}, {false, false}, {false, true}, {true, true}, {true, false}} {
+		search := &model.SavedSearch{UserID: uint(i + 1), Name: "搜索", NotifyInApp: notify[0], NotifySMS: notify[1]}
+		if err := repo.Create(ctx, search); err != nil {
+			t.Fatalf("Create失败: %v", err)
+		}
+	}
+
+	// 按ID分批读取，跳过未开启任何提醒的搜索
+	var ids []uint
+	var afterID uint
+	for {
+		batch, err := repo.GetAlertEnabled(ctx, afterID, 2)
+		if err != nil {
+			t.Fatalf("GetAlertEnabled失败: %v", err)
+		}
+		for _, search := range batch {
+			ids = append(ids, search.ID)
+		}
+		if len(batch) < 2 {
+			break
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+
+	want := []uint{1, 3, 4, 5}
+	if len(ids) != len(want) {
+		t.Fatalf("GetAlertEnabled返回 %v，期望 %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("GetAlertEnabled返回 %v，期望 %v", ids, want)
+		}
+	}
+}
+
+func TestSavedSearchRepositoryCreateNotifyOff(t *testing.T) {
+	ctx := t.Context()
+	repo := NewSavedSearchRepository(modeltest.NewDB(t))
+
+	// 关闭站内提醒的设置在创建时写入，不被默认值覆盖
+	search := &model.SavedSearch{UserID: 1, Name: "搜索", NotifyInApp: false, NotifySMS: true}
+	if err := repo.Create(ctx, search); err != nil {
+		t.Fatalf("Create失败: %v", err)
+	}
+	got, err := repo.GetByID(ctx, search.ID)
+	if err != nil {
+		t.Fatalf("GetByID失败: %v", err)
+	}
+	if got.NotifyInApp || !got.NotifySMS {
+		t.Errorf("提醒设置为 in_app=%v sms=%v，期望 false、true", got.NotifyInApp, got.NotifySMS)
+	}
+}
+
+func TestSavedSearchRepositoryCountByUserID(t *testing.T) {
+	ctx := t.Context()
+	repo := NewSavedSearchRepository(modeltest.NewDB(t))
+	for _, userID := range []uint{1, 1, 2} {
+		if err := repo.Create(ctx, &model.SavedSearch{UserID: userID, Name: "搜索"}); err != nil {
+			t.Fatalf("Create失败: %v", err)
+		}
+	}
+
+	count, err := repo.CountByUserID(ctx, 1)
+	if err != nil || count != 2 {
+		t.Errorf("CountByUserID = %d, %v，期望 2", count, err)
+	}
+	searches, err := repo.GetByUserID(ctx, 1)
+	if err != nil || len(searches) != 2 {
+		t.Errorf("GetByUserID返回%d条, %v，期望 2", len(searches), err)
+	}
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"myApp/model"
 
 	"gorm.io/gorm"
@@ -8,9 +9,9 @@ import (
 
 // SMSRecordRepository 短信记录仓库接口
 type SMSRecordRepository interface {
-	Create(record *model.SMSRecord) error
-	FindByPhone(phone string, limit, offset int) ([]*model.SMSRecord, error)
-	CountByPhone(phone string) (int64, error)
+	Create(ctx context.Context, record *model.SMSRecord) error
+	FindByPhone(ctx context.Context, phone string, limit, offset int) ([]*model.SMSRecord, error)
+	CountByPhone(ctx context.Context, phone string) (int64, error)
 }
 
 // smsRecordRepository 短信记录仓库实现
@@ -19,21 +20,21 @@ type smsRecordRepository struct {
 }
 
 // NewSMSRecordRepository 创建短信记录仓库实例
-func NewSMSRecordRepository() SMSRecordRepository {
+func NewSMSRecordRepository(db *gorm.DB) SMSRecordRepository {
 	return &smsRecordRepository{
-		db: model.GetDB(),
+		db: db,
 	}
 }
 
 // Create 创建短信记录
-func (r *smsRecordRepository) Create(record *model.SMSRecord) error {
-	return r.db.Create(record).Error
+func (r *smsRecordRepository) Create(ctx context.Context, record *model.SMSRecord) error {
+	return dbFromContext(ctx, r.db).Create(record).Error
 }
 
 // FindByPhone 根据手机号查询短信记录
-func (r *smsRecordRepository) FindByPhone(phone string, limit, offset int) ([]*model.SMSRecord, error) {
+func (r *smsRecordRepository) FindByPhone(ctx context.Context, phone string, limit, offset int) ([]*model.SMSRecord, error) {
 	var records []*model.SMSRecord
-	query := r.db.Where("phone = ?", phone).Order("created_at DESC")
+	query := dbFromContext(ctx, r.db).Where("phone = ?", phone).Order("created_at DESC")
 
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -50,9 +51,9 @@ func (r *smsRecordRepository) FindByPhone(phone string, limit, offset int) ([]*m
 }
 
 // CountByPhone 统计指定手机号的短信记录数量
-func (r *smsRecordRepository) CountByPhone(phone string) (int64, error) {
+func (r *smsRecordRepository) CountByPhone(ctx context.Context, phone string) (int64, error) {
 	var count int64
-	if err := r.db.Model(&model.SMSRecord{}).Where("phone = ?", phone).Count(&count).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Model(&model.SMSRecord{}).Where("phone = ?", phone).Count(&count).Error; err != nil {
 		return 0, err
 	}
 	return count, nil
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// TxManager 事务管理器，使多个数据仓库的操作在同一个数据库事务中执行
+type TxManager interface {
+	// Transaction 在事务中执行fn，fn返回错误或panic时回滚，否则提交
+	// fn中调用数据仓库时传入fn的ctx即在该事务中执行；ctx已处于事务中时直接复用外层事务
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// txKey 事务在context中的键
+type txKey struct{}
+
+type txManager struct {
+	db *gorm.DB
+}
+
+// NewTxManager 创建事务管理器实例
+func NewTxManager(db *gorm.DB) TxManager {
+	return &txManager{db: db}
+}
+
+func (m *txManager) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// dbFromContext 返回ctx中的事务，不在事务中时返回绑定ctx的db
+func dbFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"myApp/model"
+	"myApp/model/modeltest"
+)
+
+func TestTxManagerCommit(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	txManager := NewTxManager(db)
+	userRepo := NewUserRepository(db)
+	landlordRepo := NewLandlordRepository(db)
+
+	err := txManager.Transaction(ctx, func(ctx context.Context) error {
+		user := &model.User{Username: "landlord", Phone: "13800138000"}
+		if err := userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		return landlordRepo.Create(ctx, &model.Landlord{UserID: user.ID, RealName: "张三"})
+	})
+	if err != nil {
+		t.Fatalf("事务执行失败: %v", err)
+	}
+
+	user, err := userRepo.FindByUsername(ctx, "landlord")
+	if err != nil {
+		t.Fatalf("事务提交后应能查询到用户: %v", err)
+	}
+	if _, err := landlordRepo.FindByUserID(ctx, user.ID); err != nil {
+		t.Fatalf("事务提交后应能查询到房东: %v", err)
+	}
+}
+
+func TestTxManagerRollback(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	txManager := NewTxManager(db)
+	userRepo := NewUserRepository(db)
+	errFail := errors.New("模拟失败")
+
+	err := txManager.Transaction(ctx, func(ctx context.Context) error {
+		if err := userRepo.Create(ctx, &model.User{Username: "rollback"}); err != nil {
+			return err
+		}
+		return errFail
+	})
+	if !errors.Is(err, errFail) {
+		t.Fatalf("应返回fn的错误，实际为%v", err)
+	}
+	if _, err := userRepo.FindByUsername(ctx, "rollback"); err == nil {
+		t.Fatal("返回错误时事务应回滚")
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("fn中的panic应继续抛出")
+			}
+		}()
+		txManager.Transaction(ctx, func(ctx context.Context) error {
+			userRepo.Create(ctx, &model.User{Username: "panic"})
+			panic("模拟panic")
+		})
+	}()
+	if _, err := userRepo.FindByUsername(ctx, "panic"); err == nil {
+		t.Fatal("panic时事务应回滚")
+	}
+}
+
+func TestTxManagerNested(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	txManager := NewTxManager(db)
+	userRepo := NewUserRepository(db)
+
+	// 内层复用外层事务，外层失败时内层的修改一并回滚
+	err := txManager.Transaction(ctx, func(ctx context.Context) error {
+		err := txManager.Transaction(ctx, func(ctx context.Context) error {
+			return userRepo.Create(ctx, &model.User{Username: "nested"})
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := userRepo.FindByUsername(ctx, "nested"); err != nil {
+			t.Fatalf("外层事务中应能查询到内层创建的用户: %v", err)
+		}
+		return errors.New("外层失败")
+	})
+	if err == nil {
+		t.Fatal("应返回外层的错误")
+	}
+	if _, err := userRepo.FindByUsername(ctx, "nested"); err == nil {
+		t.Fatal("外层回滚时内层的修改也应回滚")
+	}
+}
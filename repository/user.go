@@ -1,56 +1,57 @@
 package repository
 
 import (
+	"context"
 	"myApp/model"
 
 	"gorm.io/gorm"
 )
 
 type UserRepository interface {
-	Create(user *model.User) error
-	FindByUsername(username string) (*model.User, error)
-	FindByID(id uint) (*model.User, error)
-	FindByPhone(phone string) ([]*model.User, error)
-	Update(user *model.User) error
+	Create(ctx context.Context, user *model.User) error
+	FindByUsername(ctx context.Context, username string) (*model.User, error)
+	FindByID(ctx context.Context, id uint) (*model.User, error)
+	FindByPhone(ctx context.Context, phone string) ([]*model.User, error)
+	Update(ctx context.Context, user *model.User) error
 }
 
 type userRepository struct {
 	db *gorm.DB
 }
 
-func NewUserRepository() UserRepository {
+func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{
-		db: model.GetDB(),
+		db: db,
 	}
 }
 
-func (r *userRepository) Create(user *model.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *model.User) error {
+	return dbFromContext(ctx, r.db).Create(user).Error
 }
 
-func (r *userRepository) FindByUsername(username string) (*model.User, error) {
+func (r *userRepository) FindByUsername(ctx context.Context, username string) (*model.User, error) {
 	var user model.User
-	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Where("username = ?", username).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) FindByID(id uint) (*model.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	if err := r.db.First(&user, id).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).First(&user, id).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) Update(user *model.User) error {
-	return r.db.Save(user).Error
+func (r *userRepository) Update(ctx context.Context, user *model.User) error {
+	return dbFromContext(ctx, r.db).Save(user).Error
 }
 
-func (r *userRepository) FindByPhone(phone string) ([]*model.User, error) {
+func (r *userRepository) FindByPhone(ctx context.Context, phone string) ([]*model.User, error) {
 	var users []*model.User
-	if err := r.db.Where("phone = ?", phone).Find(&users).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Where("phone = ?", phone).Find(&users).Error; err != nil {
 		return nil, err
 	}
 	return users, nil
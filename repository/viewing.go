@@ -1,47 +1,60 @@
 package repository
 
 import (
+	"context"
 	"myApp/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ViewingRepository interface {
-	Create(viewing *model.Viewing) error
-	GetByID(id uint) (*model.Viewing, error)
-	GetAll(params map[string]interface{}) ([]model.Viewing, error)
-	Update(viewing *model.Viewing) error
-	Delete(id uint) error
-	GetViewingsByUserID(userID uint) ([]model.Viewing, error)
-	GetViewingsByHouseID(houseID uint) ([]model.Viewing, error)
-	UpdateStatus(id uint, status int) error
+	Create(ctx context.Context, viewing *model.Viewing) error
+	GetByID(ctx context.Context, id uint) (*model.Viewing, error)
+	GetByIDForUpdate(ctx context.Context, id uint) (*model.Viewing, error)
+	GetAll(ctx context.Context, params map[string]interface{}) ([]model.Viewing, error)
+	Update(ctx context.Context, viewing *model.Viewing) error
+	Delete(ctx context.Context, id uint) error
+	GetViewingsByUserID(ctx context.Context, userID uint) ([]model.Viewing, error)
+	GetViewingsByHouseID(ctx context.Context, houseID uint) ([]model.Viewing, error)
+	UpdateStatus(ctx context.Context, id uint, status int) error
+	GetResponseStats(ctx context.Context, landlordUserID uint) (int64, int64, error)
 }
 
 type viewingRepository struct {
 	db *gorm.DB
 }
 
-func NewViewingRepository() ViewingRepository {
+func NewViewingRepository(db *gorm.DB) ViewingRepository {
 	return &viewingRepository{
-		db: model.GetDB(),
+		db: db,
 	}
 }
 
-func (r *viewingRepository) Create(viewing *model.Viewing) error {
-	return r.db.Create(viewing).Error
+func (r *viewingRepository) Create(ctx context.Context, viewing *model.Viewing) error {
+	return dbFromContext(ctx, r.db).Create(viewing).Error
 }
 
-func (r *viewingRepository) GetByID(id uint) (*model.Viewing, error) {
+func (r *viewingRepository) GetByID(ctx context.Context, id uint) (*model.Viewing, error) {
 	var viewing model.Viewing
-	if err := r.db.First(&viewing, id).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).First(&viewing, id).Error; err != nil {
 		return nil, err
 	}
 	return &viewing, nil
 }
 
-func (r *viewingRepository) GetAll(params map[string]interface{}) ([]model.Viewing, error) {
+// GetByIDForUpdate 加行锁读取预约看房记录，需在事务中调用，锁在事务结束时释放
+func (r *viewingRepository) GetByIDForUpdate(ctx context.Context, id uint) (*model.Viewing, error) {
+	var viewing model.Viewing
+	if err := dbFromContext(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&viewing, id).Error; err != nil {
+		return nil, err
+	}
+	return &viewing, nil
+}
+
+func (r *viewingRepository) GetAll(ctx context.Context, params map[string]interface{}) ([]model.Viewing, error) {
 	var viewings []model.Viewing
-	db := r.db
+	db := dbFromContext(ctx, r.db)
 
 	// 根据参数构建查询条件
 	if params != nil {
@@ -83,30 +96,46 @@ func (r *viewingRepository) GetAll(params map[string]interface{}) ([]model.Viewi
 	return viewings, nil
 }
 
-func (r *viewingRepository) Update(viewing *model.Viewing) error {
-	return r.db.Save(viewing).Error
+func (r *viewingRepository) Update(ctx context.Context, viewing *model.Viewing) error {
+	return dbFromContext(ctx, r.db).Save(viewing).Error
 }
 
-func (r *viewingRepository) Delete(id uint) error {
-	return r.db.Delete(&model.Viewing{}, id).Error
+func (r *viewingRepository) Delete(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Delete(&model.Viewing{}, id).Error
 }
 
-func (r *viewingRepository) GetViewingsByUserID(userID uint) ([]model.Viewing, error) {
+func (r *viewingRepository) GetViewingsByUserID(ctx context.Context, userID uint) ([]model.Viewing, error) {
 	var viewings []model.Viewing
-	if err := r.db.Where("user_id = ?", userID).Find(&viewings).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Where("user_id = ?", userID).Find(&viewings).Error; err != nil {
 		return nil, err
 	}
 	return viewings, nil
 }
 
-func (r *viewingRepository) GetViewingsByHouseID(houseID uint) ([]model.Viewing, error) {
+func (r *viewingRepository) GetViewingsByHouseID(ctx context.Context, houseID uint) ([]model.Viewing, error) {
 	var viewings []model.Viewing
-	if err := r.db.Where("house_id = ?", houseID).Find(&viewings).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Where("house_id = ?", houseID).Find(&viewings).Error; err != nil {
 		return nil, err
 	}
 	return viewings, nil
 }
 
-func (r *viewingRepository) UpdateStatus(id uint, status int) error {
-	return r.db.Model(&model.Viewing{}).Where("id = ?", id).Update("status", status).Error
+func (r *viewingRepository) UpdateStatus(ctx context.Context, id uint, status int) error {
+	return dbFromContext(ctx, r.db).Model(&model.Viewing{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// GetResponseStats 统计房东名下房源收到的看房预约总数以及房东已响应（已确认或已完成）的数量
+// 已取消的预约无法区分是租客撤回还是房东拒绝，不计为已响应
+func (r *viewingRepository) GetResponseStats(ctx context.Context, landlordUserID uint) (int64, int64, error) {
+	var stats struct {
+		Total   int64
+		Handled int64
+	}
+	err := dbFromContext(ctx, r.db).Model(&model.Viewing{}).
+		Select("COUNT(*) AS total, COALESCE(SUM(CASE WHEN viewings.status IN ? THEN 1 ELSE 0 END), 0) AS handled",
+			[]int{model.ViewingConfirmed, model.ViewingCompleted}).
+		Joins("JOIN houses ON houses.id = viewings.house_id").
+		Where("houses.landlord_id = ? AND houses.deleted_at IS NULL", landlordUserID).
+		Scan(&stats).Error
+	return stats.Total, stats.Handled, err
 }
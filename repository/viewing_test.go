@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"myApp/model"
+	"myApp/model/modeltest"
+)
+
+func TestViewingRepositoryGetResponseStats(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	houseRepo := NewHouseRepository(db)
+	repo := NewViewingRepository(db)
+
+	own := modeltest.Create(t, db, modeltest.House(), func(h *model.House) { h.LandlordID = 7 })
+	deleted := modeltest.Create(t, db, modeltest.House(), func(h *model.House) { h.LandlordID = 7 })
+	other := modeltest.Create(t, db, modeltest.House(), func(h *model.House) { h.LandlordID = 8 })
+
+	for _, v := range []struct {
+		houseID uint
+		status  int
+	}{
+		{own.ID, model.ViewingPending},
+		{own.ID, model.ViewingConfirmed},
+		{own.ID, model.ViewingCompleted},
+		{own.ID, model.ViewingCancelled},
+		{deleted.ID, model.ViewingCompleted},
+		{other.ID, model.ViewingCompleted},
+	} {
+		viewing := &model.Viewing{HouseID: v.houseID, UserID: 1, ViewingTime: time.Now(), Status: v.status}
+		if err := repo.Create(ctx, viewing); err != nil {
+			t.Fatalf("Create失败: %v", err)
+		}
+	}
+	// 已删除房源的预约不计入统计
+	if err := houseRepo.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("Delete失败: %v", err)
+	}
+
+	total, handled, err := repo.GetResponseStats(ctx, 7)
+	if err != nil {
+		t.Fatalf("GetResponseStats失败: %v", err)
+	}
+	// 只有已确认和已完成的预约计为已响应，租客取消的预约不算房东响应
+	if total != 4 || handled != 2 {
+		t.Errorf("GetResponseStats = %d, %d，期望 4, 2", total, handled)
+	}
+}
@@ -0,0 +1,22 @@
+package router
+
+import (
+	"myApp/app"
+	"myApp/handler"
+	"myApp/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitAdminRouter 初始化管理员运维相关路由
+func InitAdminRouter(r *gin.Engine, a *app.App) {
+	// 创建管理员运维处理器实例
+	adminHandler := handler.NewAdminHandler()
+
+	// 创建管理员路由组，需要管理员权限
+	adminGroup := r.Group("/api/admin")
+	adminGroup.Use(middleware.JWTAuth(), middleware.AdminAuth(a.Repos.User))
+	{
+		adminGroup.GET("/cache/stats", adminHandler.GetCacheStats) // 获取缓存命中统计
+	}
+}
@@ -0,0 +1,98 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"myApp/app/apptest"
+	"myApp/config"
+)
+
+func TestCORS(t *testing.T) {
+	s := apptest.NewWithConfig(t, func(cfg *config.Config) {
+		cfg.CORS = config.CORSConfig{
+			AllowOrigins:     []string{"https://www.example.com", "https://*.example.cn"},
+			AllowMethods:     []string{"GET", "POST", "PATCH"},
+			AllowHeaders:     []string{"Content-Type", "Authorization"},
+			ExposeHeaders:    []string{"Retry-After"},
+			AllowCredentials: true,
+			MaxAge:           600,
+		}
+	})
+	send := func(method, origin string, preflight bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/house/list", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		}
+		w := httptest.NewRecorder()
+		s.Engine.ServeHTTP(w, req)
+		return w
+	}
+
+	// 允许的来源原样返回，并允许携带凭证
+	for _, origin := range []string{"https://www.example.com", "https://m.example.cn", "https://a.b.example.cn"} {
+		w := send(http.MethodGet, origin, false)
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != origin {
+			t.Fatalf("来源%s应被允许，Access-Control-Allow-Origin为%q", origin, got)
+		}
+		if w.Header().Get("Access-Control-Allow-Credentials") != "true" || w.Header().Get("Access-Control-Expose-Headers") != "Retry-After" {
+			t.Fatalf("跨域响应头不正确: %v", w.Header())
+		}
+		if w.Header().Get("Vary") != "Origin" {
+			t.Fatalf("响应应设置Vary: Origin，实际为%v", w.Header().Values("Vary"))
+		}
+	}
+
+	// 不允许的来源不返回跨域响应头，通配子域名不匹配主域名
+	for _, origin := range []string{"https://evil.com", "https://example.cn", "http://m.example.cn"} {
+		w := send(http.MethodGet, origin, false)
+		if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Fatalf("来源%s不应被允许: %d %v", origin, w.Code, w.Header())
+		}
+	}
+
+	// 预检请求
+	w := send(http.MethodOptions, "https://www.example.com", true)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("预检请求应返回204，实际为%d", w.Code)
+	}
+	if w.Header().Get("Access-Control-Allow-Methods") != "GET, POST, PATCH" || w.Header().Get("Access-Control-Max-Age") != "600" {
+		t.Fatalf("预检响应头不正确: %v", w.Header())
+	}
+	if w = send(http.MethodOptions, "https://evil.com", true); w.Code != http.StatusForbidden {
+		t.Fatalf("不允许的来源的预检请求应返回403，实际为%d", w.Code)
+	}
+}
+
+func TestCORSAllowAnyOrigin(t *testing.T) {
+	s := apptest.NewWithConfig(t, func(cfg *config.Config) {
+		cfg.CORS = config.CORSConfig{AllowOrigins: []string{"*"}, AllowMethods: []string{"GET"}}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/house/list", nil)
+	req.Header.Set("Origin", "https://any.example.com")
+	w := httptest.NewRecorder()
+	s.Engine.ServeHTTP(w, req)
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" || w.Header().Get("Vary") != "" {
+		t.Fatalf("允许任意来源且不携带凭证时应返回*且不设置Vary: %v", w.Header())
+	}
+}
+
+func TestCORSAllowAnyOriginRefusesCredentials(t *testing.T) {
+	s := apptest.NewWithConfig(t, func(cfg *config.Config) {
+		cfg.CORS = config.CORSConfig{AllowOrigins: []string{"*"}, AllowMethods: []string{"GET"}, AllowCredentials: true}
+	})
+
+	// 配置加载时会拒绝该组合，中间件也不会为任意来源返回请求的来源和允许携带凭证
+	req := httptest.NewRequest(http.MethodGet, "/api/house/list", nil)
+	req.Header.Set("Origin", "https://evil.com")
+	w := httptest.NewRecorder()
+	s.Engine.ServeHTTP(w, req)
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" || w.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Fatalf("允许任意来源时不应允许携带凭证: %v", w.Header())
+	}
+}
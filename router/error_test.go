@@ -0,0 +1,54 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	"myApp/app/apptest"
+	"myApp/pkg/errors"
+)
+
+func TestErrorMapping(t *testing.T) {
+	s := apptest.New(t)
+
+	// 参数校验失败时返回出错的字段，字段名与请求中的一致
+	resp := s.Do(t, http.MethodPost, "/api/user/register", map[string]string{
+		"username": "zhangsan",
+		"password": "password123",
+		"phone":    "138",
+	}, "")
+	if resp.Status != http.StatusBadRequest || resp.Code != int(errors.CodeValidation) {
+		t.Fatalf("参数校验失败应返回400和%d，实际为%d和%d", errors.CodeValidation, resp.Status, resp.Code)
+	}
+	var details struct {
+		Fields []errors.FieldError `json:"fields"`
+	}
+	resp.Decode(t, &details)
+	fields := map[string]string{}
+	for _, f := range details.Fields {
+		fields[f.Field] = f.Message
+	}
+	if len(fields) != 2 || fields["phone"] == "" || fields["email"] == "" {
+		t.Fatalf("字段错误详情不正确: %+v", details.Fields)
+	}
+
+	// 资源已存在
+	register := map[string]string{
+		"username": "zhangsan",
+		"password": "password123",
+		"phone":    "13800138000",
+		"email":    "zhangsan@example.com",
+	}
+	s.Do(t, http.MethodPost, "/api/user/register", register, "")
+	resp = s.Do(t, http.MethodPost, "/api/user/register", register, "")
+	if resp.Status != http.StatusConflict || resp.Code != int(errors.CodeConflict) || resp.Message != "用户名已存在" {
+		t.Fatalf("重复注册应返回409，实际为%d %d %s", resp.Status, resp.Code, resp.Message)
+	}
+
+	// 服务层返回的资源不存在
+	token := s.Token(t, s.CreateUser(t, nil).ID)
+	resp = s.Do(t, http.MethodPut, "/api/favorite/999/notes", map[string]string{"notes": "备注"}, token)
+	if resp.Status != http.StatusNotFound || resp.Code != int(errors.CodeNotFound) || resp.Message != "收藏记录不存在" {
+		t.Fatalf("修改不存在的收藏应返回404，实际为%d %d %s", resp.Status, resp.Code, resp.Message)
+	}
+}
@@ -1,22 +1,17 @@
 package router
 
 import (
+	"myApp/app"
 	"myApp/handler"
 	"myApp/middleware"
-	"myApp/repository"
-	"myApp/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 // InitFavoriteRouter 初始化收藏相关路由
-func InitFavoriteRouter(r *gin.Engine) {
-	// 创建收藏数据仓库实例
-	favoriteRepo := repository.NewFavoriteRepository()
-	// 创建收藏服务实例，注入数据仓库依赖
-	favoriteService := service.NewFavoriteService(favoriteRepo)
+func InitFavoriteRouter(r *gin.Engine, a *app.App) {
 	// 创建收藏处理器实例，注入服务依赖
-	favoriteHandler := handler.NewFavoriteHandler(favoriteService)
+	favoriteHandler := handler.NewFavoriteHandler(a.Services.Favorite)
 
 	// 创建收藏路由组，所有收藏相关接口都在/api/favorite路径下
 	favoriteGroup := r.Group("/api/favorite")
@@ -28,5 +23,12 @@ func InitFavoriteRouter(r *gin.Engine) {
 		favoriteGroup.GET("/list", favoriteHandler.GetUserFavorites)            // 获取用户的所有收藏
 		favoriteGroup.POST("/toggle/:house_id", favoriteHandler.ToggleFavorite) // 切换收藏状态
 		favoriteGroup.GET("/check/:house_id", favoriteHandler.CheckFavorite)    // 检查是否已收藏
+		favoriteGroup.PUT("/:id/price-alert", favoriteHandler.SetPriceAlert)    // 设置降价提醒
+		favoriteGroup.PUT("/:id/notes", favoriteHandler.UpdateNotes)            // 修改收藏备注
+		favoriteGroup.PUT("/:id/folder", favoriteHandler.MoveFavorite)          // 移动收藏到收藏夹
+		favoriteGroup.GET("/folders", favoriteHandler.GetFolders)               // 获取收藏夹列表
+		favoriteGroup.POST("/folders", favoriteHandler.CreateFolder)            // 创建收藏夹
+		favoriteGroup.PUT("/folders/:id", favoriteHandler.RenameFolder)         // 重命名收藏夹
+		favoriteGroup.DELETE("/folders/:id", favoriteHandler.DeleteFolder)      // 删除收藏夹
 	}
 }
@@ -0,0 +1,63 @@
+package router_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"myApp/app/apptest"
+	"myApp/model"
+)
+
+func TestToggleFavorite(t *testing.T) {
+	ctx := t.Context()
+	s := apptest.New(t)
+	user := s.CreateUser(t, nil)
+	token := s.Token(t, user.ID)
+
+	publishedAt := time.Now()
+	house := &model.House{
+		Title:       "阳光花园 两室一厅",
+		Address:     "北京市朝阳区建国路1号",
+		RentPrice:   5000,
+		Status:      model.HouseStatusPublished,
+		PublishedAt: &publishedAt,
+		LandlordID:  user.ID + 1,
+	}
+	if err := s.App.Repos.House.Create(ctx, house); err != nil {
+		t.Fatalf("创建测试房源失败: %v", err)
+	}
+	path := "/api/favorite/toggle/" + strconv.FormatUint(uint64(house.ID), 10)
+
+	var status struct {
+		IsFavorite bool `json:"is_favorite"`
+	}
+	resp := s.Do(t, http.MethodPost, path, map[string]string{"notes": "离公司近"}, token)
+	if resp.Status != http.StatusOK {
+		t.Fatalf("收藏失败: %d %s", resp.Status, resp.Message)
+	}
+	resp.Decode(t, &status)
+	if !status.IsFavorite {
+		t.Fatal("第一次切换后应为已收藏")
+	}
+	favorites, err := s.App.Repos.Favorite.GetFavoritesByUserID(ctx, user.ID)
+	if err != nil || len(favorites) != 1 || favorites[0].PriceAtFavorite != 5000 {
+		t.Fatalf("应记录1条收藏及收藏时租金，实际为%+v, %v", favorites, err)
+	}
+
+	resp = s.Do(t, http.MethodPost, path, nil, token)
+	resp.Decode(t, &status)
+	if resp.Status != http.StatusOK || status.IsFavorite {
+		t.Fatalf("第二次切换后应为未收藏: %d %s", resp.Status, resp.Message)
+	}
+
+	// 不存在的房源不能收藏，失败时不留下收藏记录
+	resp = s.Do(t, http.MethodPost, "/api/favorite/toggle/9999", nil, token)
+	if resp.Status != http.StatusNotFound {
+		t.Fatalf("收藏不存在的房源应返回404，实际为%d %s", resp.Status, resp.Message)
+	}
+	if favorites, _ := s.App.Repos.Favorite.GetFavoritesByUserID(ctx, user.ID); len(favorites) != 0 {
+		t.Fatalf("不应留下收藏记录，实际为%d条", len(favorites))
+	}
+}
@@ -0,0 +1,27 @@
+package router
+
+import (
+	"myApp/app"
+	"myApp/handler"
+	"myApp/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// healthRoutes 健康检查路由，由探针频繁调用，不限流
+var healthRoutes = []string{"/healthz", "/readyz"}
+
+// InitHealthRouter 初始化健康检查路由
+func InitHealthRouter(r *gin.Engine, a *app.App) {
+	// 创建健康检查处理器实例，注入数据库和Redis客户端
+	healthHandler := handler.NewHealthHandler(a.DB, a.Redis)
+
+	r.GET("/healthz", healthHandler.Healthz) // 存活检查
+	r.GET("/readyz", healthHandler.Readyz)   // 就绪检查，检查数据库和Redis连接
+}
+
+// SetupMetricsRouter 设置监控指标路由
+// 监控指标包含接口、数据库和缓存的运行情况，使用单独的端口提供，不与业务接口共用端口
+func SetupMetricsRouter(r *gin.Engine) {
+	r.GET("/metrics", gin.WrapH(metrics.Handler())) // Prometheus监控指标
+}
@@ -0,0 +1,97 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"myApp/app/apptest"
+	"myApp/config"
+	"myApp/router"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHealthAndReadiness(t *testing.T) {
+	s := apptest.New(t)
+
+	if resp := s.Do(t, http.MethodGet, "/healthz", nil, ""); resp.Status != http.StatusOK {
+		t.Fatalf("存活检查应返回200，实际为%d", resp.Status)
+	}
+
+	resp := s.Do(t, http.MethodGet, "/readyz", nil, "")
+	var checks map[string]string
+	resp.Decode(t, &checks)
+	if resp.Status != http.StatusOK || checks["database"] != "ok" || checks["redis"] != "ok" {
+		t.Fatalf("依赖正常时就绪检查应返回200，实际为%d %v", resp.Status, checks)
+	}
+
+	// Redis不可用时就绪检查失败，存活检查不受影响
+	s.Redis.Close()
+	resp = s.Do(t, http.MethodGet, "/readyz", nil, "")
+	resp.Decode(t, &checks)
+	if resp.Status != http.StatusServiceUnavailable || checks["database"] != "ok" || checks["redis"] != "unavailable" {
+		t.Fatalf("Redis不可用时就绪检查应返回503，实际为%d %v", resp.Status, checks)
+	}
+	if resp := s.Do(t, http.MethodGet, "/healthz", nil, ""); resp.Status != http.StatusOK {
+		t.Fatalf("Redis不可用时存活检查应返回200，实际为%d", resp.Status)
+	}
+}
+
+func TestHealthNotRateLimited(t *testing.T) {
+	s := apptest.NewWithConfig(t, func(cfg *config.Config) {
+		cfg.RateLimit = config.RateLimitConfig{Default: config.RateLimitPolicy{Key: "ip", Rate: 1, Period: 60, Burst: 1}}
+	})
+
+	// 健康检查按路由豁免限流，与探针的地址无关
+	for i := 0; i < 3; i++ {
+		for _, path := range []string{"/healthz", "/readyz"} {
+			resp := s.Do(t, http.MethodGet, path, nil, "")
+			if resp.Status != http.StatusOK || resp.Header.Get("X-RateLimit-Limit") != "" {
+				t.Fatalf("第%d次请求%s返回%d %v，健康检查不应被限流", i+1, path, resp.Status, resp.Header)
+			}
+		}
+	}
+
+	// 同一地址请求业务接口仍按默认规则限流
+	s.Do(t, http.MethodGet, "/api/house/list", nil, "")
+	if resp := s.Do(t, http.MethodGet, "/api/house/list", nil, ""); resp.Status != http.StatusTooManyRequests {
+		t.Fatalf("业务接口超过限流后返回%d，期望429", resp.Status)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	s := apptest.New(t)
+
+	s.Do(t, http.MethodGet, "/api/house/list", nil, "")
+	s.Do(t, http.MethodGet, "/api/house/list", nil, "")
+	s.Do(t, http.MethodPost, "/api/user/sms/code", map[string]string{"phone": "13900139000"}, "")
+
+	// 业务接口的端口不提供监控指标
+	w := httptest.NewRecorder()
+	s.Engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("业务接口的端口不应提供监控指标，实际返回%d", w.Code)
+	}
+
+	metricsRouter := gin.New()
+	router.SetupMetricsRouter(metricsRouter)
+	w = httptest.NewRecorder()
+	metricsRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("获取监控指标应返回200，实际为%d", w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`myapp_http_request_duration_seconds_count{method="GET",route="/api/house/list",status="200"}`,
+		`myapp_db_query_duration_seconds_count{operation="query",table="houses"}`,
+		`myapp_cache_requests_total{namespace="houses:list",result="hit"}`,
+		`myapp_cache_hit_ratio{namespace="houses:list"}`,
+		`myapp_sms_send_total{provider="fake",result="success"}`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("监控指标中缺少%s", want)
+		}
+	}
+}
@@ -1,38 +1,54 @@
 package router
 
 import (
+	"myApp/app"
 	"myApp/handler"
 	"myApp/middleware"
-	"myApp/repository"
-	"myApp/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 // InitHouseRouter 初始化房源相关路由
-func InitHouseRouter(r *gin.Engine) {
-	// 创建房源数据仓库实例
-	houseRepo := repository.NewHouseRepository()
-	// 创建房源服务实例，注入数据仓库依赖
-	houseService := service.NewHouseService(houseRepo)
-	// 创建房源处理器实例，注入服务依赖
-	houseHandler := handler.NewHouseHandler(houseService)
+func InitHouseRouter(r *gin.Engine, a *app.App) {
+	// 创建房源处理器实例，注入房源、评价和行政区划服务依赖
+	houseHandler := handler.NewHouseHandler(a.Services.House, a.Services.Review, a.Services.Region)
 
 	// 创建房源路由组，所有房源相关接口都在/api/house路径下
 	houseGroup := r.Group("/api/house")
 	{
 		// 公开接口，不需要认证
-		houseGroup.GET("/list", houseHandler.GetAllHouses) // 获取房源列表
-		houseGroup.GET("/:id", houseHandler.GetHouse)      // 获取房源详情
+		houseGroup.GET("/list", middleware.OptionalJWTAuth(), houseHandler.GetAllHouses)            // 获取房源列表，登录用户记录搜索条件用于推荐
+		houseGroup.GET("/trending", houseHandler.GetTrendingHouses)                                 // 获取热门房源
+		houseGroup.GET("/:id", middleware.OptionalJWTAuth(), houseHandler.GetHouse)                 // 获取房源详情，登录用户按用户统计浏览次数
+		houseGroup.GET("/:id/prices", houseHandler.GetPriceHistory)                                 // 获取房源租金走势
+		houseGroup.GET("/recommend", middleware.OptionalJWTAuth(), houseHandler.GetRecommendations) // 获取推荐房源，未登录时返回热门房源
+		houseGroup.GET("/:id/similar", houseHandler.GetSimilarHouses)                               // 获取相似房源
+		houseGroup.POST("/compare", houseHandler.CompareHouses)                                     // 对比房源
+		houseGroup.POST("/compare/export", houseHandler.ExportComparison)                           // 导出房源对比表
 
 		// 需要认证的接口，添加JWT中间件
 		authorizedGroup := houseGroup.Group("/")
 		authorizedGroup.Use(middleware.JWTAuth())
 		{
-			authorizedGroup.POST("/create", houseHandler.CreateHouse)        // 创建房源
-			authorizedGroup.PUT("/:id", houseHandler.UpdateHouse)            // 更新房源
-			authorizedGroup.DELETE("/:id", houseHandler.DeleteHouse)         // 删除房源
-			authorizedGroup.GET("/landlord", houseHandler.GetLandlordHouses) // 获取房东的所有房源
+			authorizedGroup.POST("/create", houseHandler.CreateHouse)         // 创建房源
+			authorizedGroup.PUT("/:id", houseHandler.UpdateHouse)             // 更新房源
+			authorizedGroup.DELETE("/:id", houseHandler.DeleteHouse)          // 删除房源
+			authorizedGroup.GET("/landlord", houseHandler.GetLandlordHouses)  // 获取房东的所有房源
+			authorizedGroup.POST("/:id/submit", houseHandler.SubmitHouse)     // 提交房源审核
+			authorizedGroup.POST("/:id/offline", houseHandler.OfflineHouse)   // 下架房源
+			authorizedGroup.POST("/:id/rented", houseHandler.MarkHouseRented) // 标记房源已出租
+			authorizedGroup.POST("/:id/refresh", houseHandler.RefreshHouse)   // 刷新房源上架有效期
+			authorizedGroup.GET("/:id/history", houseHandler.GetHouseHistory) // 获取房源修改记录
 		}
 	}
+
+	// 管理员房源审核路由组
+	adminGroup := r.Group("/api/admin/house")
+	adminGroup.Use(middleware.JWTAuth(), middleware.AdminAuth(a.Repos.User))
+	{
+		adminGroup.GET("/pending", houseHandler.GetPendingHouses)    // 获取待审核房源列表
+		adminGroup.PUT("/:id/approve", houseHandler.ApproveHouse)    // 审核通过房源
+		adminGroup.PUT("/:id/reject", houseHandler.RejectHouse)      // 驳回房源
+		adminGroup.GET("/:id/history", houseHandler.GetHouseHistory) // 获取房源修改记录
+	}
 }
@@ -0,0 +1,365 @@
+package router_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"myApp/app/apptest"
+	"myApp/model"
+	"myApp/model/modeltest"
+	"myApp/pkg/errors"
+	"myApp/pkg/geo"
+	"myApp/pkg/region"
+)
+
+// createHouseRequest 创建房源的请求数据
+func createHouseRequest() map[string]interface{} {
+	return map[string]interface{}{
+		"title":        "阳光花园 两室一厅",
+		"description":  "南北通透，近地铁",
+		"address":      "北京市朝阳区建国路1号",
+		"region_code":  "110105",
+		"area":         80,
+		"floor":        8,
+		"total_floor":  20,
+		"rooms":        2,
+		"halls":        1,
+		"bathrooms":    1,
+		"rent_price":   5000,
+		"payment_type": 1,
+		"house_type":   1,
+		"decoration":   2,
+		"images":       `["http://example.com/img1.jpg"]`,
+	}
+}
+
+func TestHousePublishFlow(t *testing.T) {
+	ctx := t.Context()
+	s := apptest.New(t)
+	landlordUser := s.CreateUser(t, func(u *model.User) { u.UserType = model.UserTypeLandlord })
+	admin := s.CreateUser(t, func(u *model.User) { u.UserType = model.UserTypeAdmin })
+	landlordToken := s.Token(t, landlordUser.ID)
+
+	// 未认证的房东不能发布房源
+	resp := s.Do(t, http.MethodPost, "/api/house/create", createHouseRequest(), landlordToken)
+	if resp.Status != http.StatusForbidden {
+		t.Fatalf("未认证房东发布房源应返回403，实际为%d %s", resp.Status, resp.Message)
+	}
+
+	if err := s.App.Repos.Landlord.Create(ctx, &model.Landlord{UserID: landlordUser.ID, RealName: "李四", Verified: true}); err != nil {
+		t.Fatalf("创建房东失败: %v", err)
+	}
+	resp = s.Do(t, http.MethodPost, "/api/house/create", createHouseRequest(), landlordToken)
+	if resp.Status != http.StatusOK {
+		t.Fatalf("发布房源失败: %d %s", resp.Status, resp.Message)
+	}
+	var created struct {
+		ID     uint `json:"id"`
+		Status int  `json:"status"`
+	}
+	resp.Decode(t, &created)
+	if created.Status != model.HouseStatusPending {
+		t.Fatalf("新发布的房源状态应为待审核，实际为%d", created.Status)
+	}
+	id := strconv.FormatUint(uint64(created.ID), 10)
+
+	// 审核通过前不公开
+	if resp := s.Do(t, http.MethodGet, "/api/house/"+id, nil, ""); resp.Status != http.StatusNotFound {
+		t.Fatalf("待审核房源详情应返回404，实际为%d", resp.Status)
+	}
+
+	// 普通用户不能审核房源
+	if resp := s.Do(t, http.MethodPut, "/api/admin/house/"+id+"/approve", nil, landlordToken); resp.Status != http.StatusForbidden {
+		t.Fatalf("非管理员审核房源应返回403，实际为%d", resp.Status)
+	}
+	resp = s.Do(t, http.MethodPut, "/api/admin/house/"+id+"/approve", nil, s.Token(t, admin.ID))
+	if resp.Status != http.StatusOK {
+		t.Fatalf("审核房源失败: %d %s", resp.Status, resp.Message)
+	}
+
+	resp = s.Do(t, http.MethodGet, "/api/house/"+id, nil, "")
+	if resp.Status != http.StatusOK {
+		t.Fatalf("获取已发布房源失败: %d %s", resp.Status, resp.Message)
+	}
+	var detail struct {
+		Title  string `json:"title"`
+		Status int    `json:"status"`
+	}
+	resp.Decode(t, &detail)
+	if detail.Title != "阳光花园 两室一厅" || detail.Status != model.HouseStatusPublished {
+		t.Fatalf("房源详情不正确: %+v", detail)
+	}
+
+	// 重复审核返回状态冲突，不会再次发布；强制下架后不能再审核通过
+	adminToken := s.Token(t, admin.ID)
+	if resp := s.Do(t, http.MethodPut, "/api/admin/house/"+id+"/approve", nil, adminToken); resp.Status != http.StatusConflict {
+		t.Fatalf("重复审核应返回409，实际为%d %s", resp.Status, resp.Message)
+	}
+	resp = s.Do(t, http.MethodPut, "/api/admin/house/"+id+"/reject", map[string]string{"reason": "图片违规"}, adminToken)
+	if resp.Status != http.StatusOK {
+		t.Fatalf("强制下架房源失败: %d %s", resp.Status, resp.Message)
+	}
+	if resp := s.Do(t, http.MethodPut, "/api/admin/house/"+id+"/approve", nil, adminToken); resp.Status != http.StatusConflict {
+		t.Fatalf("审核通过已驳回的房源应返回409，实际为%d %s", resp.Status, resp.Message)
+	}
+	house, err := s.App.Repos.House.GetByID(ctx, created.ID)
+	if err != nil || house.Status != model.HouseStatusRejected || house.RejectReason != "图片违规" {
+		t.Fatalf("驳回后房源为%+v, %v，期望保持驳回状态", house, err)
+	}
+}
+
+func TestFlushViewCounts(t *testing.T) {
+	ctx := t.Context()
+	s := apptest.New(t)
+	houses := make([]*model.House, 2)
+	for i := range houses {
+		houses[i] = &model.House{Title: "测试房源", Address: "北京市朝阳区建国路1号", Status: model.HouseStatusPublished}
+		if err := s.App.Repos.House.Create(ctx, houses[i]); err != nil {
+			t.Fatalf("创建房源失败: %v", err)
+		}
+	}
+	id := func(i int) string { return strconv.FormatUint(uint64(houses[i].ID), 10) }
+	viewCount := func(i int) int {
+		house, err := s.App.Repos.House.GetByID(ctx, houses[i].ID)
+		if err != nil {
+			t.Fatalf("获取房源失败: %v", err)
+		}
+		return house.ViewCount
+	}
+
+	// 上次写回中断时遗留的数据只包含尚未写回的房源
+	s.Redis.HSet("house:views:flushing", id(0), "3")
+	s.Redis.HSet("house:views:pending", id(1), "2")
+
+	// 其他实例持有锁时不写回，也不释放其他实例的锁
+	s.Redis.Set("house:views:flush:lock", "other")
+	if n, err := s.App.Services.House.FlushViewCounts(ctx); err != nil || n != 0 {
+		t.Fatalf("锁被占用时不应写回，实际为%d, %v", n, err)
+	}
+	if v, _ := s.Redis.Get("house:views:flush:lock"); v != "other" {
+		t.Fatalf("不应释放其他实例持有的锁，锁的值为%q", v)
+	}
+	s.Redis.Del("house:views:flush:lock")
+
+	// 先写回遗留的数据，再写回新的浏览次数，每次只累加一次
+	if n, err := s.App.Services.House.FlushViewCounts(ctx); err != nil || n != 1 {
+		t.Fatalf("写回遗留数据 = %d, %v，期望 1", n, err)
+	}
+	if viewCount(0) != 3 || viewCount(1) != 0 || s.Redis.Exists("house:views:flushing") {
+		t.Fatalf("写回遗留数据后浏览次数为%d、%d", viewCount(0), viewCount(1))
+	}
+	if n, err := s.App.Services.House.FlushViewCounts(ctx); err != nil || n != 1 {
+		t.Fatalf("写回新数据 = %d, %v，期望 1", n, err)
+	}
+	if n, err := s.App.Services.House.FlushViewCounts(ctx); err != nil || n != 0 {
+		t.Fatalf("没有待写回数据时 = %d, %v，期望 0", n, err)
+	}
+	if viewCount(0) != 3 || viewCount(1) != 2 {
+		t.Fatalf("写回后浏览次数为%d、%d，期望3、2", viewCount(0), viewCount(1))
+	}
+	if s.Redis.Exists("house:views:flush:lock") {
+		t.Fatal("写回结束后应释放锁")
+	}
+}
+
+func TestExportComparisonEscapesFormulas(t *testing.T) {
+	ctx := t.Context()
+	s := apptest.New(t)
+	publishedAt := time.Now().Add(-time.Hour)
+	expireAt := time.Now().Add(24 * time.Hour)
+	houses := []*model.House{
+		{Title: `=HYPERLINK("http://evil.example.com","点击")`, Address: "+86 北京市", Orientation: "@SUM(A1)"},
+		{Title: "阳光花园 两室一厅", Address: "北京市朝阳区建国路1号", Orientation: "\t南北"},
+	}
+	ids := make([]uint, len(houses))
+	for i, house := range houses {
+		house.Status = model.HouseStatusPublished
+		house.PublishedAt = &publishedAt
+		house.ExpireAt = &expireAt
+		if err := s.App.Repos.House.Create(ctx, house); err != nil {
+			t.Fatalf("创建房源失败: %v", err)
+		}
+		ids[i] = house.ID
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{"house_ids": ids})
+	req := httptest.NewRequest(http.MethodPost, "/api/house/compare/export", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.Engine.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("导出对比表失败: %d %s", w.Code, w.Body.String())
+	}
+	rows, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(w.Body.String(), "\uFEFF"))).ReadAll()
+	if err != nil {
+		t.Fatalf("解析CSV失败: %v", err)
+	}
+	cells := map[string][]string{}
+	for _, row := range rows {
+		cells[row[0]] = row[1:]
+	}
+
+	// 可能被当作公式执行的单元格以单引号开头，其他单元格保持不变
+	want := map[string][]string{
+		"标题":     {`'=HYPERLINK("http://evil.example.com","点击")`, "阳光花园 两室一厅"},
+		"地址":     {"'+86 北京市", "北京市朝阳区建国路1号"},
+		"朝向":     {"'@SUM(A1)", "'\t南北"},
+		"距离(千米)": {"'-", "'-"},
+	}
+	for label, values := range want {
+		got := cells[label]
+		if len(got) != len(values) {
+			t.Fatalf("%s列为%q，期望%q", label, got, values)
+		}
+		for i := range values {
+			if got[i] != values[i] {
+				t.Errorf("%s列为%q，期望%q", label, got, values)
+				break
+			}
+		}
+	}
+}
+
+func TestHouseListTotal(t *testing.T) {
+	ctx := t.Context()
+	s := apptest.New(t)
+	publishedAt := time.Now().Add(-time.Hour)
+	expireAt := time.Now().Add(24 * time.Hour)
+	for i, status := range []int{model.HouseStatusPublished, model.HouseStatusPublished, model.HouseStatusPublished, model.HouseStatusPending} {
+		house := &model.House{Title: "总数测试房源" + strconv.Itoa(i), Address: "北京市朝阳区建国路1号", Status: status, PublishedAt: &publishedAt, ExpireAt: &expireAt}
+		if err := s.App.Repos.House.Create(ctx, house); err != nil {
+			t.Fatalf("创建房源失败: %v", err)
+		}
+	}
+
+	// 总数为符合筛选条件的房源数，与分页无关
+	// 进程内一级缓存在测试之间共享，使用本测试独有的关键词避免读到其他测试缓存的结果
+	for _, tt := range []struct {
+		query string
+		count int
+	}{
+		{"?keyword=总数测试&limit=2", 2},
+		{"?keyword=总数测试&limit=2&offset=2", 1},
+		{"?keyword=总数测试&limit=2&offset=2&order_by=rent_price", 1},
+	} {
+		resp := s.Do(t, http.MethodGet, "/api/house/list"+tt.query, nil, "")
+		if resp.Status != http.StatusOK {
+			t.Fatalf("获取房源列表失败: %d %s", resp.Status, resp.Message)
+		}
+		var result struct {
+			Total int               `json:"total"`
+			List  []json.RawMessage `json:"list"`
+		}
+		resp.Decode(t, &result)
+		if result.Total != 3 || len(result.List) != tt.count {
+			t.Errorf("%s 返回总数%d、本页%d条，期望总数3、本页%d条", tt.query, result.Total, len(result.List), tt.count)
+		}
+	}
+}
+
+func TestHouseGeocoding(t *testing.T) {
+	ctx := t.Context()
+	s := apptest.New(t)
+	landlordUser := s.CreateUser(t, func(u *model.User) { u.UserType = model.UserTypeLandlord })
+	token := s.Token(t, landlordUser.ID)
+	if err := s.App.Repos.Landlord.Create(ctx, &model.Landlord{UserID: landlordUser.ID, RealName: "李四", Verified: true}); err != nil {
+		t.Fatalf("创建房东失败: %v", err)
+	}
+	center := func(code string) geo.Point {
+		regions, _ := region.Dataset()
+		r, _ := region.NewIndex(regions).Get(code)
+		return geo.Point{Latitude: r.Latitude, Longitude: r.Longitude}
+	}
+	location := func(id uint) geo.Point {
+		house, err := s.App.Repos.House.GetByID(ctx, id)
+		if err != nil {
+			t.Fatalf("获取房源失败: %v", err)
+		}
+		return geo.Point{Latitude: house.Latitude, Longitude: house.Longitude}
+	}
+
+	// 未填写坐标时按地址所在区县的中心点定位
+	resp := s.Do(t, http.MethodPost, "/api/house/create", createHouseRequest(), token)
+	if resp.Status != http.StatusOK {
+		t.Fatalf("发布房源失败: %d %s", resp.Status, resp.Message)
+	}
+	var created struct {
+		ID uint `json:"id"`
+	}
+	resp.Decode(t, &created)
+	if got, want := location(created.ID), center("110105"); got != want {
+		t.Fatalf("房源坐标为%+v，期望朝阳区中心点%+v", got, want)
+	}
+
+	// 只修改地址和所在区域时重新解析坐标
+	id := strconv.FormatUint(uint64(created.ID), 10)
+	resp = s.Do(t, http.MethodPut, "/api/house/"+id, map[string]interface{}{"address": "北京市海淀区中关村大街1号", "region_code": "110108"}, token)
+	if resp.Status != http.StatusOK {
+		t.Fatalf("修改房源失败: %d %s", resp.Status, resp.Message)
+	}
+	if got, want := location(created.ID), center("110108"); got != want {
+		t.Fatalf("修改地址后房源坐标为%+v，期望海淀区中心点%+v", got, want)
+	}
+
+	// 填写的坐标不在所在区域范围内时拒绝保存
+	req := createHouseRequest()
+	req["latitude"], req["longitude"] = 31.2304, 121.4737
+	if resp := s.Do(t, http.MethodPost, "/api/house/create", req, token); resp.Status != http.StatusBadRequest {
+		t.Fatalf("坐标不在所在区域内应返回400，实际为%d %s", resp.Status, resp.Message)
+	}
+}
+
+func TestRefreshExpiredHouse(t *testing.T) {
+	ctx := t.Context()
+	s := apptest.New(t)
+	landlordUser := s.CreateUser(t, func(u *model.User) { u.UserType = model.UserTypeLandlord })
+	publishedAt := time.Now().Add(-60 * 24 * time.Hour)
+	expireAt := time.Now().Add(-30 * 24 * time.Hour)
+	house := modeltest.Create(t, s.App.DB, modeltest.House(), func(h *model.House) {
+		h.LandlordID = landlordUser.ID
+		h.PublishedAt = &publishedAt
+		h.ExpireAt = &expireAt
+	})
+	// 已下架的状态值为零值，创建时会被字段默认值覆盖，需单独更新
+	if err := s.App.Repos.House.UpdateColumns(ctx, house.ID, map[string]interface{}{"status": model.HouseStatusOffline}); err != nil {
+		t.Fatalf("下架房源失败: %v", err)
+	}
+
+	// 到期下架后重新上架视为新房源，发布时间更新并加入新房源提醒队列
+	resp := s.Do(t, http.MethodPost, "/api/house/"+strconv.FormatUint(uint64(house.ID), 10)+"/refresh", nil, s.Token(t, landlordUser.ID))
+	if resp.Status != http.StatusOK {
+		t.Fatalf("刷新房源失败: %d %s", resp.Status, resp.Message)
+	}
+	got, err := s.App.Repos.House.GetByID(ctx, house.ID)
+	if err != nil || got.Status != model.HouseStatusPublished || got.PublishedAt == nil || !got.PublishedAt.After(publishedAt) {
+		t.Fatalf("重新上架后房源为%+v, %v，期望已发布且发布时间更新", got, err)
+	}
+	if queued, err := s.Redis.List("saved_search:alerts:pending"); err != nil || len(queued) != 1 {
+		t.Fatalf("提醒队列为%v, %v，期望有重新上架的房源", queued, err)
+	}
+}
+
+func TestGetHouseErrors(t *testing.T) {
+	s := apptest.New(t)
+
+	resp := s.Do(t, http.MethodGet, "/api/house/999", nil, "")
+	if resp.Status != http.StatusNotFound || resp.Code != int(errors.CodeNotFound) || resp.Message != "房源不存在" {
+		t.Fatalf("房源不存在应返回404，实际为%d %d %s", resp.Status, resp.Code, resp.Message)
+	}
+
+	// 数据库错误按服务器内部错误返回，不能当作房源不存在；换一个ID避开房源不存在的缓存
+	if err := s.App.DB.Migrator().DropTable(&model.House{}); err != nil {
+		t.Fatalf("删除房源表失败: %v", err)
+	}
+	resp = s.Do(t, http.MethodGet, "/api/house/998", nil, "")
+	if resp.Status != http.StatusInternalServerError || resp.Code != int(errors.CodeInternal) {
+		t.Fatalf("数据库错误应返回500，实际为%d %d %s", resp.Status, resp.Code, resp.Message)
+	}
+}
@@ -1,34 +1,39 @@
 package router
 
 import (
+	"myApp/app"
 	"myApp/handler"
 	"myApp/middleware"
-	"myApp/repository"
-	"myApp/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 // InitLandlordRouter 初始化房东相关路由
-func InitLandlordRouter(r *gin.Engine) {
-	// 创建房东数据仓库实例
-	landlordRepo := repository.NewLandlordRepository()
-	// 创建用户数据仓库实例
-	userRepo := repository.NewUserRepository()
+func InitLandlordRouter(r *gin.Engine, a *app.App) {
+	// 创建房东处理器实例，注入房东服务，以及用于房东主页展示在租房源和评价的房源、评价服务
+	landlordHandler := handler.NewLandlordHandler(a.Services.Landlord, a.Services.House, a.Services.Review)
 
-	// 创建房东服务实例，注入数据仓库依赖
-	landlordService := service.NewLandlordService(landlordRepo, userRepo)
-
-	// 创建房东处理器实例，注入服务依赖
-	landlordHandler := handler.NewLandlordHandler(landlordService)
+	// 房东公开主页，不需要认证
+	r.GET("/api/landlord/:id", landlordHandler.GetPublicProfile)
 
 	// 创建房东路由组，所有房东相关接口都在/api/landlord路径下
 	landlordGroup := r.Group("/api/landlord")
 	// 所有房东接口都需要认证，添加JWT中间件
 	landlordGroup.Use(middleware.JWTAuth())
 	{
-		landlordGroup.POST("/create", landlordHandler.CreateLandlord)    // 申请成为房东
-		landlordGroup.GET("/profile", landlordHandler.GetLandlordProfile) // 获取房东个人资料
-		landlordGroup.PUT("/profile", landlordHandler.UpdateLandlord)     // 更新房东信息
+		landlordGroup.POST("/create", landlordHandler.CreateLandlord)           // 申请成为房东
+		landlordGroup.GET("/profile", landlordHandler.GetLandlordProfile)       // 获取房东个人资料
+		landlordGroup.PUT("/profile", landlordHandler.UpdateLandlord)           // 更新房东信息
+		landlordGroup.POST("/verification", landlordHandler.SubmitVerification) // 提交认证申请
+		landlordGroup.GET("/verification", landlordHandler.GetVerification)     // 查询认证申请状态
+	}
+
+	// 创建房东管理路由组，仅管理员可访问
+	adminGroup := r.Group("/api/admin/landlord")
+	adminGroup.Use(middleware.JWTAuth(), middleware.AdminAuth(a.Repos.User))
+	{
+		adminGroup.GET("/verifications", landlordHandler.GetVerificationQueue)            // 获取认证申请审核队列
+		adminGroup.PUT("/verifications/:id/approve", landlordHandler.ApproveVerification) // 审核通过认证申请
+		adminGroup.PUT("/verifications/:id/reject", landlordHandler.RejectVerification)   // 驳回认证申请
 	}
 }
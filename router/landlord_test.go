@@ -0,0 +1,60 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	"myApp/app/apptest"
+	"myApp/model"
+	"myApp/pkg/errors"
+)
+
+func TestSubmitVerificationErrors(t *testing.T) {
+	s := apptest.New(t)
+	user := s.CreateUser(t, nil)
+	token := s.Token(t, user.ID)
+	if err := s.App.Repos.Landlord.Create(t.Context(), &model.Landlord{UserID: user.ID}); err != nil {
+		t.Fatalf("创建房东失败: %v", err)
+	}
+	submit := func(idNumber string) *apptest.Response {
+		return s.Do(t, http.MethodPost, "/api/landlord/verification", map[string]string{
+			"real_name":     "张三",
+			"id_number":     idNumber,
+			"id_card_front": "http://example.com/front.jpg",
+			"id_card_back":  "http://example.com/back.jpg",
+		}, token)
+	}
+
+	// 身份证号校验失败属于参数错误
+	resp := submit("110105194912310021")
+	if resp.Status != http.StatusBadRequest || resp.Code != int(errors.CodeValidation) || resp.Message != "身份证号校验码错误" {
+		t.Fatalf("身份证号无效应返回400，实际为%d %d %s", resp.Status, resp.Code, resp.Message)
+	}
+
+	// 数据库错误按服务器内部错误返回，不暴露错误详情
+	if err := s.App.DB.Migrator().DropTable(&model.LandlordVerification{}); err != nil {
+		t.Fatalf("删除认证申请表失败: %v", err)
+	}
+	resp = submit("11010519491231002X")
+	if resp.Status != http.StatusInternalServerError || resp.Code != int(errors.CodeInternal) || resp.Message != "服务器内部错误" {
+		t.Fatalf("数据库错误应返回500，实际为%d %d %s", resp.Status, resp.Code, resp.Message)
+	}
+}
+
+func TestGetPublicProfileErrors(t *testing.T) {
+	s := apptest.New(t)
+
+	resp := s.Do(t, http.MethodGet, "/api/landlord/999", nil, "")
+	if resp.Status != http.StatusNotFound || resp.Code != int(errors.CodeNotFound) || resp.Message != "房东不存在" {
+		t.Fatalf("房东不存在应返回404，实际为%d %d %s", resp.Status, resp.Code, resp.Message)
+	}
+
+	// 数据库错误按服务器内部错误返回，不能当作房东不存在
+	if err := s.App.DB.Migrator().DropTable(&model.Landlord{}); err != nil {
+		t.Fatalf("删除房东表失败: %v", err)
+	}
+	resp = s.Do(t, http.MethodGet, "/api/landlord/999", nil, "")
+	if resp.Status != http.StatusInternalServerError || resp.Code != int(errors.CodeInternal) {
+		t.Fatalf("数据库错误应返回500，实际为%d %d %s", resp.Status, resp.Code, resp.Message)
+	}
+}
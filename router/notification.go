@@ -0,0 +1,25 @@
+package router
+
+import (
+	"myApp/app"
+	"myApp/handler"
+	"myApp/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitNotificationRouter 初始化站内通知相关路由
+func InitNotificationRouter(r *gin.Engine, a *app.App) {
+	// 创建通知处理器实例，注入服务依赖
+	notificationHandler := handler.NewNotificationHandler(a.Services.Notification)
+
+	// 创建通知路由组，所有通知接口都需要认证
+	notificationGroup := r.Group("/api/notification")
+	notificationGroup.Use(middleware.JWTAuth())
+	{
+		notificationGroup.GET("/list", notificationHandler.GetNotifications)       // 获取通知列表
+		notificationGroup.GET("/unread-count", notificationHandler.GetUnreadCount) // 获取未读通知数量
+		notificationGroup.PUT("/:id/read", notificationHandler.MarkRead)           // 标记通知已读
+		notificationGroup.PUT("/read-all", notificationHandler.MarkAllRead)        // 全部标记已读
+	}
+}
@@ -0,0 +1,139 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"myApp/app/apptest"
+	"myApp/config"
+	"myApp/pkg/errors"
+)
+
+func TestRateLimit(t *testing.T) {
+	s := apptest.NewWithConfig(t, func(cfg *config.Config) {
+		cfg.RateLimit = config.RateLimitConfig{
+			Default: config.RateLimitPolicy{Key: "ip", Rate: 100, Period: 1},
+			Routes: []config.RateLimitPolicy{
+				{Method: http.MethodPost, Path: "/api/user/login", Key: "ip", Rate: 2, Period: 60, Burst: 2},
+			},
+		}
+	})
+	login := map[string]string{"username": "nobody", "password": "password123"}
+
+	// 突发额度内的请求正常处理，并返回限流响应头
+	resp := s.Do(t, http.MethodPost, "/api/user/login", login, "")
+	if resp.Status != http.StatusUnauthorized {
+		t.Fatalf("未超过限流时应正常处理请求，实际为%d %s", resp.Status, resp.Message)
+	}
+	if resp.Header.Get("X-RateLimit-Limit") != "2" || resp.Header.Get("X-RateLimit-Remaining") != "1" {
+		t.Fatalf("限流响应头不正确: %v", resp.Header)
+	}
+	s.Do(t, http.MethodPost, "/api/user/login", login, "")
+
+	// 超过接口规则的突发额度后拒绝请求
+	resp = s.Do(t, http.MethodPost, "/api/user/login", login, "")
+	if resp.Status != http.StatusTooManyRequests || resp.Code != int(errors.CodeRateLimited) {
+		t.Fatalf("超过限流时应返回429，实际为%d %d", resp.Status, resp.Code)
+	}
+	if resp.Header.Get("Retry-After") != "30" || resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		t.Fatalf("被限流时的响应头不正确: %v", resp.Header)
+	}
+
+	// 其他接口使用默认规则，不受登录接口限流影响
+	resp = s.Do(t, http.MethodGet, "/api/house/list", nil, "")
+	if resp.Status != http.StatusOK || resp.Header.Get("X-RateLimit-Limit") != "100" {
+		t.Fatalf("其他接口应按默认规则限流，实际为%d %v", resp.Status, resp.Header)
+	}
+}
+
+func TestRateLimitUsesRedisTime(t *testing.T) {
+	s := apptest.NewWithConfig(t, func(cfg *config.Config) {
+		cfg.RateLimit = config.RateLimitConfig{
+			Default: config.RateLimitPolicy{Key: "ip", Rate: 1, Period: 60, Burst: 1},
+		}
+	})
+	now := time.Now()
+	s.Redis.SetTime(now)
+
+	s.Do(t, http.MethodGet, "/api/house/list", nil, "")
+	resp := s.Do(t, http.MethodGet, "/api/house/list", nil, "")
+	if resp.Status != http.StatusTooManyRequests {
+		t.Fatalf("超过限流时应返回429，实际为%d", resp.Status)
+	}
+
+	// 额度按Redis服务器时间恢复，与应用服务器的时钟无关
+	s.Redis.SetTime(now.Add(time.Minute))
+	resp = s.Do(t, http.MethodGet, "/api/house/list", nil, "")
+	if resp.Status != http.StatusOK {
+		t.Fatalf("Redis时间经过一个周期后应恢复额度，实际为%d", resp.Status)
+	}
+}
+
+func TestRateLimitAllowlist(t *testing.T) {
+	s := apptest.NewWithConfig(t, func(cfg *config.Config) {
+		cfg.RateLimit = config.RateLimitConfig{
+			Default: config.RateLimitPolicy{Key: "ip", Rate: 1, Period: 60, Burst: 1},
+			// httptest请求的客户端地址为192.0.2.1
+			Allowlist: []string{"192.0.2.0/24"},
+		}
+	})
+
+	for i := 0; i < 3; i++ {
+		resp := s.Do(t, http.MethodGet, "/api/house/list", nil, "")
+		if resp.Status != http.StatusOK {
+			t.Fatalf("白名单中的IP不应被限流，第%d次请求返回%d", i+1, resp.Status)
+		}
+		if resp.Header.Get("X-RateLimit-Limit") != "" {
+			t.Fatalf("白名单中的IP不应返回限流响应头: %v", resp.Header)
+		}
+	}
+}
+
+func TestRateLimitForwardedFor(t *testing.T) {
+	policy := config.RateLimitConfig{
+		Default:   config.RateLimitPolicy{Key: "ip", Rate: 1, Period: 60, Burst: 1},
+		Allowlist: []string{"203.0.113.0/24"},
+	}
+	// httptest请求的客户端地址为192.0.2.1，forwardedFor为空时不携带X-Forwarded-For
+	send := func(s *apptest.Server, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/house/list", nil)
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		w := httptest.NewRecorder()
+		s.Engine.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 未配置信任的代理时忽略X-Forwarded-For：伪造白名单中的地址不能绕过限流，每次更换伪造的地址也不能获得新的额度
+	s := apptest.NewWithConfig(t, func(cfg *config.Config) { cfg.RateLimit = policy })
+	if code := send(s, "203.0.113.7"); code != http.StatusOK {
+		t.Fatalf("第一次请求返回%d，期望200", code)
+	}
+	for _, forwardedFor := range []string{"203.0.113.7", "198.51.100.1", "198.51.100.2, 203.0.113.7"} {
+		if code := send(s, forwardedFor); code != http.StatusTooManyRequests {
+			t.Fatalf("伪造X-Forwarded-For: %s的请求返回%d，期望按连接地址限流返回429", forwardedFor, code)
+		}
+	}
+
+	// 请求来自信任的代理时按X-Forwarded-For取客户端IP
+	s = apptest.NewWithConfig(t, func(cfg *config.Config) {
+		cfg.RateLimit = policy
+		cfg.Server.TrustedProxies = []string{"192.0.2.0/24"}
+	})
+	for i := 0; i < 3; i++ {
+		if code := send(s, "203.0.113.7"); code != http.StatusOK {
+			t.Fatalf("经信任的代理转发的白名单IP不应被限流，第%d次请求返回%d", i+1, code)
+		}
+	}
+	for _, forwardedFor := range []string{"198.51.100.1", "198.51.100.2"} {
+		if code := send(s, forwardedFor); code != http.StatusOK {
+			t.Fatalf("经信任的代理转发的不同客户端应分别限流，%s的请求返回%d", forwardedFor, code)
+		}
+	}
+	if code := send(s, "198.51.100.1"); code != http.StatusTooManyRequests {
+		t.Fatalf("同一客户端超过限流后返回%d，期望429", code)
+	}
+}
@@ -0,0 +1,21 @@
+package router
+
+import (
+	"myApp/app"
+	"myApp/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitRegionRouter 初始化行政区划相关路由
+func InitRegionRouter(r *gin.Engine, a *app.App) {
+	// 创建行政区划处理器实例，注入服务依赖
+	regionHandler := handler.NewRegionHandler(a.Services.Region)
+
+	// 创建行政区划路由组，公开接口，不需要认证
+	regionGroup := r.Group("/api/region")
+	{
+		regionGroup.GET("/list", regionHandler.GetChildren) // 获取下级行政区划
+		regionGroup.GET("/:code", regionHandler.GetPath)    // 获取区划的完整路径
+	}
+}
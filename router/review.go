@@ -0,0 +1,40 @@
+package router
+
+import (
+	"myApp/app"
+	"myApp/handler"
+	"myApp/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitReviewRouter 初始化评价相关路由
+func InitReviewRouter(r *gin.Engine, a *app.App) {
+	// 创建评价处理器实例，注入服务依赖
+	reviewHandler := handler.NewReviewHandler(a.Services.Review)
+
+	// 创建评价路由组，所有评价相关接口都在/api/review路径下
+	reviewGroup := r.Group("/api/review")
+	{
+		// 公开接口，不需要认证
+		reviewGroup.GET("/house/:house_id", reviewHandler.GetHouseReviews)          // 获取房源评价列表
+		reviewGroup.GET("/landlord/:landlord_id", reviewHandler.GetLandlordReviews) // 获取房东评价列表
+
+		// 需要认证的接口，添加JWT中间件
+		authorizedGroup := reviewGroup.Group("/")
+		authorizedGroup.Use(middleware.JWTAuth())
+		{
+			authorizedGroup.POST("/create", reviewHandler.CreateReview)     // 发表评价
+			authorizedGroup.PUT("/:id/reply", reviewHandler.ReplyReview)    // 房东回复评价
+			authorizedGroup.POST("/:id/report", reviewHandler.ReportReview) // 举报评价
+		}
+	}
+
+	// 创建评价管理路由组，仅管理员可访问
+	adminGroup := r.Group("/api/admin/review")
+	adminGroup.Use(middleware.JWTAuth(), middleware.AdminAuth(a.Repos.User))
+	{
+		adminGroup.GET("/reports", reviewHandler.GetReports)       // 获取举报列表
+		adminGroup.PUT("/reports/:id", reviewHandler.HandleReport) // 处理举报
+	}
+}
@@ -0,0 +1,50 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"myApp/app/apptest"
+	"myApp/model"
+	"myApp/model/modeltest"
+	"myApp/pkg/errors"
+)
+
+func TestCreateReviewRequiresViewing(t *testing.T) {
+	s := apptest.New(t)
+	token := s.Token(t, s.CreateUser(t, nil).ID)
+
+	// 只能凭已完成的看房预约评价，缺少看房预约时返回参数错误
+	resp := s.Do(t, http.MethodPost, "/api/review/create", map[string]interface{}{
+		"landlord_rating": 5,
+		"house_rating":    4,
+	}, token)
+	if resp.Status != http.StatusBadRequest || resp.Code != int(errors.CodeValidation) {
+		t.Fatalf("缺少看房预约应返回400，实际为%d %d %s", resp.Status, resp.Code, resp.Message)
+	}
+}
+
+func TestCreateReviewRejectsOwnHouse(t *testing.T) {
+	s := apptest.New(t)
+	user := s.CreateUser(t, nil)
+	token := s.Token(t, user.ID)
+	modeltest.Create(t, s.App.DB, &model.Landlord{UserID: user.ID, RealName: "张三", Verified: true}, nil)
+	house := modeltest.Create(t, s.App.DB, modeltest.House(), func(h *model.House) { h.LandlordID = user.ID })
+	viewing := modeltest.Create(t, s.App.DB, &model.Viewing{
+		HouseID:     house.ID,
+		UserID:      user.ID,
+		ViewingTime: time.Now().Add(-time.Hour),
+		Status:      model.ViewingCompleted,
+	}, nil)
+
+	// 房东预约并完成了自己房源的看房，也不能评价自己的房源
+	resp := s.Do(t, http.MethodPost, "/api/review/create", map[string]interface{}{
+		"viewing_id":      viewing.ID,
+		"landlord_rating": 5,
+		"house_rating":    5,
+	}, token)
+	if resp.Status != http.StatusForbidden || resp.Code != int(errors.CodeForbidden) || resp.Message != "不能评价自己的房源" {
+		t.Fatalf("评价自己的房源应返回403，实际为%d %d %s", resp.Status, resp.Code, resp.Message)
+	}
+}
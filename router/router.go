@@ -1,23 +1,38 @@
 package router
 
 import (
+	"myApp/app"
 	"myApp/middleware"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// SetupRouter 设置所有路由和中间件
-func SetupRouter(r *gin.Engine) {
+// SetupRouter 设置所有路由和中间件，各路由共用应用容器中的服务实例
+func SetupRouter(r *gin.Engine, a *app.App) {
 
 	// 加载全局中间件
-	r.Use(middleware.CORS())        // 跨域资源共享中间件
-	r.Use(middleware.Logger())      // 结构化日志记录中间件
-	r.Use(middleware.RateLimiter()) // 请求速率限制中间件
+	r.Use(middleware.Metrics())           // 请求监控中间件，按路由统计请求耗时和状态码
+	r.Use(middleware.CORS(a.Config.CORS)) // 跨域资源共享中间件
+	r.Use(middleware.Logger())            // 结构化日志记录中间件
+	r.Use(middleware.ErrorHandler())      // 错误处理中间件，将处理器记录的错误统一转换为响应
+
+	// 请求限流中间件，按配置的规则在Redis中限流，探针调用的健康检查接口不限流
+	r.Use(middleware.RateLimiter(a.Config.RateLimit, healthRoutes...))
+
+	// 请求超时中间件，超时后取消请求中的数据库和Redis操作
+	r.Use(middleware.Timeout(time.Duration(a.Config.Server.RequestTimeout) * time.Second))
 
 	// 初始化子路由
-	InitUserRouter(r)     // 初始化用户相关路由
-	InitHouseRouter(r)    // 初始化房源相关路由
-	InitViewingRouter(r)  // 初始化预约看房相关路由
-	InitFavoriteRouter(r) // 初始化收藏相关路由
-	InitLandlordRouter(r) // 初始化房东相关路由
+	InitUserRouter(r, a)         // 初始化用户相关路由
+	InitHouseRouter(r, a)        // 初始化房源相关路由
+	InitViewingRouter(r, a)      // 初始化预约看房相关路由
+	InitFavoriteRouter(r, a)     // 初始化收藏相关路由
+	InitLandlordRouter(r, a)     // 初始化房东相关路由
+	InitReviewRouter(r, a)       // 初始化评价相关路由
+	InitNotificationRouter(r, a) // 初始化站内通知相关路由
+	InitSavedSearchRouter(r, a)  // 初始化保存的搜索相关路由
+	InitRegionRouter(r, a)       // 初始化行政区划相关路由
+	InitAdminRouter(r, a)        // 初始化管理员运维相关路由
+	InitHealthRouter(r, a)       // 初始化健康检查路由
 }
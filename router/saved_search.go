@@ -0,0 +1,27 @@
+package router
+
+import (
+	"myApp/app"
+	"myApp/handler"
+	"myApp/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitSavedSearchRouter 初始化保存的搜索相关路由
+func InitSavedSearchRouter(r *gin.Engine, a *app.App) {
+	// 创建保存的搜索处理器实例，注入服务依赖
+	savedSearchHandler := handler.NewSavedSearchHandler(a.Services.SavedSearch)
+
+	// 创建保存的搜索路由组，所有接口都需要认证
+	savedSearchGroup := r.Group("/api/saved-search")
+	savedSearchGroup.Use(middleware.JWTAuth())
+	{
+		savedSearchGroup.POST("/create", savedSearchHandler.CreateSavedSearch) // 保存搜索条件
+		savedSearchGroup.GET("/list", savedSearchHandler.GetSavedSearches)     // 获取保存的搜索及新房源数量
+		savedSearchGroup.PUT("/:id", savedSearchHandler.UpdateSavedSearch)     // 修改保存的搜索
+		savedSearchGroup.DELETE("/:id", savedSearchHandler.DeleteSavedSearch)  // 删除保存的搜索
+		savedSearchGroup.GET("/:id/matches", savedSearchHandler.GetMatches)    // 获取匹配的房源
+		savedSearchGroup.PUT("/:id/checked", savedSearchHandler.MarkChecked)   // 标记已查看，新房源数量清零
+	}
+}
@@ -0,0 +1,111 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"myApp/app/apptest"
+	"myApp/config"
+	"myApp/model"
+)
+
+func TestSavedSearchAlerts(t *testing.T) {
+	ctx := t.Context()
+	s := apptest.New(t)
+	optedOut := s.CreateUser(t, nil)
+	subscriber := s.CreateUser(t, nil)
+
+	// 未指定时默认开启站内提醒，明确关闭时保存为关闭
+	create := func(userID uint, body map[string]interface{}) bool {
+		resp := s.Do(t, http.MethodPost, "/api/saved-search/create", body, s.Token(t, userID))
+		if resp.Status != http.StatusOK {
+			t.Fatalf("保存搜索失败: %d %s", resp.Status, resp.Message)
+		}
+		var created struct {
+			NotifyInApp bool `json:"notify_in_app"`
+		}
+		resp.Decode(t, &created)
+		return created.NotifyInApp
+	}
+	filters := map[string]interface{}{"house_type": 1, "max_price": 6000}
+	if create(optedOut.ID, map[string]interface{}{"name": "两居", "filters": filters, "notify_in_app": false}) {
+		t.Fatal("关闭站内提醒的设置未生效")
+	}
+	if !create(subscriber.ID, map[string]interface{}{"name": "两居", "filters": filters}) {
+		t.Fatal("未指定时应默认开启站内提醒")
+	}
+
+	// 多套新房源合并匹配，只有匹配且开启提醒的用户收到通知
+	houses := []*model.House{
+		{Title: "匹配的房源", Address: "北京市朝阳区建国路1号", HouseType: 1, RentPrice: 5000, Status: model.HouseStatusPublished},
+		{Title: "租金过高的房源", Address: "北京市朝阳区建国路2号", HouseType: 1, RentPrice: 8000, Status: model.HouseStatusPublished},
+	}
+	for _, house := range houses {
+		if err := s.App.Repos.House.Create(ctx, house); err != nil {
+			t.Fatalf("创建房源失败: %v", err)
+		}
+		s.App.Services.SavedSearch.NotifyNewHouse(ctx, house)
+	}
+
+	// 新房源先保存在Redis队列中，服务重启不会丢失
+	if queued, err := s.Redis.List("saved_search:alerts:pending"); err != nil || len(queued) != len(houses) {
+		t.Fatalf("提醒队列为%v, %v，期望有%d套房源", queued, err, len(houses))
+	}
+	if _, err := s.App.Services.SavedSearch.ProcessAlerts(ctx); err != nil {
+		t.Fatalf("处理提醒队列失败: %v", err)
+	}
+
+	var notifications []model.Notification
+	deadline := time.Now().Add(2 * time.Second)
+	for len(notifications) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		notifications, _, _ = s.App.Services.Notification.GetUserNotifications(ctx, subscriber.ID, map[string]interface{}{})
+	}
+	if len(notifications) != 1 || notifications[0].RelatedID != houses[0].ID {
+		t.Fatalf("订阅用户应只收到匹配房源的通知，实际为%+v", notifications)
+	}
+	if got, _, _ := s.App.Services.Notification.GetUserNotifications(ctx, optedOut.ID, map[string]interface{}{}); len(got) != 0 {
+		t.Fatalf("关闭站内提醒的用户不应收到通知，实际收到%d条", len(got))
+	}
+}
+
+func TestSavedSearchAlertChannels(t *testing.T) {
+	ctx := t.Context()
+	s := apptest.NewWithConfig(t, func(cfg *config.Config) { cfg.SMS.SearchAlertTemplate = "SMS_SEARCH_ALERT" })
+	user := s.CreateUser(t, func(u *model.User) { u.Phone = "13900000001" })
+	token := s.Token(t, user.ID)
+
+	// 同一用户的两个搜索分别只开启站内通知和只开启短信，匹配同一房源时两种提醒都要发送
+	filters := map[string]interface{}{"house_type": 1}
+	for _, body := range []map[string]interface{}{
+		{"name": "站内", "filters": filters, "notify_in_app": true, "notify_sms": false},
+		{"name": "短信", "filters": filters, "notify_in_app": false, "notify_sms": true},
+	} {
+		if resp := s.Do(t, http.MethodPost, "/api/saved-search/create", body, token); resp.Status != http.StatusOK {
+			t.Fatalf("保存搜索失败: %d %s", resp.Status, resp.Message)
+		}
+	}
+
+	house := &model.House{Title: "新房源", Address: "北京市朝阳区建国路1号", HouseType: 1, RentPrice: 5000, Status: model.HouseStatusPublished}
+	if err := s.App.Repos.House.Create(ctx, house); err != nil {
+		t.Fatalf("创建房源失败: %v", err)
+	}
+	s.App.Services.SavedSearch.NotifyNewHouse(ctx, house)
+
+	var notifications []model.Notification
+	deadline := time.Now().Add(2 * time.Second)
+	for (len(notifications) == 0 || len(s.SMS.Sent()) == 0) && time.Now().Before(deadline) {
+		if _, err := s.App.Services.SavedSearch.ProcessAlerts(ctx); err != nil {
+			t.Fatalf("处理提醒队列失败: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+		notifications, _, _ = s.App.Services.Notification.GetUserNotifications(ctx, user.ID, map[string]interface{}{})
+	}
+	if len(notifications) != 1 {
+		t.Fatalf("应收到1条站内通知，实际为%+v", notifications)
+	}
+	if sent := s.SMS.Sent(); len(sent) != 1 || sent[0].Phones[0] != user.Phone || sent[0].TemplateCode != "SMS_SEARCH_ALERT" {
+		t.Fatalf("应收到1条新房源短信，实际为%+v", sent)
+	}
+}
@@ -1,25 +1,19 @@
 package router
 
 import (
+	"myApp/app"
 	"myApp/handler"
 	"myApp/middleware"
-	"myApp/repository"
-	"myApp/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 // InitUserRouter 初始化用户相关路由
-func InitUserRouter(r *gin.Engine) {
-	// 创建用户数据仓库实例
-	userRepo := repository.NewUserRepository()
-	// 创建用户服务实例，注入数据仓库依赖
-	userService := service.NewUserService(userRepo)
+func InitUserRouter(r *gin.Engine, a *app.App) {
 	// 创建用户处理器实例，注入服务依赖
-	userHandler := handler.NewUserHandler(userService)
-
-	// 创建短信验证码处理器实例
-	smsCodeHandler := handler.NewSMSCodeHandler()
+	userHandler := handler.NewUserHandler(a.Services.User)
+	// 创建短信验证码处理器实例，注入短信验证码服务依赖
+	smsCodeHandler := handler.NewSMSCodeHandler(a.Services.SMSCode)
 
 	// 创建用户路由组，所有用户相关接口都在/api/user路径下
 	userGroup := r.Group("/api/user")
@@ -0,0 +1,114 @@
+package router_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"myApp/app/apptest"
+	"myApp/service"
+)
+
+func TestRegisterLoginAndGetUserInfo(t *testing.T) {
+	s := apptest.New(t)
+
+	resp := s.Do(t, http.MethodPost, "/api/user/register", map[string]string{
+		"username": "zhangsan",
+		"password": "password123",
+		"phone":    "13800138000",
+		"email":    "zhangsan@example.com",
+	}, "")
+	if resp.Status != http.StatusOK {
+		t.Fatalf("注册失败: %d %s", resp.Status, resp.Message)
+	}
+
+	resp = s.Do(t, http.MethodPost, "/api/user/login", map[string]string{"username": "zhangsan", "password": "wrong-password"}, "")
+	if resp.Status != http.StatusUnauthorized {
+		t.Fatalf("密码错误时应返回401，实际为%d", resp.Status)
+	}
+
+	resp = s.Do(t, http.MethodPost, "/api/user/login", map[string]string{"username": "zhangsan", "password": "password123"}, "")
+	if resp.Status != http.StatusOK {
+		t.Fatalf("登录失败: %d %s", resp.Status, resp.Message)
+	}
+	var login struct {
+		Token string `json:"token"`
+	}
+	resp.Decode(t, &login)
+	if login.Token == "" {
+		t.Fatal("登录响应缺少令牌")
+	}
+
+	if resp := s.Do(t, http.MethodGet, "/api/user/info", nil, ""); resp.Status != http.StatusUnauthorized {
+		t.Fatalf("未携带令牌时应返回401，实际为%d", resp.Status)
+	}
+	resp = s.Do(t, http.MethodGet, "/api/user/info", nil, login.Token)
+	var info struct {
+		Username string `json:"username"`
+		Phone    string `json:"phone"`
+	}
+	resp.Decode(t, &info)
+	if info.Username != "zhangsan" || info.Phone != "13800138000" {
+		t.Fatalf("用户信息不正确: %+v", info)
+	}
+}
+
+func TestSMSCodeLogin(t *testing.T) {
+	s := apptest.New(t)
+	phone := "13900139000"
+
+	resp := s.Do(t, http.MethodPost, "/api/user/sms/code", map[string]string{"phone": phone}, "")
+	if resp.Status != http.StatusOK {
+		t.Fatalf("发送验证码失败: %d %s", resp.Status, resp.Message)
+	}
+	code := s.SMS.LastCode(phone)
+	if code == "" {
+		t.Fatal("假短信服务提供商未收到验证码短信")
+	}
+	if stored, _ := s.Redis.Get(service.SMSCodePrefix + phone); stored != code {
+		t.Fatalf("Redis中的验证码为%q，期望%q", stored, code)
+	}
+
+	// 有效期内重复请求不重新发送
+	s.Do(t, http.MethodPost, "/api/user/sms/code", map[string]string{"phone": phone}, "")
+	if n := len(s.SMS.Sent()); n != 1 {
+		t.Fatalf("有效期内重复请求发送了%d条短信", n)
+	}
+
+	resp = s.Do(t, http.MethodPost, "/api/user/sms/login", map[string]string{"phone": phone, "code": code}, "")
+	if resp.Status != http.StatusOK {
+		t.Fatalf("验证码登录失败: %d %s", resp.Status, resp.Message)
+	}
+	var login struct {
+		Token string `json:"token"`
+		User  struct {
+			Phone string `json:"phone"`
+		} `json:"user"`
+	}
+	resp.Decode(t, &login)
+	if login.Token == "" || login.User.Phone != phone {
+		t.Fatalf("验证码登录响应不正确: %+v", login)
+	}
+
+	// 验证码只能使用一次
+	resp = s.Do(t, http.MethodPost, "/api/user/sms/login", map[string]string{"phone": phone, "code": code}, "")
+	if resp.Status != http.StatusUnauthorized {
+		t.Fatalf("重复使用验证码应返回401，实际为%d", resp.Status)
+	}
+}
+
+func TestSMSCodeSendFailure(t *testing.T) {
+	s := apptest.New(t)
+	s.SMS.Err = errors.New("模拟短信发送失败")
+
+	resp := s.Do(t, http.MethodPost, "/api/user/sms/code", map[string]string{"phone": "13700137000"}, "")
+	if resp.Status != http.StatusInternalServerError {
+		t.Fatalf("短信发送失败时应返回500，实际为%d", resp.Status)
+	}
+
+	var count int64
+	s.App.DB.Table("sms_records").Where("phone = ? AND status = ?", "13700137000", false).Count(&count)
+	if count != 1 {
+		t.Fatalf("应记录1条发送失败的短信，实际为%d", count)
+	}
+}
@@ -1,28 +1,17 @@
 package router
 
 import (
+	"myApp/app"
 	"myApp/handler"
 	"myApp/middleware"
-	"myApp/repository"
-	"myApp/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 // InitViewingRouter 初始化预约看房相关路由
-func InitViewingRouter(r *gin.Engine) {
-	// 创建预约看房数据仓库实例
-	viewingRepo := repository.NewViewingRepository()
-	// 创建预约看房服务实例，注入数据仓库依赖
-	viewingService := service.NewViewingService(viewingRepo)
-
-	// 创建房源数据仓库实例
-	houseRepo := repository.NewHouseRepository()
-	// 创建房源服务实例，注入数据仓库依赖
-	houseService := service.NewHouseService(houseRepo)
-
-	// 创建预约看房处理器实例，注入服务依赖
-	viewingHandler := handler.NewViewingHandler(viewingService, houseService)
+func InitViewingRouter(r *gin.Engine, a *app.App) {
+	// 创建预约看房处理器实例，注入预约看房和房源服务依赖
+	viewingHandler := handler.NewViewingHandler(a.Services.Viewing, a.Services.House)
 
 	// 创建预约看房路由组，所有预约看房相关接口都在/api/viewing路径下
 	viewingGroup := r.Group("/api/viewing")
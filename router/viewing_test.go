@@ -0,0 +1,86 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"myApp/app/apptest"
+	"myApp/model"
+	"myApp/model/modeltest"
+	"myApp/pkg/errors"
+)
+
+func TestCreateViewingRejectsOwnHouse(t *testing.T) {
+	s := apptest.New(t)
+	user := s.CreateUser(t, nil)
+	house := modeltest.Create(t, s.App.DB, modeltest.House(), func(h *model.House) { h.LandlordID = user.ID })
+
+	// 房东不能预约自己的房源
+	resp := s.Do(t, http.MethodPost, "/api/viewing/create", map[string]interface{}{
+		"house_id":      house.ID,
+		"view_date":     time.Now().Add(24 * time.Hour),
+		"contact_name":  "张三",
+		"contact_phone": "13800138000",
+	}, s.Token(t, user.ID))
+	if resp.Status != http.StatusForbidden || resp.Code != int(errors.CodeForbidden) || resp.Message != "不能预约自己的房源" {
+		t.Fatalf("预约自己的房源应返回403，实际为%d %d %s", resp.Status, resp.Code, resp.Message)
+	}
+}
+
+func TestCreateViewingRequiresBookableHouse(t *testing.T) {
+	s := apptest.New(t)
+	// 测试房源的房东ID为1，租客为第二个用户
+	s.CreateUser(t, nil)
+	token := s.Token(t, s.CreateUser(t, nil).ID)
+	book := func(houseID uint) *apptest.Response {
+		return s.Do(t, http.MethodPost, "/api/viewing/create", map[string]interface{}{
+			"house_id":      houseID,
+			"view_date":     time.Now().Add(24 * time.Hour),
+			"contact_name":  "张三",
+			"contact_phone": "13800138000",
+		}, token)
+	}
+
+	// 待审核的房源对租客不可见
+	pending := modeltest.Create(t, s.App.DB, modeltest.House(), func(h *model.House) { h.Status = model.HouseStatusPending })
+	if resp := book(pending.ID); resp.Status != http.StatusBadRequest {
+		t.Fatalf("预约待审核房源应返回400，实际为%d %s", resp.Status, resp.Message)
+	}
+
+	// 已出租和已过上架有效期的房源公开可见但不能预约
+	rented := modeltest.Create(t, s.App.DB, modeltest.House(), func(h *model.House) { h.Status = model.HouseStatusRented })
+	expired := modeltest.Create(t, s.App.DB, modeltest.House(), func(h *model.House) {
+		expireAt := time.Now().Add(-time.Hour)
+		h.ExpireAt = &expireAt
+	})
+	for _, id := range []uint{rented.ID, expired.ID} {
+		if resp := book(id); resp.Status != http.StatusConflict || resp.Code != int(errors.CodeConflict) {
+			t.Fatalf("预约房源%d应返回409，实际为%d %d %s", id, resp.Status, resp.Code, resp.Message)
+		}
+	}
+
+	published := modeltest.Create(t, s.App.DB, modeltest.House(), nil)
+	if resp := book(published.ID); resp.Status != http.StatusOK {
+		t.Fatalf("预约已发布房源失败: %d %s", resp.Status, resp.Message)
+	}
+}
+
+func TestGetViewingErrors(t *testing.T) {
+	s := apptest.New(t)
+	token := s.Token(t, s.CreateUser(t, nil).ID)
+
+	resp := s.Do(t, http.MethodGet, "/api/viewing/999", nil, token)
+	if resp.Status != http.StatusNotFound || resp.Code != int(errors.CodeNotFound) || resp.Message != "预约记录不存在" {
+		t.Fatalf("预约记录不存在应返回404，实际为%d %d %s", resp.Status, resp.Code, resp.Message)
+	}
+
+	// 数据库错误按服务器内部错误返回，不能当作预约记录不存在
+	if err := s.App.DB.Migrator().DropTable(&model.Viewing{}); err != nil {
+		t.Fatalf("删除预约看房表失败: %v", err)
+	}
+	resp = s.Do(t, http.MethodGet, "/api/viewing/999", nil, token)
+	if resp.Status != http.StatusInternalServerError || resp.Code != int(errors.CodeInternal) {
+		t.Fatalf("数据库错误应返回500，实际为%d %d %s", resp.Status, resp.Code, resp.Message)
+	}
+}
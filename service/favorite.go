@@ -1,64 +1,256 @@
 package service
 
 import (
+	"context"
 	"myApp/model"
+	"myApp/pkg/errors"
 	"myApp/repository"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 type FavoriteService interface {
-	AddFavorite(favorite *model.Favorite) error
-	RemoveFavorite(id uint) error
-	GetFavoriteByID(id uint) (*model.Favorite, error)
-	GetUserFavorites(userID uint) ([]model.Favorite, error)
-	IsFavorite(userID, houseID uint) (bool, error)
-	ToggleFavorite(userID, houseID uint, notes string) error
+	AddFavorite(ctx context.Context, favorite *model.Favorite) error
+	RemoveFavorite(ctx context.Context, id uint) error
+	GetFavoriteByID(ctx context.Context, id uint) (*model.Favorite, error)
+	GetUserFavorites(ctx context.Context, userID uint, params map[string]interface{}) ([]FavoriteItem, int64, error)
+	IsFavorite(ctx context.Context, userID, houseID uint) (bool, error)
+	ToggleFavorite(ctx context.Context, userID, houseID uint, notes string) error
+	SetPriceDropAlert(ctx context.Context, id, userID uint, enabled bool) error
+	UpdateNotes(ctx context.Context, id, userID uint, notes string) error
+	MoveToFolder(ctx context.Context, id, userID, folderID uint) error
+	CreateFolder(ctx context.Context, folder *model.FavoriteFolder) error
+	GetFolders(ctx context.Context, userID uint) ([]FavoriteFolderSummary, int64, error)
+	RenameFolder(ctx context.Context, id, userID uint, name string) error
+	DeleteFolder(ctx context.Context, id, userID uint) error
 }
 
-type favoriteService struct {
-	repo repository.FavoriteRepository
+var (
+	// ErrFavoriteNotFound 收藏记录不存在或不属于当前用户时返回的错误
+	ErrFavoriteNotFound = errors.NotFound("收藏记录不存在")
+	// ErrAlreadyFavorited 重复收藏同一房源时返回的错误
+	ErrAlreadyFavorited = errors.Conflict("已收藏该房源")
+	// ErrFavoriteHouseNotFound 收藏的房源不存在或未公开时返回的错误
+	ErrFavoriteHouseNotFound = errors.NotFound("房源不存在")
+)
+
+// FavoriteItem 收藏记录及收藏的房源，房源已删除时House为nil
+type FavoriteItem struct {
+	Favorite model.Favorite
+	House    *model.House
 }
 
-func NewFavoriteService(repo repository.FavoriteRepository) FavoriteService {
-	return &favoriteService{repo: repo}
+// HouseAvailable 收藏的房源当前是否可租
+func (i FavoriteItem) HouseAvailable() bool {
+	return i.House != nil && i.House.Status == model.HouseStatusPublished && !houseExpired(i.House)
 }
 
-func (s *favoriteService) AddFavorite(favorite *model.Favorite) error {
-	return s.repo.Create(favorite)
+// HouseStatusText 收藏的房源当前状态说明
+func (i FavoriteItem) HouseStatusText() string {
+	switch {
+	case i.House == nil:
+		return "已删除"
+	case i.House.Status == model.HouseStatusPublished && houseExpired(i.House):
+		return "已过期"
+	case i.House.Status == model.HouseStatusPublished:
+		return "在租"
+	case i.House.Status == model.HouseStatusRented:
+		return "已出租"
+	case i.House.Status == model.HouseStatusOffline:
+		return "已下架"
+	default:
+		return "暂不可见"
+	}
 }
 
-func (s *favoriteService) RemoveFavorite(id uint) error {
-	return s.repo.Delete(id)
+// PriceChange 当前租金相对收藏时的变化，负数表示降价，房源已删除或未记录收藏时租金时为0
+func (i FavoriteItem) PriceChange() float64 {
+	if i.House == nil || i.Favorite.PriceAtFavorite == 0 {
+		return 0
+	}
+	return i.House.RentPrice - i.Favorite.PriceAtFavorite
 }
 
-func (s *favoriteService) GetFavoriteByID(id uint) (*model.Favorite, error) {
-	return s.repo.GetByID(id)
+// houseExpired 房源是否已过上架到期时间，到期房源在定时任务下架前仍为已发布状态
+func houseExpired(house *model.House) bool {
+	return house.ExpireAt != nil && house.ExpireAt.Before(time.Now())
 }
 
-func (s *favoriteService) GetUserFavorites(userID uint) ([]model.Favorite, error) {
-	return s.repo.GetFavoritesByUserID(userID)
+type favoriteService struct {
+	repo       repository.FavoriteRepository
+	folderRepo repository.FavoriteFolderRepository
+	houseRepo  repository.HouseRepository
+	txManager  repository.TxManager
 }
 
-func (s *favoriteService) IsFavorite(userID, houseID uint) (bool, error) {
-	return s.repo.IsFavorite(userID, houseID)
+func NewFavoriteService(repo repository.FavoriteRepository, folderRepo repository.FavoriteFolderRepository, houseRepo repository.HouseRepository, txManager repository.TxManager) FavoriteService {
+	return &favoriteService{repo: repo, folderRepo: folderRepo, houseRepo: houseRepo, txManager: txManager}
 }
 
-func (s *favoriteService) ToggleFavorite(userID, houseID uint, notes string) error {
-	// 检查是否已收藏
-	isFav, err := s.repo.IsFavorite(userID, houseID)
+// AddFavorite 收藏房源，记录收藏时的租金用于提示租金变动
+func (s *favoriteService) AddFavorite(ctx context.Context, favorite *model.Favorite) error {
+	if favorite.FolderID != 0 {
+		if _, err := s.getOwnedFolder(ctx, favorite.FolderID, favorite.UserID); err != nil {
+			return err
+		}
+	}
+
+	err := s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		return s.createFavorite(ctx, favorite)
+	})
+	if err != nil {
+		return err
+	}
+	recordHouseActivity(ctx, favorite.HouseID, trendingWeightFavor)
+	return nil
+}
+
+// createFavorite 校验房源可收藏且未重复收藏后创建收藏记录，需在事务中调用
+func (s *favoriteService) createFavorite(ctx context.Context, favorite *model.Favorite) error {
+	house, err := s.houseRepo.GetByID(ctx, favorite.HouseID)
+	if err != nil || !house.IsPublic() {
+		return ErrFavoriteHouseNotFound
+	}
+
+	isFav, err := s.repo.IsFavorite(ctx, favorite.UserID, favorite.HouseID)
 	if err != nil {
 		return err
 	}
-	
-	// 如果已收藏，则取消收藏
 	if isFav {
-		return s.repo.DeleteByUserAndHouse(userID, houseID)
+		return ErrAlreadyFavorited
 	}
-	
-	// 如果未收藏，则添加收藏
-	favorite := &model.Favorite{
-		UserID:  userID,
-		HouseID: houseID,
-		Notes:   notes,
+
+	// 并发收藏时检查和创建之间可能被其他请求抢先，由唯一索引保证不重复
+	favorite.PriceAtFavorite = house.RentPrice
+	if err := s.repo.Create(ctx, favorite); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrAlreadyFavorited
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *favoriteService) RemoveFavorite(ctx context.Context, id uint) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *favoriteService) GetFavoriteByID(ctx context.Context, id uint) (*model.Favorite, error) {
+	favorite, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "收藏记录不存在")
+	}
+	return favorite, nil
+}
+
+// GetUserFavorites 分页获取用户的收藏及收藏的房源，params支持按收藏夹和备注关键词筛选
+func (s *favoriteService) GetUserFavorites(ctx context.Context, userID uint, params map[string]interface{}) ([]FavoriteItem, int64, error) {
+	params["user_id"] = userID
+	total, err := s.repo.Count(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	favorites, err := s.repo.GetAll(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(favorites) == 0 {
+		return []FavoriteItem{}, total, nil
+	}
+
+	// 一次查询当前页收藏的全部房源，已删除的房源不会返回
+	houseIDs := make([]uint, len(favorites))
+	for i, favorite := range favorites {
+		houseIDs[i] = favorite.HouseID
 	}
-	return s.repo.Create(favorite)
-}
\ No newline at end of file
+	houses, err := s.houseRepo.GetAll(ctx, map[string]interface{}{"ids": houseIDs})
+	if err != nil {
+		return nil, 0, err
+	}
+	houseMap := make(map[uint]*model.House, len(houses))
+	for i := range houses {
+		houseMap[houses[i].ID] = &houses[i]
+	}
+	applyPendingViews(ctx, housePointers(houses)...)
+
+	items := make([]FavoriteItem, len(favorites))
+	for i, favorite := range favorites {
+		items[i] = FavoriteItem{Favorite: favorite, House: houseMap[favorite.HouseID]}
+	}
+	return items, total, nil
+}
+
+func (s *favoriteService) IsFavorite(ctx context.Context, userID, houseID uint) (bool, error) {
+	return s.repo.IsFavorite(ctx, userID, houseID)
+}
+
+// ToggleFavorite 切换收藏状态，检查与取消或添加收藏在同一事务中完成
+func (s *favoriteService) ToggleFavorite(ctx context.Context, userID, houseID uint, notes string) error {
+	added := false
+	err := s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		// 检查是否已收藏
+		isFav, err := s.repo.IsFavorite(ctx, userID, houseID)
+		if err != nil {
+			return err
+		}
+
+		// 如果已收藏，则取消收藏
+		if isFav {
+			return s.repo.DeleteByUserAndHouse(ctx, userID, houseID)
+		}
+
+		// 如果未收藏，则添加收藏
+		added = true
+		return s.createFavorite(ctx, &model.Favorite{
+			UserID:  userID,
+			HouseID: houseID,
+			Notes:   notes,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if added {
+		recordHouseActivity(ctx, houseID, trendingWeightFavor)
+	}
+	return nil
+}
+
+// SetPriceDropAlert 开启或关闭收藏房源的降价提醒，只能操作本人的收藏
+func (s *favoriteService) SetPriceDropAlert(ctx context.Context, id, userID uint, enabled bool) error {
+	if _, err := s.getOwnedFavorite(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.UpdateColumns(ctx, id, map[string]interface{}{"notify_price_drop": enabled})
+}
+
+// UpdateNotes 修改收藏备注，只能操作本人的收藏
+func (s *favoriteService) UpdateNotes(ctx context.Context, id, userID uint, notes string) error {
+	if _, err := s.getOwnedFavorite(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.UpdateColumns(ctx, id, map[string]interface{}{"notes": notes})
+}
+
+// MoveToFolder 将收藏移动到指定收藏夹，folderID为0时移回默认收藏夹
+func (s *favoriteService) MoveToFolder(ctx context.Context, id, userID, folderID uint) error {
+	if _, err := s.getOwnedFavorite(ctx, id, userID); err != nil {
+		return err
+	}
+	if folderID != 0 {
+		if _, err := s.getOwnedFolder(ctx, folderID, userID); err != nil {
+			return err
+		}
+	}
+	return s.repo.UpdateColumns(ctx, id, map[string]interface{}{"folder_id": folderID})
+}
+
+// getOwnedFavorite 获取收藏记录并校验是否属于指定用户
+func (s *favoriteService) getOwnedFavorite(ctx context.Context, id, userID uint) (*model.Favorite, error) {
+	favorite, err := s.repo.GetByID(ctx, id)
+	if err != nil || favorite.UserID != userID {
+		return nil, ErrFavoriteNotFound
+	}
+	return favorite, nil
+}
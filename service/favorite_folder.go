@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"myApp/model"
+	"myApp/pkg/errors"
+	"strings"
+)
+
+// maxFavoriteFolders 每个用户最多创建的收藏夹数量
+const maxFavoriteFolders = 20
+
+var (
+	// ErrFolderNotFound 收藏夹不存在或不属于当前用户时返回的错误
+	ErrFolderNotFound = errors.NotFound("收藏夹不存在")
+	// ErrFolderNameExists 收藏夹重名时返回的错误
+	ErrFolderNameExists = errors.Conflict("已存在同名收藏夹")
+	// ErrFolderLimit 收藏夹数量达到上限时返回的错误
+	ErrFolderLimit = errors.Newf(errors.CodeConflict, "最多只能创建%d个收藏夹", maxFavoriteFolders)
+)
+
+// FavoriteFolderSummary 收藏夹及其中的收藏数量
+type FavoriteFolderSummary struct {
+	Folder model.FavoriteFolder
+	Count  int64
+}
+
+// CreateFolder 创建收藏夹，同一用户的收藏夹不能重名
+func (s *favoriteService) CreateFolder(ctx context.Context, folder *model.FavoriteFolder) error {
+	folder.Name = strings.TrimSpace(folder.Name)
+
+	count, err := s.folderRepo.CountByUserID(ctx, folder.UserID)
+	if err != nil {
+		return err
+	}
+	if count >= maxFavoriteFolders {
+		return ErrFolderLimit
+	}
+
+	exists, err := s.folderRepo.ExistsByName(ctx, folder.UserID, folder.Name, 0)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrFolderNameExists
+	}
+	return s.folderRepo.Create(ctx, folder)
+}
+
+// GetFolders 获取用户的收藏夹及各收藏夹中的收藏数量，同时返回默认收藏夹中的收藏数量
+func (s *favoriteService) GetFolders(ctx context.Context, userID uint) ([]FavoriteFolderSummary, int64, error) {
+	folders, err := s.folderRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	counts, err := s.repo.CountByFolder(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	summaries := make([]FavoriteFolderSummary, len(folders))
+	for i, folder := range folders {
+		summaries[i] = FavoriteFolderSummary{Folder: folder, Count: counts[folder.ID]}
+	}
+	return summaries, counts[0], nil
+}
+
+// RenameFolder 重命名收藏夹
+func (s *favoriteService) RenameFolder(ctx context.Context, id, userID uint, name string) error {
+	if _, err := s.getOwnedFolder(ctx, id, userID); err != nil {
+		return err
+	}
+
+	name = strings.TrimSpace(name)
+	exists, err := s.folderRepo.ExistsByName(ctx, userID, name, id)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrFolderNameExists
+	}
+	return s.folderRepo.UpdateName(ctx, id, name)
+}
+
+// DeleteFolder 删除收藏夹，其中的收藏移回默认收藏夹
+func (s *favoriteService) DeleteFolder(ctx context.Context, id, userID uint) error {
+	if _, err := s.getOwnedFolder(ctx, id, userID); err != nil {
+		return err
+	}
+	if err := s.repo.ResetFolder(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.folderRepo.Delete(ctx, id)
+}
+
+// getOwnedFolder 获取收藏夹并校验是否属于指定用户
+func (s *favoriteService) getOwnedFolder(ctx context.Context, id, userID uint) (*model.FavoriteFolder, error) {
+	folder, err := s.folderRepo.GetByID(ctx, id)
+	if err != nil || folder.UserID != userID {
+		return nil, ErrFolderNotFound
+	}
+	return folder, nil
+}
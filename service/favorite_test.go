@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"myApp/model"
+	"myApp/model/modeltest"
+	"myApp/repository"
+)
+
+// racyFavoriteRepository 模拟并发请求：检查时尚未收藏，创建时已被其他请求抢先收藏
+type racyFavoriteRepository struct {
+	repository.FavoriteRepository
+}
+
+func (r racyFavoriteRepository) IsFavorite(ctx context.Context, userID, houseID uint) (bool, error) {
+	return false, nil
+}
+
+func TestFavoriteDuplicateKeyIsConflict(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	favoriteRepo := repository.NewFavoriteRepository(db)
+	houseRepo := repository.NewHouseRepository(db)
+	svc := NewFavoriteService(racyFavoriteRepository{favoriteRepo}, repository.NewFavoriteFolderRepository(db), houseRepo, repository.NewTxManager(db))
+
+	house := &model.House{Title: "测试房源", Address: "北京市朝阳区建国路1号", RentPrice: 5000, Status: model.HouseStatusPublished}
+	if err := houseRepo.Create(ctx, house); err != nil {
+		t.Fatalf("创建房源失败: %v", err)
+	}
+	if err := favoriteRepo.Create(ctx, &model.Favorite{UserID: 1, HouseID: house.ID}); err != nil {
+		t.Fatalf("创建收藏失败: %v", err)
+	}
+
+	if err := svc.AddFavorite(ctx, &model.Favorite{UserID: 1, HouseID: house.ID}); err != ErrAlreadyFavorited {
+		t.Errorf("AddFavorite重复收藏返回%v，期望ErrAlreadyFavorited", err)
+	}
+	if err := svc.ToggleFavorite(ctx, 1, house.ID, ""); err != ErrAlreadyFavorited {
+		t.Errorf("ToggleFavorite重复收藏返回%v，期望ErrAlreadyFavorited", err)
+	}
+	if count, _ := favoriteRepo.Count(ctx, map[string]interface{}{"user_id": uint(1)}); count != 1 {
+		t.Errorf("收藏记录数为%d，期望1", count)
+	}
+}
@@ -1,228 +1,619 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"myApp/config"
 	"myApp/model"
-	"myApp/pkg/redis"
+	"myApp/pkg/errors"
+	"myApp/pkg/geo"
+	"myApp/pkg/logger"
+	"myApp/pkg/redis/cache"
 	"myApp/repository"
+	"strconv"
+	"strings"
 	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type HouseService interface {
-	CreateHouse(house *model.House) error
-	GetHouseByID(id uint) (*model.House, error)
-	GetAllHouses(params map[string]interface{}) ([]model.House, error)
-	UpdateHouse(house *model.House) error
-	DeleteHouse(id uint) error
-	GetHousesByLandlordID(landlordID uint) ([]model.House, error)
-	IncrementViewCount(id uint) error
+	CreateHouse(ctx context.Context, house *model.House) error
+	GetHouseByID(ctx context.Context, id uint) (*model.House, error)
+	GetAllHouses(ctx context.Context, params map[string]interface{}) ([]model.House, error)
+	CountHouses(ctx context.Context, params map[string]interface{}) (int64, error)
+	UpdateHouse(ctx context.Context, id, operatorID uint, update func(house *model.House)) (*model.House, error)
+	DeleteHouse(ctx context.Context, id uint) error
+	GetHousesByLandlordID(ctx context.Context, landlordID uint) ([]model.House, error)
+	RecordView(ctx context.Context, id uint, visitor string) error
+	FlushViewCounts(ctx context.Context) (int, error)
+	GetTrendingHouses(ctx context.Context, params map[string]interface{}, limit int) ([]TrendingHouse, error)
+	DecayTrending(ctx context.Context) (int64, error)
+	RecordSearch(ctx context.Context, userID uint, params map[string]interface{})
+	GetRecommendations(ctx context.Context, userID uint, limit int) ([]model.House, bool, error)
+	GetSimilarHouses(ctx context.Context, id uint, limit int) ([]model.House, error)
+	GetRegionFacets(ctx context.Context, params map[string]interface{}) ([]RegionFacet, error)
+	CompareHouses(ctx context.Context, ids []uint, origin *geo.Point) (*HouseCompareResult, error)
+	SubmitHouse(ctx context.Context, id, userID uint) (*model.House, error)
+	OfflineHouse(ctx context.Context, id, userID uint) error
+	MarkHouseRented(ctx context.Context, id, userID uint) error
+	RefreshHouse(ctx context.Context, id, userID uint) (*model.House, error)
+	GetPendingHouses(ctx context.Context, params map[string]interface{}) ([]model.House, int64, error)
+	ApproveHouse(ctx context.Context, id, reviewerID uint) error
+	RejectHouse(ctx context.Context, id, reviewerID uint, reason string) error
+	ExpireListings(ctx context.Context) (int, error)
+	GetHouseRevisions(ctx context.Context, houseID uint, params map[string]interface{}) ([]model.HouseRevision, int64, error)
+	GetPriceHistory(ctx context.Context, houseID uint) ([]model.HousePriceHistory, error)
 }
 
+var (
+	// ErrLandlordNotVerified 未认证房东发布房源时返回的错误
+	ErrLandlordNotVerified = errors.Forbidden("仅已认证的房东可以发布房源")
+	// ErrHouseNotFound 房源不存在时返回的错误
+	ErrHouseNotFound = errors.NotFound("房源不存在")
+	// ErrHouseForbidden 操作非本人房源时返回的错误
+	ErrHouseForbidden = errors.Forbidden("无权操作该房源")
+	// ErrHouseCheckFailed 房源未通过自动审核时返回的错误
+	ErrHouseCheckFailed = errors.Validation("房源未通过自动审核")
+)
+
 type houseService struct {
-	repo repository.HouseRepository
+	repo                repository.HouseRepository
+	landlordRepo        repository.LandlordRepository
+	revisionRepo        repository.HouseRevisionRepository
+	priceHistoryRepo    repository.HousePriceHistoryRepository
+	favoriteRepo        repository.FavoriteRepository
+	viewingRepo         repository.ViewingRepository
+	notificationService NotificationService
+	savedSearchService  SavedSearchService
+	regionService       RegionService
+	geocoder            geo.Geocoder // 地理编码服务提供商，为nil时不根据地址解析坐标
+	txManager           repository.TxManager
 }
 
-func NewHouseService(repo repository.HouseRepository) HouseService {
-	return &houseService{repo: repo}
+func NewHouseService(repo repository.HouseRepository, landlordRepo repository.LandlordRepository, revisionRepo repository.HouseRevisionRepository, priceHistoryRepo repository.HousePriceHistoryRepository, favoriteRepo repository.FavoriteRepository, viewingRepo repository.ViewingRepository, notificationService NotificationService, savedSearchService SavedSearchService, regionService RegionService, geocoder geo.Geocoder, txManager repository.TxManager) HouseService {
+	return &houseService{
+		repo:                repo,
+		landlordRepo:        landlordRepo,
+		revisionRepo:        revisionRepo,
+		priceHistoryRepo:    priceHistoryRepo,
+		favoriteRepo:        favoriteRepo,
+		viewingRepo:         viewingRepo,
+		notificationService: notificationService,
+		savedSearchService:  savedSearchService,
+		regionService:       regionService,
+		geocoder:            geocoder,
+		txManager:           txManager,
+	}
 }
 
-func (s *houseService) CreateHouse(house *model.House) error {
-	return s.repo.Create(house)
-}
+func (s *houseService) CreateHouse(ctx context.Context, house *model.House) error {
+	// 只有已认证的房东才能发布房源，房源的LandlordID为房东的用户ID
+	if err := s.checkLandlordVerified(ctx, house.LandlordID); err != nil {
+		return err
+	}
+
+	// 未指定所在区域时根据地址识别，再补全并校验坐标
+	if err := s.fillHouseRegion(ctx, house); err != nil {
+		return err
+	}
+	if err := s.fillHouseLocation(ctx, house, nil); err != nil {
+		return err
+	}
+
+	// 非草稿房源创建后直接提交审核
+	house.PublishedAt = nil
+	house.ExpireAt = nil
+	if house.Status != model.HouseStatusDraft {
+		applyCheckResult(house, s.checkHouse(ctx, house))
+	}
+
+	if err := s.repo.Create(ctx, house); err != nil {
+		return err
+	}
 
-func (s *houseService) GetHouseByID(id uint) (*model.House, error) {
-	// 构造缓存键
-	cacheKey := fmt.Sprintf("house:%d", id)
+	// 记录初始租金，作为租金走势的起点
+	if err := s.priceHistoryRepo.Create(ctx, &model.HousePriceHistory{
+		HouseID: house.ID,
+		Price:   house.RentPrice,
+	}); err != nil {
+		logger.FromContext(ctx).With(zap.Error(err)).Error(fmt.Sprintf("记录房源%d初始租金失败", house.ID))
+	}
+
+	// 新建房源尚未发布，不影响公开列表
+	s.invalidateHouseCache(ctx, house.ID, house.LandlordID, false)
+	return nil
+}
 
-	// 尝试从缓存获取
-	cacheData, err := redis.Get(cacheKey)
-	if err == nil {
-		// 缓存命中，反序列化数据
-		var house model.House
-		if err := json.Unmarshal([]byte(cacheData), &house); err == nil {
-			return &house, nil
+// GetHouseByID 获取房源详情，优先读取缓存
+func (s *houseService) GetHouseByID(ctx context.Context, id uint) (*model.House, error) {
+	house, err := cache.GetOrLoad(ctx, houseCache, strconv.FormatUint(uint64(id), 10), houseDetailCacheOptions, func(ctx context.Context) (*model.House, error) {
+		house, err := s.repo.GetByID(ctx, id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, cache.ErrNotFound
 		}
-		// 如果是空JSON对象，表示空结果
-		if cacheData == "{}" {
-			return nil, nil
+		return house, err
+	})
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil, ErrHouseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	applyPendingViews(ctx, house)
+	return house, nil
+}
+
+// GetAllHouses 按条件查询房源列表，优先读取缓存
+func (s *houseService) GetAllHouses(ctx context.Context, params map[string]interface{}) ([]model.House, error) {
+	// 列表缓存注册到所包含房源的标签下，按房东筛选时同时注册到房东标签下
+	opts := houseListCacheOptions
+	opts.Tags = func(houses []model.House) []string {
+		tags := houseListTags(params)
+		for _, house := range houses {
+			tags = append(tags, houseTag(house.ID))
 		}
-	} else if err != redis.Nil {
-		// 如果是其他错误，记录但不影响主流程
-		fmt.Printf("Redis获取缓存错误: %v\n", err)
+		return tags
 	}
 
-	// 缓存未命中或反序列化失败，从数据库获取
-	house, err := s.repo.GetByID(id)
+	houses, err := cache.GetOrLoad(ctx, houseListCache, houseListCacheKey(params), opts, func(ctx context.Context) ([]model.House, error) {
+		return s.repo.GetAll(ctx, params)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// 将数据存入缓存
-	if house != nil {
-		houseData, err := json.Marshal(house)
-		if err == nil {
-			// 设置缓存，过期时间30分钟
-			_ = redis.Set(cacheKey, string(houseData), 30*time.Minute)
+	applyPendingViews(ctx, housePointers(houses)...)
+	return houses, nil
+}
+
+// CountHouses 统计符合条件的房源总数，params中的分页和排序参数不影响结果，优先读取缓存
+func (s *houseService) CountHouses(ctx context.Context, params map[string]interface{}) (int64, error) {
+	filters := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		if key != "limit" && key != "offset" && key != "order_by" {
+			filters[key] = value
 		}
-	} else {
-		// 缓存空结果，设置较短的过期时间（5分钟）
-		_ = redis.Set(cacheKey, "{}", 5*time.Minute)
 	}
 
-	return house, nil
+	// 房源增减或列表字段变化时随列表缓存一起失效
+	opts := houseCountCacheOptions
+	opts.Tags = func(int64) []string { return houseListTags(filters) }
+	return cache.GetOrLoad(ctx, houseListCache, "count:"+houseListCacheKey(filters), opts, func(ctx context.Context) (int64, error) {
+		return s.repo.Count(ctx, filters)
+	})
 }
 
-func (s *houseService) GetAllHouses(params map[string]interface{}) ([]model.House, error) {
-	// 构造缓存键，基于查询参数
-	var cacheKey string
+// houseListCacheKey 根据查询参数生成列表缓存键
+func houseListCacheKey(params map[string]interface{}) string {
 	if len(params) == 0 {
-		cacheKey = "houses:list:all"
-	} else {
-		// 对于有参数的查询，生成唯一的缓存键
-		paramsData, err := json.Marshal(params)
-		if err == nil {
-			cacheKey = fmt.Sprintf("houses:list:%x", paramsData)
-		} else {
-			// 如果无法序列化参数，使用默认键
-			cacheKey = "houses:list:default"
-		}
+		return "all"
+	}
+	// 对于有参数的查询，生成唯一的缓存键
+	paramsData, err := json.Marshal(params)
+	if err != nil {
+		// 如果无法序列化参数，使用默认键
+		return "default"
 	}
+	return fmt.Sprintf("%x", paramsData)
+}
 
-	// 尝试从缓存获取
-	cacheData, err := redis.Get(cacheKey)
-	if err == nil {
-		// 缓存命中，反序列化数据
-		var houses []model.House
-		if err := json.Unmarshal([]byte(cacheData), &houses); err == nil {
-			return houses, nil
-		}
-		// 检查是否是空结果的标记
-		if cacheData == "empty_list" {
-			return []model.House{}, nil
-		}
-	} else if err != redis.Nil {
-		// 如果是其他错误，记录但不影响主流程
-		fmt.Printf("Redis获取缓存错误: %v\n", err)
+// houseListTags 列表缓存的基础标签，按房东筛选时同时注册到房东标签下
+func houseListTags(params map[string]interface{}) []string {
+	tags := []string{houseListTag}
+	if landlordID, ok := params["landlord_id"].(uint); ok {
+		tags = append(tags, landlordTag(landlordID))
 	}
+	return tags
+}
 
-	// 缓存未命中或反序列化失败，从数据库获取
-	houses, err := s.repo.GetAll(params)
+// UpdateHouse 更新房源信息，只写入发生变化的字段并记录修改历史
+// update在从数据库读取的最新房源上修改字段，避免基于缓存中的旧数据覆盖其他修改
+func (s *houseService) UpdateHouse(ctx context.Context, id, operatorID uint, update func(house *model.House)) (*model.House, error) {
+	existingHouse, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		return nil, houseNotFoundOr(err)
+	}
+
+	houseCopy := *existingHouse
+	house := &houseCopy
+	update(house)
+
+	// 房源归属、状态和审核信息只能通过发布流程变更
+	house.ID = existingHouse.ID
+	house.LandlordID = existingHouse.LandlordID
+	house.Status = existingHouse.Status
+	house.RejectReason = existingHouse.RejectReason
+	house.ModerationFlags = existingHouse.ModerationFlags
+	house.PublishedAt = existingHouse.PublishedAt
+	house.ExpireAt = existingHouse.ExpireAt
+	house.ViewCount = existingHouse.ViewCount
+	house.Rating = existingHouse.Rating
+	house.ReviewCount = existingHouse.ReviewCount
+	house.CreatedAt = existingHouse.CreatedAt
+
+	// 只修改了地址而未指定所在区域时，根据新地址重新识别
+	if house.Address != existingHouse.Address && toHouseRegionOf(house) == toHouseRegionOf(existingHouse) {
+		HouseRegion{}.Apply(house)
+	}
+	if err := s.fillHouseRegion(ctx, house); err != nil {
+		return nil, err
+	}
+	// 只修改了地址而未修改坐标时，原坐标已失效，根据新地址重新解析
+	if house.Address != existingHouse.Address && house.Latitude == existingHouse.Latitude && house.Longitude == existingHouse.Longitude {
+		house.Latitude, house.Longitude = 0, 0
+	}
+	if err := s.fillHouseLocation(ctx, house, existingHouse); err != nil {
 		return nil, err
 	}
 
-	// 将数据存入缓存
-	if len(houses) > 0 {
-		housesData, err := json.Marshal(houses)
-		if err == nil {
-			// 设置缓存，过期时间15分钟
-			_ = redis.Set(cacheKey, string(housesData), 15*time.Minute)
+	// 已发布或审核中的房源修改后仍需通过自动审核
+	if house.Status == model.HouseStatusPublished || house.Status == model.HouseStatusPending {
+		if result := s.checkHouse(ctx, house); len(result.RejectReasons) > 0 {
+			return nil, errors.Wrap(ErrHouseCheckFailed, errors.CodeValidation, ErrHouseCheckFailed.Message+"："+strings.Join(result.RejectReasons, "；"))
 		}
-	} else {
-		// 缓存空结果，设置较短的过期时间（5分钟）
-		_ = redis.Set(cacheKey, "[]", 5*time.Minute)
 	}
 
-	return houses, nil
+	changes := diffHouse(existingHouse, house)
+	if len(changes) == 0 {
+		return house, nil
+	}
+
+	// 只更新发生变化的字段
+	columns := make(map[string]interface{}, len(changes))
+	for _, change := range changes {
+		columns[change.Field] = change.New
+	}
+	if err := s.repo.UpdateColumns(ctx, house.ID, columns); err != nil {
+		return nil, err
+	}
+
+	s.recordHouseChanges(ctx, house, operatorID, changes)
+	s.invalidateHouseCache(ctx, house.ID, house.LandlordID, house.Status == model.HouseStatusPublished && affectsHouseLists(changes))
+	return house, nil
 }
 
-func (s *houseService) UpdateHouse(house *model.House) error {
-	// 更新数据库
-	err := s.repo.Update(house)
+func (s *houseService) DeleteHouse(ctx context.Context, id uint) error {
+	// 先获取房源信息，用于后续清除相关缓存
+	house, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	// 删除缓存，强制下次请求重新从数据库加载
-	cacheKey := fmt.Sprintf("house:%d", house.ID)
-	_ = redis.Delete(cacheKey)
-
-	// 删除相关列表缓存
-	_ = redis.DeleteByPattern("houses:list:*")
-	_ = redis.DeleteByPattern(fmt.Sprintf("houses:landlord:%d", house.LandlordID))
+	// 删除数据库记录
+	err = s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
 
+	s.invalidateHouseCache(ctx, id, house.LandlordID, house.Status == model.HouseStatusPublished)
 	return nil
 }
 
-func (s *houseService) DeleteHouse(id uint) error {
-	// 先获取房源信息，用于后续清除相关缓存
-	house, err := s.repo.GetByID(id)
+// GetHousesByLandlordID 获取房东的全部房源，优先读取缓存
+func (s *houseService) GetHousesByLandlordID(ctx context.Context, landlordID uint) ([]model.House, error) {
+	houses, err := cache.GetOrLoad(ctx, landlordHouseCache, strconv.FormatUint(uint64(landlordID), 10), landlordHouseCacheOptions, func(ctx context.Context) ([]model.House, error) {
+		return s.repo.GetHousesByLandlordID(ctx, landlordID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	applyPendingViews(ctx, housePointers(houses)...)
+	return houses, nil
+}
+
+// SubmitHouse 房东将草稿、被驳回或已下架的房源提交审核，提交前进行自动审核
+func (s *houseService) SubmitHouse(ctx context.Context, id, userID uint) (*model.House, error) {
+	house, err := s.getOwnedHouse(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch house.Status {
+	case model.HouseStatusDraft, model.HouseStatusRejected, model.HouseStatusOffline:
+	default:
+		return nil, errors.Conflict("当前状态的房源不能提交审核")
+	}
+
+	if err := s.checkLandlordVerified(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// 在副本上应用自动审核结果，以便记录状态变更前后的值
+	submitted := *house
+	applyCheckResult(&submitted, s.checkHouse(ctx, &submitted))
+	err = s.updateColumns(ctx, house, userID, map[string]interface{}{
+		"status":           submitted.Status,
+		"reject_reason":    submitted.RejectReason,
+		"moderation_flags": submitted.ModerationFlags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &submitted, nil
+}
+
+// OfflineHouse 房东下架已发布或已出租的房源
+func (s *houseService) OfflineHouse(ctx context.Context, id, userID uint) error {
+	house, err := s.getOwnedHouse(ctx, id, userID)
 	if err != nil {
 		return err
 	}
+	if house.Status != model.HouseStatusPublished && house.Status != model.HouseStatusRented {
+		return errors.Conflict("仅已发布或已出租的房源可以下架")
+	}
 
-	// 删除数据库记录
-	err = s.repo.Delete(id)
+	// 主动下架的房源清空到期时间，重新上架需再次审核
+	return s.updateColumns(ctx, house, userID, map[string]interface{}{
+		"status":    model.HouseStatusOffline,
+		"expire_at": nil,
+	})
+}
+
+// MarkHouseRented 房东将已发布的房源标记为已出租
+func (s *houseService) MarkHouseRented(ctx context.Context, id, userID uint) error {
+	house, err := s.getOwnedHouse(ctx, id, userID)
 	if err != nil {
 		return err
 	}
+	if house.Status != model.HouseStatusPublished {
+		return errors.Conflict("仅已发布的房源可以标记为已出租")
+	}
+
+	return s.updateColumns(ctx, house, userID, map[string]interface{}{
+		"status":    model.HouseStatusRented,
+		"expire_at": nil,
+	})
+}
+
+// RefreshHouse 房东刷新房源，延长已发布房源的上架有效期，或重新上架因到期而下架的房源
+func (s *houseService) RefreshHouse(ctx context.Context, id, userID uint) (*model.House, error) {
+	house, err := s.getOwnedHouse(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 到期下架的房源会保留到期时间，以区别于房东主动下架
+	expired := house.Status == model.HouseStatusOffline && house.ExpireAt != nil
+	if house.Status != model.HouseStatusPublished && !expired {
+		return nil, errors.Conflict("仅已发布或到期下架的房源可以刷新")
+	}
+
+	now := time.Now()
+	expireAt := listingExpireAt(now)
+	columns := map[string]interface{}{
+		"status":    model.HouseStatusPublished,
+		"expire_at": &expireAt,
+	}
+	// 到期下架后重新上架的房源视为新房源，更新发布时间，使其计入保存的搜索的新房源并排在前面
+	if expired {
+		columns["published_at"] = now
+	}
+	if err := s.updateColumns(ctx, house, userID, columns); err != nil {
+		return nil, err
+	}
 
-	// 删除缓存
-	cacheKey := fmt.Sprintf("house:%d", id)
-	_ = redis.Delete(cacheKey)
+	house.Status = model.HouseStatusPublished
+	house.ExpireAt = &expireAt
+	if expired {
+		house.PublishedAt = &now
+		s.notifyHousePublished(ctx, *house)
+	}
+	return house, nil
+}
+
+// GetPendingHouses 获取待审核房源列表及总数
+func (s *houseService) GetPendingHouses(ctx context.Context, params map[string]interface{}) ([]model.House, int64, error) {
+	params["status"] = model.HouseStatusPending
+	if _, ok := params["order_by"]; !ok {
+		params["order_by"] = "updated_at ASC"
+	}
+
+	total, err := s.repo.Count(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	houses, err := s.repo.GetAll(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	return houses, total, nil
+}
 
-	// 删除相关列表缓存
-	_ = redis.DeleteByPattern("houses:list:*")
-	if house != nil {
-		_ = redis.DeleteByPattern(fmt.Sprintf("houses:landlord:%d", house.LandlordID))
+// ApproveHouse 管理员审核通过房源，房源发布并开始计算上架有效期
+func (s *houseService) ApproveHouse(ctx context.Context, id, reviewerID uint) error {
+	now := time.Now()
+	expireAt := listingExpireAt(now)
+	house, err := s.reviewHouse(ctx, id, reviewerID, func(house *model.House) (map[string]interface{}, error) {
+		if house.Status != model.HouseStatusPending {
+			return nil, errors.Conflict("房源不是待审核状态")
+		}
+		return map[string]interface{}{
+			"status":        model.HouseStatusPublished,
+			"reject_reason": "",
+			"published_at":  now,
+			"expire_at":     expireAt,
+		}, nil
+	})
+	if err != nil {
+		return err
 	}
 
+	// 事务提交后再加入新房源提醒队列，审核失败回滚时不会发出提醒
+	published := *house
+	published.Status = model.HouseStatusPublished
+	published.PublishedAt = &now
+	published.ExpireAt = &expireAt
+	s.notifyHousePublished(ctx, published)
 	return nil
 }
 
-func (s *houseService) GetHousesByLandlordID(landlordID uint) ([]model.House, error) {
-	// 构造缓存键
-	cacheKey := fmt.Sprintf("houses:landlord:%d", landlordID)
+// RejectHouse 管理员驳回待审核的房源，或强制下架已发布的违规房源
+func (s *houseService) RejectHouse(ctx context.Context, id, reviewerID uint, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.Validation("驳回原因不能为空")
+	}
+
+	_, err := s.reviewHouse(ctx, id, reviewerID, func(house *model.House) (map[string]interface{}, error) {
+		if house.Status != model.HouseStatusPending && house.Status != model.HouseStatusPublished {
+			return nil, errors.Conflict("仅待审核或已发布的房源可以驳回")
+		}
+		return map[string]interface{}{
+			"status":        model.HouseStatusRejected,
+			"reject_reason": reason,
+			"expire_at":     nil,
+		}, nil
+	})
+	return err
+}
 
-	// 尝试从缓存获取
-	cacheData, err := redis.Get(cacheKey)
-	if err == nil {
-		// 缓存命中，反序列化数据
-		var houses []model.House
-		if err := json.Unmarshal([]byte(cacheData), &houses); err == nil {
-			return houses, nil
+// reviewHouse 在事务中加锁读取房源，由decide校验状态并返回要更新的字段，更新后记录修改历史
+// 并发审核同一房源时后执行的一方会看到已变更的状态，事务提交后再清除缓存，返回更新前的房源
+func (s *houseService) reviewHouse(ctx context.Context, id, reviewerID uint, decide func(house *model.House) (map[string]interface{}, error)) (*model.House, error) {
+	var house *model.House
+	var changes []model.HouseFieldChange
+	err := s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		house, err = s.repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return houseNotFoundOr(err)
 		}
-		// 检查是否是空结果的标记
-		if cacheData == "empty_list" {
-			return []model.House{}, nil
+		columns, err := decide(house)
+		if err != nil {
+			return err
 		}
-	} else if err != redis.Nil {
-		// 如果是其他错误，记录但不影响主流程
-		fmt.Printf("Redis获取缓存错误: %v\n", err)
+		changes = diffHouseColumns(house, columns)
+		if err := s.repo.UpdateColumns(ctx, house.ID, columns); err != nil {
+			return err
+		}
+		s.recordHouseChanges(ctx, house, reviewerID, changes)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// 缓存未命中或反序列化失败，从数据库获取
-	houses, err := s.repo.GetHousesByLandlordID(landlordID)
+	s.invalidateHouseCache(ctx, house.ID, house.LandlordID, affectsHouseLists(changes))
+	return house, nil
+}
+
+// ExpireListings 将超过上架有效期的房源下架，返回下架的房源数量
+func (s *houseService) ExpireListings(ctx context.Context) (int, error) {
+	houses, err := s.repo.GetExpired(ctx, time.Now())
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	// 将数据存入缓存
-	if len(houses) > 0 {
-		housesData, err := json.Marshal(houses)
-		if err == nil {
-			// 设置缓存，过期时间20分钟
-			_ = redis.Set(cacheKey, string(housesData), 20*time.Minute)
+	count := 0
+	for i := range houses {
+		// 系统自动下架，操作人记为0
+		if err := s.updateColumns(ctx, &houses[i], 0, map[string]interface{}{"status": model.HouseStatusOffline}); err != nil {
+			logger.FromContext(ctx).With(zap.Error(err)).Error(fmt.Sprintf("房源%d到期下架失败", houses[i].ID))
+			continue
 		}
-	} else {
-		// 缓存空结果，设置较短的过期时间（5分钟）
-		_ = redis.Set(cacheKey, "[]", 5*time.Minute)
+		count++
 	}
+	return count, nil
+}
 
-	return houses, nil
+// checkLandlordVerified 检查用户是否为已认证的房东，不是房东或未认证时返回ErrLandlordNotVerified，查询失败时返回原错误
+func (s *houseService) checkLandlordVerified(ctx context.Context, userID uint) error {
+	landlord, err := s.landlordRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrLandlordNotVerified
+	}
+	if err != nil {
+		return err
+	}
+	if !landlord.Verified {
+		return ErrLandlordNotVerified
+	}
+	return nil
+}
+
+// houseNotFoundOr 记录不存在时返回ErrHouseNotFound，其他错误原样返回
+func houseNotFoundOr(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrHouseNotFound
+	}
+	return err
 }
 
-func (s *houseService) IncrementViewCount(id uint) error {
-	// 更新数据库
-	err := s.repo.IncrementViewCount(id)
+// getOwnedHouse 获取房源并校验是否属于指定房东，房源的LandlordID为房东的用户ID
+func (s *houseService) getOwnedHouse(ctx context.Context, id, userID uint) (*model.House, error) {
+	house, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		return nil, houseNotFoundOr(err)
+	}
+	if house.LandlordID != userID {
+		return nil, ErrHouseForbidden
+	}
+	return house, nil
+}
+
+// updateColumns 更新房源字段，记录修改历史并清除相关缓存
+func (s *houseService) updateColumns(ctx context.Context, house *model.House, operatorID uint, columns map[string]interface{}) error {
+	changes := diffHouseColumns(house, columns)
+	if err := s.repo.UpdateColumns(ctx, house.ID, columns); err != nil {
 		return err
 	}
 
-	// 删除缓存，强制下次请求重新从数据库加载
-	cacheKey := fmt.Sprintf("house:%d", id)
-	_ = redis.Delete(cacheKey)
+	s.recordHouseChanges(ctx, house, operatorID, changes)
+	s.invalidateHouseCache(ctx, house.ID, house.LandlordID, affectsHouseLists(changes))
+	return nil
+}
+
+// GetHouseRevisions 获取房源的修改记录
+func (s *houseService) GetHouseRevisions(ctx context.Context, houseID uint, params map[string]interface{}) ([]model.HouseRevision, int64, error) {
+	return s.revisionRepo.GetByHouseID(ctx, houseID, params)
+}
+
+// GetPriceHistory 获取房源的租金变动记录
+func (s *houseService) GetPriceHistory(ctx context.Context, houseID uint) ([]model.HousePriceHistory, error) {
+	return s.priceHistoryRepo.GetByHouseID(ctx, houseID)
+}
+
+// invalidateHouseCache 清除房源详情、包含该房源的列表以及房东的房源列表缓存
+// listsChanged为true时房源可能进入或移出其他列表的筛选结果，需要清除全部列表缓存
+func (s *houseService) invalidateHouseCache(ctx context.Context, id, landlordID uint, listsChanged bool) {
+	tags := []string{houseTag(id), landlordTag(landlordID)}
+	if listsChanged {
+		tags = append(tags, houseListTag)
+	}
+	if err := cache.InvalidateTags(ctx, tags...); err != nil {
+		logger.FromContext(ctx).With(zap.Error(err)).Error(fmt.Sprintf("清除房源%d缓存失败", id))
+	}
+	// 空结果缓存不注册标签，需要单独删除
+	_ = houseCache.Delete(ctx, strconv.FormatUint(uint64(id), 10))
+}
+
+// notifyHousePublished 房源发布后加入新房源提醒队列，由后台任务匹配用户保存的搜索并发送提醒
+func (s *houseService) notifyHousePublished(ctx context.Context, house model.House) {
+	if s.savedSearchService == nil || house.Status != model.HouseStatusPublished {
+		return
+	}
+	s.savedSearchService.NotifyNewHouse(ctx, &house)
+}
+
+// listingExpireAt 计算房源上架到期时间
+func listingExpireAt(from time.Time) time.Time {
+	return from.AddDate(0, 0, config.Conf.House.ListingTTLDays)
+}
 
+// fillHouseRegion 房源未指定所在区域时尽力根据地址识别，识别失败时保持为空
+func (s *houseService) fillHouseRegion(ctx context.Context, house *model.House) error {
+	if !toHouseRegionOf(house).IsEmpty() {
+		return nil
+	}
+	parsed, err := s.regionService.ParseAddress(ctx, house.Address)
+	if err != nil {
+		return err
+	}
+	parsed.Apply(house)
 	return nil
 }
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"myApp/model"
+	"myApp/pkg/redis"
+	"myApp/pkg/redis/cache"
+	"time"
+)
+
+// 房源相关缓存
+var (
+	houseCache         = cache.New("house")           // 房源详情缓存
+	houseListCache     = cache.New("houses:list")     // 房源列表缓存
+	landlordHouseCache = cache.New("houses:landlord") // 房东房源列表缓存
+)
+
+// 房源相关缓存的读取选项
+var (
+	// 房源详情：不存在的房源缓存空结果，防止缓存穿透
+	houseDetailCacheOptions = cache.Options[*model.House]{
+		TTL:         30 * time.Minute,
+		NegativeTTL: 5 * time.Minute,
+		StaleTTL:    5 * time.Minute,
+		Jitter:      0.1,
+		LocalTTL:    5 * time.Second,
+		Tags: func(house *model.House) []string {
+			if house == nil {
+				return nil
+			}
+			return []string{houseTag(house.ID)}
+		},
+	}
+	// 房源列表：标签在查询时根据筛选条件和结果生成
+	houseListCacheOptions = cache.Options[[]model.House]{
+		TTL:      15 * time.Minute,
+		StaleTTL: 2 * time.Minute,
+		Jitter:   0.1,
+		LocalTTL: 5 * time.Second,
+	}
+	// 房源列表总数：标签在查询时根据筛选条件生成
+	houseCountCacheOptions = cache.Options[int64]{
+		TTL:      15 * time.Minute,
+		StaleTTL: 2 * time.Minute,
+		Jitter:   0.1,
+		LocalTTL: 5 * time.Second,
+	}
+	// 房东房源列表：包含草稿和待审核房源，仅房东本人查看，不启用一级缓存以保证修改后立即可见
+	landlordHouseCacheOptions = cache.Options[[]model.House]{
+		TTL:    20 * time.Minute,
+		Jitter: 0.1,
+	}
+)
+
+// houseListTag 所有房源列表缓存共用的标签
+const houseListTag = "houses:list"
+
+// houseListFields 会影响列表筛选结果或排序的房源字段
+var houseListFields = map[string]bool{
+	"status":         true,
+	"title":          true,
+	"description":    true,
+	"address":        true,
+	"province_code":  true,
+	"city_code":      true,
+	"district_code":  true,
+	"community_code": true,
+	"area":           true,
+	"rent_price":     true,
+	"rooms":          true,
+	"house_type":     true,
+}
+
+// houseTag 房源标签，房源详情和包含该房源的列表缓存都注册在此标签下
+func houseTag(id uint) string {
+	return cache.Tag("house:%d", id)
+}
+
+// landlordTag 房东标签，房东的房源列表和按房东筛选的列表缓存注册在此标签下
+func landlordTag(landlordID uint) string {
+	return cache.Tag("landlord:%d", landlordID)
+}
+
+// affectsHouseLists 判断字段变更是否可能改变房源列表的筛选结果
+func affectsHouseLists(changes []model.HouseFieldChange) bool {
+	for _, change := range changes {
+		if houseListFields[change.Field] {
+			return true
+		}
+	}
+	return false
+}
+
+// ResetHouseData 清空Redis中与房源相关的数据，包括缓存、热度有序集合和待写回的浏览次数
+// 重新生成测试数据后房源ID会被复用，需要同时清空，避免旧数据出现在新的房源上
+func ResetHouseData(ctx context.Context) error {
+	// 列表、筛选统计、推荐和热门榜单等缓存都注册在列表标签下，详情和房东房源列表注册在各自的标签下
+	if err := cache.InvalidateTags(ctx, houseListTag); err != nil {
+		return err
+	}
+	for _, pattern := range []string{"house:*", "landlord:*"} {
+		if err := cache.InvalidateTagPattern(ctx, pattern); err != nil {
+			return err
+		}
+	}
+	// 未注册标签的缓存（如不存在的房源的空结果）按命名空间删除
+	for _, pattern := range []string{houseCache.Key("*"), landlordHouseCache.Key("*"), "house:view:seen:*"} {
+		if err := redis.DeleteByPattern(ctx, pattern); err != nil {
+			return err
+		}
+	}
+	for _, key := range []string{houseTrendingKey, houseTrendingEpochKey, houseViewPendingKey, houseViewFlushingKey} {
+		if err := redis.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
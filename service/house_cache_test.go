@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"myApp/pkg/redis"
+)
+
+func TestResetHouseData(t *testing.T) {
+	ctx := t.Context()
+	mr := newTestRedis(t)
+
+	if err := houseCache.Set(ctx, "1", "{}", time.Hour, houseTag(1)); err != nil {
+		t.Fatalf("写入房源详情缓存失败: %v", err)
+	}
+	if err := houseListCache.Set(ctx, "abc", "[]", time.Hour, houseListTag); err != nil {
+		t.Fatalf("写入房源列表缓存失败: %v", err)
+	}
+	if err := landlordHouseCache.Set(ctx, "2", "[]", time.Hour, landlordTag(2)); err != nil {
+		t.Fatalf("写入房东房源缓存失败: %v", err)
+	}
+	if _, err := redis.HIncrBy(ctx, houseViewPendingKey, "1", 3); err != nil {
+		t.Fatalf("写入浏览次数失败: %v", err)
+	}
+	if _, err := redis.HIncrBy(ctx, houseViewFlushingKey, "1", 2); err != nil {
+		t.Fatalf("写入浏览次数失败: %v", err)
+	}
+	mr.Set("house:view:seen:1:visitor", "1")
+	mr.ZAdd(houseTrendingKey, 10, "1")
+	mr.Set(houseTrendingEpochKey, "1700000000")
+	mr.Set("user:1", "保留")
+
+	if err := ResetHouseData(ctx); err != nil {
+		t.Fatalf("清空房源数据失败: %v", err)
+	}
+
+	// 只保留与房源无关的键
+	if keys := mr.Keys(); len(keys) != 1 || keys[0] != "user:1" {
+		t.Errorf("清空后剩余的键为%v，期望只保留user:1", keys)
+	}
+}
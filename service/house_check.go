@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"myApp/config"
+	"myApp/model"
+	"strings"
+)
+
+// 价格异常检测所需的最少同类房源样本数，样本不足时不做判断
+const priceOutlierMinSamples = 5
+
+// houseCheckResult 房源自动审核结果
+type houseCheckResult struct {
+	RejectReasons []string // 自动驳回的原因，房东修改后才能重新提交
+	Flags         []string // 需要管理员重点关注的提示
+}
+
+// checkHouse 对房源进行自动审核：违禁词、缺少图片直接驳回，价格异常仅提示管理员
+func (s *houseService) checkHouse(ctx context.Context, house *model.House) houseCheckResult {
+	var result houseCheckResult
+
+	if words := findBannedWords(house.Title + " " + house.Description + " " + house.Address); len(words) > 0 {
+		result.RejectReasons = append(result.RejectReasons, fmt.Sprintf("包含违禁词：%s", strings.Join(words, "、")))
+	}
+
+	if countImages(house.Images) == 0 {
+		result.RejectReasons = append(result.RejectReasons, "缺少房源图片")
+	}
+
+	if flag := s.checkPriceOutlier(ctx, house); flag != "" {
+		result.Flags = append(result.Flags, flag)
+	}
+
+	return result
+}
+
+// applyCheckResult 根据自动审核结果设置房源状态：存在驳回原因时直接驳回，否则进入待审核
+func applyCheckResult(house *model.House, result houseCheckResult) {
+	house.ModerationFlags = ""
+	if len(result.Flags) > 0 {
+		if data, err := json.Marshal(result.Flags); err == nil {
+			house.ModerationFlags = string(data)
+		}
+	}
+
+	if len(result.RejectReasons) > 0 {
+		house.Status = model.HouseStatusRejected
+		house.RejectReason = strings.Join(result.RejectReasons, "；")
+		return
+	}
+	house.Status = model.HouseStatusPending
+	house.RejectReason = ""
+}
+
+// checkPriceOutlier 将每平米租金与同类型已发布房源的均值比较，偏离超过阈值时返回提示
+func (s *houseService) checkPriceOutlier(ctx context.Context, house *model.House) string {
+	if house.Area <= 0 {
+		return ""
+	}
+
+	avg, samples, err := s.repo.GetAvgPricePerArea(ctx, house.HouseType)
+	if err != nil || samples < priceOutlierMinSamples || avg <= 0 {
+		return ""
+	}
+
+	ratio := config.Conf.House.PriceOutlierRatio
+	price := house.RentPrice / house.Area
+	switch {
+	case price > avg*ratio:
+		return fmt.Sprintf("价格异常：每平米租金%.2f元，高于同类房源均值%.2f元的%.1f倍", price, avg, ratio)
+	case price < avg/ratio:
+		return fmt.Sprintf("价格异常：每平米租金%.2f元，低于同类房源均值%.2f元的1/%.1f", price, avg, ratio)
+	}
+	return ""
+}
+
+// findBannedWords 返回文本中出现的违禁词
+func findBannedWords(text string) []string {
+	text = strings.ToLower(text)
+	var found []string
+	for _, word := range config.Conf.House.BannedWords {
+		word = strings.TrimSpace(word)
+		if word != "" && strings.Contains(text, strings.ToLower(word)) {
+			found = append(found, word)
+		}
+	}
+	return found
+}
+
+// countImages 统计房源图片数量，图片字段为JSON格式的URL数组
+func countImages(images string) int {
+	var urls []string
+	if err := json.Unmarshal([]byte(images), &urls); err != nil {
+		return 0
+	}
+	count := 0
+	for _, url := range urls {
+		if strings.TrimSpace(url) != "" {
+			count++
+		}
+	}
+	return count
+}
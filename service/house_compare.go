@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"myApp/model"
+	"myApp/pkg/errors"
+	"myApp/pkg/geo"
+	"strings"
+)
+
+// maxCompareHouses 一次最多对比的房源数量
+const maxCompareHouses = 5
+
+// ErrCompareHouseCount 对比的房源数量不在允许范围内时返回的错误
+var ErrCompareHouseCount = errors.Newf(errors.CodeValidation, "请选择2到%d套房源进行对比", maxCompareHouses)
+
+// HouseComparison 单套房源的对比数据
+type HouseComparison struct {
+	House      model.House
+	RentPerSqm float64  // 每平方米月租金，面积未填写时为0
+	Facilities []string // 配套设施
+	Distance   *float64 // 距指定位置的距离（千米），未指定位置或房源无坐标时为nil
+}
+
+// HouseCompareResult 房源对比结果，Items按请求的房源顺序排列
+type HouseCompareResult struct {
+	Items      []HouseComparison
+	Facilities []string // 全部房源配套设施的并集，按首次出现的顺序排列
+}
+
+// HasFacility 第i套房源是否有指定配套设施
+func (r *HouseCompareResult) HasFacility(i int, facility string) bool {
+	for _, f := range r.Items[i].Facilities {
+		if f == facility {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareHouses 对比多套公开房源，origin不为nil时计算各房源到该位置的距离
+func (s *houseService) CompareHouses(ctx context.Context, ids []uint, origin *geo.Point) (*HouseCompareResult, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) < 2 || len(ids) > maxCompareHouses {
+		return nil, ErrCompareHouseCount
+	}
+
+	houses, err := s.repo.GetAll(ctx, map[string]interface{}{"ids": ids})
+	if err != nil {
+		return nil, err
+	}
+	applyPendingViews(ctx, housePointers(houses)...)
+	houseMap := make(map[uint]model.House, len(houses))
+	for _, house := range houses {
+		houseMap[house.ID] = house
+	}
+
+	result := &HouseCompareResult{Items: make([]HouseComparison, 0, len(ids))}
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		house, ok := houseMap[id]
+		if !ok || !house.IsPublic() {
+			return nil, ErrHouseNotFound
+		}
+
+		item := HouseComparison{
+			House:      house,
+			Facilities: parseFacilities(house.Facilities),
+		}
+		if house.Area > 0 {
+			item.RentPerSqm = math.Round(house.RentPrice/house.Area*100) / 100
+		}
+		point := geo.Point{Latitude: house.Latitude, Longitude: house.Longitude}
+		if origin != nil && point.Valid() {
+			distance := math.Round(geo.Distance(*origin, point)*100) / 100
+			item.Distance = &distance
+		}
+
+		for _, facility := range item.Facilities {
+			if !seen[facility] {
+				seen[facility] = true
+				result.Facilities = append(result.Facilities, facility)
+			}
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result, nil
+}
+
+// parseFacilities 解析房源的配套设施，兼容JSON数组和逗号分隔两种格式
+func parseFacilities(data string) []string {
+	data = strings.TrimSpace(data)
+	if data == "" {
+		return nil
+	}
+
+	var facilities []string
+	if json.Unmarshal([]byte(data), &facilities) != nil {
+		facilities = strings.FieldsFunc(data, func(r rune) bool { return r == ',' || r == '，' || r == '、' })
+	}
+
+	result := make([]string, 0, len(facilities))
+	for _, facility := range facilities {
+		if facility = strings.TrimSpace(facility); facility != "" {
+			result = append(result, facility)
+		}
+	}
+	return result
+}
+
+// uniqueIDs 去除重复的ID，保留首次出现的顺序
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
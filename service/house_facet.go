@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"myApp/pkg/redis/cache"
+	"sort"
+	"time"
+)
+
+// RegionFacet 某个行政区划下符合筛选条件的房源数量
+type RegionFacet struct {
+	Code  string
+	Name  string
+	Count int64
+}
+
+// regionFacetCacheOptions 区划统计缓存选项，与房源列表共用标签，房源变更时一并失效
+var regionFacetCacheOptions = cache.Options[[]RegionFacet]{
+	TTL:      15 * time.Minute,
+	StaleTTL: 2 * time.Minute,
+	Jitter:   0.1,
+	LocalTTL: 5 * time.Second,
+	Tags:     func([]RegionFacet) []string { return []string{houseListTag} },
+}
+
+// GetRegionFacets 统计符合筛选条件的房源在下一级行政区划中的分布，按数量倒序
+// 未按区划筛选时按省统计，按省筛选时按市统计，依此类推；已筛选到街道时返回空列表
+func (s *houseService) GetRegionFacets(ctx context.Context, params map[string]interface{}) ([]RegionFacet, error) {
+	column := nextRegionColumn(params)
+	if column == "" {
+		return []RegionFacet{}, nil
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey := fmt.Sprintf("facets:%x", data)
+
+	return cache.GetOrLoad(ctx, houseListCache, cacheKey, regionFacetCacheOptions, func(ctx context.Context) ([]RegionFacet, error) {
+		counts, err := s.repo.CountByRegion(ctx, params, column)
+		if err != nil {
+			return nil, err
+		}
+
+		codes := make([]string, 0, len(counts))
+		for code := range counts {
+			codes = append(codes, code)
+		}
+		names, err := s.regionService.GetNames(ctx, codes)
+		if err != nil {
+			return nil, err
+		}
+
+		facets := make([]RegionFacet, 0, len(counts))
+		for code, count := range counts {
+			facets = append(facets, RegionFacet{Code: code, Name: names[code], Count: count})
+		}
+		sort.Slice(facets, func(i, j int) bool {
+			if facets[i].Count != facets[j].Count {
+				return facets[i].Count > facets[j].Count
+			}
+			return facets[i].Code < facets[j].Code
+		})
+		return facets, nil
+	})
+}
+
+// nextRegionColumn 根据查询参数中最细的区划筛选条件，返回下一级区划的统计字段
+func nextRegionColumn(params map[string]interface{}) string {
+	levels := []string{"province_code", "city_code", "district_code", "community_code"}
+	next := levels[0]
+	for i, column := range levels {
+		if code, ok := params[column].(string); ok && code != "" {
+			if i+1 == len(levels) {
+				return ""
+			}
+			next = levels[i+1]
+		}
+	}
+	return next
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"myApp/model"
+	"strings"
+)
+
+// HouseFilter 房源筛选条件，与房源列表接口的查询参数一致，供列表查询和保存的搜索共用
+type HouseFilter struct {
+	LandlordID    uint    `json:"landlord_id,omitempty"`    // 房东ID
+	ProvinceCode  string  `json:"province_code,omitempty"`  // 省级区划代码
+	CityCode      string  `json:"city_code,omitempty"`      // 市级区划代码
+	DistrictCode  string  `json:"district_code,omitempty"`  // 区县区划代码
+	CommunityCode string  `json:"community_code,omitempty"` // 街道区划代码
+	MinPrice      float64 `json:"min_price,omitempty"`      // 最低租金
+	MaxPrice      float64 `json:"max_price,omitempty"`      // 最高租金
+	Rooms         int     `json:"rooms,omitempty"`          // 房间数
+	HouseType     int     `json:"house_type,omitempty"`     // 房屋类型
+	Keyword       string  `json:"keyword,omitempty"`        // 关键词，匹配标题、描述和地址
+}
+
+// IsEmpty 是否未设置任何筛选条件
+func (f HouseFilter) IsEmpty() bool {
+	return f == HouseFilter{}
+}
+
+// ToParams 转换为仓库层的查询参数
+func (f HouseFilter) ToParams() map[string]interface{} {
+	params := make(map[string]interface{})
+	if f.LandlordID > 0 {
+		params["landlord_id"] = f.LandlordID
+	}
+	if f.ProvinceCode != "" {
+		params["province_code"] = f.ProvinceCode
+	}
+	if f.CityCode != "" {
+		params["city_code"] = f.CityCode
+	}
+	if f.DistrictCode != "" {
+		params["district_code"] = f.DistrictCode
+	}
+	if f.CommunityCode != "" {
+		params["community_code"] = f.CommunityCode
+	}
+	if f.MinPrice > 0 {
+		params["min_price"] = f.MinPrice
+	}
+	if f.MaxPrice > 0 {
+		params["max_price"] = f.MaxPrice
+	}
+	if f.Rooms > 0 {
+		params["rooms"] = f.Rooms
+	}
+	if f.HouseType > 0 {
+		params["house_type"] = f.HouseType
+	}
+	if f.Keyword != "" {
+		params["keyword"] = f.Keyword
+	}
+	return params
+}
+
+// Matches 判断房源是否符合筛选条件，规则与仓库层的查询条件保持一致
+func (f HouseFilter) Matches(house *model.House) bool {
+	if f.LandlordID > 0 && house.LandlordID != f.LandlordID {
+		return false
+	}
+	if (f.ProvinceCode != "" && house.ProvinceCode != f.ProvinceCode) ||
+		(f.CityCode != "" && house.CityCode != f.CityCode) ||
+		(f.DistrictCode != "" && house.DistrictCode != f.DistrictCode) ||
+		(f.CommunityCode != "" && house.CommunityCode != f.CommunityCode) {
+		return false
+	}
+	if f.MinPrice > 0 && house.RentPrice < f.MinPrice {
+		return false
+	}
+	if f.MaxPrice > 0 && house.RentPrice > f.MaxPrice {
+		return false
+	}
+	if f.Rooms > 0 && house.Rooms != f.Rooms {
+		return false
+	}
+	if f.HouseType > 0 && house.HouseType != f.HouseType {
+		return false
+	}
+	if f.Keyword != "" {
+		keyword := strings.ToLower(f.Keyword)
+		if !strings.Contains(strings.ToLower(house.Title), keyword) &&
+			!strings.Contains(strings.ToLower(house.Description), keyword) &&
+			!strings.Contains(strings.ToLower(house.Address), keyword) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"myApp/model"
+	"myApp/pkg/errors"
+	"myApp/pkg/geo"
+	"myApp/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// fillHouseLocation 补全并校验房源坐标
+// 未填写坐标时尽力根据地址进行地理编码，编码失败或结果不在所在区域内时保持为空，不影响保存；
+// 填写了坐标时校验其位于所在区县范围内。existing为修改前的房源，创建时为nil，
+// 修改时只有坐标或所在区域发生变化才校验，避免历史数据阻止修改其他字段
+func (s *houseService) fillHouseLocation(ctx context.Context, house, existing *model.House) error {
+	point := geo.Point{Latitude: house.Latitude, Longitude: house.Longitude}
+	if !point.Valid() {
+		house.Latitude, house.Longitude = 0, 0
+		s.geocodeHouse(ctx, house)
+		return nil
+	}
+
+	if existing != nil && house.Latitude == existing.Latitude && house.Longitude == existing.Longitude &&
+		house.DistrictCode == existing.DistrictCode {
+		return nil
+	}
+	return s.regionService.CheckLocation(ctx, houseRegionCode(house), point)
+}
+
+// geocodeHouse 根据地址解析房源坐标，失败时只记录日志，未配置地理编码服务时跳过
+func (s *houseService) geocodeHouse(ctx context.Context, house *model.House) {
+	if s.geocoder == nil || house.Address == "" {
+		return
+	}
+	point, err := s.geocoder.Geocode(house.Address)
+	if err != nil {
+		if !errors.Is(err, geo.ErrAddressNotFound) {
+			logger.FromContext(ctx).With(zap.Error(err)).Warn(fmt.Sprintf("解析房源地址坐标失败：%s", house.Address))
+		}
+		return
+	}
+
+	// 地址与所选区域不一致时编码结果不可信，不写入坐标
+	if err := s.regionService.CheckLocation(ctx, houseRegionCode(house), point); err != nil {
+		if !errors.Is(err, ErrLocationOutOfRegion) {
+			logger.FromContext(ctx).With(zap.Error(err)).Warn("校验地理编码结果失败")
+		}
+		return
+	}
+	house.Latitude, house.Longitude = point.Latitude, point.Longitude
+}
+
+// houseRegionCode 房源最细一级的区划代码，用于坐标校验
+func houseRegionCode(house *model.House) string {
+	for _, code := range []string{house.CommunityCode, house.DistrictCode, house.CityCode, house.ProvinceCode} {
+		if code != "" {
+			return code
+		}
+	}
+	return ""
+}
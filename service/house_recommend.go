@@ -0,0 +1,408 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"myApp/model"
+	"myApp/pkg/geo"
+	"myApp/pkg/logger"
+	"myApp/pkg/redis"
+	"myApp/pkg/redis/cache"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// userSearchKey 用户最近的房源搜索条件列表
+	userSearchKey = "user:search:%d"
+	// userSearchLimit 保留的最近搜索条件数量
+	userSearchLimit = 10
+	// userSearchTTL 搜索条件的保留时间
+	userSearchTTL = 30 * 24 * time.Hour
+	// recommendCandidateSize 推荐时参与打分的候选房源数量
+	recommendCandidateSize = 200
+	// recommendProximityKm 位置相近的参考距离（千米），距离越近得分越高
+	recommendProximityKm = 3.0
+	// similarPriceRatio 相似房源的租金浮动比例
+	similarPriceRatio = 0.3
+)
+
+// 各类偏好来源的权重，预约看房比收藏更能体现租房意向
+const (
+	preferenceWeightSearch   = 1
+	preferenceWeightFavorite = 3
+	preferenceWeightViewing  = 5
+)
+
+var (
+	// houseRecommendCache 用户推荐房源缓存
+	houseRecommendCache = cache.New("houses:recommend")
+	// houseSimilarCache 相似房源缓存
+	houseSimilarCache = cache.New("houses:similar")
+)
+
+// houseSearchFilter 用户搜索时使用的筛选条件
+type houseSearchFilter struct {
+	MinPrice  float64 `json:"min_price,omitempty"`
+	MaxPrice  float64 `json:"max_price,omitempty"`
+	Rooms     int     `json:"rooms,omitempty"`
+	HouseType int     `json:"house_type,omitempty"`
+}
+
+// houseRecommendation 推荐结果，Personalized为false表示用户暂无偏好数据，返回的是热门房源
+type houseRecommendation struct {
+	Houses       []model.House
+	Personalized bool
+}
+
+// housePreference 根据用户行为汇总的租房偏好
+type housePreference struct {
+	priceSum    float64
+	priceWeight float64
+	minPrice    float64
+	maxPrice    float64
+	rooms       map[int]float64
+	houseTypes  map[int]float64
+	locations   []geo.Point
+	total       float64
+}
+
+func newHousePreference() *housePreference {
+	return &housePreference{
+		minPrice:   math.MaxFloat64,
+		rooms:      make(map[int]float64),
+		houseTypes: make(map[int]float64),
+	}
+}
+
+// addHouse 将用户收藏或预约过的房源计入偏好
+func (p *housePreference) addHouse(house *model.House, weight float64) {
+	p.addPrice(house.RentPrice, weight)
+	p.rooms[house.Rooms] += weight
+	p.houseTypes[house.HouseType] += weight
+	if point := (geo.Point{Latitude: house.Latitude, Longitude: house.Longitude}); point.Valid() {
+		p.locations = append(p.locations, point)
+	}
+	p.total += weight
+}
+
+// addSearch 将用户的搜索条件计入偏好
+func (p *housePreference) addSearch(filter houseSearchFilter, weight float64) {
+	switch {
+	case filter.MinPrice > 0 && filter.MaxPrice > 0:
+		p.addPrice((filter.MinPrice+filter.MaxPrice)/2, weight)
+	case filter.MaxPrice > 0:
+		p.addPrice(filter.MaxPrice, weight)
+	case filter.MinPrice > 0:
+		p.addPrice(filter.MinPrice, weight)
+	}
+	if filter.Rooms > 0 {
+		p.rooms[filter.Rooms] += weight
+	}
+	if filter.HouseType > 0 {
+		p.houseTypes[filter.HouseType] += weight
+	}
+	p.total += weight
+}
+
+func (p *housePreference) addPrice(price, weight float64) {
+	if price <= 0 {
+		return
+	}
+	p.priceSum += price * weight
+	p.priceWeight += weight
+	p.minPrice = math.Min(p.minPrice, price)
+	p.maxPrice = math.Max(p.maxPrice, price)
+}
+
+// empty 是否没有任何偏好数据
+func (p *housePreference) empty() bool {
+	return p.total == 0
+}
+
+// candidateParams 根据偏好构造候选房源的查询条件
+func (p *housePreference) candidateParams() map[string]interface{} {
+	params := map[string]interface{}{
+		"status":      model.HouseStatusPublished,
+		"not_expired": true,
+		"order_by":    "updated_at DESC",
+		"limit":       recommendCandidateSize,
+	}
+	if p.priceWeight > 0 {
+		params["min_price"] = p.minPrice * (1 - similarPriceRatio)
+		params["max_price"] = p.maxPrice * (1 + similarPriceRatio)
+	}
+	return params
+}
+
+// score 计算房源与偏好的匹配度，综合租金、户型、房屋类型和位置
+func (p *housePreference) score(house *model.House) float64 {
+	var score float64
+
+	if p.priceWeight > 0 {
+		target := p.priceSum / p.priceWeight
+		score += 0.35 * math.Max(0, 1-math.Abs(house.RentPrice-target)/target)
+	}
+
+	if roomsTotal := sumWeights(p.rooms); roomsTotal > 0 {
+		// 相差一个房间的户型得一半分
+		match := p.rooms[house.Rooms] + 0.5*(p.rooms[house.Rooms-1]+p.rooms[house.Rooms+1])
+		score += 0.2 * math.Min(1, match/roomsTotal)
+	}
+
+	if typesTotal := sumWeights(p.houseTypes); typesTotal > 0 {
+		score += 0.2 * p.houseTypes[house.HouseType] / typesTotal
+	}
+
+	point := geo.Point{Latitude: house.Latitude, Longitude: house.Longitude}
+	if point.Valid() && len(p.locations) > 0 {
+		nearest := math.MaxFloat64
+		for _, location := range p.locations {
+			nearest = math.Min(nearest, geo.Distance(point, location))
+		}
+		score += 0.25 / (1 + nearest/recommendProximityKm)
+	}
+
+	return score
+}
+
+func sumWeights(weights map[int]float64) float64 {
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+	return total
+}
+
+// RecordSearch 记录登录用户的搜索条件，作为推荐房源的依据，失败只记录日志
+func (s *houseService) RecordSearch(ctx context.Context, userID uint, params map[string]interface{}) {
+	var filter houseSearchFilter
+	filter.MinPrice, _ = params["min_price"].(float64)
+	filter.MaxPrice, _ = params["max_price"].(float64)
+	filter.Rooms, _ = params["rooms"].(int)
+	filter.HouseType, _ = params["house_type"].(int)
+	if filter == (houseSearchFilter{}) {
+		return
+	}
+
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return
+	}
+	key := fmt.Sprintf(userSearchKey, userID)
+	if err := redis.LPush(ctx, key, string(data)); err != nil {
+		logger.FromContext(ctx).With(zap.Error(err)).Warn(fmt.Sprintf("记录用户%d搜索条件失败", userID))
+		return
+	}
+	_ = redis.LTrim(ctx, key, 0, userSearchLimit-1)
+	_ = redis.Expire(ctx, key, userSearchTTL)
+}
+
+// GetRecommendations 根据用户的收藏、预约看房和搜索记录推荐房源
+// 用户暂无偏好数据时返回热门房源，第二个返回值表示结果是否为个性化推荐
+func (s *houseService) GetRecommendations(ctx context.Context, userID uint, limit int) ([]model.House, bool, error) {
+	// 未登录用户直接返回热门房源
+	if userID == 0 {
+		trending, err := s.GetTrendingHouses(ctx, map[string]interface{}{}, limit)
+		if err != nil {
+			return nil, false, err
+		}
+		houses := make([]model.House, len(trending))
+		for i := range trending {
+			houses[i] = trending[i].House
+		}
+		return houses, false, nil
+	}
+
+	opts := cache.Options[houseRecommendation]{
+		TTL:    10 * time.Minute,
+		Jitter: 0.1,
+		Tags: func(houseRecommendation) []string {
+			return []string{houseListTag}
+		},
+	}
+	key := fmt.Sprintf("%d:%d", userID, limit)
+	result, err := cache.GetOrLoad(ctx, houseRecommendCache, key, opts, func(ctx context.Context) (houseRecommendation, error) {
+		return s.loadRecommendations(ctx, userID, limit)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	applyPendingViews(ctx, housePointers(result.Houses)...)
+	return result.Houses, result.Personalized, nil
+}
+
+// loadRecommendations 汇总用户偏好并对候选房源打分，推荐数量不足时用热门房源补足
+func (s *houseService) loadRecommendations(ctx context.Context, userID uint, limit int) (houseRecommendation, error) {
+	preference, seenIDs, err := s.buildUserPreference(ctx, userID)
+	if err != nil {
+		return houseRecommendation{}, err
+	}
+	if preference.empty() {
+		houses, err := s.trendingFallback(ctx, limit, nil)
+		return houseRecommendation{Houses: houses}, err
+	}
+
+	params := preference.candidateParams()
+	params["exclude_ids"] = seenIDs
+	candidates, err := s.repo.GetAll(ctx, params)
+	if err != nil {
+		return houseRecommendation{}, err
+	}
+	houses := rankHouses(candidates, preference, limit)
+
+	if len(houses) < limit {
+		exclude := append([]uint{}, seenIDs...)
+		for _, house := range houses {
+			exclude = append(exclude, house.ID)
+		}
+		fallback, err := s.trendingFallback(ctx, limit-len(houses), exclude)
+		if err != nil {
+			logger.FromContext(ctx).With(zap.Error(err)).Warn("获取热门房源补充推荐失败")
+		}
+		houses = append(houses, fallback...)
+	}
+	return houseRecommendation{Houses: houses, Personalized: true}, nil
+}
+
+// buildUserPreference 根据用户的收藏、预约看房和最近搜索汇总偏好，同时返回用户已收藏或预约过的房源ID
+func (s *houseService) buildUserPreference(ctx context.Context, userID uint) (*housePreference, []uint, error) {
+	preference := newHousePreference()
+	weights := make(map[uint]float64)
+
+	favorites, err := s.favoriteRepo.GetFavoritesByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, favorite := range favorites {
+		weights[favorite.HouseID] += preferenceWeightFavorite
+	}
+
+	viewings, err := s.viewingRepo.GetViewingsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, viewing := range viewings {
+		weights[viewing.HouseID] += preferenceWeightViewing
+	}
+
+	seenIDs := make([]uint, 0, len(weights))
+	for id := range weights {
+		seenIDs = append(seenIDs, id)
+	}
+	if len(seenIDs) > 0 {
+		// 已出租或下架的房源同样能体现用户偏好，不限制状态
+		houses, err := s.repo.GetAll(ctx, map[string]interface{}{"ids": seenIDs})
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range houses {
+			preference.addHouse(&houses[i], weights[houses[i].ID])
+		}
+	}
+
+	searches, err := redis.LRange(ctx, fmt.Sprintf(userSearchKey, userID), 0, userSearchLimit-1)
+	if err != nil && err != redis.Nil {
+		logger.FromContext(ctx).With(zap.Error(err)).Warn(fmt.Sprintf("读取用户%d搜索条件失败", userID))
+	}
+	for _, data := range searches {
+		var filter houseSearchFilter
+		if json.Unmarshal([]byte(data), &filter) == nil {
+			preference.addSearch(filter, preferenceWeightSearch)
+		}
+	}
+
+	return preference, seenIDs, nil
+}
+
+// GetSimilarHouses 获取与指定房源相似的房源，按租金、户型、房屋类型和距离综合排序
+func (s *houseService) GetSimilarHouses(ctx context.Context, id uint, limit int) ([]model.House, error) {
+	opts := cache.Options[[]model.House]{
+		TTL:    10 * time.Minute,
+		Jitter: 0.1,
+		Tags: func(houses []model.House) []string {
+			tags := []string{houseListTag, houseTag(id)}
+			for _, house := range houses {
+				tags = append(tags, houseTag(house.ID))
+			}
+			return tags
+		},
+	}
+	key := fmt.Sprintf("%d:%d", id, limit)
+	houses, err := cache.GetOrLoad(ctx, houseSimilarCache, key, opts, func(ctx context.Context) ([]model.House, error) {
+		house, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		preference := newHousePreference()
+		preference.addHouse(house, 1)
+		candidates, err := s.repo.GetAll(ctx, map[string]interface{}{
+			"status":      model.HouseStatusPublished,
+			"not_expired": true,
+			"house_type":  house.HouseType,
+			"min_price":   house.RentPrice * (1 - similarPriceRatio),
+			"max_price":   house.RentPrice * (1 + similarPriceRatio),
+			"exclude_ids": []uint{house.ID},
+			"order_by":    "updated_at DESC",
+			"limit":       recommendCandidateSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return rankHouses(candidates, preference, limit), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	applyPendingViews(ctx, housePointers(houses)...)
+	return houses, nil
+}
+
+// trendingFallback 冷启动时使用热门房源作为推荐结果，排除指定的房源
+func (s *houseService) trendingFallback(ctx context.Context, limit int, excludeIDs []uint) ([]model.House, error) {
+	if limit <= 0 {
+		return []model.House{}, nil
+	}
+
+	excluded := make(map[uint]bool, len(excludeIDs))
+	for _, id := range excludeIDs {
+		excluded[id] = true
+	}
+
+	trending, err := s.loadTrendingHouses(ctx, map[string]interface{}{}, limit+len(excludeIDs))
+	if err != nil {
+		return nil, err
+	}
+	houses := make([]model.House, 0, limit)
+	for _, item := range trending {
+		if len(houses) == limit {
+			break
+		}
+		if !excluded[item.House.ID] {
+			houses = append(houses, item.House)
+		}
+	}
+	return houses, nil
+}
+
+// rankHouses 按偏好匹配度从高到低排序，返回前limit个房源
+func rankHouses(candidates []model.House, preference *housePreference, limit int) []model.House {
+	scores := make(map[uint]float64, len(candidates))
+	for i := range candidates {
+		scores[candidates[i].ID] = preference.score(&candidates[i])
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return scores[candidates[i].ID] > scores[candidates[j].ID]
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"myApp/model"
+	"myApp/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// houseTrackedFields 记录修改历史的房源字段，键为数据库列名
+var houseTrackedFields = []struct {
+	column string
+	value  func(h *model.House) interface{}
+}{
+	{"title", func(h *model.House) interface{} { return h.Title }},
+	{"description", func(h *model.House) interface{} { return h.Description }},
+	{"address", func(h *model.House) interface{} { return h.Address }},
+	{"province_code", func(h *model.House) interface{} { return h.ProvinceCode }},
+	{"city_code", func(h *model.House) interface{} { return h.CityCode }},
+	{"district_code", func(h *model.House) interface{} { return h.DistrictCode }},
+	{"community_code", func(h *model.House) interface{} { return h.CommunityCode }},
+	{"area", func(h *model.House) interface{} { return h.Area }},
+	{"floor", func(h *model.House) interface{} { return h.Floor }},
+	{"total_floor", func(h *model.House) interface{} { return h.TotalFloor }},
+	{"rooms", func(h *model.House) interface{} { return h.Rooms }},
+	{"halls", func(h *model.House) interface{} { return h.Halls }},
+	{"bathrooms", func(h *model.House) interface{} { return h.Bathrooms }},
+	{"rent_price", func(h *model.House) interface{} { return h.RentPrice }},
+	{"deposit", func(h *model.House) interface{} { return h.Deposit }},
+	{"payment_type", func(h *model.House) interface{} { return h.PaymentType }},
+	{"house_type", func(h *model.House) interface{} { return h.HouseType }},
+	{"orientation", func(h *model.House) interface{} { return h.Orientation }},
+	{"decoration", func(h *model.House) interface{} { return h.Decoration }},
+	{"facilities", func(h *model.House) interface{} { return h.Facilities }},
+	{"images", func(h *model.House) interface{} { return h.Images }},
+	{"latitude", func(h *model.House) interface{} { return h.Latitude }},
+	{"longitude", func(h *model.House) interface{} { return h.Longitude }},
+	{"is_elevator", func(h *model.House) interface{} { return h.IsElevator }},
+	{"status", func(h *model.House) interface{} { return h.Status }},
+	{"reject_reason", func(h *model.House) interface{} { return h.RejectReason }},
+}
+
+// diffHouse 比较修改前后的房源，返回发生变化的字段
+func diffHouse(old, new *model.House) []model.HouseFieldChange {
+	var changes []model.HouseFieldChange
+	for _, f := range houseTrackedFields {
+		oldValue, newValue := f.value(old), f.value(new)
+		if oldValue != newValue {
+			changes = append(changes, model.HouseFieldChange{Field: f.column, Old: oldValue, New: newValue})
+		}
+	}
+	return changes
+}
+
+// diffHouseColumns 比较待更新的列与房源当前值，返回发生变化的字段
+func diffHouseColumns(house *model.House, columns map[string]interface{}) []model.HouseFieldChange {
+	var changes []model.HouseFieldChange
+	for _, f := range houseTrackedFields {
+		newValue, ok := columns[f.column]
+		if !ok {
+			continue
+		}
+		if oldValue := f.value(house); oldValue != newValue {
+			changes = append(changes, model.HouseFieldChange{Field: f.column, Old: oldValue, New: newValue})
+		}
+	}
+	return changes
+}
+
+// recordHouseChanges 记录房源修改历史，租金变动时追加租金记录并通知开启降价提醒的收藏用户
+// 记录失败只写日志，不影响房源本身的修改
+func (s *houseService) recordHouseChanges(ctx context.Context, house *model.House, operatorID uint, changes []model.HouseFieldChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(changes)
+	if err == nil {
+		err = s.revisionRepo.Create(ctx, &model.HouseRevision{
+			HouseID:    house.ID,
+			OperatorID: operatorID,
+			Changes:    string(data),
+		})
+	}
+	if err != nil {
+		logger.FromContext(ctx).With(zap.Error(err)).Error(fmt.Sprintf("记录房源%d修改历史失败", house.ID))
+	}
+
+	for _, change := range changes {
+		if change.Field != "rent_price" {
+			continue
+		}
+		oldPrice, _ := change.Old.(float64)
+		newPrice, _ := change.New.(float64)
+		if err := s.priceHistoryRepo.Create(ctx, &model.HousePriceHistory{
+			HouseID:   house.ID,
+			Price:     newPrice,
+			PrevPrice: oldPrice,
+		}); err != nil {
+			logger.FromContext(ctx).With(zap.Error(err)).Error(fmt.Sprintf("记录房源%d租金变动失败", house.ID))
+		}
+		if newPrice < oldPrice {
+			s.notifyPriceDrop(ctx, house, oldPrice, newPrice)
+		}
+	}
+}
+
+// notifyPriceDrop 向开启降价提醒的收藏用户发送站内通知
+func (s *houseService) notifyPriceDrop(ctx context.Context, house *model.House, oldPrice, newPrice float64) {
+	favorites, err := s.favoriteRepo.GetPriceDropSubscribers(ctx, house.ID)
+	if err != nil {
+		logger.FromContext(ctx).With(zap.Error(err)).Error(fmt.Sprintf("获取房源%d降价提醒用户失败", house.ID))
+		return
+	}
+
+	for _, f := range favorites {
+		err := s.notificationService.Notify(ctx, &model.Notification{
+			UserID:    f.UserID,
+			Type:      model.NotificationPriceDrop,
+			Title:     "收藏的房源降价了",
+			Content:   fmt.Sprintf("您收藏的房源「%s」租金由%.2f元/月降至%.2f元/月", house.Title, oldPrice, newPrice),
+			RelatedID: house.ID,
+		})
+		if err != nil {
+			logger.FromContext(ctx).With(zap.Error(err)).Error(fmt.Sprintf("发送房源%d降价通知失败", house.ID))
+		}
+	}
+}
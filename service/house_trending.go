@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"myApp/config"
+	"myApp/model"
+	"myApp/pkg/logger"
+	"myApp/pkg/redis"
+	"myApp/pkg/redis/cache"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 房源热度保存在Redis有序集合中，采用前向衰减：
+// 每次行为的加分为 权重×2^((当前时间-基准时间)/半衰期)，越新的行为加分越高，
+// 分数之间的比较等价于对所有行为按时间衰减后求和，读取时再除以当前的衰减系数得到实际热度
+const (
+	houseTrendingKey      = "house:trending"       // 房源热度有序集合，成员为房源ID
+	houseTrendingEpochKey = "house:trending:epoch" // 热度计算的基准时间（Unix秒）
+)
+
+// 各类行为的热度权重
+const (
+	trendingWeightView    = 1  // 浏览
+	trendingWeightFavor   = 5  // 收藏
+	trendingWeightViewing = 10 // 预约看房
+)
+
+const (
+	// trendingCandidateSize 热门榜单从有序集合中读取的候选房源数量，再按筛选条件过滤
+	trendingCandidateSize = 500
+	// trendingMinScore 衰减后低于该分数的房源从有序集合中移除
+	trendingMinScore = 0.05
+	// trendingRebaseHalfLives 基准时间距今超过该数量的半衰期时重置基准时间，避免分数溢出
+	trendingRebaseHalfLives = 32
+)
+
+// TrendingHouse 热门房源及其当前热度
+type TrendingHouse struct {
+	House model.House
+	Score float64 // 衰减后的热度
+}
+
+// trendingIncrScript 按前向衰减累加房源热度，基准时间不存在时以当前时间为基准
+var trendingIncrScript = redis.NewScript(`
+local epoch = tonumber(redis.call('GET', KEYS[2]))
+local now = tonumber(ARGV[2])
+if not epoch then
+	epoch = now
+	redis.call('SET', KEYS[2], epoch)
+end
+local score = tonumber(ARGV[1]) * math.pow(2, (now - epoch) / tonumber(ARGV[3]))
+return redis.call('ZINCRBY', KEYS[1], score, ARGV[4])
+`)
+
+// trendingDecayScript 移除热度过低的房源，并在需要时将基准时间重置为当前时间、按比例缩小全部分数
+var trendingDecayScript = redis.NewScript(`
+local epoch = tonumber(redis.call('GET', KEYS[2]))
+if not epoch then
+	return 0
+end
+local now = tonumber(ARGV[1])
+local halfLife = tonumber(ARGV[2])
+local exponent = (now - epoch) / halfLife
+local removed = redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', '(' .. (tonumber(ARGV[3]) * math.pow(2, exponent)))
+if exponent > tonumber(ARGV[4]) then
+	redis.call('ZUNIONSTORE', KEYS[1], 1, KEYS[1], 'WEIGHTS', math.pow(2, -exponent))
+	redis.call('SET', KEYS[2], now)
+end
+return removed
+`)
+
+// recordHouseActivity 累加房源热度，失败只记录日志，不影响业务流程
+func recordHouseActivity(ctx context.Context, houseID uint, weight float64) {
+	_, err := redis.RunScript(ctx, trendingIncrScript,
+		[]string{houseTrendingKey, houseTrendingEpochKey},
+		weight, time.Now().Unix(), trendingHalfLife(), strconv.FormatUint(uint64(houseID), 10))
+	if err != nil {
+		logger.FromContext(ctx).With(zap.Error(err)).Warn(fmt.Sprintf("更新房源%d热度失败", houseID))
+	}
+}
+
+// GetTrendingHouses 获取热门房源，按热度从高到低排列，params支持城市、区县和房屋类型筛选
+func (s *houseService) GetTrendingHouses(ctx context.Context, params map[string]interface{}, limit int) ([]TrendingHouse, error) {
+	paramsData, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey := fmt.Sprintf("trending:%d:%x", limit, paramsData)
+
+	// 热门榜单变化较快，只做短时间缓存
+	opts := cache.Options[[]TrendingHouse]{
+		TTL:      time.Minute,
+		LocalTTL: 5 * time.Second,
+		Tags: func([]TrendingHouse) []string {
+			return []string{houseListTag}
+		},
+	}
+	trending, err := cache.GetOrLoad(ctx, houseListCache, cacheKey, opts, func(ctx context.Context) ([]TrendingHouse, error) {
+		return s.loadTrendingHouses(ctx, params, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	houses := make([]*model.House, len(trending))
+	for i := range trending {
+		houses[i] = &trending[i].House
+	}
+	applyPendingViews(ctx, houses...)
+	return trending, nil
+}
+
+// loadTrendingHouses 从有序集合读取候选房源，过滤掉未发布和不符合筛选条件的房源
+func (s *houseService) loadTrendingHouses(ctx context.Context, params map[string]interface{}, limit int) ([]TrendingHouse, error) {
+	members, err := redis.ZRevRangeWithScores(ctx, houseTrendingKey, 0, trendingCandidateSize-1)
+	if err != nil {
+		return nil, err
+	}
+
+	// 当前的衰减系数，用于将有序集合中的分数换算为实际热度
+	decay := 1.0
+	if epochStr, err := redis.Get(ctx, houseTrendingEpochKey); err == nil {
+		if epoch, err := strconv.ParseInt(epochStr, 10, 64); err == nil {
+			decay = math.Pow(2, float64(time.Now().Unix()-epoch)/trendingHalfLife())
+		}
+	}
+
+	scores := make(map[uint]float64, len(members))
+	ids := make([]uint, 0, len(members))
+	for _, member := range members {
+		memberStr, ok := member.Member.(string)
+		if !ok {
+			continue
+		}
+		id, err := strconv.ParseUint(memberStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		scores[uint(id)] = member.Score / decay
+		ids = append(ids, uint(id))
+	}
+	if len(ids) == 0 {
+		return []TrendingHouse{}, nil
+	}
+
+	query := make(map[string]interface{}, len(params)+3)
+	for key, value := range params {
+		query[key] = value
+	}
+	query["ids"] = ids
+	query["status"] = model.HouseStatusPublished
+	query["not_expired"] = true
+
+	houses, err := s.repo.GetAll(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(houses, func(i, j int) bool {
+		return scores[houses[i].ID] > scores[houses[j].ID]
+	})
+	if len(houses) > limit {
+		houses = houses[:limit]
+	}
+
+	trending := make([]TrendingHouse, len(houses))
+	for i, house := range houses {
+		trending[i] = TrendingHouse{House: house, Score: math.Round(scores[house.ID]*100) / 100}
+	}
+	return trending, nil
+}
+
+// DecayTrending 移除热度过低的房源，并定期重置热度基准时间，返回移除的房源数量
+func (s *houseService) DecayTrending(ctx context.Context) (int64, error) {
+	result, err := redis.RunScript(ctx, trendingDecayScript,
+		[]string{houseTrendingKey, houseTrendingEpochKey},
+		time.Now().Unix(), trendingHalfLife(), trendingMinScore, trendingRebaseHalfLives)
+	if err != nil {
+		return 0, err
+	}
+	removed, _ := result.(int64)
+	return removed, nil
+}
+
+// trendingHalfLife 热度半衰期（秒）
+func trendingHalfLife() float64 {
+	return float64(time.Duration(config.Conf.House.TrendingHalfLife) * time.Hour / time.Second)
+}
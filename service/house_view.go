@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"myApp/config"
+	"myApp/model"
+	"myApp/pkg/logger"
+	"myApp/pkg/redis"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 浏览次数先累加到Redis哈希中，由定时任务批量写回数据库
+const (
+	houseViewPendingKey  = "house:views:pending"    // 待写回的浏览次数，字段为房源ID
+	houseViewFlushingKey = "house:views:flushing"   // 正在写回的浏览次数
+	houseViewFlushLock   = "house:views:flush:lock" // 写回任务锁，避免多个实例同时写回
+	houseViewSeenKey     = "house:view:seen:%d:%s"  // 访客浏览记录，用于去重
+)
+
+// houseViewFlushLockTTL 写回任务锁的有效期
+const houseViewFlushLockTTL = 5 * time.Minute
+
+// restoreViewScript 将写回失败的浏览次数放回待写回哈希，并从正在写回的哈希中移除该字段
+// KEYS[1]为待写回哈希，KEYS[2]为正在写回的哈希；ARGV[1]为房源ID，ARGV[2]为浏览次数
+var restoreViewScript = redis.NewScript(`
+redis.call('HINCRBY', KEYS[1], ARGV[1], ARGV[2])
+return redis.call('HDEL', KEYS[2], ARGV[1])
+`)
+
+// RecordView 记录访客浏览房源，同一访客在去重窗口内的重复浏览只计一次
+// visitor为访客标识，登录用户为"u:用户ID"，匿名访客为"ip:IP地址"
+func (s *houseService) RecordView(ctx context.Context, id uint, visitor string) error {
+	window := time.Duration(config.Conf.House.ViewDedupeWindow) * time.Second
+	first, err := redis.SetNX(ctx, fmt.Sprintf(houseViewSeenKey, id, visitor), 1, window)
+	if err != nil {
+		return err
+	}
+	if !first {
+		return nil
+	}
+
+	if _, err := redis.HIncrBy(ctx, houseViewPendingKey, strconv.FormatUint(uint64(id), 10), 1); err != nil {
+		return err
+	}
+	recordHouseActivity(ctx, id, trendingWeightView)
+	return nil
+}
+
+// FlushViewCounts 将Redis中累加的浏览次数批量写回数据库，返回写回的房源数量
+// 每个房源写回数据库后立即从正在写回的哈希中删除，中断后重新执行时只写回剩余的房源，不会重复累加
+func (s *houseService) FlushViewCounts(ctx context.Context) (int, error) {
+	token, err := redis.TryLock(ctx, houseViewFlushLock, houseViewFlushLockTTL)
+	if err != nil {
+		return 0, err
+	}
+	if token == "" {
+		return 0, nil
+	}
+	defer func() {
+		if err := redis.Unlock(ctx, houseViewFlushLock, token); err != nil {
+			logger.FromContext(ctx).With(zap.Error(err)).Warn("释放浏览次数写回锁失败")
+		}
+	}()
+
+	// 上次写回中断时遗留的数据先写回，避免被本次覆盖
+	exists, err := redis.Exists(ctx, houseViewFlushingKey)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		exists, err = redis.Exists(ctx, houseViewPendingKey)
+		if err != nil || !exists {
+			return 0, err
+		}
+		// 重命名后新的浏览记录会累加到新的待写回哈希中
+		if err := redis.Rename(ctx, houseViewPendingKey, houseViewFlushingKey); err != nil {
+			return 0, err
+		}
+	}
+
+	counts, err := redis.HGetAll(ctx, houseViewFlushingKey)
+	if err != nil {
+		return 0, err
+	}
+
+	flushed := 0
+	for field, value := range counts {
+		id, idErr := strconv.ParseUint(field, 10, 64)
+		delta, deltaErr := strconv.ParseInt(value, 10, 64)
+		if idErr != nil || deltaErr != nil || delta <= 0 {
+			// 无效的字段直接丢弃
+			if err := redis.HDel(ctx, houseViewFlushingKey, field); err != nil {
+				return flushed, err
+			}
+			continue
+		}
+
+		if err := s.repo.IncrementViewCount(ctx, uint(id), delta); err != nil {
+			// 写回失败的次数放回待写回哈希，下次重试
+			logger.FromContext(ctx).With(zap.Error(err)).Error(fmt.Sprintf("写回房源%d浏览次数失败", id))
+			if _, err := redis.RunScript(ctx, restoreViewScript, []string{houseViewPendingKey, houseViewFlushingKey}, field, delta); err != nil {
+				return flushed, err
+			}
+			continue
+		}
+		// 数据库已更新，删除字段后即使任务中断也不会重复写回
+		if err := redis.HDel(ctx, houseViewFlushingKey, field); err != nil {
+			return flushed, err
+		}
+		// 详情缓存中的浏览次数已过时，删除后重新加载；列表缓存到期后自然更新
+		_ = houseCache.Delete(ctx, field)
+		flushed++
+	}
+	return flushed, nil
+}
+
+// applyPendingViews 将尚未写回数据库的浏览次数累加到房源的ViewCount上
+func applyPendingViews(ctx context.Context, houses ...*model.House) {
+	if len(houses) == 0 {
+		return
+	}
+
+	fields := make([]string, len(houses))
+	for i, house := range houses {
+		fields[i] = strconv.FormatUint(uint64(house.ID), 10)
+	}
+
+	for _, key := range []string{houseViewPendingKey, houseViewFlushingKey} {
+		values, err := redis.HMGet(ctx, key, fields...)
+		if err != nil {
+			return
+		}
+		for i, value := range values {
+			str, ok := value.(string)
+			if !ok {
+				continue
+			}
+			if delta, err := strconv.Atoi(str); err == nil {
+				houses[i].ViewCount += delta
+			}
+		}
+	}
+}
+
+// housePointers 返回指向房源切片元素的指针，便于就地修改
+func housePointers(houses []model.House) []*model.House {
+	pointers := make([]*model.House, len(houses))
+	for i := range houses {
+		pointers[i] = &houses[i]
+	}
+	return pointers
+}
@@ -1,97 +1,390 @@
 package service
 
 import (
-	"errors"
+	"context"
+	"math"
 	"myApp/model"
+	"myApp/pkg/errors"
+	"myApp/pkg/idcard"
 	"myApp/repository"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 type LandlordService interface {
-	CreateLandlord(landlord *model.Landlord) error
-	GetLandlordByID(id uint) (*model.Landlord, error)
-	GetLandlordByUserID(userID uint) (*model.Landlord, error)
-	UpdateLandlord(landlord *model.Landlord) error
-	DeleteLandlord(id uint) error
-	VerifyLandlord(id uint) error
+	CreateLandlord(ctx context.Context, landlord *model.Landlord, documents string) error
+	GetLandlordByID(ctx context.Context, id uint) (*model.Landlord, error)
+	GetLandlordByUserID(ctx context.Context, userID uint) (*model.Landlord, error)
+	GetPublicProfile(ctx context.Context, id uint) (*LandlordPublicProfile, error)
+	UpdateLandlord(ctx context.Context, landlord *model.Landlord) error
+	DeleteLandlord(ctx context.Context, id uint) error
+	SubmitVerification(ctx context.Context, userID uint, verification *model.LandlordVerification) error
+	GetLatestVerification(ctx context.Context, userID uint) (*model.LandlordVerification, error)
+	GetVerificationQueue(ctx context.Context, params map[string]interface{}) ([]model.LandlordVerification, int64, error)
+	ApproveVerification(ctx context.Context, id, reviewerID uint) error
+	RejectVerification(ctx context.Context, id, reviewerID uint, reason string) error
+}
+
+// LandlordPublicProfile 房东公开主页信息，供租客查看
+type LandlordPublicProfile struct {
+	Landlord       *model.Landlord
+	ActiveListings int64    // 已发布且未过期的房源数量
+	ResponseRate   *float64 // 看房预约响应率，暂无预约时为nil
 }
 
 type landlordService struct {
-	repo repository.LandlordRepository
-	userRepo repository.UserRepository
+	repo             repository.LandlordRepository
+	userRepo         repository.UserRepository
+	verificationRepo repository.LandlordVerificationRepository
+	houseRepo        repository.HouseRepository
+	viewingRepo      repository.ViewingRepository
+	txManager        repository.TxManager
 }
 
-func NewLandlordService(repo repository.LandlordRepository, userRepo repository.UserRepository) LandlordService {
-	return &landlordService{repo: repo, userRepo: userRepo}
+func NewLandlordService(repo repository.LandlordRepository, userRepo repository.UserRepository, verificationRepo repository.LandlordVerificationRepository, houseRepo repository.HouseRepository, viewingRepo repository.ViewingRepository, txManager repository.TxManager) LandlordService {
+	return &landlordService{
+		repo:             repo,
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		houseRepo:        houseRepo,
+		viewingRepo:      viewingRepo,
+		txManager:        txManager,
+	}
 }
 
-func (s *landlordService) CreateLandlord(landlord *model.Landlord) error {
+// CreateLandlord 创建房东信息并提交首次认证申请
+// 房东创建后处于未认证状态，用户类型在管理员审核通过后才会变更为房东
+func (s *landlordService) CreateLandlord(ctx context.Context, landlord *model.Landlord, documents string) error {
 	// 检查用户是否存在
-	user, err := s.userRepo.FindByID(landlord.UserID)
+	user, err := s.userRepo.FindByID(ctx, landlord.UserID)
 	if err != nil {
-		return errors.New("用户不存在")
+		return notFoundOr(err, "用户不存在")
 	}
-	
+
 	// 检查用户是否已经是房东
-	existingLandlord, _ := s.repo.FindByUserID(landlord.UserID)
+	existingLandlord, err := s.repo.FindByUserID(ctx, landlord.UserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
 	if existingLandlord != nil {
-		return errors.New("该用户已经是房东")
+		return errors.Conflict("该用户已经是房东")
 	}
-	
-	// 更新用户类型为房东
-	user.UserType = 1 // 1-房东
-	if err := s.userRepo.Update(user); err != nil {
+
+	// 校验身份信息
+	landlord.IDNumber = idcard.Normalize(landlord.IDNumber)
+	if err := s.validateIdentity(ctx, user, 0, landlord.RealName, landlord.IDNumber); err != nil {
 		return err
 	}
-	
-	return s.repo.Create(landlord)
+
+	// 收款账户必须为本人账户
+	if landlord.AccountName != "" && landlord.AccountName != landlord.RealName {
+		return errors.Validation("开户人姓名必须与真实姓名一致")
+	}
+
+	// 房东信息与首次认证申请在同一事务中创建
+	landlord.Verified = false
+	return s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, landlord); err != nil {
+			return err
+		}
+
+		// 提交首次认证申请
+		return s.verificationRepo.Create(ctx, &model.LandlordVerification{
+			LandlordID:  landlord.ID,
+			UserID:      landlord.UserID,
+			RealName:    landlord.RealName,
+			IDNumber:    landlord.IDNumber,
+			IdCardFront: landlord.IdCardFront,
+			IdCardBack:  landlord.IdCardBack,
+			Documents:   documents,
+			Status:      model.VerificationPending,
+		})
+	})
 }
 
-func (s *landlordService) GetLandlordByID(id uint) (*model.Landlord, error) {
-	return s.repo.FindByID(id)
+func (s *landlordService) GetLandlordByID(ctx context.Context, id uint) (*model.Landlord, error) {
+	return s.repo.FindByID(ctx, id)
 }
 
-func (s *landlordService) GetLandlordByUserID(userID uint) (*model.Landlord, error) {
-	return s.repo.FindByUserID(userID)
+func (s *landlordService) GetLandlordByUserID(ctx context.Context, userID uint) (*model.Landlord, error) {
+	landlord, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, notFoundOr(err, "房东信息不存在")
+	}
+	return landlord, nil
 }
 
-func (s *landlordService) UpdateLandlord(landlord *model.Landlord) error {
+// GetPublicProfile 获取房东公开主页信息，包含上架房源数量和看房预约响应率
+func (s *landlordService) GetPublicProfile(ctx context.Context, id uint) (*LandlordPublicProfile, error) {
+	landlord, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "房东不存在")
+	}
+
+	// 房源的LandlordID为房东的用户ID
+	activeListings, err := s.houseRepo.Count(ctx, map[string]interface{}{
+		"landlord_id": landlord.UserID,
+		"status":      model.HouseStatusPublished,
+		"not_expired": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &LandlordPublicProfile{
+		Landlord:       landlord,
+		ActiveListings: activeListings,
+	}
+
+	total, handled, err := s.viewingRepo.GetResponseStats(ctx, landlord.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if total > 0 {
+		rate := math.Round(float64(handled)/float64(total)*100) / 100
+		profile.ResponseRate = &rate
+	}
+
+	return profile, nil
+}
+
+func (s *landlordService) UpdateLandlord(ctx context.Context, landlord *model.Landlord) error {
 	// 检查房东是否存在
-	existingLandlord, err := s.repo.FindByID(landlord.ID)
+	existingLandlord, err := s.repo.FindByID(ctx, landlord.ID)
 	if err != nil {
-		return errors.New("房东不存在")
+		return errors.NotFound("房东不存在")
 	}
-	
+
 	// 保持用户ID不变
 	landlord.UserID = existingLandlord.UserID
-	
-	return s.repo.Update(landlord)
+
+	// 身份信息和认证状态只能通过认证申请变更
+	landlord.RealName = existingLandlord.RealName
+	landlord.IDNumber = existingLandlord.IDNumber
+	landlord.IdCardFront = existingLandlord.IdCardFront
+	landlord.IdCardBack = existingLandlord.IdCardBack
+	landlord.Verified = existingLandlord.Verified
+
+	// 收款账户必须为本人账户
+	if landlord.AccountName != "" && landlord.AccountName != landlord.RealName {
+		return errors.Validation("开户人姓名必须与真实姓名一致")
+	}
+
+	return s.repo.Update(ctx, landlord)
 }
 
-func (s *landlordService) DeleteLandlord(id uint) error {
+func (s *landlordService) DeleteLandlord(ctx context.Context, id uint) error {
 	// 获取房东信息
-	landlord, err := s.repo.FindByID(id)
+	landlord, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return errors.NotFound("房东不存在")
+	}
+
+	// 用户类型恢复为普通用户与删除房东在同一事务中完成，任一步失败都不做修改
+	return s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.FindByID(ctx, landlord.UserID)
+		if err != nil {
+			return errors.NotFound("用户不存在")
+		}
+		user.UserType = model.UserTypeNormal
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return s.repo.Delete(ctx, id)
+	})
+}
+
+// SubmitVerification 提交认证申请，仅在没有待审核申请且尚未认证时允许提交
+func (s *landlordService) SubmitVerification(ctx context.Context, userID uint, verification *model.LandlordVerification) error {
+	landlord, err := s.repo.FindByUserID(ctx, userID)
 	if err != nil {
-		return errors.New("房东不存在")
+		return notFoundOr(err, "房东不存在")
+	}
+	if landlord.Verified {
+		return errors.Conflict("房东已完成认证")
 	}
-	
-	// 更新用户类型为普通用户
-	user, err := s.userRepo.FindByID(landlord.UserID)
-	if err == nil {
-		user.UserType = 0 // 0-普通用户
-		s.userRepo.Update(user)
+
+	// 存在待审核的申请时不允许重复提交
+	latest, err := s.verificationRepo.GetLatestByLandlordID(ctx, landlord.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if latest != nil && latest.Status == model.VerificationPending {
+		return errors.Conflict("已有待审核的认证申请")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return notFoundOr(err, "用户不存在")
 	}
-	
-	return s.repo.Delete(id)
+
+	// 校验身份信息
+	verification.IDNumber = idcard.Normalize(verification.IDNumber)
+	if err := s.validateIdentity(ctx, user, landlord.ID, verification.RealName, verification.IDNumber); err != nil {
+		return err
+	}
+	if landlord.AccountName != "" && landlord.AccountName != verification.RealName {
+		return errors.Validation("开户人姓名必须与真实姓名一致")
+	}
+
+	verification.LandlordID = landlord.ID
+	verification.UserID = userID
+	verification.Status = model.VerificationPending
+	verification.RejectReason = ""
+	verification.ReviewerID = 0
+	verification.ReviewedAt = nil
+	return s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.verificationRepo.Create(ctx, verification); err != nil {
+			return err
+		}
+
+		// 同步房东资料中的身份信息
+		landlord.RealName = verification.RealName
+		landlord.IDNumber = verification.IDNumber
+		landlord.IdCardFront = verification.IdCardFront
+		landlord.IdCardBack = verification.IdCardBack
+		return s.repo.Update(ctx, landlord)
+	})
 }
 
-func (s *landlordService) VerifyLandlord(id uint) error {
-	// 获取房东信息
-	landlord, err := s.repo.FindByID(id)
+// GetLatestVerification 获取用户最近一次提交的认证申请
+func (s *landlordService) GetLatestVerification(ctx context.Context, userID uint) (*model.LandlordVerification, error) {
+	landlord, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, notFoundOr(err, "房东不存在")
+	}
+
+	verification, err := s.verificationRepo.GetLatestByLandlordID(ctx, landlord.ID)
+	if err != nil {
+		return nil, notFoundOr(err, "认证申请不存在")
+	}
+	return verification, nil
+}
+
+// GetVerificationQueue 获取认证申请审核队列
+func (s *landlordService) GetVerificationQueue(ctx context.Context, params map[string]interface{}) ([]model.LandlordVerification, int64, error) {
+	return s.verificationRepo.GetAll(ctx, params)
+}
+
+// ApproveVerification 审核通过认证申请，标记房东为已认证并将用户类型变更为房东
+// 认证申请在事务中加锁读取，并发审核同一申请时只有一个能成功
+func (s *landlordService) ApproveVerification(ctx context.Context, id, reviewerID uint) error {
+	// 申请状态、房东认证状态和用户类型在同一事务中更新
+	return s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		verification, err := s.getPendingVerification(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		landlord, err := s.repo.FindByID(ctx, verification.LandlordID)
+		if err != nil {
+			return notFoundOr(err, "房东不存在")
+		}
+
+		// 同一身份证号只能认证一个房东
+		other, err := s.repo.FindVerifiedByIDNumber(ctx, verification.IDNumber)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if other != nil && other.ID != landlord.ID {
+			return errors.Conflict("该身份证号已被其他房东认证")
+		}
+
+		now := time.Now()
+		verification.Status = model.VerificationApproved
+		verification.ReviewerID = reviewerID
+		verification.ReviewedAt = &now
+		if err := s.verificationRepo.Update(ctx, verification); err != nil {
+			return err
+		}
+
+		// 更新认证状态
+		landlord.Verified = true
+		if err := s.repo.Update(ctx, landlord); err != nil {
+			return err
+		}
+
+		// 更新用户类型为房东
+		user, err := s.userRepo.FindByID(ctx, landlord.UserID)
+		if err != nil {
+			return notFoundOr(err, "用户不存在")
+		}
+		if user.UserType == model.UserTypeNormal {
+			user.UserType = model.UserTypeLandlord
+			return s.userRepo.Update(ctx, user)
+		}
+		return nil
+	})
+}
+
+// RejectVerification 驳回认证申请，房东可修改资料后重新提交
+func (s *landlordService) RejectVerification(ctx context.Context, id, reviewerID uint, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.Validation("驳回原因不能为空")
+	}
+
+	return s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		verification, err := s.getPendingVerification(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		verification.Status = model.VerificationRejected
+		verification.RejectReason = reason
+		verification.ReviewerID = reviewerID
+		verification.ReviewedAt = &now
+		return s.verificationRepo.Update(ctx, verification)
+	})
+}
+
+// getPendingVerification 加锁读取待审核的认证申请，需在事务中调用
+func (s *landlordService) getPendingVerification(ctx context.Context, id uint) (*model.LandlordVerification, error) {
+	verification, err := s.verificationRepo.GetByIDForUpdate(ctx, id)
 	if err != nil {
-		return errors.New("房东不存在")
+		return nil, notFoundOr(err, "认证申请不存在")
+	}
+	if verification.Status != model.VerificationPending {
+		return nil, errors.Conflict("认证申请已审核")
+	}
+	return verification, nil
+}
+
+// validateIdentity 校验身份证号合法性以及姓名、身份证号与用户实名信息的一致性
+func (s *landlordService) validateIdentity(ctx context.Context, user *model.User, landlordID uint, realName, idNumber string) error {
+	if strings.TrimSpace(realName) == "" {
+		return errors.Validation("真实姓名不能为空")
+	}
+	if err := idcard.Validate(idNumber); err != nil {
+		return errors.Validation(err.Error())
 	}
-	
-	// 更新认证状态
-	landlord.Verified = true
-	return s.repo.Update(landlord)
-}
\ No newline at end of file
+
+	// 用户已登记实名信息时，认证资料必须与之一致
+	if user.RealName != "" && user.RealName != realName {
+		return errors.Validation("姓名与用户实名信息不一致")
+	}
+	if user.IdCard != "" && idcard.Normalize(user.IdCard) != idNumber {
+		return errors.Validation("身份证号与用户实名信息不一致")
+	}
+
+	// 同一身份证号只能认证一个房东
+	other, err := s.repo.FindVerifiedByIDNumber(ctx, idNumber)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if other != nil && other.ID != landlordID {
+		return errors.Conflict("该身份证号已被其他房东认证")
+	}
+	return nil
+}
+
+// notFoundOr 记录不存在时返回带提示信息的资源不存在错误，其他数据库错误原样返回，由错误处理中间件按服务器内部错误处理
+func notFoundOr(err error, message string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.NotFound(message)
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"myApp/model"
+	"myApp/model/modeltest"
+	"myApp/pkg/errors"
+	"myApp/repository"
+)
+
+// failingUserRepository 更新用户时返回错误，用于验证事务回滚
+type failingUserRepository struct {
+	repository.UserRepository
+}
+
+func (r failingUserRepository) Update(ctx context.Context, user *model.User) error {
+	return stderrors.New("更新用户失败")
+}
+
+func TestApproveVerificationRollsBack(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	userRepo := repository.NewUserRepository(db)
+	landlordRepo := repository.NewLandlordRepository(db)
+	verificationRepo := repository.NewLandlordVerificationRepository(db)
+	newService := func(userRepo repository.UserRepository) LandlordService {
+		return NewLandlordService(landlordRepo, userRepo, verificationRepo, repository.NewHouseRepository(db),
+			repository.NewViewingRepository(db), repository.NewTxManager(db))
+	}
+
+	user := modeltest.Create(t, db, modeltest.User(), nil)
+	landlord := modeltest.Create(t, db, &model.Landlord{UserID: user.ID, RealName: "张三", IDNumber: "11010519491231002X"}, nil)
+	verification := modeltest.Create(t, db, &model.LandlordVerification{
+		LandlordID: landlord.ID,
+		UserID:     user.ID,
+		RealName:   landlord.RealName,
+		IDNumber:   landlord.IDNumber,
+		Status:     model.VerificationPending,
+	}, nil)
+
+	// 事务中最后一步失败时，申请状态和房东认证状态都回滚
+	if err := newService(failingUserRepository{userRepo}).ApproveVerification(ctx, verification.ID, 1); err == nil {
+		t.Fatal("更新用户失败时审核通过应返回错误")
+	}
+	got, err := verificationRepo.GetByID(ctx, verification.ID)
+	if err != nil || got.Status != model.VerificationPending || got.ReviewedAt != nil {
+		t.Fatalf("回滚后认证申请为%+v, %v，期望仍待审核", got, err)
+	}
+	if got, err := landlordRepo.FindByID(ctx, landlord.ID); err != nil || got.Verified {
+		t.Fatalf("回滚后房东为%+v, %v，期望未认证", got, err)
+	}
+
+	// 回滚后可以重新审核，已审核的申请不能再次审核
+	svc := newService(userRepo)
+	if err := svc.ApproveVerification(ctx, verification.ID, 1); err != nil {
+		t.Fatalf("审核通过失败: %v", err)
+	}
+	if got, err := userRepo.FindByID(ctx, user.ID); err != nil || got.UserType != model.UserTypeLandlord {
+		t.Fatalf("审核通过后用户为%+v, %v，期望用户类型为房东", got, err)
+	}
+	if err := svc.ApproveVerification(ctx, verification.ID, 1); errors.CodeOf(err) != errors.CodeConflict {
+		t.Errorf("重复审核返回%v，期望状态冲突", err)
+	}
+	if err := svc.RejectVerification(ctx, verification.ID, 1, "资料不清晰"); errors.CodeOf(err) != errors.CodeConflict {
+		t.Errorf("驳回已通过的申请返回%v，期望状态冲突", err)
+	}
+}
+
+// brokenLandlordRepository 查询房东时返回数据库错误
+type brokenLandlordRepository struct {
+	repository.LandlordRepository
+}
+
+func (r brokenLandlordRepository) FindByUserID(ctx context.Context, userID uint) (*model.Landlord, error) {
+	return nil, stderrors.New("数据库连接断开")
+}
+
+func TestCreateLandlordLookupErrors(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	userRepo := repository.NewUserRepository(db)
+	newService := func(landlordRepo repository.LandlordRepository) LandlordService {
+		return NewLandlordService(landlordRepo, userRepo, repository.NewLandlordVerificationRepository(db),
+			repository.NewHouseRepository(db), repository.NewViewingRepository(db), repository.NewTxManager(db))
+	}
+	user := modeltest.Create(t, db, modeltest.User(), nil)
+
+	svc := newService(repository.NewLandlordRepository(db))
+	if err := svc.CreateLandlord(ctx, &model.Landlord{UserID: user.ID + 100, RealName: "张三"}, ""); errors.CodeOf(err) != errors.CodeNotFound {
+		t.Errorf("用户不存在时返回%v，期望不存在", err)
+	}
+
+	// 数据库错误原样返回，不能当作用户还不是房东继续创建
+	err := newService(brokenLandlordRepository{repository.NewLandlordRepository(db)}).
+		CreateLandlord(ctx, &model.Landlord{UserID: user.ID, RealName: "张三"}, "")
+	if err == nil || errors.CodeOf(err) == errors.CodeNotFound {
+		t.Errorf("查询房东失败时返回%v，期望原始错误", err)
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"myApp/model"
+	"myApp/pkg/errors"
+	"myApp/repository"
+)
+
+// NotificationService 站内通知服务接口
+type NotificationService interface {
+	Notify(ctx context.Context, notification *model.Notification) error
+	GetUserNotifications(ctx context.Context, userID uint, params map[string]interface{}) ([]model.Notification, int64, error)
+	GetUnreadCount(ctx context.Context, userID uint) (int64, error)
+	MarkRead(ctx context.Context, id, userID uint) error
+	MarkAllRead(ctx context.Context, userID uint) error
+}
+
+// notificationService 站内通知服务实现
+type notificationService struct {
+	repo repository.NotificationRepository
+}
+
+// NewNotificationService 创建站内通知服务实例
+func NewNotificationService(repo repository.NotificationRepository) NotificationService {
+	return &notificationService{repo: repo}
+}
+
+// Notify 向用户发送站内通知
+func (s *notificationService) Notify(ctx context.Context, notification *model.Notification) error {
+	notification.IsRead = false
+	notification.ReadAt = nil
+	return s.repo.Create(ctx, notification)
+}
+
+// GetUserNotifications 获取用户的通知列表
+func (s *notificationService) GetUserNotifications(ctx context.Context, userID uint, params map[string]interface{}) ([]model.Notification, int64, error) {
+	params["user_id"] = userID
+	return s.repo.GetAll(ctx, params)
+}
+
+// GetUnreadCount 获取用户的未读通知数量
+func (s *notificationService) GetUnreadCount(ctx context.Context, userID uint) (int64, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// MarkRead 将通知标记为已读，只能操作本人的通知
+func (s *notificationService) MarkRead(ctx context.Context, id, userID uint) error {
+	affected, err := s.repo.MarkRead(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.NotFound("通知不存在或已读")
+	}
+	return nil
+}
+
+// MarkAllRead 将用户的全部通知标记为已读
+func (s *notificationService) MarkAllRead(ctx context.Context, userID uint) error {
+	return s.repo.MarkAllRead(ctx, userID)
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"myApp/config"
+	"myApp/model"
+	"myApp/pkg/errors"
+	"myApp/pkg/geo"
+	"myApp/pkg/logger"
+	"myApp/pkg/region"
+	"myApp/repository"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// regionIndexTTL 行政区划索引在进程内的缓存时间，区划数据只在迁移时变化
+const regionIndexTTL = time.Hour
+
+var (
+	// ErrRegionInvalid 区划代码不存在或级别高于区县时返回的错误
+	ErrRegionInvalid = errors.Validation("所在区域无效，请选择到区县或街道")
+	// ErrLocationOutOfRegion 坐标不在所在区域范围内时返回的错误
+	ErrLocationOutOfRegion = errors.Validation("房源坐标不在所在区域范围内，请检查地址或地图选点")
+)
+
+// regionIndexCache 进程内共享的行政区划索引
+var regionIndexCache struct {
+	sync.Mutex
+	index    *region.Index
+	loadedAt time.Time
+}
+
+// HouseRegion 房源所在的行政区划代码
+type HouseRegion struct {
+	ProvinceCode  string
+	CityCode      string
+	DistrictCode  string
+	CommunityCode string
+}
+
+// IsEmpty 是否未识别出任何区划
+func (r HouseRegion) IsEmpty() bool {
+	return r == HouseRegion{}
+}
+
+// Apply 将区划代码写入房源
+func (r HouseRegion) Apply(house *model.House) {
+	house.ProvinceCode = r.ProvinceCode
+	house.CityCode = r.CityCode
+	house.DistrictCode = r.DistrictCode
+	house.CommunityCode = r.CommunityCode
+}
+
+// RegionService 行政区划服务接口
+type RegionService interface {
+	GetChildren(ctx context.Context, parentCode string) ([]model.Region, error)
+	GetPath(ctx context.Context, code string) ([]model.Region, error)
+	GetNames(ctx context.Context, codes []string) (map[string]string, error)
+	ResolveCode(ctx context.Context, code string) (HouseRegion, error)
+	ParseAddress(ctx context.Context, address string) (HouseRegion, error)
+	CheckLocation(ctx context.Context, code string, point geo.Point) error
+}
+
+// regionService 行政区划服务实现
+type regionService struct {
+	repo repository.RegionRepository
+}
+
+// NewRegionService 创建行政区划服务实例
+func NewRegionService(repo repository.RegionRepository) RegionService {
+	return &regionService{repo: repo}
+}
+
+// GetChildren 获取下级区划，parentCode为空时返回省级区划
+func (s *regionService) GetChildren(ctx context.Context, parentCode string) ([]model.Region, error) {
+	index, err := s.index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := index.Get(parentCode); parentCode != "" && !ok {
+		return nil, ErrRegionInvalid
+	}
+	return toRegionModels(index.Children(parentCode)), nil
+}
+
+// GetPath 获取从省级到指定区划的完整路径
+func (s *regionService) GetPath(ctx context.Context, code string) ([]model.Region, error) {
+	path, err := s.path(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return toRegionModels(path), nil
+}
+
+// GetNames 批量查询区划名称，不存在的代码不返回
+func (s *regionService) GetNames(ctx context.Context, codes []string) (map[string]string, error) {
+	index, err := s.index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(codes))
+	for _, code := range codes {
+		if r, ok := index.Get(code); ok {
+			names[code] = r.Name
+		}
+	}
+	return names, nil
+}
+
+// ResolveCode 根据区县或街道的区划代码补全房源的完整区划
+func (s *regionService) ResolveCode(ctx context.Context, code string) (HouseRegion, error) {
+	path, err := s.path(ctx, code)
+	if err != nil {
+		return HouseRegion{}, err
+	}
+	if path[len(path)-1].Level < region.LevelDistrict {
+		return HouseRegion{}, ErrRegionInvalid
+	}
+	return toHouseRegion(path), nil
+}
+
+// ParseAddress 尽力从地址文本中识别房源的区划，无法识别时返回空区划
+func (s *regionService) ParseAddress(ctx context.Context, address string) (HouseRegion, error) {
+	index, err := s.index(ctx)
+	if err != nil {
+		return HouseRegion{}, err
+	}
+	return toHouseRegion(index.ParseAddress(address)), nil
+}
+
+// CheckLocation 校验坐标是否位于区划的覆盖范围内，按路径中最细一级有中心点的区划判断
+// 区划代码为空、不存在或没有中心点数据时不校验
+func (s *regionService) CheckLocation(ctx context.Context, code string, point geo.Point) error {
+	if code == "" {
+		return nil
+	}
+	index, err := s.index(ctx)
+	if err != nil {
+		return err
+	}
+	path := index.Path(code)
+	for i := len(path) - 1; i >= 0; i-- {
+		r := path[i]
+		if !r.HasLocation() {
+			continue
+		}
+		if geo.Distance(point, geo.Point{Latitude: r.Latitude, Longitude: r.Longitude}) > r.Radius {
+			return ErrLocationOutOfRegion
+		}
+		return nil
+	}
+	return nil
+}
+
+// path 获取从省级到指定区划的路径，代码不存在时返回ErrRegionInvalid
+func (s *regionService) path(ctx context.Context, code string) ([]region.Region, error) {
+	index, err := s.index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	path := index.Path(code)
+	if len(path) == 0 {
+		return nil, ErrRegionInvalid
+	}
+	return path, nil
+}
+
+// index 获取行政区划索引，过期后从数据库重新加载
+// 区划表为空（尚未执行迁移工具的regions命令）时使用内置数据集，保证开发环境可用
+func (s *regionService) index(ctx context.Context) (*region.Index, error) {
+	regionIndexCache.Lock()
+	defer regionIndexCache.Unlock()
+
+	if regionIndexCache.index != nil && time.Since(regionIndexCache.loadedAt) < regionIndexTTL {
+		return regionIndexCache.index, nil
+	}
+
+	rows, err := s.repo.GetAll(ctx)
+	if err != nil {
+		// 数据库暂时不可用时继续使用已加载的索引
+		if regionIndexCache.index != nil {
+			logger.FromContext(ctx).With(zap.Error(err)).Warn("重新加载行政区划失败，继续使用已加载的数据")
+			return regionIndexCache.index, nil
+		}
+		return nil, err
+	}
+
+	var regions []region.Region
+	if len(rows) == 0 {
+		if regions, err = region.Load(config.Conf.Region.DatasetFile); err != nil {
+			return nil, err
+		}
+	} else {
+		regions = make([]region.Region, len(rows))
+		for i, row := range rows {
+			regions[i] = region.Region{
+				Code:       row.Code,
+				ParentCode: row.ParentCode,
+				Name:       row.Name,
+				Level:      row.Level,
+				Latitude:   row.Latitude,
+				Longitude:  row.Longitude,
+				Radius:     row.Radius,
+			}
+		}
+	}
+
+	regionIndexCache.index = region.NewIndex(regions)
+	regionIndexCache.loadedAt = time.Now()
+	return regionIndexCache.index, nil
+}
+
+// toHouseRegionOf 读取房源当前的区划代码
+func toHouseRegionOf(house *model.House) HouseRegion {
+	return HouseRegion{
+		ProvinceCode:  house.ProvinceCode,
+		CityCode:      house.CityCode,
+		DistrictCode:  house.DistrictCode,
+		CommunityCode: house.CommunityCode,
+	}
+}
+
+// toHouseRegion 将从省级开始的区划路径转换为房源的区划代码
+func toHouseRegion(path []region.Region) HouseRegion {
+	var r HouseRegion
+	for _, node := range path {
+		switch node.Level {
+		case region.LevelProvince:
+			r.ProvinceCode = node.Code
+		case region.LevelCity:
+			r.CityCode = node.Code
+		case region.LevelDistrict:
+			r.DistrictCode = node.Code
+		case region.LevelCommunity:
+			r.CommunityCode = node.Code
+		}
+	}
+	return r
+}
+
+// toRegionModels 将索引中的区划转换为模型
+func toRegionModels(regions []region.Region) []model.Region {
+	list := make([]model.Region, len(regions))
+	for i, r := range regions {
+		list[i] = model.Region{
+			Code:       r.Code,
+			ParentCode: r.ParentCode,
+			Name:       r.Name,
+			Level:      r.Level,
+			Latitude:   r.Latitude,
+			Longitude:  r.Longitude,
+			Radius:     r.Radius,
+		}
+	}
+	return list
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"math"
+	"myApp/model"
+	"myApp/pkg/errors"
+	"myApp/pkg/redis/cache"
+	"myApp/repository"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 评价相关常量
+const (
+	ReviewAutoHideReports = 5   // 被举报次数达到该值时自动隐藏评价，等待管理员处理
+	DefaultLandlordRating = 5.0 // 房东暂无评价时的默认评分
+)
+
+// ReviewService 评价服务接口
+type ReviewService interface {
+	CreateReview(ctx context.Context, review *model.Review) error
+	GetReviewByID(ctx context.Context, id uint) (*model.Review, error)
+	GetHouseReviews(ctx context.Context, houseID uint, params map[string]interface{}) ([]model.Review, int64, error)
+	GetLandlordReviews(ctx context.Context, landlordID uint, params map[string]interface{}) ([]model.Review, int64, error)
+	ReplyReview(ctx context.Context, id, landlordUserID uint, reply string) error
+	ReportReview(ctx context.Context, id, userID uint, reason string) error
+	GetReports(ctx context.Context, params map[string]interface{}) ([]model.ReviewReport, int64, error)
+	HandleReport(ctx context.Context, id, handlerID uint, hide bool) error
+}
+
+// reviewService 评价服务实现
+type reviewService struct {
+	repo         repository.ReviewRepository
+	reportRepo   repository.ReviewReportRepository
+	viewingRepo  repository.ViewingRepository
+	houseRepo    repository.HouseRepository
+	landlordRepo repository.LandlordRepository
+	txManager    repository.TxManager
+}
+
+// NewReviewService 创建评价服务实例
+func NewReviewService(repo repository.ReviewRepository, reportRepo repository.ReviewReportRepository, viewingRepo repository.ViewingRepository, houseRepo repository.HouseRepository, landlordRepo repository.LandlordRepository, txManager repository.TxManager) ReviewService {
+	return &reviewService{
+		repo:         repo,
+		reportRepo:   reportRepo,
+		viewingRepo:  viewingRepo,
+		houseRepo:    houseRepo,
+		landlordRepo: landlordRepo,
+		txManager:    txManager,
+	}
+}
+
+// CreateReview 创建评价，只有完成看房的租客才能对房源和房东进行评价
+func (s *reviewService) CreateReview(ctx context.Context, review *model.Review) error {
+	if review.LandlordRating < 1 || review.LandlordRating > 5 || review.HouseRating < 1 || review.HouseRating > 5 {
+		return errors.Validation("评分必须在1-5星之间")
+	}
+
+	// 检查看房预约是否属于当前用户且已完成
+	viewing, err := s.viewingRepo.GetByID(ctx, review.ViewingID)
+	if err != nil {
+		return notFoundOr(err, "看房预约不存在")
+	}
+	if viewing.UserID != review.UserID {
+		return errors.NotFound("看房预约不存在")
+	}
+	if viewing.Status != model.ViewingCompleted {
+		return errors.Conflict("完成看房后才能评价")
+	}
+
+	// 每次看房只能评价一次
+	existing, err := s.repo.GetByViewingID(ctx, viewing.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil {
+		return errors.Conflict("该次看房已评价")
+	}
+
+	// 房源的LandlordID为房东的用户ID，需要换算为房东ID
+	house, err := s.houseRepo.GetByID(ctx, viewing.HouseID)
+	if err != nil {
+		return notFoundOr(err, "房源不存在")
+	}
+	// 房东不能评价自己的房源
+	if viewing.UserID == house.LandlordID {
+		return errors.Forbidden("不能评价自己的房源")
+	}
+	landlord, err := s.landlordRepo.FindByUserID(ctx, house.LandlordID)
+	if err != nil {
+		return notFoundOr(err, "房东不存在")
+	}
+
+	review.HouseID = house.ID
+	review.LandlordID = landlord.ID
+	review.Status = model.ReviewNormal
+	review.Content = strings.TrimSpace(review.Content)
+	if err := s.repo.Create(ctx, review); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Conflict("该次看房已评价")
+		}
+		return err
+	}
+
+	if err := s.recomputeRatings(ctx, review.HouseID, review.LandlordID); err != nil {
+		return err
+	}
+	invalidateReviewedHouse(ctx, review.HouseID)
+	return nil
+}
+
+// GetReviewByID 根据ID获取评价
+func (s *reviewService) GetReviewByID(ctx context.Context, id uint) (*model.Review, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// GetHouseReviews 获取房源的正常评价列表
+func (s *reviewService) GetHouseReviews(ctx context.Context, houseID uint, params map[string]interface{}) ([]model.Review, int64, error) {
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+	params["house_id"] = houseID
+	params["status"] = model.ReviewNormal
+	return s.repo.GetAll(ctx, params)
+}
+
+// GetLandlordReviews 获取房东的正常评价列表
+func (s *reviewService) GetLandlordReviews(ctx context.Context, landlordID uint, params map[string]interface{}) ([]model.Review, int64, error) {
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+	params["landlord_id"] = landlordID
+	params["status"] = model.ReviewNormal
+	return s.repo.GetAll(ctx, params)
+}
+
+// ReplyReview 房东回复评价，重复回复会覆盖之前的内容
+func (s *reviewService) ReplyReview(ctx context.Context, id, landlordUserID uint, reply string) error {
+	reply = strings.TrimSpace(reply)
+	if reply == "" {
+		return errors.Validation("回复内容不能为空")
+	}
+
+	review, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return notFoundOr(err, "评价不存在")
+	}
+
+	// 只有被评价的房东本人可以回复
+	landlord, err := s.landlordRepo.FindByUserID(ctx, landlordUserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if landlord == nil || landlord.ID != review.LandlordID {
+		return errors.Forbidden("无权回复该评价")
+	}
+
+	// 只更新回复字段，避免覆盖同时发生的举报计数和隐藏状态
+	return s.repo.UpdateColumns(ctx, review.ID, map[string]interface{}{
+		"reply":      reply,
+		"reply_time": time.Now(),
+	})
+}
+
+// ReportReview 举报评价，被举报次数达到阈值时自动隐藏并重新计算评分
+func (s *reviewService) ReportReview(ctx context.Context, id, userID uint, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.Validation("举报原因不能为空")
+	}
+
+	// 锁定评价后再记录举报和累加举报次数，并发举报时计数不会丢失
+	var hidden *model.Review
+	err := s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		review, err := s.repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return notFoundOr(err, "评价不存在")
+		}
+		if review.UserID == userID {
+			return errors.Forbidden("不能举报自己的评价")
+		}
+
+		// 每个用户对同一评价只能举报一次
+		reported, err := s.reportRepo.ExistsByUserAndReview(ctx, userID, id)
+		if err != nil {
+			return err
+		}
+		if reported {
+			return errors.Conflict("您已举报过该评价")
+		}
+
+		if err := s.reportRepo.Create(ctx, &model.ReviewReport{
+			ReviewID: id,
+			UserID:   userID,
+			Reason:   reason,
+			Status:   model.ReportPending,
+		}); err != nil {
+			return err
+		}
+
+		columns := map[string]interface{}{"report_count": review.ReportCount + 1}
+		if review.Status != model.ReviewNormal || review.ReportCount+1 < ReviewAutoHideReports {
+			return s.repo.UpdateColumns(ctx, review.ID, columns)
+		}
+		columns["status"] = model.ReviewHidden
+		if err := s.repo.UpdateColumns(ctx, review.ID, columns); err != nil {
+			return err
+		}
+		hidden = review
+		return s.recomputeRatings(ctx, review.HouseID, review.LandlordID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if hidden != nil {
+		invalidateReviewedHouse(ctx, hidden.HouseID)
+	}
+	return nil
+}
+
+// GetReports 获取举报列表
+func (s *reviewService) GetReports(ctx context.Context, params map[string]interface{}) ([]model.ReviewReport, int64, error) {
+	return s.reportRepo.GetAll(ctx, params)
+}
+
+// HandleReport 管理员处理举报：hide为true时隐藏评价，否则驳回举报并恢复评价显示
+// 同一评价下所有待处理的举报会一并处理
+func (s *reviewService) HandleReport(ctx context.Context, id, handlerID uint, hide bool) error {
+	report, err := s.reportRepo.GetByID(ctx, id)
+	if err != nil {
+		return notFoundOr(err, "举报记录不存在")
+	}
+
+	status := model.ReportDismissed
+	reviewStatus := model.ReviewNormal
+	if hide {
+		status = model.ReportHidden
+		reviewStatus = model.ReviewHidden
+	}
+
+	// 锁定评价后处理，与同时发生的举报互斥；锁定后重新检查举报状态，避免重复处理
+	var changed *model.Review
+	err = s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		review, err := s.repo.GetByIDForUpdate(ctx, report.ReviewID)
+		if err != nil {
+			return notFoundOr(err, "评价不存在")
+		}
+		report, err := s.reportRepo.GetByID(ctx, id)
+		if err != nil {
+			return notFoundOr(err, "举报记录不存在")
+		}
+		if report.Status != model.ReportPending {
+			return errors.Conflict("举报已处理")
+		}
+
+		if err := s.reportRepo.UpdateStatusByReviewID(ctx, review.ID, status, handlerID); err != nil {
+			return err
+		}
+
+		if review.Status == reviewStatus {
+			return nil
+		}
+		if err := s.repo.UpdateColumns(ctx, review.ID, map[string]interface{}{"status": reviewStatus}); err != nil {
+			return err
+		}
+		changed = review
+		return s.recomputeRatings(ctx, review.HouseID, review.LandlordID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if changed != nil {
+		invalidateReviewedHouse(ctx, changed.HouseID)
+	}
+	return nil
+}
+
+// recomputeRatings 根据有效评价重新计算房源和房东的聚合评分
+func (s *reviewService) recomputeRatings(ctx context.Context, houseID, landlordID uint) error {
+	houseRating, houseCount, err := s.repo.GetHouseRatingStats(ctx, houseID)
+	if err != nil {
+		return err
+	}
+	if err := s.houseRepo.UpdateRating(ctx, houseID, roundRating(houseRating), houseCount); err != nil {
+		return err
+	}
+
+	landlordRating, landlordCount, err := s.repo.GetLandlordRatingStats(ctx, landlordID)
+	if err != nil {
+		return err
+	}
+	if landlordCount == 0 {
+		landlordRating = DefaultLandlordRating
+	}
+	return s.landlordRepo.UpdateRating(ctx, landlordID, roundRating(landlordRating), landlordCount)
+}
+
+// invalidateReviewedHouse 清除房源详情及包含该房源的列表缓存，使新的评分立即生效
+// 需在评分写入的事务提交后调用，否则缓存可能在提交前被旧数据重新填充
+func invalidateReviewedHouse(ctx context.Context, houseID uint) {
+	_ = cache.InvalidateTags(ctx, houseTag(houseID))
+}
+
+// roundRating 将评分保留一位小数
+func roundRating(rating float64) float64 {
+	return math.Round(rating*10) / 10
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"myApp/model"
+	"myApp/model/modeltest"
+	"myApp/repository"
+)
+
+func TestReportReviewAutoHide(t *testing.T) {
+	ctx := t.Context()
+	newTestRedis(t)
+	db := modeltest.NewDB(t)
+	repo := repository.NewReviewRepository(db)
+	svc := NewReviewService(repo, repository.NewReviewReportRepository(db), repository.NewViewingRepository(db),
+		repository.NewHouseRepository(db), repository.NewLandlordRepository(db), repository.NewTxManager(db))
+
+	landlordUser := modeltest.Create(t, db, modeltest.User(), nil)
+	landlord := modeltest.Create(t, db, &model.Landlord{UserID: landlordUser.ID, RealName: "张三", Verified: true}, nil)
+	house := modeltest.Create(t, db, modeltest.House(), func(h *model.House) { h.LandlordID = landlordUser.ID })
+	review := modeltest.Create(t, db, &model.Review{
+		UserID:         modeltest.Create(t, db, modeltest.User(), nil).ID,
+		HouseID:        house.ID,
+		LandlordID:     landlord.ID,
+		ViewingID:      1,
+		LandlordRating: 1,
+		HouseRating:    1,
+	}, nil)
+
+	// 举报次数逐次累加，达到阈值时隐藏评价
+	for i := 0; i < ReviewAutoHideReports; i++ {
+		reporter := modeltest.Create(t, db, modeltest.User(), nil)
+		if err := svc.ReportReview(ctx, review.ID, reporter.ID, "广告"); err != nil {
+			t.Fatalf("第%d次举报失败: %v", i+1, err)
+		}
+	}
+	got, err := repo.GetByID(ctx, review.ID)
+	if err != nil || got.ReportCount != ReviewAutoHideReports || got.Status != model.ReviewHidden {
+		t.Fatalf("举报%d次后评价为%+v, %v，期望已隐藏", ReviewAutoHideReports, got, err)
+	}
+
+	// 房东回复只更新回复字段，不会恢复已隐藏的评价
+	if err := svc.ReplyReview(ctx, review.ID, landlordUser.ID, "感谢反馈"); err != nil {
+		t.Fatalf("回复评价失败: %v", err)
+	}
+	got, err = repo.GetByID(ctx, review.ID)
+	if err != nil || got.Reply != "感谢反馈" || got.Status != model.ReviewHidden || got.ReportCount != ReviewAutoHideReports {
+		t.Fatalf("回复后评价为%+v, %v，期望保持隐藏和举报次数", got, err)
+	}
+}
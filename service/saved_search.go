@@ -0,0 +1,466 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"myApp/config"
+	"myApp/model"
+	"myApp/pkg/errors"
+	"myApp/pkg/logger"
+	"myApp/pkg/redis"
+	"myApp/pkg/sms"
+	"myApp/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxSavedSearches 每个用户最多保存的搜索数量
+	maxSavedSearches = 20
+	// savedSearchBatchSize 匹配新房源时每批读取的搜索数量
+	savedSearchBatchSize = 500
+	// savedSearchSMSInterval 同一搜索两次短信提醒的最小间隔
+	savedSearchSMSInterval = 24 * time.Hour
+	// savedSearchAlertBatchSize 每次遍历保存的搜索时最多匹配的新房源数量
+	savedSearchAlertBatchSize = 50
+	// savedSearchAlertLockTTL 新房源提醒任务锁的有效期
+	savedSearchAlertLockTTL = 5 * time.Minute
+)
+
+// 等待匹配的新房源ID保存在Redis列表中，服务重启不会丢失，由后台任务批量取出匹配
+const (
+	savedSearchAlertPendingKey    = "saved_search:alerts:pending"    // 等待匹配的新房源ID
+	savedSearchAlertProcessingKey = "saved_search:alerts:processing" // 正在匹配的新房源ID，匹配完成后删除
+	savedSearchAlertLock          = "saved_search:alerts:lock"       // 提醒任务锁，避免多个实例重复发送
+)
+
+// claimAlertsScript 从等待队列中取出最早加入的一批房源ID放入正在匹配的列表，返回取出的ID
+// KEYS[1]为等待队列（LPUSH写入），KEYS[2]为正在匹配的列表；ARGV[1]为最多取出的数量
+var claimAlertsScript = redis.NewScript(`
+local ids = redis.call('LRANGE', KEYS[1], -tonumber(ARGV[1]), -1)
+if #ids > 0 then
+	redis.call('LTRIM', KEYS[1], 0, -#ids - 1)
+	redis.call('RPUSH', KEYS[2], unpack(ids))
+end
+return ids
+`)
+
+var (
+	// ErrSavedSearchNotFound 保存的搜索不存在或不属于当前用户时返回的错误
+	ErrSavedSearchNotFound = errors.NotFound("保存的搜索不存在")
+	// ErrSavedSearchLimit 保存的搜索数量达到上限时返回的错误
+	ErrSavedSearchLimit = errors.Newf(errors.CodeConflict, "最多只能保存%d个搜索", maxSavedSearches)
+	// ErrSavedSearchEmpty 未设置任何筛选条件时返回的错误
+	ErrSavedSearchEmpty = errors.Validation("请至少设置一个筛选条件")
+)
+
+// SavedSearchService 保存的搜索服务接口
+type SavedSearchService interface {
+	CreateSavedSearch(ctx context.Context, search *model.SavedSearch, filter HouseFilter) error
+	GetUserSavedSearches(ctx context.Context, userID uint) ([]SavedSearchSummary, error)
+	UpdateSavedSearch(ctx context.Context, id, userID uint, update SavedSearchUpdate) (*model.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, id, userID uint) error
+	GetMatches(ctx context.Context, id, userID uint, params map[string]interface{}) (*model.SavedSearch, []model.House, int64, error)
+	MarkChecked(ctx context.Context, id, userID uint) error
+	NotifyNewHouse(ctx context.Context, house *model.House)
+	ProcessAlerts(ctx context.Context) (int, error)
+}
+
+// SavedSearchSummary 保存的搜索及其新房源数量
+type SavedSearchSummary struct {
+	Search        model.SavedSearch
+	Filter        HouseFilter
+	NewMatchCount int64 // 上次查看后新发布的匹配房源数量
+}
+
+// SavedSearchUpdate 保存的搜索的可修改字段，为nil的字段保持不变
+type SavedSearchUpdate struct {
+	Name        *string
+	Filter      *HouseFilter
+	NotifyInApp *bool
+	NotifySMS   *bool
+}
+
+// savedSearchService 保存的搜索服务实现
+type savedSearchService struct {
+	repo                repository.SavedSearchRepository
+	houseRepo           repository.HouseRepository
+	userRepo            repository.UserRepository
+	smsRecordRepo       repository.SMSRecordRepository
+	notificationService NotificationService
+	smsProvider         sms.SMSProvider
+}
+
+// NewSavedSearchService 创建保存的搜索服务实例，smsProvider为nil时不发送短信提醒
+func NewSavedSearchService(repo repository.SavedSearchRepository, houseRepo repository.HouseRepository, userRepo repository.UserRepository, smsRecordRepo repository.SMSRecordRepository, notificationService NotificationService, smsProvider sms.SMSProvider) SavedSearchService {
+	return &savedSearchService{
+		repo:                repo,
+		houseRepo:           houseRepo,
+		userRepo:            userRepo,
+		smsRecordRepo:       smsRecordRepo,
+		notificationService: notificationService,
+		smsProvider:         smsProvider,
+	}
+}
+
+// CreateSavedSearch 保存搜索条件，保存时间之后发布的房源计为新房源
+func (s *savedSearchService) CreateSavedSearch(ctx context.Context, search *model.SavedSearch, filter HouseFilter) error {
+	if filter.IsEmpty() {
+		return ErrSavedSearchEmpty
+	}
+
+	count, err := s.repo.CountByUserID(ctx, search.UserID)
+	if err != nil {
+		return err
+	}
+	if count >= maxSavedSearches {
+		return ErrSavedSearchLimit
+	}
+
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	search.Filters = string(data)
+	search.LastCheckedAt = &now
+	return s.repo.Create(ctx, search)
+}
+
+// GetUserSavedSearches 获取用户保存的全部搜索及各自的新房源数量
+func (s *savedSearchService) GetUserSavedSearches(ctx context.Context, userID uint) ([]SavedSearchSummary, error) {
+	searches, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]SavedSearchSummary, 0, len(searches))
+	for i := range searches {
+		filter := DecodeHouseFilter(&searches[i])
+		count, err := s.houseRepo.Count(ctx, newMatchParams(&searches[i], filter))
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, SavedSearchSummary{
+			Search:        searches[i],
+			Filter:        filter,
+			NewMatchCount: count,
+		})
+	}
+	return summaries, nil
+}
+
+// UpdateSavedSearch 修改保存的搜索，只能操作本人的搜索
+func (s *savedSearchService) UpdateSavedSearch(ctx context.Context, id, userID uint, update SavedSearchUpdate) (*model.SavedSearch, error) {
+	search, err := s.getOwnedSearch(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]interface{})
+	if update.Name != nil {
+		search.Name = strings.TrimSpace(*update.Name)
+		columns["name"] = search.Name
+	}
+	if update.Filter != nil {
+		if update.Filter.IsEmpty() {
+			return nil, ErrSavedSearchEmpty
+		}
+		data, err := json.Marshal(update.Filter)
+		if err != nil {
+			return nil, err
+		}
+		search.Filters = string(data)
+		columns["filters"] = search.Filters
+	}
+	if update.NotifyInApp != nil {
+		search.NotifyInApp = *update.NotifyInApp
+		columns["notify_in_app"] = search.NotifyInApp
+	}
+	if update.NotifySMS != nil {
+		search.NotifySMS = *update.NotifySMS
+		columns["notify_sms"] = search.NotifySMS
+	}
+	if len(columns) == 0 {
+		return search, nil
+	}
+
+	if err := s.repo.UpdateColumns(ctx, id, columns); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// DeleteSavedSearch 删除保存的搜索，只能操作本人的搜索
+func (s *savedSearchService) DeleteSavedSearch(ctx context.Context, id, userID uint) error {
+	if _, err := s.getOwnedSearch(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// GetMatches 获取符合保存的搜索条件的已发布房源，按发布时间倒序
+func (s *savedSearchService) GetMatches(ctx context.Context, id, userID uint, params map[string]interface{}) (*model.SavedSearch, []model.House, int64, error) {
+	search, err := s.getOwnedSearch(ctx, id, userID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	query := DecodeHouseFilter(search).ToParams()
+	query["status"] = model.HouseStatusPublished
+	query["not_expired"] = true
+	total, err := s.houseRepo.Count(ctx, query)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	query["order_by"] = "published_at DESC, id DESC"
+	for key, value := range params {
+		query[key] = value
+	}
+	houses, err := s.houseRepo.GetAll(ctx, query)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return search, houses, total, nil
+}
+
+// MarkChecked 将保存的搜索标记为已查看，新房源数量清零
+func (s *savedSearchService) MarkChecked(ctx context.Context, id, userID uint) error {
+	if _, err := s.getOwnedSearch(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.UpdateColumns(ctx, id, map[string]interface{}{"last_checked_at": time.Now()})
+}
+
+// NotifyNewHouse 将新发布的房源加入Redis中的提醒队列后立即返回，由后台任务ProcessAlerts匹配开启提醒的搜索并发送站内通知和短信
+// 加入队列失败只记录日志，不影响房源发布
+func (s *savedSearchService) NotifyNewHouse(ctx context.Context, house *model.House) {
+	if house.Status != model.HouseStatusPublished {
+		return
+	}
+	if err := redis.LPush(ctx, savedSearchAlertPendingKey, house.ID); err != nil {
+		logger.FromContext(ctx).With(zap.Error(err)).Error(fmt.Sprintf("房源%d加入新房源提醒队列失败", house.ID))
+	}
+}
+
+// ProcessAlerts 取出提醒队列中的新房源并发送提醒，直到队列为空，返回处理的房源数量
+// 每批最多savedSearchAlertBatchSize套房源合并后一次遍历保存的搜索，避免每次发布都全量扫描；
+// 正在匹配的房源ID在发送完成后才删除，任务中断后下次执行时重新发送该批提醒
+func (s *savedSearchService) ProcessAlerts(ctx context.Context) (int, error) {
+	token, err := redis.TryLock(ctx, savedSearchAlertLock, savedSearchAlertLockTTL)
+	if err != nil {
+		return 0, err
+	}
+	if token == "" {
+		return 0, nil
+	}
+	defer func() {
+		if err := redis.Unlock(ctx, savedSearchAlertLock, token); err != nil {
+			logger.FromContext(ctx).With(zap.Error(err)).Warn("释放新房源提醒任务锁失败")
+		}
+	}()
+
+	processed := 0
+	for ctx.Err() == nil {
+		// 上次中断时遗留的一批先处理
+		ids, err := redis.LRange(ctx, savedSearchAlertProcessingKey, 0, -1)
+		if err != nil {
+			return processed, err
+		}
+		if len(ids) == 0 {
+			claimed, err := redis.RunScript(ctx, claimAlertsScript, []string{savedSearchAlertPendingKey, savedSearchAlertProcessingKey}, savedSearchAlertBatchSize)
+			if err != nil {
+				return processed, err
+			}
+			for _, id := range claimed.([]interface{}) {
+				ids = append(ids, id.(string))
+			}
+		}
+		if len(ids) == 0 {
+			return processed, nil
+		}
+
+		houses, err := s.loadAlertHouses(ctx, ids)
+		if err != nil {
+			return processed, err
+		}
+		s.notifyNewHouses(ctx, houses)
+		if err := redis.Delete(ctx, savedSearchAlertProcessingKey); err != nil {
+			return processed, err
+		}
+		processed += len(ids)
+	}
+	return processed, ctx.Err()
+}
+
+// loadAlertHouses 读取待提醒的房源，已删除或已不再发布的房源不发送提醒
+func (s *savedSearchService) loadAlertHouses(ctx context.Context, ids []string) ([]*model.House, error) {
+	houses := make([]*model.House, 0, len(ids))
+	seen := make(map[uint64]bool, len(ids))
+	for _, value := range ids {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		house, err := s.houseRepo.GetByID(ctx, uint(id))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if house.Status == model.HouseStatusPublished {
+			houses = append(houses, house)
+		}
+	}
+	return houses, nil
+}
+
+// notifyNewHouses 逐批读取开启提醒的搜索并与新房源匹配，向匹配的用户发送提醒
+// 同一用户有多个搜索匹配同一房源时站内通知和短信各只发送一次，失败只记录日志
+func (s *savedSearchService) notifyNewHouses(ctx context.Context, houses []*model.House) {
+	if len(houses) == 0 {
+		return
+	}
+	sent := alertDeliveries{inApp: make(map[[2]uint]bool), sms: make(map[[2]uint]bool)}
+	var afterID uint
+	for {
+		searches, err := s.repo.GetAlertEnabled(ctx, afterID, savedSearchBatchSize)
+		if err != nil {
+			logger.FromContext(ctx).With(zap.Error(err)).Error(fmt.Sprintf("匹配%d套新房源的保存的搜索失败", len(houses)))
+			return
+		}
+
+		for i := range searches {
+			search := &searches[i]
+			filter := DecodeHouseFilter(search)
+			if filter.IsEmpty() {
+				continue
+			}
+			for _, house := range houses {
+				// 房东发布的房源不提醒房东本人
+				if search.UserID == house.LandlordID || !filter.Matches(house) {
+					continue
+				}
+				s.sendAlert(ctx, search, house, sent)
+			}
+		}
+
+		if len(searches) < savedSearchBatchSize {
+			return
+		}
+		afterID = searches[len(searches)-1].ID
+	}
+}
+
+// alertDeliveries 一次匹配中已向用户发送过提醒的房源，键为用户ID和房源ID
+// 站内通知和短信分别记录，用户的某个搜索只开启了站内通知时，其他开启短信的搜索仍会发送短信
+type alertDeliveries struct {
+	inApp map[[2]uint]bool
+	sms   map[[2]uint]bool
+}
+
+// sendAlert 按搜索的提醒设置发送新房源站内通知和短信，已向该用户发送过的方式不再重复发送
+func (s *savedSearchService) sendAlert(ctx context.Context, search *model.SavedSearch, house *model.House, sent alertDeliveries) {
+	key := [2]uint{search.UserID, house.ID}
+	if search.NotifyInApp && !sent.inApp[key] {
+		sent.inApp[key] = true
+		err := s.notificationService.Notify(ctx, &model.Notification{
+			UserID:    search.UserID,
+			Type:      model.NotificationSearchMatch,
+			Title:     "您保存的搜索有新房源",
+			Content:   fmt.Sprintf("「%s」有新发布的房源：%s，租金%.0f元/月", search.Name, house.Title, house.RentPrice),
+			RelatedID: house.ID,
+		})
+		if err != nil {
+			logger.FromContext(ctx).With(zap.Error(err)).Error(fmt.Sprintf("向用户%d发送新房源通知失败", search.UserID))
+		}
+	}
+
+	if search.NotifySMS && !sent.sms[key] && s.smsDue(search) {
+		sent.sms[key] = true
+		s.sendSMSAlert(ctx, search, house)
+	}
+}
+
+// smsDue 是否可以发送短信提醒，未配置短信服务提供商、短信模板或距上次提醒不足最小间隔时不发送
+func (s *savedSearchService) smsDue(search *model.SavedSearch) bool {
+	if s.smsProvider == nil || config.Conf.SMS.SearchAlertTemplate == "" {
+		return false
+	}
+	return search.LastNotifiedAt == nil || time.Since(*search.LastNotifiedAt) >= savedSearchSMSInterval
+}
+
+// sendSMSAlert 向用户发送新房源短信并记录发送结果
+func (s *savedSearchService) sendSMSAlert(ctx context.Context, search *model.SavedSearch, house *model.House) {
+	provider := s.smsProvider
+	user, err := s.userRepo.FindByID(ctx, search.UserID)
+	if err != nil || user.Phone == "" {
+		return
+	}
+
+	templateCode := config.Conf.SMS.SearchAlertTemplate
+	param, _ := json.Marshal(map[string]string{"name": search.Name, "title": house.Title})
+	success, bizId, requestId, err := provider.SendSMS([]string{user.Phone}, provider.GetSignName(), templateCode, string(param))
+
+	record := &model.SMSRecord{
+		Phone:      user.Phone,
+		TemplateID: templateCode,
+		Content:    string(param),
+		Status:     success,
+		Provider:   provider.GetName(),
+		BizId:      bizId,
+		RequestId:  requestId,
+	}
+	if err != nil {
+		record.Status = false
+		record.FailReason = err.Error()
+		logger.FromContext(ctx).With(zap.Error(err)).Error(fmt.Sprintf("向用户%d发送新房源短信失败", search.UserID))
+	}
+	_ = s.smsRecordRepo.Create(ctx, record)
+
+	if err == nil {
+		// 同一批次中的其他房源按新的提醒时间判断是否发送
+		now := time.Now()
+		search.LastNotifiedAt = &now
+		_ = s.repo.UpdateColumns(ctx, search.ID, map[string]interface{}{"last_notified_at": now})
+	}
+}
+
+// getOwnedSearch 获取保存的搜索并校验是否属于指定用户
+func (s *savedSearchService) getOwnedSearch(ctx context.Context, id, userID uint) (*model.SavedSearch, error) {
+	search, err := s.repo.GetByID(ctx, id)
+	if err != nil || search.UserID != userID {
+		return nil, ErrSavedSearchNotFound
+	}
+	return search, nil
+}
+
+// DecodeHouseFilter 解析保存的搜索中的筛选条件，格式错误时返回空条件
+func DecodeHouseFilter(search *model.SavedSearch) HouseFilter {
+	var filter HouseFilter
+	if search.Filters != "" {
+		_ = json.Unmarshal([]byte(search.Filters), &filter)
+	}
+	return filter
+}
+
+// newMatchParams 构造统计上次查看后新发布的匹配房源的查询参数
+func newMatchParams(search *model.SavedSearch, filter HouseFilter) map[string]interface{} {
+	params := filter.ToParams()
+	params["status"] = model.HouseStatusPublished
+	params["not_expired"] = true
+	since := search.CreatedAt
+	if search.LastCheckedAt != nil {
+		since = *search.LastCheckedAt
+	}
+	params["published_after"] = since
+	return params
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"strconv"
+	"testing"
+
+	"myApp/config"
+	"myApp/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newTestRedis 启动miniredis并设置为服务使用的Redis客户端
+func newTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	port, _ := strconv.Atoi(mr.Port())
+	client, err := redis.NewClient(config.RedisConfig{Host: mr.Host(), Port: port})
+	if err != nil {
+		t.Fatalf("连接miniredis失败: %v", err)
+	}
+	redis.SetClient(client)
+	t.Cleanup(func() { client.Close() })
+	return mr
+}
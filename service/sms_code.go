@@ -1,11 +1,12 @@
 package service
 
 import (
+	"context"
 	"crypto/rand"
-	"errors"
 	"fmt"
 	"math/big"
 	"myApp/model"
+	"myApp/pkg/errors"
 	"myApp/pkg/redis"
 	"myApp/pkg/sms"
 	"myApp/repository"
@@ -20,24 +21,29 @@ const (
 	SMSCodeLength = 6           // 短信验证码长度
 )
 
+// ErrSMSProviderNotConfigured 未配置可用的短信服务提供商时返回的错误
+var ErrSMSProviderNotConfigured = errors.New(errors.CodeInternal, "短信服务提供商未配置")
+
 // SMSCodeService 短信验证码服务接口
 type SMSCodeService interface {
-	SendCode(phone string, ipAddress, userAgent string) (bool, error) // 发送验证码
-	VerifyCode(phone, code string) (bool, error)                      // 验证验证码
-	LoginByCode(phone, code string) (*model.User, error)              // 通过验证码登录
+	SendCode(ctx context.Context, phone string, ipAddress, userAgent string) (bool, error) // 发送验证码
+	VerifyCode(ctx context.Context, phone, code string) (bool, error)                      // 验证验证码
+	LoginByCode(ctx context.Context, phone, code string) (*model.User, error)              // 通过验证码登录
 }
 
 // smsCodeService 短信验证码服务实现
 type smsCodeService struct {
 	userRepo      repository.UserRepository
 	smsRecordRepo repository.SMSRecordRepository
+	provider      sms.SMSProvider
 }
 
-// NewSMSCodeService 创建短信验证码服务实例
-func NewSMSCodeService(userRepo repository.UserRepository, smsRecordRepo repository.SMSRecordRepository) SMSCodeService {
+// NewSMSCodeService 创建短信验证码服务实例，provider为nil时发送验证码返回ErrSMSProviderNotConfigured
+func NewSMSCodeService(userRepo repository.UserRepository, smsRecordRepo repository.SMSRecordRepository, provider sms.SMSProvider) SMSCodeService {
 	return &smsCodeService{
 		userRepo:      userRepo,
 		smsRecordRepo: smsRecordRepo,
+		provider:      provider,
 	}
 }
 
@@ -60,14 +66,18 @@ func (s *smsCodeService) generateCode() string {
 }
 
 // SendCode 发送短信验证码
-func (s *smsCodeService) SendCode(phone string, ipAddress, userAgent string) (bool, error) {
+func (s *smsCodeService) SendCode(ctx context.Context, phone string, ipAddress, userAgent string) (bool, error) {
 	if phone == "" {
-		return false, errors.New("手机号不能为空")
+		return false, errors.Validation("手机号不能为空")
+	}
+	if s.provider == nil {
+		return false, ErrSMSProviderNotConfigured
 	}
+	provider := s.provider
 
 	// 先检查Redis中是否存在未过期的验证码
 	key := SMSCodePrefix + phone
-	existingCode, err := redis.Get(key)
+	existingCode, err := redis.Get(ctx, key)
 	// 如果验证码存在且未过期，直接返回成功
 	if err == nil && existingCode != "" {
 		return true, nil
@@ -77,15 +87,9 @@ func (s *smsCodeService) SendCode(phone string, ipAddress, userAgent string) (bo
 	code := s.generateCode()
 
 	// 存储验证码到Redis
-	err = redis.Set(key, code, time.Duration(SMSCodeExpire)*time.Second)
-	if err != nil {
-		return false, fmt.Errorf("存储验证码失败: %v", err)
-	}
-
-	// 创建短信服务提供商
-	provider, err := sms.CreateSMSProvider()
+	err = redis.Set(ctx, key, code, time.Duration(SMSCodeExpire)*time.Second)
 	if err != nil {
-		return false, fmt.Errorf("创建短信服务提供商失败: %v", err)
+		return false, errors.Wrap(err, errors.CodeInternal, "存储验证码失败")
 	}
 
 	// 构建短信模板参数
@@ -118,55 +122,58 @@ func (s *smsCodeService) SendCode(phone string, ipAddress, userAgent string) (bo
 		smsRecord.Status = false
 		smsRecord.FailReason = err.Error()
 		// 记录短信发送失败日志，但不影响主流程返回
-		_ = s.smsRecordRepo.Create(smsRecord)
-		return false, fmt.Errorf("发送短信失败: %v", err)
+		_ = s.smsRecordRepo.Create(ctx, smsRecord)
+		return false, errors.Wrap(err, errors.CodeInternal, "发送短信失败")
 	}
 
 	// 记录短信发送成功日志
-	_ = s.smsRecordRepo.Create(smsRecord)
+	_ = s.smsRecordRepo.Create(ctx, smsRecord)
 
 	return success, nil
 }
 
 // VerifyCode 验证短信验证码
-func (s *smsCodeService) VerifyCode(phone, code string) (bool, error) {
+func (s *smsCodeService) VerifyCode(ctx context.Context, phone, code string) (bool, error) {
 	if phone == "" || code == "" {
-		return false, errors.New("手机号和验证码不能为空")
+		return false, errors.Validation("手机号和验证码不能为空")
 	}
 
 	// 从Redis获取存储的验证码
 	key := SMSCodePrefix + phone
-	storedCode, err := redis.Get(key)
+	storedCode, err := redis.Get(ctx, key)
 
 	// 如果获取失败或验证码不存在
 	if err != nil {
 		if err == redis.Nil {
-			return false, errors.New("验证码已过期或不存在")
+			return false, errors.Unauthorized("验证码已过期或不存在")
 		}
-		return false, fmt.Errorf("获取验证码失败: %v", err)
+		return false, errors.Wrap(err, errors.CodeInternal, "获取验证码失败")
 	}
 
 	// 验证码比对
 	if storedCode != code {
-		return false, errors.New("验证码错误")
+		return false, errors.Unauthorized("验证码错误")
 	}
 
 	// 验证成功后删除验证码，防止重复使用
-	_ = redis.Delete(key)
+	_ = redis.Delete(ctx, key)
 
 	return true, nil
 }
 
 // LoginByCode 通过验证码登录
-func (s *smsCodeService) LoginByCode(phone, code string) (*model.User, error) {
+func (s *smsCodeService) LoginByCode(ctx context.Context, phone, code string) (*model.User, error) {
 	// 验证验证码
-	valid, err := s.VerifyCode(phone, code)
-	if !valid || err != nil {
-		return nil, errors.New("验证码验证失败")
+	valid, err := s.VerifyCode(ctx, phone, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, errors.Unauthorized("验证码验证失败")
 	}
 
 	// 查找用户
-	user, err := s.findOrCreateUserByPhone(phone)
+	user, err := s.findOrCreateUserByPhone(ctx, phone)
 	if err != nil {
 		return nil, err
 	}
@@ -174,18 +181,18 @@ func (s *smsCodeService) LoginByCode(phone, code string) (*model.User, error) {
 	// 更新最后登录时间
 	now := time.Now()
 	user.LastLogin = &now
-	err = s.userRepo.Update(user)
+	err = s.userRepo.Update(ctx, user)
 	if err != nil {
-		return nil, fmt.Errorf("更新用户登录时间失败: %v", err)
+		return nil, errors.Wrap(err, errors.CodeInternal, "更新用户登录时间失败")
 	}
 
 	return user, nil
 }
 
 // findOrCreateUserByPhone 根据手机号查找用户，如果不存在则创建
-func (s *smsCodeService) findOrCreateUserByPhone(phone string) (*model.User, error) {
+func (s *smsCodeService) findOrCreateUserByPhone(ctx context.Context, phone string) (*model.User, error) {
 	// 尝试通过手机号查找用户
-	users, err := s.userRepo.FindByPhone(phone)
+	users, err := s.userRepo.FindByPhone(ctx, phone)
 	if err == nil && len(users) > 0 {
 		// 用户存在，返回第一个匹配的用户
 		return users[0], nil
@@ -199,9 +206,9 @@ func (s *smsCodeService) findOrCreateUserByPhone(phone string) (*model.User, err
 	}
 
 	// 创建用户
-	err = s.userRepo.Create(newUser)
+	err = s.userRepo.Create(ctx, newUser)
 	if err != nil {
-		return nil, fmt.Errorf("创建用户失败: %v", err)
+		return nil, errors.Wrap(err, errors.CodeInternal, "创建用户失败")
 	}
 
 	return newUser, nil
@@ -1,8 +1,9 @@
 package service
 
 import (
-	"errors"
+	"context"
 	"myApp/model"
+	"myApp/pkg/errors"
 	"myApp/pkg/logger"
 	"myApp/repository"
 
@@ -11,9 +12,9 @@ import (
 )
 
 type UserService interface {
-	Register(user *model.User) (*model.User, error)
-	Login(username, password string) (*model.User, error)
-	GetUserProfile(id uint) (*model.User, error)
+	Register(ctx context.Context, user *model.User) (*model.User, error)
+	Login(ctx context.Context, username, password string) (*model.User, error)
+	GetUserProfile(ctx context.Context, id uint) (*model.User, error)
 }
 
 type userService struct {
@@ -24,36 +25,36 @@ func NewUserService(repo repository.UserRepository) UserService {
 	return &userService{repo: repo}
 }
 
-func (s *userService) Register(user *model.User) (*model.User, error) {
+func (s *userService) Register(ctx context.Context, user *model.User) (*model.User, error) {
 	// 记录用户注册开始
-	logger.Info("用户注册开始",
+	logger.FromContext(ctx).Info("用户注册开始",
 		zap.String("username", user.Username),
 		zap.String("email", user.Email),
 		zap.String("phone", user.Phone),
 	)
 
 	// 检查用户名是否已存在
-	existingUser, _ := s.repo.FindByUsername(user.Username)
+	existingUser, _ := s.repo.FindByUsername(ctx, user.Username)
 	if existingUser != nil {
-		logger.Warn("用户注册失败：用户名已存在", zap.String("username", user.Username))
-		return nil, errors.New("用户名已存在")
+		logger.FromContext(ctx).Warn("用户注册失败：用户名已存在", zap.String("username", user.Username))
+		return nil, errors.Conflict("用户名已存在")
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
-		logger.Error("用户注册失败：密码加密失败", zap.Error(err))
-		return nil, errors.New("密码加密失败")
+		logger.FromContext(ctx).Error("用户注册失败：密码加密失败", zap.Error(err))
+		return nil, errors.Wrap(err, errors.CodeInternal, "密码加密失败")
 	}
 
 	user.Password = string(hashedPassword)
-	err = s.repo.Create(user)
+	err = s.repo.Create(ctx, user)
 	if err != nil {
-		logger.Error("用户注册失败：创建用户失败", zap.Error(err))
-		return nil, errors.New("创建用户失败")
+		logger.FromContext(ctx).Error("用户注册失败：创建用户失败", zap.Error(err))
+		return nil, errors.Wrap(err, errors.CodeInternal, "创建用户失败")
 	}
 
 	// 记录用户注册成功
-	logger.Info("用户注册成功",
+	logger.FromContext(ctx).Info("用户注册成功",
 		zap.String("username", user.Username),
 		zap.Uint("user_id", user.ID),
 	)
@@ -61,27 +62,27 @@ func (s *userService) Register(user *model.User) (*model.User, error) {
 	return user, nil
 }
 
-func (s *userService) Login(username, password string) (*model.User, error) {
+func (s *userService) Login(ctx context.Context, username, password string) (*model.User, error) {
 	// 记录登录尝试
-	logger.Info("用户登录尝试", zap.String("username", username))
+	logger.FromContext(ctx).Info("用户登录尝试", zap.String("username", username))
 
-	user, err := s.repo.FindByUsername(username)
+	user, err := s.repo.FindByUsername(ctx, username)
 	if err != nil {
-		logger.Warn("用户登录失败：用户不存在", zap.String("username", username))
-		return nil, errors.New("用户不存在")
+		logger.FromContext(ctx).Warn("用户登录失败：用户不存在", zap.String("username", username))
+		return nil, errors.NotFound("用户不存在")
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		logger.Warn("用户登录失败：密码错误",
+		logger.FromContext(ctx).Warn("用户登录失败：密码错误",
 			zap.String("username", username),
 			zap.Uint("user_id", user.ID),
 		)
-		return nil, errors.New("密码错误")
+		return nil, errors.Unauthorized("密码错误")
 	}
 
 	// 记录登录成功
-	logger.Info("用户登录成功",
+	logger.FromContext(ctx).Info("用户登录成功",
 		zap.String("username", username),
 		zap.Uint("user_id", user.ID),
 	)
@@ -89,15 +90,15 @@ func (s *userService) Login(username, password string) (*model.User, error) {
 	return user, nil
 }
 
-func (s *userService) GetUserProfile(id uint) (*model.User, error) {
-	logger.Debug("获取用户资料", zap.Uint("user_id", id))
+func (s *userService) GetUserProfile(ctx context.Context, id uint) (*model.User, error) {
+	logger.FromContext(ctx).Debug("获取用户资料", zap.Uint("user_id", id))
 
-	user, err := s.repo.FindByID(id)
+	user, err := s.repo.FindByID(ctx, id)
 	if err != nil {
-		logger.Warn("获取用户资料失败：用户不存在", zap.Uint("user_id", id))
-		return nil, errors.New("用户不存在")
+		logger.FromContext(ctx).Warn("获取用户资料失败", zap.Uint("user_id", id), zap.Error(err))
+		return nil, notFoundOr(err, "用户不存在")
 	}
 
-	logger.Debug("获取用户资料成功", zap.Uint("user_id", id))
+	logger.FromContext(ctx).Debug("获取用户资料成功", zap.Uint("user_id", id))
 	return user, nil
 }
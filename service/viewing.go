@@ -1,106 +1,129 @@
 package service
 
 import (
+	"context"
 	"myApp/model"
+	"myApp/pkg/errors"
 	"myApp/repository"
 	"time"
 )
 
 type ViewingService interface {
-	CreateViewing(viewing *model.Viewing) error
-	GetViewingByID(id uint) (*model.Viewing, error)
-	GetAllViewings(params map[string]interface{}) ([]model.Viewing, error)
-	UpdateViewing(viewing *model.Viewing) error
-	DeleteViewing(id uint) error
-	GetViewingsByUserID(userID uint) ([]model.Viewing, error)
-	GetViewingsByHouseID(houseID uint) ([]model.Viewing, error)
-	ConfirmViewing(id uint) error
-	CompleteViewing(id uint) error
-	CancelViewing(id uint, reason string) error
+	CreateViewing(ctx context.Context, viewing *model.Viewing) error
+	GetViewingByID(ctx context.Context, id uint) (*model.Viewing, error)
+	GetAllViewings(ctx context.Context, params map[string]interface{}) ([]model.Viewing, error)
+	UpdateViewing(ctx context.Context, viewing *model.Viewing) error
+	DeleteViewing(ctx context.Context, id uint) error
+	GetViewingsByUserID(ctx context.Context, userID uint) ([]model.Viewing, error)
+	GetViewingsByHouseID(ctx context.Context, houseID uint) ([]model.Viewing, error)
+	ConfirmViewing(ctx context.Context, id uint) error
+	CompleteViewing(ctx context.Context, id uint) error
+	CancelViewing(ctx context.Context, id uint, reason string) error
 }
 
 type viewingService struct {
-	repo repository.ViewingRepository
+	repo      repository.ViewingRepository
+	txManager repository.TxManager
 }
 
-func NewViewingService(repo repository.ViewingRepository) ViewingService {
-	return &viewingService{repo: repo}
+func NewViewingService(repo repository.ViewingRepository, txManager repository.TxManager) ViewingService {
+	return &viewingService{repo: repo, txManager: txManager}
 }
 
-func (s *viewingService) CreateViewing(viewing *model.Viewing) error {
+func (s *viewingService) CreateViewing(ctx context.Context, viewing *model.Viewing) error {
 	// 设置初始状态为待确认
 	viewing.Status = model.ViewingPending
-	return s.repo.Create(viewing)
+	if err := s.repo.Create(ctx, viewing); err != nil {
+		return err
+	}
+	recordHouseActivity(ctx, viewing.HouseID, trendingWeightViewing)
+	return nil
 }
 
-func (s *viewingService) GetViewingByID(id uint) (*model.Viewing, error) {
-	return s.repo.GetByID(id)
+func (s *viewingService) GetViewingByID(ctx context.Context, id uint) (*model.Viewing, error) {
+	viewing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "预约记录不存在")
+	}
+	return viewing, nil
 }
 
-func (s *viewingService) GetAllViewings(params map[string]interface{}) ([]model.Viewing, error) {
-	return s.repo.GetAll(params)
+func (s *viewingService) GetAllViewings(ctx context.Context, params map[string]interface{}) ([]model.Viewing, error) {
+	return s.repo.GetAll(ctx, params)
 }
 
-func (s *viewingService) UpdateViewing(viewing *model.Viewing) error {
-	return s.repo.Update(viewing)
+func (s *viewingService) UpdateViewing(ctx context.Context, viewing *model.Viewing) error {
+	return s.repo.Update(ctx, viewing)
 }
 
-func (s *viewingService) DeleteViewing(id uint) error {
-	return s.repo.Delete(id)
+func (s *viewingService) DeleteViewing(ctx context.Context, id uint) error {
+	return s.repo.Delete(ctx, id)
 }
 
-func (s *viewingService) GetViewingsByUserID(userID uint) ([]model.Viewing, error) {
-	return s.repo.GetViewingsByUserID(userID)
+func (s *viewingService) GetViewingsByUserID(ctx context.Context, userID uint) ([]model.Viewing, error) {
+	return s.repo.GetViewingsByUserID(ctx, userID)
 }
 
-func (s *viewingService) GetViewingsByHouseID(houseID uint) ([]model.Viewing, error) {
-	return s.repo.GetViewingsByHouseID(houseID)
+func (s *viewingService) GetViewingsByHouseID(ctx context.Context, houseID uint) ([]model.Viewing, error) {
+	return s.repo.GetViewingsByHouseID(ctx, houseID)
 }
 
-func (s *viewingService) ConfirmViewing(id uint) error {
-	// 获取预约看房记录
-	viewing, err := s.repo.GetByID(id)
-	if err != nil {
-		return err
-	}
-	
-	// 更新状态为已确认
-	viewing.Status = model.ViewingConfirmed
-	
-	// 设置确认时间
-	now := time.Now()
-	viewing.ConfirmTime = &now
-	
-	return s.repo.Update(viewing)
+func (s *viewingService) ConfirmViewing(ctx context.Context, id uint) error {
+	return s.updateViewing(ctx, id, func(viewing *model.Viewing) error {
+		if viewing.Status != model.ViewingPending {
+			return errors.Conflict("仅待确认的预约可以确认")
+		}
+
+		// 更新状态为已确认
+		viewing.Status = model.ViewingConfirmed
+
+		// 设置确认时间
+		now := time.Now()
+		viewing.ConfirmTime = &now
+		return nil
+	})
 }
 
-func (s *viewingService) CompleteViewing(id uint) error {
-	// 获取预约看房记录
-	viewing, err := s.repo.GetByID(id)
-	if err != nil {
-		return err
-	}
-	
-	// 更新状态为已完成
-	viewing.Status = model.ViewingCompleted
-	
-	return s.repo.Update(viewing)
+func (s *viewingService) CompleteViewing(ctx context.Context, id uint) error {
+	return s.updateViewing(ctx, id, func(viewing *model.Viewing) error {
+		if viewing.Status != model.ViewingConfirmed {
+			return errors.Conflict("仅已确认的预约可以完成")
+		}
+
+		// 更新状态为已完成
+		viewing.Status = model.ViewingCompleted
+		return nil
+	})
 }
 
-func (s *viewingService) CancelViewing(id uint, reason string) error {
-	// 获取预约看房记录
-	viewing, err := s.repo.GetByID(id)
-	if err != nil {
-		return err
-	}
-	
-	// 更新状态为已取消
-	viewing.Status = model.ViewingCancelled
-	
-	// 设置取消时间和原因
-	now := time.Now()
-	viewing.CancelTime = &now
-	viewing.CancelReason = reason
-	
-	return s.repo.Update(viewing)
-}
\ No newline at end of file
+func (s *viewingService) CancelViewing(ctx context.Context, id uint, reason string) error {
+	return s.updateViewing(ctx, id, func(viewing *model.Viewing) error {
+		if viewing.Status != model.ViewingPending && viewing.Status != model.ViewingConfirmed {
+			return errors.Conflict("仅待确认或已确认的预约可以取消")
+		}
+
+		// 更新状态为已取消
+		viewing.Status = model.ViewingCancelled
+
+		// 设置取消时间和原因
+		now := time.Now()
+		viewing.CancelTime = &now
+		viewing.CancelReason = reason
+		return nil
+	})
+}
+
+// updateViewing 在事务中加锁读取预约看房记录、修改并保存，避免并发修改时基于过期数据互相覆盖
+// modify在加锁读取的最新记录上检查状态变更是否允许，返回错误时不保存
+func (s *viewingService) updateViewing(ctx context.Context, id uint, modify func(viewing *model.Viewing) error) error {
+	return s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		viewing, err := s.repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return notFoundOr(err, "预约记录不存在")
+		}
+		if err := modify(viewing); err != nil {
+			return err
+		}
+		return s.repo.Update(ctx, viewing)
+	})
+}
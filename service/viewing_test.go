@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"myApp/model"
+	"myApp/model/modeltest"
+	"myApp/pkg/errors"
+	"myApp/repository"
+)
+
+func TestViewingStatusTransitions(t *testing.T) {
+	ctx := t.Context()
+	db := modeltest.NewDB(t)
+	repo := repository.NewViewingRepository(db)
+	svc := NewViewingService(repo, repository.NewTxManager(db))
+
+	house := modeltest.Create(t, db, modeltest.House(), nil)
+	newViewing := func() uint {
+		return modeltest.Create(t, db, &model.Viewing{
+			HouseID:     house.ID,
+			UserID:      2,
+			ViewingTime: time.Now().Add(24 * time.Hour),
+			Status:      model.ViewingPending,
+		}, nil).ID
+	}
+	status := func(id uint) int {
+		viewing, err := repo.GetByID(ctx, id)
+		if err != nil {
+			t.Fatalf("查询预约失败: %v", err)
+		}
+		return viewing.Status
+	}
+
+	// 待确认 → 已确认 → 已完成，不允许跳过或重复变更
+	id := newViewing()
+	steps := []struct {
+		name   string
+		fn     func(id uint) error
+		ok     bool
+		status int
+	}{
+		{"待确认时完成", func(id uint) error { return svc.CompleteViewing(ctx, id) }, false, model.ViewingPending},
+		{"确认", func(id uint) error { return svc.ConfirmViewing(ctx, id) }, true, model.ViewingConfirmed},
+		{"重复确认", func(id uint) error { return svc.ConfirmViewing(ctx, id) }, false, model.ViewingConfirmed},
+		{"完成", func(id uint) error { return svc.CompleteViewing(ctx, id) }, true, model.ViewingCompleted},
+		{"完成后取消", func(id uint) error { return svc.CancelViewing(ctx, id, "时间冲突") }, false, model.ViewingCompleted},
+	}
+	for _, step := range steps {
+		err := step.fn(id)
+		if step.ok && err != nil {
+			t.Fatalf("%s失败: %v", step.name, err)
+		}
+		if !step.ok && errors.CodeOf(err) != errors.CodeConflict {
+			t.Fatalf("%s返回%v，期望状态冲突", step.name, err)
+		}
+		if got := status(id); got != step.status {
+			t.Fatalf("%s后状态为%d，期望%d", step.name, got, step.status)
+		}
+	}
+
+	// 待确认和已确认的预约可以取消，取消后不能再确认
+	pending, confirmed := newViewing(), newViewing()
+	if err := svc.ConfirmViewing(ctx, confirmed); err != nil {
+		t.Fatalf("确认预约失败: %v", err)
+	}
+	for _, id := range []uint{pending, confirmed} {
+		if err := svc.CancelViewing(ctx, id, "时间冲突"); err != nil {
+			t.Fatalf("取消预约%d失败: %v", id, err)
+		}
+		if got := status(id); got != model.ViewingCancelled {
+			t.Fatalf("取消后状态为%d，期望%d", got, model.ViewingCancelled)
+		}
+	}
+	if err := svc.ConfirmViewing(ctx, pending); errors.CodeOf(err) != errors.CodeConflict {
+		t.Errorf("确认已取消的预约返回%v，期望状态冲突", err)
+	}
+	if err := svc.ConfirmViewing(ctx, 9999); errors.CodeOf(err) != errors.CodeNotFound {
+		t.Errorf("确认不存在的预约返回%v，期望不存在", err)
+	}
+}